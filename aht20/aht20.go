@@ -0,0 +1,155 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package aht20
+
+import (
+	"fmt"
+	"time"
+
+	"periph.io/x/periph/conn"
+	"periph.io/x/periph/conn/i2c"
+	"periph.io/x/periph/conn/physic"
+
+	"github.com/sigurn/crc8"
+)
+
+// Addr is the I²C address of the AHT20, it cannot be changed
+const Addr uint16 = 0x38
+
+// MeasurementTime is how long a measurement takes
+const MeasurementTime = 80 * time.Millisecond
+
+// AHT20 commands from the datasheet
+var (
+	cmdStatus    = []byte{0x71}             // Returns the status byte
+	cmdInit      = []byte{0xbe, 0x08, 0x00} // Loads the calibration
+	cmdTrigger   = []byte{0xac, 0x33, 0x00} // Starts a measurement
+	cmdSoftReset = []byte{0xba}             // Resets the sensor
+)
+
+// Status bits
+const (
+	statusBusy       uint8 = 0x80 // A measurement is running
+	statusCalibrated uint8 = 0x08 // The calibration has been loaded
+)
+
+var (
+	crc8aht20 = crc8.MakeTable(crc8.Params{
+		Poly:   0x31,
+		Init:   0xFF,
+		RefIn:  false,
+		RefOut: false,
+		XorOut: 0x00,
+		Check:  0xF7,
+		Name:   "CRC-8/AHT20",
+	})
+)
+
+// Dev holds the connection to the AHT20
+type Dev struct {
+	i2c conn.Conn // i2c device handle for the aht20
+}
+
+var _ conn.Resource = &Dev{}
+
+// New returns an AHT20 device struct for communicating with the device
+//
+// The sensor needs 40ms after it is powered up before New is called. If the
+// calibration bit is not set it sends the initialization command to load it.
+func New(i i2c.Bus) (*Dev, error) {
+	d := &Dev{i2c: &i2c.Dev{Bus: i, Addr: Addr}}
+	if err := d.calibrate(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// String implements conn.Resource.
+func (d *Dev) String() string {
+	return fmt.Sprintf("aht20{%s}", d.i2c)
+}
+
+// Halt implements conn.Resource.
+//
+// The AHT20 sleeps after each measurement, so there is nothing to halt.
+func (d *Dev) Halt() error {
+	return nil
+}
+
+// SoftReset resets the sensor, and reloads its calibration
+func (d *Dev) SoftReset() error {
+	if err := d.i2c.Tx(cmdSoftReset, nil); err != nil {
+		return fmt.Errorf("aht20: Error while resetting: %w", err)
+	}
+	// Requires a 20ms delay after the reset
+	time.Sleep(20 * time.Millisecond)
+	return d.calibrate()
+}
+
+// Sense triggers a measurement, waits for it to finish, and returns the temperature
+// and relative humidity
+func (d *Dev) Sense(env *physic.Env) error {
+	if err := d.i2c.Tx(cmdTrigger, nil); err != nil {
+		return fmt.Errorf("aht20: Error while triggering measurement: %w", err)
+	}
+	time.Sleep(MeasurementTime)
+
+	// Status, 20 bits of humidity, 20 bits of temperature, and the CRC8
+	var data [7]byte
+	for i := 0; ; i++ {
+		if err := d.i2c.Tx(nil, data[:]); err != nil {
+			return fmt.Errorf("aht20: Error while reading measurement: %w", err)
+		}
+		if data[0]&statusBusy == 0 {
+			break
+		}
+		if i == 10 {
+			return fmt.Errorf("aht20: Timeout waiting for measurement")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if crc8.Checksum(data[0:6], crc8aht20) != data[6] {
+		return fmt.Errorf("aht20: Measurement CRC8 failed on: %v", data)
+	}
+
+	// RH = 100 * raw / 2^20, T = -50 + 200 * raw / 2^20
+	rh := int64(data[1])<<12 | int64(data[2])<<4 | int64(data[3])>>4
+	t := int64(data[3]&0x0f)<<16 | int64(data[4])<<8 | int64(data[5])
+	env.Humidity = physic.RelativeHumidity(rh * int64(100*physic.PercentRH) >> 20)
+	env.Temperature = physic.ZeroCelsius - 50*physic.Celsius + physic.Temperature(t*200000>>20)*physic.MilliCelsius
+	return nil
+}
+
+// calibrate loads the calibration if the calibration bit is not set
+func (d *Dev) calibrate() error {
+	status, err := d.status()
+	if err != nil {
+		return err
+	}
+	if status&statusCalibrated != 0 {
+		return nil
+	}
+	if err := d.i2c.Tx(cmdInit, nil); err != nil {
+		return fmt.Errorf("aht20: Error while initializing: %w", err)
+	}
+	// Requires a 10ms delay after the initialization
+	time.Sleep(10 * time.Millisecond)
+	if status, err = d.status(); err != nil {
+		return err
+	}
+	if status&statusCalibrated == 0 {
+		return fmt.Errorf("aht20: Calibration failed, status is 0x%02X", status)
+	}
+	return nil
+}
+
+// status returns the status byte
+func (d *Dev) status() (uint8, error) {
+	var status [1]byte
+	if err := d.i2c.Tx(cmdStatus, status[:]); err != nil {
+		return 0, fmt.Errorf("aht20: Error while reading status: %w", err)
+	}
+	return status[0], nil
+}
@@ -0,0 +1,141 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package aht20
+
+import (
+	"testing"
+
+	"periph.io/x/periph/conn/i2c/i2ctest"
+	"periph.io/x/periph/conn/physic"
+)
+
+var (
+	// 50%rH and 30°C with the CRC8
+	GoodData = []byte{0x1c, 0x80, 0x00, 0x06, 0x66, 0x66, 0x5c}
+)
+
+func TestNew(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Check the calibration
+			{Addr: 0x38, W: []byte{0x71}, R: []byte{0x18}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if d.String() != "aht20{playback(56)}" {
+		t.Fatalf("String Error: %s", d.String())
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCalibrate(t *testing.T) {
+	bus := &i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x38, W: []byte{0x71}, R: []byte{0x10}},
+			{Addr: 0x38, W: []byte{0xbe, 0x08, 0x00}},
+			{Addr: 0x38, W: []byte{0x71}, R: []byte{0x18}},
+		},
+		DontPanic: true,
+	}
+	if _, err := New(bus); err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	bus = &i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x38, W: []byte{0x71}, R: []byte{0x10}},
+			{Addr: 0x38, W: []byte{0xbe, 0x08, 0x00}},
+			{Addr: 0x38, W: []byte{0x71}, R: []byte{0x10}},
+		},
+		DontPanic: true,
+	}
+	if _, err := New(bus); err == nil {
+		t.Fatal("Calibration failed Error")
+	}
+}
+
+func TestSense(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Check the calibration
+			{Addr: 0x38, W: []byte{0x71}, R: []byte{0x18}},
+			{Addr: 0x38, W: []byte{0xac, 0x33, 0x00}},
+			{Addr: 0x38, W: []byte{}, R: []byte{0x9c, 0, 0, 0, 0, 0, 0}},
+			{Addr: 0x38, W: []byte{}, R: GoodData},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	var env physic.Env
+	if err := d.Sense(&env); err != nil {
+		t.Fatalf("Sense Error: %s", err)
+	}
+	if env.Humidity != 50*physic.PercentRH {
+		t.Errorf("Humidity Error: %s", env.Humidity)
+	}
+	if env.Temperature != physic.ZeroCelsius+29999*physic.MilliCelsius {
+		t.Errorf("Temperature Error: %s", env.Temperature)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSenseCRC(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Check the calibration
+			{Addr: 0x38, W: []byte{0x71}, R: []byte{0x18}},
+			{Addr: 0x38, W: []byte{0xac, 0x33, 0x00}},
+			{Addr: 0x38, W: []byte{}, R: []byte{0x1c, 0x80, 0x00, 0x06, 0x66, 0x67, 0x5c}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	var env physic.Env
+	if err := d.Sense(&env); err == nil {
+		t.Fatal("CRC Error")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSoftReset(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Check the calibration
+			{Addr: 0x38, W: []byte{0x71}, R: []byte{0x18}},
+			{Addr: 0x38, W: []byte{0xba}},
+			{Addr: 0x38, W: []byte{0x71}, R: []byte{0x18}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if err := d.SoftReset(); err != nil {
+		t.Fatalf("SoftReset Error: %s", err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,13 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package aht20 controls an Aosong AHT20 temperature and humidity sensor over I²C.
+//
+// New calibrates the sensor if its calibration bit is not set, and Sense triggers a
+// measurement and checks its CRC8.
+//
+// Datasheet
+//
+// https://cdn-learn.adafruit.com/assets/assets/000/091/676/original/AHT20-datasheet-2020-4-16.pdf
+package aht20
@@ -0,0 +1,63 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package aqi
+
+// Category is the name of a US EPA AQI range
+type Category int
+
+const (
+	Good                        Category = iota // AQI 0 to 50
+	Moderate                                    // AQI 51 to 100
+	UnhealthyForSensitiveGroups                 // AQI 101 to 150
+	Unhealthy                                   // AQI 151 to 200
+	VeryUnhealthy                               // AQI 201 to 300
+	Hazardous                                   // AQI 301 and higher
+)
+
+// String returns the name of the category
+func (c Category) String() string {
+	switch c {
+	case Good:
+		return "Good"
+	case Moderate:
+		return "Moderate"
+	case UnhealthyForSensitiveGroups:
+		return "Unhealthy for Sensitive Groups"
+	case Unhealthy:
+		return "Unhealthy"
+	case VeryUnhealthy:
+		return "Very Unhealthy"
+	case Hazardous:
+		return "Hazardous"
+	}
+	return "Unknown"
+}
+
+// CategoryOf returns the category of a US EPA AQI value
+func CategoryOf(aqi int) Category {
+	switch {
+	case aqi <= 50:
+		return Good
+	case aqi <= 100:
+		return Moderate
+	case aqi <= 150:
+		return UnhealthyForSensitiveGroups
+	case aqi <= 200:
+		return Unhealthy
+	case aqi <= 300:
+		return VeryUnhealthy
+	}
+	return Hazardous
+}
+
+// PM25 returns the US EPA AQI for a 24 hour average PM2.5 concentration in μg/m³
+func PM25(c float64) int {
+	return USEPA.PM25(c)
+}
+
+// PM10 returns the US EPA AQI for a 24 hour average PM10 concentration in μg/m³
+func PM10(c float64) int {
+	return USEPA.PM10(c)
+}
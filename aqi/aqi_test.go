@@ -0,0 +1,80 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package aqi
+
+import (
+	"testing"
+)
+
+func TestPM25(t *testing.T) {
+	tests := []struct {
+		c   float64
+		aqi int
+	}{
+		{0, 0},
+		{9.0, 50},
+		{9.05, 50},
+		{9.1, 51},
+		{12.0, 56},
+		{35.4, 100},
+		{35.5, 101},
+		{55.5, 151},
+		{125.5, 201},
+		{225.5, 301},
+		{325.4, 500},
+		{1000, 500},
+	}
+	for _, tt := range tests {
+		if aqi := PM25(tt.c); aqi != tt.aqi {
+			t.Errorf("PM25(%v) = %d, expected %d", tt.c, aqi, tt.aqi)
+		}
+	}
+}
+
+func TestPM10(t *testing.T) {
+	tests := []struct {
+		c   float64
+		aqi int
+	}{
+		{0, 0},
+		{54, 50},
+		{54.9, 50},
+		{55, 51},
+		{100, 73},
+		{155, 101},
+		{425, 301},
+		{604, 500},
+		{1000, 500},
+	}
+	for _, tt := range tests {
+		if aqi := PM10(tt.c); aqi != tt.aqi {
+			t.Errorf("PM10(%v) = %d, expected %d", tt.c, aqi, tt.aqi)
+		}
+	}
+}
+
+func TestCategory(t *testing.T) {
+	tests := []struct {
+		aqi int
+		cat Category
+	}{
+		{0, Good},
+		{50, Good},
+		{51, Moderate},
+		{150, UnhealthyForSensitiveGroups},
+		{151, Unhealthy},
+		{300, VeryUnhealthy},
+		{301, Hazardous},
+		{500, Hazardous},
+	}
+	for _, tt := range tests {
+		if cat := CategoryOf(tt.aqi); cat != tt.cat {
+			t.Errorf("CategoryOf(%d) = %s, expected %s", tt.aqi, cat, tt.cat)
+		}
+	}
+	if UnhealthyForSensitiveGroups.String() != "Unhealthy for Sensitive Groups" {
+		t.Errorf("Category String is wrong: %s", UnhealthyForSensitiveGroups)
+	}
+}
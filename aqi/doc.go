@@ -0,0 +1,16 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package aqi converts particle concentrations into an Air Quality Index.
+//
+// The US EPA AQI uses the PM2.5 breakpoints from the 2024 revision of the standard,
+// and the PM10 breakpoints with the combined Hazardous category.
+//
+// The European Common Air Quality Index (CAQI) hourly grid, and the Chinese
+// Individual Air Quality Index from HJ 633-2012, are selected with Standard.
+//
+// Technical Assistance Document
+//
+// https://document.airnow.gov/technical-assistance-document-for-the-reporting-of-daily-air-quailty.pdf
+package aqi
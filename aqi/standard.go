@@ -0,0 +1,222 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package aqi
+
+import (
+	"math"
+)
+
+// Standard selects the air quality index to calculate
+type Standard int
+
+const (
+	// USEPA is the US EPA Air Quality Index, for 24 hour averages
+	USEPA Standard = iota
+	// CAQI is the European Common Air Quality Index, for hourly averages
+	CAQI
+	// ChinaHJ633 is the Chinese Individual Air Quality Index from HJ 633-2012, for
+	// 24 hour averages
+	ChinaHJ633
+)
+
+// breakpoint maps a concentration range onto an index range
+type breakpoint struct {
+	cLow, cHigh float64 // Concentration range in μg/m³
+	iLow, iHigh int     // Index range
+}
+
+// category is the name of the index values up to max
+type category struct {
+	max  int
+	name string
+}
+
+// table holds the breakpoints and rules for a Standard
+type table struct {
+	name        string
+	pm25        []breakpoint
+	pm10        []breakpoint
+	pm25Places  int  // Decimal places to truncate PM2.5 to, or -1 to not truncate
+	pm10Places  int  // Decimal places to truncate PM10 to, or -1 to not truncate
+	roundUp     bool // Round the index up instead of to the nearest integer
+	extrapolate bool // Extend the last breakpoint instead of capping the index
+	categories  []category
+}
+
+var tables = map[Standard]table{
+	USEPA: {
+		name: "US EPA AQI",
+		// PM2.5 breakpoints from the 2024 revision, truncated to 1 decimal place
+		pm25: []breakpoint{
+			{0.0, 9.0, 0, 50},
+			{9.1, 35.4, 51, 100},
+			{35.5, 55.4, 101, 150},
+			{55.5, 125.4, 151, 200},
+			{125.5, 225.4, 201, 300},
+			{225.5, 325.4, 301, 500},
+		},
+		// PM10 breakpoints, truncated to an integer
+		pm10: []breakpoint{
+			{0, 54, 0, 50},
+			{55, 154, 51, 100},
+			{155, 254, 101, 150},
+			{255, 354, 151, 200},
+			{355, 424, 201, 300},
+			{425, 604, 301, 500},
+		},
+		pm25Places: 1,
+		pm10Places: 0,
+		categories: []category{
+			{50, Good.String()},
+			{100, Moderate.String()},
+			{150, UnhealthyForSensitiveGroups.String()},
+			{200, Unhealthy.String()},
+			{300, VeryUnhealthy.String()},
+			{math.MaxInt32, Hazardous.String()},
+		},
+	},
+	CAQI: {
+		name: "CAQI",
+		// Hourly grid, values above 100 are Very High
+		pm25: []breakpoint{
+			{0, 15, 0, 25},
+			{15, 30, 25, 50},
+			{30, 55, 50, 75},
+			{55, 110, 75, 100},
+		},
+		pm10: []breakpoint{
+			{0, 25, 0, 25},
+			{25, 50, 25, 50},
+			{50, 90, 50, 75},
+			{90, 180, 75, 100},
+		},
+		pm25Places:  -1,
+		pm10Places:  -1,
+		extrapolate: true,
+		categories: []category{
+			{25, "Very Low"},
+			{50, "Low"},
+			{75, "Medium"},
+			{100, "High"},
+			{math.MaxInt32, "Very High"},
+		},
+	},
+	ChinaHJ633: {
+		name: "China HJ 633",
+		// 24 hour average breakpoints
+		pm25: []breakpoint{
+			{0, 35, 0, 50},
+			{35, 75, 50, 100},
+			{75, 115, 100, 150},
+			{115, 150, 150, 200},
+			{150, 250, 200, 300},
+			{250, 350, 300, 400},
+			{350, 500, 400, 500},
+		},
+		pm10: []breakpoint{
+			{0, 50, 0, 50},
+			{50, 150, 50, 100},
+			{150, 250, 100, 150},
+			{250, 350, 150, 200},
+			{350, 420, 200, 300},
+			{420, 500, 300, 400},
+			{500, 600, 400, 500},
+		},
+		pm25Places: -1,
+		pm10Places: -1,
+		roundUp:    true,
+		categories: []category{
+			{50, "Excellent"},
+			{100, "Good"},
+			{150, "Lightly Polluted"},
+			{200, "Moderately Polluted"},
+			{300, "Heavily Polluted"},
+			{math.MaxInt32, "Severely Polluted"},
+		},
+	},
+}
+
+// String returns the name of the standard
+func (s Standard) String() string {
+	if t, ok := tables[s]; ok {
+		return t.name
+	}
+	return "Unknown"
+}
+
+// PM25 returns the index for a PM2.5 concentration in μg/m³
+func (s Standard) PM25(c float64) int {
+	t := tables[s]
+	return t.index(t.pm25, truncate(c, t.pm25Places))
+}
+
+// PM10 returns the index for a PM10 concentration in μg/m³
+func (s Standard) PM10(c float64) int {
+	t := tables[s]
+	return t.index(t.pm10, truncate(c, t.pm10Places))
+}
+
+// Index returns the higher of the PM2.5 and PM10 indexes
+func (s Standard) Index(pm25, pm10 float64) int {
+	i := s.PM25(pm25)
+	if i10 := s.PM10(pm10); i10 > i {
+		i = i10
+	}
+	return i
+}
+
+// Category returns the name of the standard's category for an index value
+func (s Standard) Category(index int) string {
+	for _, c := range tables[s].categories {
+		if index <= c.max {
+			return c.name
+		}
+	}
+	return "Unknown"
+}
+
+// truncate truncates c to places decimal places, -1 leaves it unchanged
+func truncate(c float64, places int) float64 {
+	if places < 0 {
+		return c
+	}
+	p := math.Pow(10, float64(places))
+	// The small offset keeps values like 9.1 from truncating down to 9.0
+	return math.Floor(c*p+1e-9) / p
+}
+
+// index returns the index for the concentration c
+func (t table) index(breakpoints []breakpoint, c float64) int {
+	if len(breakpoints) == 0 {
+		return 0
+	}
+	if c < 0 {
+		c = 0
+	}
+	last := breakpoints[len(breakpoints)-1]
+	for i, bp := range breakpoints {
+		// Values between the truncated breakpoints belong to the lower one
+		if c > bp.cHigh && i < len(breakpoints)-1 && c < breakpoints[i+1].cLow {
+			c = bp.cHigh
+		}
+		if c <= bp.cHigh {
+			return t.interpolate(bp, c)
+		}
+	}
+	if t.extrapolate {
+		return t.interpolate(last, c)
+	}
+	return last.iHigh
+}
+
+// interpolate returns the index for c within the breakpoint
+func (t table) interpolate(bp breakpoint, c float64) int {
+	i := float64(bp.iHigh-bp.iLow)/(bp.cHigh-bp.cLow)*(c-bp.cLow) + float64(bp.iLow)
+	if t.roundUp {
+		// Allow for floating point error before rounding up
+		return int(math.Ceil(i - 1e-9))
+	}
+	return int(math.Round(i))
+}
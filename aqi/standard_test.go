@@ -0,0 +1,47 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package aqi
+
+import (
+	"testing"
+)
+
+func TestStandards(t *testing.T) {
+	tests := []struct {
+		s          Standard
+		pm25, pm10 float64
+		index      int
+		category   string
+	}{
+		{USEPA, 12, 20, 56, "Moderate"},
+		{USEPA, 5, 200, 123, "Unhealthy for Sensitive Groups"},
+		{CAQI, 10, 10, 17, "Very Low"},
+		{CAQI, 40, 30, 60, "Medium"},
+		{CAQI, 110, 100, 100, "High"},
+		{CAQI, 165, 100, 125, "Very High"},
+		{ChinaHJ633, 20, 30, 30, "Excellent"},
+		{ChinaHJ633, 80, 100, 107, "Lightly Polluted"},
+		{ChinaHJ633, 300, 100, 350, "Severely Polluted"},
+		{ChinaHJ633, 1000, 100, 500, "Severely Polluted"},
+	}
+	for _, tt := range tests {
+		index := tt.s.Index(tt.pm25, tt.pm10)
+		if index != tt.index {
+			t.Errorf("%s Index(%v, %v) = %d, expected %d", tt.s, tt.pm25, tt.pm10, index, tt.index)
+		}
+		if c := tt.s.Category(index); c != tt.category {
+			t.Errorf("%s Category(%d) = %s, expected %s", tt.s, index, c, tt.category)
+		}
+	}
+}
+
+func TestStandardString(t *testing.T) {
+	if ChinaHJ633.String() != "China HJ 633" {
+		t.Errorf("Standard String is wrong: %s", ChinaHJ633)
+	}
+	if Standard(99).String() != "Unknown" {
+		t.Errorf("Unknown Standard String is wrong: %s", Standard(99))
+	}
+}
@@ -0,0 +1,290 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package bme280
+
+import (
+	"fmt"
+	"time"
+
+	"periph.io/x/periph/conn"
+	"periph.io/x/periph/conn/i2c"
+	"periph.io/x/periph/conn/physic"
+)
+
+// ChipID is the value of the BME280's chip ID register
+const ChipID uint8 = 0x60
+
+// BME280 registers from the datasheet
+const (
+	regCalib00  uint8 = 0x88 // Temperature and pressure calibration, and H1
+	regChipID   uint8 = 0xd0 // Chip ID
+	regReset    uint8 = 0xe0 // Write resetWord to reset the sensor
+	regCalib26  uint8 = 0xe1 // Humidity calibration
+	regCtrlHum  uint8 = 0xf2 // Humidity oversampling
+	regStatus   uint8 = 0xf3 // Measuring and NVM copy status
+	regCtrlMeas uint8 = 0xf4 // Temperature and pressure oversampling, and the mode
+	regConfig   uint8 = 0xf5 // Standby time and filter
+	regData     uint8 = 0xf7 // Pressure, temperature, and humidity readings
+)
+
+const (
+	resetWord       uint8 = 0xb6 // Resets the sensor when written to regReset
+	statusMeasuring uint8 = 0x08 // A measurement is running
+	statusImUpdate  uint8 = 0x01 // The calibration is being copied from the NVM
+	modeSleep       uint8 = 0x00 // No measurements
+	modeForced      uint8 = 0x01 // Make one measurement, and return to sleep
+	modeNormal      uint8 = 0x03 // Measure continuously, with standby between them
+)
+
+// Standby sets the time between measurements in normal mode
+type Standby uint8
+
+const (
+	// Standby0_5ms waits 0.5ms between measurements
+	Standby0_5ms Standby = iota
+	// Standby62_5ms waits 62.5ms between measurements
+	Standby62_5ms
+	// Standby125ms waits 125ms between measurements
+	Standby125ms
+	// Standby250ms waits 250ms between measurements
+	Standby250ms
+	// Standby500ms waits 500ms between measurements
+	Standby500ms
+	// Standby1s waits 1s between measurements
+	Standby1s
+	// Standby10ms waits 10ms between measurements
+	Standby10ms
+	// Standby20ms waits 20ms between measurements
+	Standby20ms
+)
+
+// Dev holds the connection to the BME280 and its calibration
+type Dev struct {
+	i2c    conn.Conn    // i2c device handle for the bme280
+	addr   uint16       // I²C address of the bme280
+	osrsT  Oversampling // Temperature oversampling
+	osrsP  Oversampling // Pressure oversampling
+	osrsH  Oversampling // Humidity oversampling
+	filter Filter       // IIR filter coefficient
+	normal bool         // Normal mode measurements are running
+	calib  calibration  // Factory calibration
+}
+
+var _ conn.Resource = &Dev{}
+
+// New returns a BME280 device struct for communicating with the device
+//
+// It checks the chip ID, reads the calibration, and configures the oversampling and
+// filter. The sensor is left in sleep mode.
+func New(i i2c.Bus, opts ...Option) (*Dev, error) {
+	d := &Dev{
+		addr:  DefaultAddr,
+		osrsT: O1x,
+		osrsP: O1x,
+		osrsH: O1x,
+	}
+	for _, o := range opts {
+		o(d)
+	}
+	if d.osrsT == Skip || d.osrsT > O16x || d.osrsP > O16x || d.osrsH > O16x {
+		return nil, fmt.Errorf("bme280: Invalid oversampling: %d %d %d", d.osrsT, d.osrsP, d.osrsH)
+	}
+	if d.filter > Filter16 {
+		return nil, fmt.Errorf("bme280: Invalid filter: %d", d.filter)
+	}
+	d.i2c = &i2c.Dev{Bus: i, Addr: d.addr}
+
+	var id [1]byte
+	if err := d.i2c.Tx([]byte{regChipID}, id[:]); err != nil {
+		return nil, fmt.Errorf("bme280: Error while reading chip ID: %w", err)
+	}
+	if id[0] != ChipID {
+		return nil, fmt.Errorf("bme280: BME280 not found, chip ID is 0x%02X", id[0])
+	}
+
+	var tp [26]byte
+	if err := d.i2c.Tx([]byte{regCalib00}, tp[:]); err != nil {
+		return nil, fmt.Errorf("bme280: Error while reading calibration: %w", err)
+	}
+	var h [7]byte
+	if err := d.i2c.Tx([]byte{regCalib26}, h[:]); err != nil {
+		return nil, fmt.Errorf("bme280: Error while reading humidity calibration: %w", err)
+	}
+	d.calib = newCalibration(tp[:], h[:])
+
+	// The config is only written in sleep mode, and ctrl_hum takes effect after
+	// ctrl_meas is written.
+	if err := d.configure(modeSleep, Standby0_5ms); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// String implements conn.Resource.
+func (d *Dev) String() string {
+	return fmt.Sprintf("bme280{%s}", d.i2c)
+}
+
+// Halt implements conn.Resource.
+//
+// It stops the normal mode measurements.
+func (d *Dev) Halt() error {
+	if !d.normal {
+		return nil
+	}
+	return d.Stop()
+}
+
+// Reset resets the sensor, and waits for it to reload its calibration
+// The oversampling and filter need to be set again by calling New.
+func (d *Dev) Reset() error {
+	if err := d.i2c.Tx([]byte{regReset, resetWord}, nil); err != nil {
+		return fmt.Errorf("bme280: Error while resetting: %w", err)
+	}
+	d.normal = false
+	for i := 0; i < 10; i++ {
+		time.Sleep(2 * time.Millisecond)
+		status, err := d.status()
+		if err != nil {
+			return err
+		}
+		if status&statusImUpdate == 0 {
+			return nil
+		}
+	}
+	return fmt.Errorf("bme280: Timeout waiting for reset")
+}
+
+// StartNormal makes the sensor measure continuously, waiting standby between the
+// measurements. Sense returns the latest measurement.
+func (d *Dev) StartNormal(standby Standby) error {
+	if standby > Standby20ms {
+		return fmt.Errorf("bme280: Invalid standby time: %d", standby)
+	}
+	// The config is ignored in normal mode, so make sure the sensor is asleep
+	if err := d.configure(modeSleep, standby); err != nil {
+		return err
+	}
+	if err := d.writeCtrlMeas(modeNormal); err != nil {
+		return err
+	}
+	d.normal = true
+	return nil
+}
+
+// Stop stops the normal mode measurements, putting the sensor to sleep
+func (d *Dev) Stop() error {
+	if err := d.writeCtrlMeas(modeSleep); err != nil {
+		return err
+	}
+	d.normal = false
+	return nil
+}
+
+// Sense returns the temperature, pressure, and humidity
+//
+// In sleep mode it makes a forced mode measurement, and waits for it to finish. In
+// normal mode it returns the latest measurement. Measurements that are skipped by
+// the oversampling settings are not updated.
+func (d *Dev) Sense(env *physic.Env) error {
+	if !d.normal {
+		if err := d.writeCtrlMeas(modeForced); err != nil {
+			return err
+		}
+		if err := d.waitMeasurement(); err != nil {
+			return err
+		}
+	}
+
+	var data [8]byte
+	if err := d.i2c.Tx([]byte{regData}, data[:]); err != nil {
+		return fmt.Errorf("bme280: Error while reading measurement: %w", err)
+	}
+	rawP := int32(data[0])<<12 | int32(data[1])<<4 | int32(data[2])>>4
+	rawT := int32(data[3])<<12 | int32(data[4])<<4 | int32(data[5])>>4
+	rawH := int32(data[6])<<8 | int32(data[7])
+
+	t, tFine := d.calib.temperature(rawT)
+	env.Temperature = physic.ZeroCelsius + physic.Temperature(t)*10*physic.MilliCelsius
+	if d.osrsP != Skip {
+		p := d.calib.pressure(rawP, tFine)
+		env.Pressure = physic.Pressure(p) * physic.Pascal / 256
+	}
+	if d.osrsH != Skip {
+		h := d.calib.humidity(rawH, tFine)
+		env.Humidity = physic.RelativeHumidity(int64(h) * int64(physic.PercentRH) / 1024)
+	}
+	return nil
+}
+
+// MeasurementTime returns the maximum time a measurement takes, using the datasheet's
+// formula for the oversampling settings
+func (d *Dev) MeasurementTime() time.Duration {
+	// 1.25ms + 2.3ms * T + (2.3ms * P + 0.575ms) + (2.3ms * H + 0.575ms)
+	t := 1250 + 2300*samples(d.osrsT)
+	if d.osrsP != Skip {
+		t += 2300*samples(d.osrsP) + 575
+	}
+	if d.osrsH != Skip {
+		t += 2300*samples(d.osrsH) + 575
+	}
+	return time.Duration(t) * time.Microsecond
+}
+
+// samples returns the number of samples made for the oversampling
+func samples(o Oversampling) int {
+	if o == Skip {
+		return 0
+	}
+	return 1 << (o - 1)
+}
+
+// waitMeasurement waits for a forced mode measurement to finish
+func (d *Dev) waitMeasurement() error {
+	time.Sleep(d.MeasurementTime())
+	for i := 0; i < 10; i++ {
+		status, err := d.status()
+		if err != nil {
+			return err
+		}
+		if status&statusMeasuring == 0 {
+			return nil
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return fmt.Errorf("bme280: Timeout waiting for measurement")
+}
+
+// status returns the status register
+func (d *Dev) status() (uint8, error) {
+	var status [1]byte
+	if err := d.i2c.Tx([]byte{regStatus}, status[:]); err != nil {
+		return 0, fmt.Errorf("bme280: Error while reading status: %w", err)
+	}
+	return status[0], nil
+}
+
+// configure writes the humidity oversampling, the standby time and filter, and the
+// temperature and pressure oversampling with the mode
+func (d *Dev) configure(mode uint8, standby Standby) error {
+	w := []byte{
+		regCtrlHum, uint8(d.osrsH),
+		regConfig, uint8(standby)<<5 | uint8(d.filter)<<2,
+		regCtrlMeas, uint8(d.osrsT)<<5 | uint8(d.osrsP)<<2 | mode,
+	}
+	if err := d.i2c.Tx(w, nil); err != nil {
+		return fmt.Errorf("bme280: Error while writing configuration: %w", err)
+	}
+	return nil
+}
+
+// writeCtrlMeas writes the temperature and pressure oversampling with the mode
+func (d *Dev) writeCtrlMeas(mode uint8) error {
+	w := []byte{regCtrlMeas, uint8(d.osrsT)<<5 | uint8(d.osrsP)<<2 | mode}
+	if err := d.i2c.Tx(w, nil); err != nil {
+		return fmt.Errorf("bme280: Error while writing mode: %w", err)
+	}
+	return nil
+}
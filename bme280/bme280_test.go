@@ -0,0 +1,235 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package bme280
+
+import (
+	"testing"
+
+	"periph.io/x/periph/conn/i2c/i2ctest"
+	"periph.io/x/periph/conn/physic"
+)
+
+var (
+	// Calibration and measurement captured from a BME280
+	CalibrationData = []byte{0x10, 0x6e, 0x6c, 0x66, 0x32, 0x0, 0x5d, 0x95, 0xb8, 0xd5, 0xd0, 0xb, 0x77,
+		0x1e, 0x9d, 0xff, 0xf9, 0xff, 0xac, 0x26, 0xa, 0xd8, 0xbd, 0x10, 0x0, 0x4b}
+	HumidityCalibrationData = []byte{0x6e, 0x1, 0x0, 0x13, 0x5, 0x0, 0x1e}
+	MeasurementData         = []byte{0x4a, 0x52, 0xc0, 0x80, 0x96, 0xc0, 0x7a, 0x76}
+	GoodEnv                 = physic.Env{
+		Temperature: physic.ZeroCelsius + 23720*physic.MilliCelsius,
+		Pressure:    100942695312500 * physic.NanoPascal,
+		Humidity:    6530566 * physic.TenthMicroRH,
+	}
+)
+
+func TestNew(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the calibration
+			{Addr: 0x76, W: []byte{0xd0}, R: []byte{0x60}},
+			{Addr: 0x76, W: []byte{0x88}, R: CalibrationData},
+			{Addr: 0x76, W: []byte{0xe1}, R: HumidityCalibrationData},
+			{Addr: 0x76, W: []byte{0xf2, 0x01, 0xf5, 0x00, 0xf4, 0x24}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if d.String() != "bme280{playback(118)}" {
+		t.Fatalf("String Error: %s", d.String())
+	}
+	if d.calib.h4 != 0x135 || d.calib.h5 != 0 || d.calib.h6 != 0x1e {
+		t.Fatalf("Humidity calibration Error: %#v", d.calib)
+	}
+}
+
+func TestWrongChipID(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x76, W: []byte{0xd0}, R: []byte{0x58}},
+		},
+	}
+	if _, err := New(&bus); err == nil {
+		t.Fatal("Wrong chip ID Error")
+	}
+}
+
+func TestBadOptions(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops:       []i2ctest.IO{},
+		DontPanic: true,
+	}
+	if _, err := New(&bus, WithOversampling(Skip, O1x, O1x)); err == nil {
+		t.Error("Skipped temperature Error")
+	}
+	if _, err := New(&bus, WithOversampling(O1x, O16x+1, O1x)); err == nil {
+		t.Error("Invalid oversampling Error")
+	}
+	if _, err := New(&bus, WithFilter(Filter16+1)); err == nil {
+		t.Error("Invalid filter Error")
+	}
+	if _, err := New(&bus); err == nil {
+		t.Error("Failed New Error")
+	}
+}
+
+func TestSenseForced(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the calibration
+			{Addr: 0x76, W: []byte{0xd0}, R: []byte{0x60}},
+			{Addr: 0x76, W: []byte{0x88}, R: CalibrationData},
+			{Addr: 0x76, W: []byte{0xe1}, R: HumidityCalibrationData},
+			{Addr: 0x76, W: []byte{0xf2, 0x01, 0xf5, 0x00, 0xf4, 0x24}},
+			{Addr: 0x76, W: []byte{0xf4, 0x25}},
+			// Not finished, and then finished
+			{Addr: 0x76, W: []byte{0xf3}, R: []byte{0x08}},
+			{Addr: 0x76, W: []byte{0xf3}, R: []byte{0x00}},
+			{Addr: 0x76, W: []byte{0xf7}, R: MeasurementData},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	var env physic.Env
+	if err := d.Sense(&env); err != nil {
+		t.Fatalf("Sense Error: %s", err)
+	}
+	if env != GoodEnv {
+		t.Fatalf("Sense Error: %d %d %d", env.Temperature, env.Pressure, env.Humidity)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatalf("Close Error: %s", err)
+	}
+}
+
+func TestSenseNormal(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the calibration
+			{Addr: 0x76, W: []byte{0xd0}, R: []byte{0x60}},
+			{Addr: 0x76, W: []byte{0x88}, R: CalibrationData},
+			{Addr: 0x76, W: []byte{0xe1}, R: HumidityCalibrationData},
+			{Addr: 0x76, W: []byte{0xf2, 0x01, 0xf5, 0x00, 0xf4, 0x24}},
+			{Addr: 0x76, W: []byte{0xf2, 0x01, 0xf5, 0xa0, 0xf4, 0x24}},
+			{Addr: 0x76, W: []byte{0xf4, 0x27}},
+			{Addr: 0x76, W: []byte{0xf7}, R: MeasurementData},
+			{Addr: 0x76, W: []byte{0xf4, 0x24}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if err := d.StartNormal(Standby20ms + 1); err == nil {
+		t.Fatal("Invalid standby Error")
+	}
+	if err := d.StartNormal(Standby1s); err != nil {
+		t.Fatalf("StartNormal Error: %s", err)
+	}
+	var env physic.Env
+	if err := d.Sense(&env); err != nil {
+		t.Fatalf("Sense Error: %s", err)
+	}
+	if env != GoodEnv {
+		t.Fatalf("Sense Error: %d %d %d", env.Temperature, env.Pressure, env.Humidity)
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatalf("Halt Error: %s", err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatalf("Close Error: %s", err)
+	}
+}
+
+func TestSenseSkipped(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x77, W: []byte{0xd0}, R: []byte{0x60}},
+			{Addr: 0x77, W: []byte{0x88}, R: CalibrationData},
+			{Addr: 0x77, W: []byte{0xe1}, R: HumidityCalibrationData},
+			{Addr: 0x77, W: []byte{0xf2, 0x00, 0xf5, 0x10, 0xf4, 0xa0}},
+			{Addr: 0x77, W: []byte{0xf4, 0xa1}},
+			{Addr: 0x77, W: []byte{0xf3}, R: []byte{0x00}},
+			{Addr: 0x77, W: []byte{0xf7}, R: MeasurementData},
+		},
+	}
+	d, err := New(&bus, WithAddress(AltAddr), WithOversampling(O16x, Skip, Skip), WithFilter(Filter16))
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	env := physic.Env{Pressure: 1, Humidity: 1}
+	if err := d.Sense(&env); err != nil {
+		t.Fatalf("Sense Error: %s", err)
+	}
+	if env.Temperature != GoodEnv.Temperature || env.Pressure != 1 || env.Humidity != 1 {
+		t.Fatalf("Sense skipped Error: %d %d %d", env.Temperature, env.Pressure, env.Humidity)
+	}
+}
+
+func TestMeasurementTime(t *testing.T) {
+	d := &Dev{osrsT: O1x, osrsP: O1x, osrsH: O1x}
+	if d.MeasurementTime() != 9300*1000 {
+		t.Errorf("MeasurementTime Error: %s", d.MeasurementTime())
+	}
+	d = &Dev{osrsT: O16x, osrsP: O16x, osrsH: O16x}
+	if d.MeasurementTime() != 112800*1000 {
+		t.Errorf("MeasurementTime Error: %s", d.MeasurementTime())
+	}
+	d = &Dev{osrsT: O1x}
+	if d.MeasurementTime() != 3550*1000 {
+		t.Errorf("MeasurementTime Error: %s", d.MeasurementTime())
+	}
+}
+
+func TestReset(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the calibration
+			{Addr: 0x76, W: []byte{0xd0}, R: []byte{0x60}},
+			{Addr: 0x76, W: []byte{0x88}, R: CalibrationData},
+			{Addr: 0x76, W: []byte{0xe1}, R: HumidityCalibrationData},
+			{Addr: 0x76, W: []byte{0xf2, 0x01, 0xf5, 0x00, 0xf4, 0x24}},
+			{Addr: 0x76, W: []byte{0xe0, 0xb6}},
+			{Addr: 0x76, W: []byte{0xf3}, R: []byte{0x01}},
+			{Addr: 0x76, W: []byte{0xf3}, R: []byte{0x00}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if err := d.Reset(); err != nil {
+		t.Fatalf("Reset Error: %s", err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatalf("Close Error: %s", err)
+	}
+}
+
+func TestCompensation(t *testing.T) {
+	// Raw values captured from a BME280, and its compensated results
+	c := calibration{
+		t1: 28176, t2: 26220, t3: 350,
+		p1: 38237, p2: -10824, p3: 3024, p4: 7799, p5: -99, p6: -7, p7: 9900, p8: -10230, p9: 4285,
+		h1: 75, h2: 366, h3: 0, h4: 309, h5: 0, h6: 30,
+	}
+	temp, tFine := c.temperature(524112)
+	if tFine != 117407 || temp != 2293 {
+		t.Errorf("temperature Error: %d %d", temp, tFine)
+	}
+	if p := c.pressure(309104, tFine); p != 25611063 {
+		t.Errorf("pressure Error: %d", p)
+	}
+	if h := c.humidity(30987, tFine); h != 64686 {
+		t.Errorf("humidity Error: %d", h)
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package bme280
+
+import (
+	"encoding/binary"
+)
+
+// calibration holds the sensor's factory calibration
+type calibration struct {
+	t1             uint16
+	t2, t3         int16
+	p1             uint16
+	p2, p3, p4, p5 int16
+	p6, p7, p8, p9 int16
+	h1, h3         uint8
+	h2, h4, h5     int16
+	h6             int8
+}
+
+// newCalibration returns the calibration from the 26 bytes starting at register 0x88,
+// and the 7 bytes starting at register 0xE1
+func newCalibration(tp, h []byte) calibration {
+	le := binary.LittleEndian
+	return calibration{
+		t1: le.Uint16(tp[0:]),
+		t2: int16(le.Uint16(tp[2:])),
+		t3: int16(le.Uint16(tp[4:])),
+		p1: le.Uint16(tp[6:]),
+		p2: int16(le.Uint16(tp[8:])),
+		p3: int16(le.Uint16(tp[10:])),
+		p4: int16(le.Uint16(tp[12:])),
+		p5: int16(le.Uint16(tp[14:])),
+		p6: int16(le.Uint16(tp[16:])),
+		p7: int16(le.Uint16(tp[18:])),
+		p8: int16(le.Uint16(tp[20:])),
+		p9: int16(le.Uint16(tp[22:])),
+		h1: tp[25],
+		h2: int16(le.Uint16(h[0:])),
+		h3: h[2],
+		// H4 and H5 are 12 bit values, sharing the nibbles of 0xE5
+		h4: int16(int8(h[3]))<<4 | int16(h[4]&0x0f),
+		h5: int16(int8(h[5]))<<4 | int16(h[4]>>4),
+		h6: int8(h[6]),
+	}
+}
+
+// temperature returns the temperature in 0.01°C and t_fine, which is used to
+// compensate the pressure and humidity
+func (c *calibration) temperature(raw int32) (int32, int32) {
+	var1 := (((raw >> 3) - (int32(c.t1) << 1)) * int32(c.t2)) >> 11
+	var2 := (((((raw >> 4) - int32(c.t1)) * ((raw >> 4) - int32(c.t1))) >> 12) * int32(c.t3)) >> 14
+	tFine := var1 + var2
+	return (tFine*5 + 128) >> 8, tFine
+}
+
+// pressure returns the pressure in Pa as an unsigned 24.8 fixed point number
+func (c *calibration) pressure(raw, tFine int32) uint32 {
+	var1 := int64(tFine) - 128000
+	var2 := var1 * var1 * int64(c.p6)
+	var2 += (var1 * int64(c.p5)) << 17
+	var2 += int64(c.p4) << 35
+	var1 = ((var1 * var1 * int64(c.p3)) >> 8) + ((var1 * int64(c.p2)) << 12)
+	var1 = (((int64(1) << 47) + var1) * int64(c.p1)) >> 33
+	if var1 == 0 {
+		// Avoid dividing by zero
+		return 0
+	}
+	p := 1048576 - int64(raw)
+	p = (((p << 31) - var2) * 3125) / var1
+	var1 = (int64(c.p9) * (p >> 13) * (p >> 13)) >> 25
+	var2 = (int64(c.p8) * p) >> 19
+	p = ((p + var1 + var2) >> 8) + (int64(c.p7) << 4)
+	return uint32(p)
+}
+
+// humidity returns the relative humidity in %rH as an unsigned 22.10 fixed point number
+func (c *calibration) humidity(raw, tFine int32) uint32 {
+	v := tFine - 76800
+	v = ((((raw << 14) - (int32(c.h4) << 20) - (int32(c.h5) * v)) + 16384) >> 15) *
+		(((((((v*int32(c.h6))>>10)*(((v*int32(c.h3))>>11)+32768))>>10)+2097152)*int32(c.h2) + 8192) >> 14)
+	v -= ((((v >> 15) * (v >> 15)) >> 7) * int32(c.h1)) >> 4
+	if v < 0 {
+		v = 0
+	} else if v > 419430400 {
+		v = 419430400
+	}
+	return uint32(v >> 12)
+}
@@ -0,0 +1,16 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package bme280 controls a Bosch BME280 temperature, humidity, and pressure sensor
+// over I²C.
+//
+// The raw readings are compensated with the sensor's factory calibration using the
+// integer formulas from the datasheet. Sense makes a forced mode measurement, or
+// StartNormal makes the sensor measure continuously and Sense returns the latest
+// measurement.
+//
+// Datasheet
+//
+// https://www.bosch-sensortec.com/media/boschsensortec/downloads/datasheets/bst-bme280-ds002.pdf
+package bme280
@@ -0,0 +1,45 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package bme280_test
+
+import (
+	"fmt"
+	"log"
+
+	"periph.io/x/periph/conn/i2c/i2creg"
+	"periph.io/x/periph/conn/physic"
+	"periph.io/x/periph/host"
+
+	"github.com/bcl/air-sensors/bme280"
+)
+
+func Example() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Open a handle to the first available I²C bus:
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer bus.Close()
+
+	// The datasheet's indoor navigation settings
+	d, err := bme280.New(bus,
+		bme280.WithOversampling(bme280.O2x, bme280.O16x, bme280.O1x),
+		bme280.WithFilter(bme280.Filter16))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer d.Halt() //nolint
+
+	var env physic.Env
+	if err := d.Sense(&env); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%8s %10s %9s\n", env.Temperature, env.Pressure, env.Humidity)
+}
@@ -0,0 +1,77 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package bme280
+
+const (
+	// DefaultAddr is the I²C address of the BME280 with its SDO pin low
+	DefaultAddr uint16 = 0x76
+
+	// AltAddr is the I²C address of the BME280 with its SDO pin high
+	AltAddr uint16 = 0x77
+)
+
+// Oversampling selects how many samples are averaged for each measurement, more
+// samples reduce the noise but take longer
+type Oversampling uint8
+
+const (
+	// Skip skips the measurement, it is not updated in the physic.Env
+	Skip Oversampling = iota
+	// O1x makes 1 sample
+	O1x
+	// O2x averages 2 samples
+	O2x
+	// O4x averages 4 samples
+	O4x
+	// O8x averages 8 samples
+	O8x
+	// O16x averages 16 samples
+	O16x
+)
+
+// Filter sets the IIR filter coefficient, used to smooth out short changes in the
+// temperature and pressure, eg. from a door slamming
+type Filter uint8
+
+const (
+	// FilterOff disables the filter
+	FilterOff Filter = iota
+	// Filter2 uses a coefficient of 2
+	Filter2
+	// Filter4 uses a coefficient of 4
+	Filter4
+	// Filter8 uses a coefficient of 8
+	Filter8
+	// Filter16 uses a coefficient of 16
+	Filter16
+)
+
+// Option configures the Dev returned by New
+type Option func(*Dev)
+
+// WithAddress sets the I²C address of the sensor, the default is DefaultAddr
+func WithAddress(addr uint16) Option {
+	return func(d *Dev) {
+		d.addr = addr
+	}
+}
+
+// WithOversampling sets the oversampling of the temperature, pressure, and humidity
+// measurements, the default is O1x for each of them. The temperature cannot be
+// skipped, it is needed to compensate the others.
+func WithOversampling(temperature, pressure, humidity Oversampling) Option {
+	return func(d *Dev) {
+		d.osrsT = temperature
+		d.osrsP = pressure
+		d.osrsH = humidity
+	}
+}
+
+// WithFilter sets the IIR filter coefficient, the default is FilterOff
+func WithFilter(f Filter) Option {
+	return func(d *Dev) {
+		d.filter = f
+	}
+}
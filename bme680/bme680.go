@@ -0,0 +1,248 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package bme680
+
+import (
+	"fmt"
+	"time"
+
+	"periph.io/x/periph/conn"
+	"periph.io/x/periph/conn/i2c"
+	"periph.io/x/periph/conn/physic"
+)
+
+// ChipID is the value of the BME680's chip ID register
+const ChipID uint8 = 0x61
+
+// BME680 registers from the datasheet
+const (
+	regResHeatVal uint8 = 0x00 // Heater calibration, res_heat_val, res_heat_range, and range_sw_err
+	regField0     uint8 = 0x1d // Measurement status, followed by the readings
+	regResHeat0   uint8 = 0x5a // Heater resistance of profile 0, 10 registers
+	regGasWait0   uint8 = 0x64 // Heater duration of profile 0, 10 registers
+	regCtrlGas0   uint8 = 0x70 // Heater off
+	regCtrlGas1   uint8 = 0x71 // Run gas, and the heater profile to use
+	regCtrlHum    uint8 = 0x72 // Humidity oversampling
+	regCtrlMeas   uint8 = 0x74 // Temperature and pressure oversampling, and the mode
+	regConfig     uint8 = 0x75 // Filter
+	regCoeff1     uint8 = 0x89 // Temperature and pressure calibration, 25 bytes
+	regChipID     uint8 = 0xd0 // Chip ID
+	regReset      uint8 = 0xe0 // Write resetWord to reset the sensor
+	regCoeff2     uint8 = 0xe1 // Humidity, temperature, and gas calibration, 16 bytes
+)
+
+const (
+	resetWord     uint8 = 0xb6 // Resets the sensor when written to regReset
+	statusNewData uint8 = 0x80 // The measurement has finished
+	gasValid      uint8 = 0x20 // The gas measurement is valid
+	heatStable    uint8 = 0x10 // The heater reached its target temperature
+	runGas        uint8 = 0x10 // Run the gas measurement
+	heatOff       uint8 = 0x08 // Turn off the heater
+	modeSleep     uint8 = 0x00 // No measurements
+	modeForced    uint8 = 0x01 // Make one measurement, and return to sleep
+)
+
+// Reading holds the readings from the BME680
+type Reading struct {
+	Temperature   physic.Temperature      `json:"temperature"`    // Temperature
+	Pressure      physic.Pressure         `json:"pressure"`       // Pressure
+	Humidity      physic.RelativeHumidity `json:"humidity"`       // Relative humidity
+	GasResistance uint32                  `json:"gas_resistance"` // Gas sensor resistance in Ω
+	GasValid      bool                    `json:"gas_valid"`      // The gas resistance was measured at the heater's target temperature
+	Timestamp     time.Time               `json:"timestamp"`      // When the reading was made
+}
+
+// Dev holds the connection to the BME680 and its calibration
+type Dev struct {
+	i2c      conn.Conn          // i2c device handle for the bme680
+	addr     uint16             // I²C address of the bme680
+	osrsT    Oversampling       // Temperature oversampling
+	osrsP    Oversampling       // Pressure oversampling
+	osrsH    Oversampling       // Humidity oversampling
+	filter   Filter             // IIR filter coefficient
+	profiles [10]HeaterProfile  // Heater profiles
+	profile  int                // Heater profile used by Read
+	ambient  physic.Temperature // Last temperature, used to calculate the heater resistance
+	calib    calibration        // Factory calibration
+}
+
+var _ conn.Resource = &Dev{}
+
+// New returns a BME680 device struct for communicating with the device
+//
+// It checks the chip ID, reads the calibration, and configures the oversampling,
+// filter, and heater profile 0. The sensor is left in sleep mode.
+func New(i i2c.Bus, opts ...Option) (*Dev, error) {
+	d := &Dev{
+		addr:    DefaultAddr,
+		osrsT:   O2x,
+		osrsP:   O4x,
+		osrsH:   O2x,
+		filter:  Filter3,
+		ambient: physic.ZeroCelsius + 25*physic.Celsius,
+	}
+	d.profiles[0] = DefaultHeaterProfile
+	for _, o := range opts {
+		o(d)
+	}
+	if d.osrsT == Skip || d.osrsT > O16x || d.osrsP > O16x || d.osrsH > O16x {
+		return nil, fmt.Errorf("bme680: Invalid oversampling: %d %d %d", d.osrsT, d.osrsP, d.osrsH)
+	}
+	if d.filter > Filter127 {
+		return nil, fmt.Errorf("bme680: Invalid filter: %d", d.filter)
+	}
+	d.i2c = &i2c.Dev{Bus: i, Addr: d.addr}
+
+	var id [1]byte
+	if err := d.i2c.Tx([]byte{regChipID}, id[:]); err != nil {
+		return nil, fmt.Errorf("bme680: Error while reading chip ID: %w", err)
+	}
+	if id[0] != ChipID {
+		return nil, fmt.Errorf("bme680: BME680 not found, chip ID is 0x%02X", id[0])
+	}
+
+	var c1 [25]byte
+	if err := d.i2c.Tx([]byte{regCoeff1}, c1[:]); err != nil {
+		return nil, fmt.Errorf("bme680: Error while reading calibration: %w", err)
+	}
+	var c2 [16]byte
+	if err := d.i2c.Tx([]byte{regCoeff2}, c2[:]); err != nil {
+		return nil, fmt.Errorf("bme680: Error while reading calibration: %w", err)
+	}
+	var heat [5]byte
+	if err := d.i2c.Tx([]byte{regResHeatVal}, heat[:]); err != nil {
+		return nil, fmt.Errorf("bme680: Error while reading heater calibration: %w", err)
+	}
+	d.calib = newCalibration(c1[:], c2[:], heat[:])
+
+	w := []byte{
+		regCtrlMeas, d.ctrlMeas(modeSleep),
+		regConfig, uint8(d.filter) << 2,
+		regGasWait0, gasWait(d.profiles[0].Duration),
+	}
+	if err := d.i2c.Tx(w, nil); err != nil {
+		return nil, fmt.Errorf("bme680: Error while writing configuration: %w", err)
+	}
+	return d, nil
+}
+
+// String implements conn.Resource.
+func (d *Dev) String() string {
+	return fmt.Sprintf("bme680{%s}", d.i2c)
+}
+
+// Halt implements conn.Resource.
+//
+// The BME680 returns to sleep after each measurement, so there is nothing to halt.
+func (d *Dev) Halt() error {
+	return nil
+}
+
+// Reset resets the sensor, the configuration needs to be set again by calling New
+func (d *Dev) Reset() error {
+	if err := d.i2c.Tx([]byte{regReset, resetWord}, nil); err != nil {
+		return fmt.Errorf("bme680: Error while resetting: %w", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	return nil
+}
+
+// Sense returns the temperature, pressure, and humidity
+// It makes a full measurement with Read, including the gas resistance.
+func (d *Dev) Sense(env *physic.Env) error {
+	r, err := d.Read()
+	if err != nil {
+		return err
+	}
+	env.Temperature = r.Temperature
+	env.Pressure = r.Pressure
+	env.Humidity = r.Humidity
+	return nil
+}
+
+// Read makes a forced mode measurement of the temperature, pressure, humidity, and
+// gas resistance using the selected heater profile, and waits for it to finish.
+// Measurements that are skipped by the oversampling settings are 0.
+func (d *Dev) Read() (Reading, error) {
+	p := d.profiles[d.profile]
+	gas := []byte{regCtrlGas0, heatOff, regCtrlGas1, 0}
+	if p.Duration > 0 {
+		target := int32((p.Temperature - physic.ZeroCelsius) / physic.Celsius)
+		ambient := int32((d.ambient - physic.ZeroCelsius) / physic.Celsius)
+		gas = []byte{
+			regResHeat0 + uint8(d.profile), d.calib.heaterResistance(target, ambient),
+			regCtrlGas0, 0,
+			regCtrlGas1, runGas | uint8(d.profile),
+		}
+	}
+	w := append(gas, regCtrlHum, uint8(d.osrsH), regCtrlMeas, d.ctrlMeas(modeForced))
+	if err := d.i2c.Tx(w, nil); err != nil {
+		return Reading{}, fmt.Errorf("bme680: Error while starting measurement: %w", err)
+	}
+
+	time.Sleep(d.MeasurementTime() + p.Duration)
+	var data [15]byte
+	for i := 0; ; i++ {
+		if err := d.i2c.Tx([]byte{regField0}, data[:]); err != nil {
+			return Reading{}, fmt.Errorf("bme680: Error while reading measurement: %w", err)
+		}
+		if data[0]&statusNewData != 0 {
+			break
+		}
+		if i == 10 {
+			return Reading{}, fmt.Errorf("bme680: Timeout waiting for measurement")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return d.convert(data[:]), nil
+}
+
+// convert returns the compensated Reading from the 15 bytes starting at regField0
+func (d *Dev) convert(data []byte) Reading {
+	rawP := int32(data[2])<<12 | int32(data[3])<<4 | int32(data[4])>>4
+	rawT := int32(data[5])<<12 | int32(data[6])<<4 | int32(data[7])>>4
+	rawH := int32(data[8])<<8 | int32(data[9])
+	rawG := uint16(data[13])<<2 | uint16(data[14])>>6
+
+	var r Reading
+	t, tFine := d.calib.temperature(rawT)
+	r.Temperature = physic.ZeroCelsius + physic.Temperature(t)*10*physic.MilliCelsius
+	d.ambient = r.Temperature
+	if d.osrsP != Skip {
+		r.Pressure = physic.Pressure(d.calib.pressure(rawP, tFine)) * physic.Pascal
+	}
+	if d.osrsH != Skip {
+		r.Humidity = physic.RelativeHumidity(int64(d.calib.humidity(rawH, tFine)) * int64(physic.PercentRH) / 1000)
+	}
+	if d.profiles[d.profile].Duration > 0 {
+		r.GasResistance = d.calib.gasResistance(rawG, data[14]&0x0f)
+		r.GasValid = data[14]&(gasValid|heatStable) == gasValid|heatStable
+	}
+	r.Timestamp = time.Now()
+	return r
+}
+
+// MeasurementTime returns how long the temperature, pressure, and humidity measurement
+// takes, using Bosch's formula for the oversampling settings. The gas measurement
+// adds the heater profile's duration.
+func (d *Dev) MeasurementTime() time.Duration {
+	cycles := samples(d.osrsT) + samples(d.osrsP) + samples(d.osrsH)
+	us := cycles*1963 + 477*4 + 477*5 + 500
+	// Rounded to ms, plus 1ms to wake up
+	return time.Duration((us+500)/1000+1) * time.Millisecond
+}
+
+// samples returns the number of samples made for the oversampling
+func samples(o Oversampling) int {
+	if o == Skip {
+		return 0
+	}
+	return 1 << (o - 1)
+}
+
+// ctrlMeas returns the ctrl_meas register for the oversampling and mode
+func (d *Dev) ctrlMeas(mode uint8) uint8 {
+	return uint8(d.osrsT)<<5 | uint8(d.osrsP)<<2 | mode
+}
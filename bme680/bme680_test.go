@@ -0,0 +1,288 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package bme680
+
+import (
+	"testing"
+	"time"
+
+	"periph.io/x/periph/conn/i2c/i2ctest"
+	"periph.io/x/periph/conn/physic"
+)
+
+var (
+	// Calibration with typical values, and a measurement made with it
+	Coeff1 = []byte{0x00, 0x78, 0x66, 0x03, 0x00, 0xa0, 0x8c, 0x7c, 0xd7, 0x58, 0x00, 0x96, 0x19,
+		0xd0, 0xff, 0x1f, 0x1e, 0x00, 0x00, 0x48, 0xf4, 0x30, 0xf8, 0x1e, 0x00}
+	Coeff2 = []byte{0x3e, 0x80, 0x32, 0x00, 0x2d, 0x14, 0x78, 0x9c, 0x90, 0x65, 0xd8, 0xdc, 0xec,
+		0x12, 0x00, 0x00}
+	HeatCoeff       = []byte{0x28, 0x00, 0x10, 0x00, 0x00}
+	MeasurementData = []byte{0x80, 0x00, 0x5b, 0x2e, 0x00, 0x7f, 0x3a, 0x00, 0x64, 0x00, 0x00, 0x00,
+		0x00, 0xac, 0x35}
+)
+
+func TestNew(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the calibration
+			{Addr: 0x76, W: []byte{0xd0}, R: []byte{0x61}},
+			{Addr: 0x76, W: []byte{0x89}, R: Coeff1},
+			{Addr: 0x76, W: []byte{0xe1}, R: Coeff2},
+			{Addr: 0x76, W: []byte{0x00}, R: HeatCoeff},
+			{Addr: 0x76, W: []byte{0x74, 0x4c, 0x75, 0x08, 0x64, 0x65}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if d.String() != "bme680{playback(118)}" {
+		t.Fatalf("String Error: %s", d.String())
+	}
+	c := d.calib
+	if c.t1 != 26000 || c.t2 != 26232 || c.t3 != 3 {
+		t.Errorf("Temperature calibration Error: %#v", c)
+	}
+	if c.p1 != 36000 || c.p2 != -10372 || c.p6 != 30 || c.p7 != 31 || c.p10 != 30 {
+		t.Errorf("Pressure calibration Error: %#v", c)
+	}
+	if c.h1 != 800 || c.h2 != 1000 || c.h4 != 45 || c.h7 != -100 {
+		t.Errorf("Humidity calibration Error: %#v", c)
+	}
+	if c.gh1 != -20 || c.gh2 != -9000 || c.gh3 != 18 || c.resHeatRange != 1 || c.resHeatVal != 40 {
+		t.Errorf("Gas calibration Error: %#v", c)
+	}
+}
+
+func TestWrongChipID(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x76, W: []byte{0xd0}, R: []byte{0x60}},
+		},
+	}
+	if _, err := New(&bus); err == nil {
+		t.Fatal("Wrong chip ID Error")
+	}
+}
+
+func TestBadOptions(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops:       []i2ctest.IO{},
+		DontPanic: true,
+	}
+	if _, err := New(&bus, WithOversampling(Skip, O1x, O1x)); err == nil {
+		t.Error("Skipped temperature Error")
+	}
+	if _, err := New(&bus, WithOversampling(O1x, O1x, O16x+1)); err == nil {
+		t.Error("Invalid oversampling Error")
+	}
+	if _, err := New(&bus, WithFilter(Filter127+1)); err == nil {
+		t.Error("Invalid filter Error")
+	}
+}
+
+// within returns true if v is within tol of want
+func within(v, want, tol float64) bool {
+	return v > want-tol && v < want+tol
+}
+
+func TestCompensation(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the calibration
+			{Addr: 0x76, W: []byte{0xd0}, R: []byte{0x61}},
+			{Addr: 0x76, W: []byte{0x89}, R: Coeff1},
+			{Addr: 0x76, W: []byte{0xe1}, R: Coeff2},
+			{Addr: 0x76, W: []byte{0x00}, R: HeatCoeff},
+			{Addr: 0x76, W: []byte{0x74, 0x4c, 0x75, 0x08, 0x64, 0x65}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+
+	// Expected values are from the datasheet's floating point formulas, the integer
+	// pressure formula rounds to within a few Pa of them
+	temp, tFine := d.calib.temperature(0x7f3a0)
+	if !within(float64(temp)/100, 32.878, 0.01) {
+		t.Errorf("temperature Error: %d", temp)
+	}
+	if p := d.calib.pressure(0x5b2e0, tFine); !within(float64(p), 100186.7, 6) {
+		t.Errorf("pressure Error: %d", p)
+	}
+	if h := d.calib.humidity(0x6400, tFine); !within(float64(h)/1000, 71.18, 0.05) {
+		t.Errorf("humidity Error: %d", h)
+	}
+	if r := d.calib.gasResistance(0x2b0, 5); !within(float64(r), 219183, 200) {
+		t.Errorf("gasResistance Error: %d", r)
+	}
+	if r := d.calib.heaterResistance(320, 25); r < 120 || r > 122 {
+		t.Errorf("heaterResistance Error: %d", r)
+	}
+}
+
+func TestRead(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the calibration
+			{Addr: 0x76, W: []byte{0xd0}, R: []byte{0x61}},
+			{Addr: 0x76, W: []byte{0x89}, R: Coeff1},
+			{Addr: 0x76, W: []byte{0xe1}, R: Coeff2},
+			{Addr: 0x76, W: []byte{0x00}, R: HeatCoeff},
+			{Addr: 0x76, W: []byte{0x74, 0x4c, 0x75, 0x08, 0x64, 0x65}},
+			{Addr: 0x76, W: []byte{0x5a, 0x78, 0x70, 0x00, 0x71, 0x10, 0x72, 0x02, 0x74, 0x4d}},
+			{Addr: 0x76, W: []byte{0x1d}, R: make([]byte, 15)},
+			{Addr: 0x76, W: []byte{0x1d}, R: MeasurementData},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	r, err := d.Read()
+	if err != nil {
+		t.Fatalf("Read Error: %s", err)
+	}
+	if r.Temperature != physic.ZeroCelsius+32880*physic.MilliCelsius {
+		t.Errorf("Temperature Error: %s", r.Temperature)
+	}
+	if !within(float64(r.Pressure/physic.Pascal), 100186.7, 6) {
+		t.Errorf("Pressure Error: %s", r.Pressure)
+	}
+	if !within(float64(r.Humidity)/float64(physic.PercentRH), 71.18, 0.05) {
+		t.Errorf("Humidity Error: %s", r.Humidity)
+	}
+	if !within(float64(r.GasResistance), 219183, 200) || !r.GasValid {
+		t.Errorf("Gas Error: %d %v", r.GasResistance, r.GasValid)
+	}
+	if d.ambient != r.Temperature {
+		t.Errorf("Ambient temperature Error: %s", d.ambient)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadNoGas(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the calibration
+			{Addr: 0x76, W: []byte{0xd0}, R: []byte{0x61}},
+			{Addr: 0x76, W: []byte{0x89}, R: Coeff1},
+			{Addr: 0x76, W: []byte{0xe1}, R: Coeff2},
+			{Addr: 0x76, W: []byte{0x00}, R: HeatCoeff},
+			{Addr: 0x76, W: []byte{0x74, 0x4c, 0x75, 0x08, 0x64, 0x65}},
+			{Addr: 0x76, W: []byte{0x66, 0x00}},
+			{Addr: 0x76, W: []byte{0x70, 0x08, 0x71, 0x00, 0x72, 0x02, 0x74, 0x4d}},
+			{Addr: 0x76, W: []byte{0x1d}, R: MeasurementData},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if err := d.SetHeaterProfile(2, HeaterProfile{}); err != nil {
+		t.Fatalf("SetHeaterProfile Error: %s", err)
+	}
+	if err := d.SelectHeaterProfile(2); err != nil {
+		t.Fatalf("SelectHeaterProfile Error: %s", err)
+	}
+	r, err := d.Read()
+	if err != nil {
+		t.Fatalf("Read Error: %s", err)
+	}
+	if r.GasResistance != 0 || r.GasValid {
+		t.Errorf("Gas Error: %d %v", r.GasResistance, r.GasValid)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHeaterProfile(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the calibration
+			{Addr: 0x76, W: []byte{0xd0}, R: []byte{0x61}},
+			{Addr: 0x76, W: []byte{0x89}, R: Coeff1},
+			{Addr: 0x76, W: []byte{0xe1}, R: Coeff2},
+			{Addr: 0x76, W: []byte{0x00}, R: HeatCoeff},
+			{Addr: 0x76, W: []byte{0x74, 0x4c, 0x75, 0x08, 0x64, 0x65}},
+			{Addr: 0x76, W: []byte{0x6d, 0xfe}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	p := HeaterProfile{Temperature: physic.ZeroCelsius + 200*physic.Celsius, Duration: 4 * time.Second}
+	if err := d.SetHeaterProfile(9, p); err != nil {
+		t.Fatalf("SetHeaterProfile Error: %s", err)
+	}
+	if d.profiles[9] != p {
+		t.Errorf("Profile Error: %#v", d.profiles[9])
+	}
+	if err := d.SetHeaterProfile(10, p); err == nil {
+		t.Error("Invalid profile Error")
+	}
+	if err := d.SetHeaterProfile(0, HeaterProfile{Temperature: p.Temperature, Duration: 5 * time.Second}); err == nil {
+		t.Error("Invalid duration Error")
+	}
+	if err := d.SetHeaterProfile(0, HeaterProfile{Temperature: physic.ZeroCelsius + 500*physic.Celsius, Duration: time.Second}); err == nil {
+		t.Error("Invalid temperature Error")
+	}
+	if err := d.SelectHeaterProfile(-1); err == nil {
+		t.Error("Invalid select Error")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGasWait(t *testing.T) {
+	for _, tt := range []struct {
+		dur  time.Duration
+		want uint8
+	}{
+		{0, 0x00},
+		{63 * time.Millisecond, 0x3f},
+		{100 * time.Millisecond, 0x59},
+		{150 * time.Millisecond, 0x65},
+		{1 * time.Second, 0xbe},
+		{MaxHeaterDuration, 0xff},
+	} {
+		if w := gasWait(tt.dur); w != tt.want {
+			t.Errorf("gasWait(%s) Error: 0x%02x != 0x%02x", tt.dur, w, tt.want)
+		}
+	}
+}
+
+func TestMeasurementTime(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the calibration
+			{Addr: 0x76, W: []byte{0xd0}, R: []byte{0x61}},
+			{Addr: 0x76, W: []byte{0x89}, R: Coeff1},
+			{Addr: 0x76, W: []byte{0xe1}, R: Coeff2},
+			{Addr: 0x76, W: []byte{0x00}, R: HeatCoeff},
+			{Addr: 0x76, W: []byte{0x74, 0x4c, 0x75, 0x08, 0x64, 0x65}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	// 2 + 4 + 2 cycles
+	if mt := d.MeasurementTime(); mt != 21*time.Millisecond {
+		t.Errorf("MeasurementTime Error: %s", mt)
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package bme680
+
+// calibration holds the sensor's factory calibration
+type calibration struct {
+	t1             uint16
+	t2             int16
+	t3             int8
+	p1             uint16
+	p2, p4, p5     int16
+	p8, p9         int16
+	p3, p6, p7     int8
+	p10            uint8
+	h1, h2         uint16
+	h3, h4, h5, h7 int8
+	h6             uint8
+	gh1, gh3       int8
+	gh2            int16
+	resHeatRange   uint8
+	resHeatVal     int8
+	rangeSwErr     int8
+}
+
+// newCalibration returns the calibration from the 25 bytes starting at register
+// 0x89, the 16 bytes starting at register 0xE1, and registers 0x00 to 0x04
+func newCalibration(c1, c2, heat []byte) calibration {
+	return calibration{
+		t1:  uint16(c2[8]) | uint16(c2[9])<<8,
+		t2:  int16(uint16(c1[1]) | uint16(c1[2])<<8),
+		t3:  int8(c1[3]),
+		p1:  uint16(c1[5]) | uint16(c1[6])<<8,
+		p2:  int16(uint16(c1[7]) | uint16(c1[8])<<8),
+		p3:  int8(c1[9]),
+		p4:  int16(uint16(c1[11]) | uint16(c1[12])<<8),
+		p5:  int16(uint16(c1[13]) | uint16(c1[14])<<8),
+		p7:  int8(c1[15]),
+		p6:  int8(c1[16]),
+		p8:  int16(uint16(c1[19]) | uint16(c1[20])<<8),
+		p9:  int16(uint16(c1[21]) | uint16(c1[22])<<8),
+		p10: c1[23],
+		// H1 and H2 are 12 bit values, sharing the nibbles of 0xE2
+		h1:  uint16(c2[2])<<4 | uint16(c2[1]&0x0f),
+		h2:  uint16(c2[0])<<4 | uint16(c2[1]>>4),
+		h3:  int8(c2[3]),
+		h4:  int8(c2[4]),
+		h5:  int8(c2[5]),
+		h6:  c2[6],
+		h7:  int8(c2[7]),
+		gh2: int16(uint16(c2[10]) | uint16(c2[11])<<8),
+		gh1: int8(c2[12]),
+		gh3: int8(c2[13]),
+
+		resHeatVal:   int8(heat[0]),
+		resHeatRange: (heat[2] >> 4) & 0x03,
+		rangeSwErr:   int8(heat[4]) >> 4,
+	}
+}
+
+// temperature returns the temperature in 0.01°C and t_fine, which is used to
+// compensate the pressure and humidity
+func (c *calibration) temperature(raw int32) (int32, int32) {
+	var1 := (raw >> 3) - (int32(c.t1) << 1)
+	var2 := (var1 * int32(c.t2)) >> 11
+	var3 := ((var1 >> 1) * (var1 >> 1)) >> 12
+	var3 = (var3 * (int32(c.t3) << 4)) >> 14
+	tFine := var2 + var3
+	return (tFine*5 + 128) >> 8, tFine
+}
+
+// pressure returns the pressure in Pa
+func (c *calibration) pressure(raw, tFine int32) int32 {
+	var1 := (tFine >> 1) - 64000
+	var2 := ((((var1 >> 2) * (var1 >> 2)) >> 11) * int32(c.p6)) >> 2
+	var2 += (var1 * int32(c.p5)) << 1
+	var2 = (var2 >> 2) + (int32(c.p4) << 16)
+	var1 = (((((var1 >> 2) * (var1 >> 2)) >> 13) * (int32(c.p3) << 5)) >> 3) + ((int32(c.p2) * var1) >> 1)
+	var1 >>= 18
+	var1 = ((32768 + var1) * int32(c.p1)) >> 15
+	if var1 == 0 {
+		// Avoid dividing by zero
+		return 0
+	}
+	p := 1048576 - raw
+	p = (p - (var2 >> 12)) * 3125
+	if p >= 1<<30 {
+		p = (p / var1) << 1
+	} else {
+		p = (p << 1) / var1
+	}
+	var1 = (int32(c.p9) * (((p >> 3) * (p >> 3)) >> 13)) >> 12
+	var2 = ((p >> 2) * int32(c.p8)) >> 13
+	var3 := ((p >> 8) * (p >> 8) * (p >> 8) * int32(c.p10)) >> 17
+	return p + ((var1 + var2 + var3 + (int32(c.p7) << 7)) >> 4)
+}
+
+// humidity returns the relative humidity in 0.001%rH
+func (c *calibration) humidity(raw, tFine int32) int32 {
+	t := (tFine*5 + 128) >> 8
+	var1 := (raw - int32(c.h1)*16) - (((t * int32(c.h3)) / 100) >> 1)
+	var2 := (int32(c.h2) * (((t * int32(c.h4)) / 100) + (((t * ((t * int32(c.h5)) / 100)) >> 6) / 100) + (1 << 14))) >> 10
+	var3 := var1 * var2
+	var4 := ((int32(c.h6) << 7) + ((t * int32(c.h7)) / 100)) >> 4
+	var5 := ((var3 >> 14) * (var3 >> 14)) >> 10
+	var6 := (var4 * var5) >> 1
+	h := (((var3 + var6) >> 10) * 1000) >> 12
+	if h < 0 {
+		h = 0
+	} else if h > 100000 {
+		h = 100000
+	}
+	return h
+}
+
+// gasLookup1 and gasLookup2 are the gas resistance range constants from the datasheet
+var (
+	gasLookup1 = [16]int64{2147483647, 2147483647, 2147483647, 2147483647, 2147483647,
+		2126008810, 2147483647, 2130303777, 2147483647, 2147483647, 2143188679, 2136746228,
+		2147483647, 2126008810, 2147483647, 2147483647}
+	gasLookup2 = [16]int64{4096000000, 2048000000, 1024000000, 512000000, 255744255,
+		127110228, 64000000, 32258064, 16016016, 8000000, 4000000, 2000000, 1000000, 500000,
+		250000, 125000}
+)
+
+// gasResistance returns the gas sensor's resistance in Ω
+func (c *calibration) gasResistance(raw uint16, gasRange uint8) uint32 {
+	var1 := ((1340 + 5*int64(c.rangeSwErr)) * gasLookup1[gasRange&0x0f]) >> 16
+	var2 := (int64(raw) << 15) - 16777216 + var1
+	var3 := (gasLookup2[gasRange&0x0f] * var1) >> 9
+	return uint32((var3 + (var2 >> 1)) / var2)
+}
+
+// heaterResistance returns the res_heat register value for the target temperature in
+// °C, at the ambient temperature in °C
+func (c *calibration) heaterResistance(target, ambient int32) uint8 {
+	if target > 400 {
+		target = 400
+	}
+	var1 := ((ambient * int32(c.gh3)) / 1000) * 256
+	var2 := (int32(c.gh1) + 784) * (((((int32(c.gh2) + 154009) * target * 5) / 100) + 3276800) / 10)
+	var3 := var1 + (var2 / 2)
+	var4 := var3 / (int32(c.resHeatRange) + 4)
+	var5 := (131 * int32(c.resHeatVal)) + 65536
+	res := ((var4 / var5) - 250) * 34
+	return uint8((res + 50) / 100)
+}
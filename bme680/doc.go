@@ -0,0 +1,19 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package bme680 controls a Bosch BME680 temperature, humidity, pressure, and gas
+// sensor over I²C.
+//
+// Each forced mode measurement heats the gas sensor's hotplate using one of 10
+// heater profiles, and reads its resistance, which drops in the presence of VOCs.
+//
+// Bosch's BSEC library, which calculates their IAQ index from the gas resistance,
+// is closed source. IAQEstimator is an open alternative, it scores the gas resistance
+// against a baseline learned during a burn-in period, and the humidity against the
+// ideal of 40%rH. It is not calibrated against BSEC and its values will not match.
+//
+// Datasheet
+//
+// https://www.bosch-sensortec.com/media/boschsensortec/downloads/datasheets/bst-bme680-ds001.pdf
+package bme680
@@ -0,0 +1,51 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package bme680_test
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"periph.io/x/periph/conn/i2c/i2creg"
+	"periph.io/x/periph/host"
+
+	"github.com/bcl/air-sensors/bme680"
+)
+
+func Example() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Open a handle to the first available I²C bus:
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer bus.Close()
+
+	d, err := bme680.New(bus)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer d.Halt() //nolint
+
+	// Burn in the baseline using the first 5 minutes of readings
+	iaq := bme680.NewIAQEstimator(100)
+	for {
+		r, err := d.Read()
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%8s %10s %9s %7dΩ", r.Temperature, r.Pressure, r.Humidity, r.GasResistance)
+		if v, ok := iaq.Estimate(r); ok {
+			fmt.Printf(" IAQ %3.0f", v)
+		}
+		fmt.Println()
+		time.Sleep(3 * time.Second)
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package bme680
+
+import (
+	"fmt"
+	"time"
+
+	"periph.io/x/periph/conn/physic"
+)
+
+// MaxHeaterDuration is the longest heater duration that the sensor supports
+const MaxHeaterDuration = 4032 * time.Millisecond
+
+// HeaterProfile sets the temperature of the gas sensor's hotplate, and how long it is
+// heated before the gas resistance is measured
+//
+// A Duration of 0 turns off the heater and skips the gas measurement.
+type HeaterProfile struct {
+	Temperature physic.Temperature // Target temperature, up to 400°C
+	Duration    time.Duration      // Heating time, up to MaxHeaterDuration
+}
+
+// SetHeaterProfile sets one of the 10 heater profiles
+func (d *Dev) SetHeaterProfile(n int, p HeaterProfile) error {
+	if n < 0 || n >= len(d.profiles) {
+		return fmt.Errorf("bme680: Invalid heater profile: %d", n)
+	}
+	if p.Duration < 0 || p.Duration > MaxHeaterDuration {
+		return fmt.Errorf("bme680: Invalid heater duration: %s", p.Duration)
+	}
+	if p.Duration > 0 && (p.Temperature < physic.ZeroCelsius || p.Temperature > physic.ZeroCelsius+400*physic.Celsius) {
+		return fmt.Errorf("bme680: Invalid heater temperature: %s", p.Temperature)
+	}
+	if err := d.i2c.Tx([]byte{regGasWait0 + uint8(n), gasWait(p.Duration)}, nil); err != nil {
+		return fmt.Errorf("bme680: Error while setting heater profile: %w", err)
+	}
+	d.profiles[n] = p
+	return nil
+}
+
+// SelectHeaterProfile selects the heater profile used by Read
+func (d *Dev) SelectHeaterProfile(n int) error {
+	if n < 0 || n >= len(d.profiles) {
+		return fmt.Errorf("bme680: Invalid heater profile: %d", n)
+	}
+	d.profile = n
+	return nil
+}
+
+// gasWait encodes the heater duration for the gas_wait register
+// The lower 6 bits are the time in ms, the upper 2 bits multiply it by 1, 4, 16, or 64.
+func gasWait(dur time.Duration) uint8 {
+	ms := dur.Milliseconds()
+	if ms >= 0xfc0 {
+		return 0xff
+	}
+	var factor uint8
+	for ms > 0x3f {
+		ms /= 4
+		factor++
+	}
+	return uint8(ms) + factor*64
+}
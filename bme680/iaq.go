@@ -0,0 +1,86 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package bme680
+
+import (
+	"periph.io/x/periph/conn/physic"
+)
+
+const (
+	// iaqHumidityIdeal is the relative humidity that scores the full humidity weight
+	iaqHumidityIdeal = 40 * physic.PercentRH
+	// iaqHumidityWeight is the part of the score from the humidity, the rest is the gas
+	iaqHumidityWeight = 25.0
+)
+
+// IAQEstimator estimates an indoor air quality index from 0 (good) to 500 (bad)
+//
+// The gas resistance is scored against a baseline, the mean resistance of the first
+// burnIn valid readings, which is raised whenever a higher (cleaner) resistance is
+// seen. The humidity is scored by its distance from 40%rH. The gas score is 75% of
+// the total, and the humidity score is 25%.
+//
+// The sensor's gas resistance drifts for the first few days of use, and for about
+// 30 minutes after it is powered up. The baseline should be saved with Baseline and
+// restored with SetBaseline instead of burning in again after every restart.
+type IAQEstimator struct {
+	burnIn   int     // Number of readings used to calculate the baseline
+	samples  int     // Number of burn in readings so far
+	sum      float64 // Sum of the burn in readings
+	baseline float64 // Gas resistance baseline in Ω, 0 until the burn in is done
+}
+
+// NewIAQEstimator returns an IAQEstimator that burns in its baseline using the first
+// burnIn valid readings
+func NewIAQEstimator(burnIn int) *IAQEstimator {
+	if burnIn < 1 {
+		burnIn = 1
+	}
+	return &IAQEstimator{burnIn: burnIn}
+}
+
+// Estimate returns the IAQ index for the reading
+//
+// It returns false while the baseline is burning in, and for readings without a
+// valid gas resistance.
+func (e *IAQEstimator) Estimate(r Reading) (float64, bool) {
+	if !r.GasValid || r.GasResistance == 0 {
+		return 0, false
+	}
+	gas := float64(r.GasResistance)
+	if e.baseline == 0 {
+		e.sum += gas
+		e.samples++
+		if e.samples < e.burnIn {
+			return 0, false
+		}
+		e.baseline = e.sum / float64(e.samples)
+	}
+	if gas > e.baseline {
+		e.baseline = gas
+	}
+
+	score := humidityScore(r.Humidity) + gas/e.baseline*(100-iaqHumidityWeight)
+	return (100 - score) * 5, true
+}
+
+// Baseline returns the gas resistance baseline in Ω, or 0 if it is still burning in
+func (e *IAQEstimator) Baseline() uint32 {
+	return uint32(e.baseline)
+}
+
+// SetBaseline sets the gas resistance baseline in Ω, skipping the burn in
+func (e *IAQEstimator) SetBaseline(baseline uint32) {
+	e.baseline = float64(baseline)
+}
+
+// humidityScore returns up to iaqHumidityWeight, decreasing linearly as the humidity
+// moves away from iaqHumidityIdeal to 0%rH or 100%rH
+func humidityScore(h physic.RelativeHumidity) float64 {
+	if h < iaqHumidityIdeal {
+		return iaqHumidityWeight * float64(h) / float64(iaqHumidityIdeal)
+	}
+	return iaqHumidityWeight * float64(100*physic.PercentRH-h) / float64(100*physic.PercentRH-iaqHumidityIdeal)
+}
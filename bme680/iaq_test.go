@@ -0,0 +1,69 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package bme680
+
+import (
+	"testing"
+
+	"periph.io/x/periph/conn/physic"
+)
+
+// iaqReading returns a valid Reading with the gas resistance and humidity
+func iaqReading(gas uint32, rh physic.RelativeHumidity) Reading {
+	var r Reading
+	r.GasResistance = gas
+	r.GasValid = true
+	r.Humidity = rh
+	return r
+}
+
+func TestIAQBurnIn(t *testing.T) {
+	e := NewIAQEstimator(3)
+	for _, gas := range []uint32{100000, 200000} {
+		if _, ok := e.Estimate(iaqReading(gas, 40*physic.PercentRH)); ok {
+			t.Fatal("Burn in Error")
+		}
+	}
+	if _, ok := e.Estimate(Reading{}); ok {
+		t.Fatal("Invalid gas Error")
+	}
+	// The baseline is the mean, and the reading scores 75 of the gas weight
+	iaq, ok := e.Estimate(iaqReading(150000, 40*physic.PercentRH))
+	if !ok {
+		t.Fatal("Estimate Error")
+	}
+	if e.Baseline() != 150000 {
+		t.Errorf("Baseline Error: %d", e.Baseline())
+	}
+	if !within(iaq, 0, 0.001) {
+		t.Errorf("IAQ Error: %f", iaq)
+	}
+}
+
+func TestIAQEstimate(t *testing.T) {
+	e := NewIAQEstimator(1)
+	e.SetBaseline(200000)
+	for _, tt := range []struct {
+		gas  uint32
+		rh   physic.RelativeHumidity
+		want float64
+	}{
+		{200000, 40 * physic.PercentRH, 0},
+		{100000, 40 * physic.PercentRH, 187.5},
+		{200000, 20 * physic.PercentRH, 62.5},
+		{200000, 70 * physic.PercentRH, 62.5},
+		{50000, 100 * physic.PercentRH, 406.25},
+	} {
+		iaq, ok := e.Estimate(iaqReading(tt.gas, tt.rh))
+		if !ok || !within(iaq, tt.want, 0.001) {
+			t.Errorf("Estimate(%d, %s) Error: %f != %f", tt.gas, tt.rh, iaq, tt.want)
+		}
+	}
+
+	// A cleaner reading raises the baseline
+	if _, ok := e.Estimate(iaqReading(250000, 40*physic.PercentRH)); !ok || e.Baseline() != 250000 {
+		t.Errorf("Baseline Error: %d", e.Baseline())
+	}
+}
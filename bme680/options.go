@@ -0,0 +1,102 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package bme680
+
+import (
+	"time"
+
+	"periph.io/x/periph/conn/physic"
+)
+
+const (
+	// DefaultAddr is the I²C address of the BME680 with its SDO pin low
+	DefaultAddr uint16 = 0x76
+
+	// AltAddr is the I²C address of the BME680 with its SDO pin high
+	AltAddr uint16 = 0x77
+)
+
+// Oversampling selects how many samples are averaged for each measurement, more
+// samples reduce the noise but take longer
+type Oversampling uint8
+
+const (
+	// Skip skips the measurement, it is not updated in the Reading
+	Skip Oversampling = iota
+	// O1x makes 1 sample
+	O1x
+	// O2x averages 2 samples
+	O2x
+	// O4x averages 4 samples
+	O4x
+	// O8x averages 8 samples
+	O8x
+	// O16x averages 16 samples
+	O16x
+)
+
+// Filter sets the IIR filter coefficient for the temperature and pressure
+type Filter uint8
+
+const (
+	// FilterOff disables the filter
+	FilterOff Filter = iota
+	// Filter1 uses a coefficient of 1
+	Filter1
+	// Filter3 uses a coefficient of 3
+	Filter3
+	// Filter7 uses a coefficient of 7
+	Filter7
+	// Filter15 uses a coefficient of 15
+	Filter15
+	// Filter31 uses a coefficient of 31
+	Filter31
+	// Filter63 uses a coefficient of 63
+	Filter63
+	// Filter127 uses a coefficient of 127
+	Filter127
+)
+
+// DefaultHeaterProfile heats the hotplate to 320°C for 150ms, Bosch's example profile
+var DefaultHeaterProfile = HeaterProfile{
+	Temperature: physic.ZeroCelsius + 320*physic.Celsius,
+	Duration:    150 * time.Millisecond,
+}
+
+// Option configures the Dev returned by New
+type Option func(*Dev)
+
+// WithAddress sets the I²C address of the sensor, the default is DefaultAddr
+func WithAddress(addr uint16) Option {
+	return func(d *Dev) {
+		d.addr = addr
+	}
+}
+
+// WithOversampling sets the oversampling of the temperature, pressure, and humidity
+// measurements, the default is O2x, O4x, and O2x. The temperature cannot be skipped,
+// it is needed to compensate the others.
+func WithOversampling(temperature, pressure, humidity Oversampling) Option {
+	return func(d *Dev) {
+		d.osrsT = temperature
+		d.osrsP = pressure
+		d.osrsH = humidity
+	}
+}
+
+// WithFilter sets the IIR filter coefficient, the default is Filter3
+func WithFilter(f Filter) Option {
+	return func(d *Dev) {
+		d.filter = f
+	}
+}
+
+// WithHeaterProfile sets heater profile 0, which is used by default, the default is
+// DefaultHeaterProfile.
+func WithHeaterProfile(p HeaterProfile) Option {
+	return func(d *Dev) {
+		d.profiles[0] = p
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package bmp3xx
+
+import (
+	"math"
+
+	"periph.io/x/periph/conn/physic"
+)
+
+// SeaLevelPressure is the standard atmosphere's pressure at sea level
+const SeaLevelPressure = 101325 * physic.Pascal
+
+// Altitude returns the altitude for the pressure, using the barometric formula with
+// the pressure at sea level, which is SeaLevelPressure or the local pressure from a
+// weather report.
+func Altitude(p, seaLevel physic.Pressure) physic.Distance {
+	m := 44330 * (1 - math.Pow(float64(p)/float64(seaLevel), 1/5.255))
+	return physic.Distance(math.Round(m*1000)) * physic.MilliMetre
+}
+
+// SeaLevel returns the pressure at sea level for the pressure measured at a known
+// altitude, which can be passed to Altitude.
+func SeaLevel(p physic.Pressure, altitude physic.Distance) physic.Pressure {
+	m := float64(altitude) / float64(physic.Metre)
+	pa := float64(p) / float64(physic.Pascal) / math.Pow(1-m/44330, 5.255)
+	return physic.Pressure(math.Round(pa*1000)) * physic.MilliPascal
+}
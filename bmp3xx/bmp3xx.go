@@ -0,0 +1,179 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package bmp3xx
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"periph.io/x/periph/conn"
+	"periph.io/x/periph/conn/i2c"
+	"periph.io/x/periph/conn/physic"
+)
+
+// Values of the chip ID register
+const (
+	ChipIDBMP388 uint8 = 0x50
+	ChipIDBMP390 uint8 = 0x60
+)
+
+// BMP3xx registers from the datasheet
+const (
+	regChipID  uint8 = 0x00 // Chip ID
+	regStatus  uint8 = 0x03 // Command ready and data ready status
+	regData    uint8 = 0x04 // Pressure and temperature readings
+	regPwrCtrl uint8 = 0x1b // Pressure and temperature enable, and the mode
+	regOSR     uint8 = 0x1c // Temperature and pressure oversampling
+	regConfig  uint8 = 0x1f // Filter
+	regCalib   uint8 = 0x31 // Temperature and pressure calibration, 21 bytes
+	regCmd     uint8 = 0x7e // Commands
+)
+
+const (
+	cmdSoftReset  uint8 = 0xb6 // Resets the sensor when written to regCmd
+	statusDrdy    uint8 = 0x60 // The pressure and temperature readings are ready
+	pressEnable   uint8 = 0x01 // Measure the pressure
+	tempEnable    uint8 = 0x02 // Measure the temperature
+	modeForced    uint8 = 0x10 // Make one measurement, and return to sleep
+	maxDrdyChecks       = 10   // Number of times to check the status after a measurement
+)
+
+// Dev holds the connection to the BMP3xx and its calibration
+type Dev struct {
+	i2c    conn.Conn    // i2c device handle for the bmp3xx
+	addr   uint16       // I²C address of the bmp3xx
+	chipID uint8        // ChipIDBMP388 or ChipIDBMP390
+	osrT   Oversampling // Temperature oversampling
+	osrP   Oversampling // Pressure oversampling
+	filter Filter       // IIR filter coefficient
+	calib  calibration  // Factory calibration
+}
+
+var _ conn.Resource = &Dev{}
+
+// New returns a BMP3xx device struct for communicating with the device
+//
+// It checks the chip ID, reads the calibration, and configures the oversampling and
+// filter. The sensor is left in sleep mode.
+func New(i i2c.Bus, opts ...Option) (*Dev, error) {
+	d := &Dev{
+		addr: DefaultAddr,
+		osrT: O1x,
+		osrP: O4x,
+	}
+	for _, o := range opts {
+		o(d)
+	}
+	if d.osrT > O32x || d.osrP > O32x {
+		return nil, fmt.Errorf("bmp3xx: Invalid oversampling: %d %d", d.osrT, d.osrP)
+	}
+	if d.filter > Filter127 {
+		return nil, fmt.Errorf("bmp3xx: Invalid filter: %d", d.filter)
+	}
+	d.i2c = &i2c.Dev{Bus: i, Addr: d.addr}
+
+	var id [1]byte
+	if err := d.i2c.Tx([]byte{regChipID}, id[:]); err != nil {
+		return nil, fmt.Errorf("bmp3xx: Error while reading chip ID: %w", err)
+	}
+	if id[0] != ChipIDBMP388 && id[0] != ChipIDBMP390 {
+		return nil, fmt.Errorf("bmp3xx: BMP388 or BMP390 not found, chip ID is 0x%02X", id[0])
+	}
+	d.chipID = id[0]
+
+	var nvm [21]byte
+	if err := d.i2c.Tx([]byte{regCalib}, nvm[:]); err != nil {
+		return nil, fmt.Errorf("bmp3xx: Error while reading calibration: %w", err)
+	}
+	d.calib = newCalibration(nvm[:])
+
+	if err := d.configure(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// String implements conn.Resource.
+func (d *Dev) String() string {
+	return fmt.Sprintf("bmp3xx{%s}", d.i2c)
+}
+
+// Halt implements conn.Resource.
+//
+// The BMP3xx returns to sleep after each measurement, so there is nothing to halt.
+func (d *Dev) Halt() error {
+	return nil
+}
+
+// ChipID returns the sensor's chip ID, ChipIDBMP388 or ChipIDBMP390
+func (d *Dev) ChipID() uint8 {
+	return d.chipID
+}
+
+// Reset resets the sensor, and restores the oversampling and filter settings
+func (d *Dev) Reset() error {
+	if err := d.i2c.Tx([]byte{regCmd, cmdSoftReset}, nil); err != nil {
+		return fmt.Errorf("bmp3xx: Error while resetting: %w", err)
+	}
+	// Requires a 2ms delay after the reset
+	time.Sleep(2 * time.Millisecond)
+	return d.configure()
+}
+
+// Sense makes a forced mode measurement, and returns the temperature and pressure
+// The humidity is not measured.
+func (d *Dev) Sense(env *physic.Env) error {
+	if err := d.i2c.Tx([]byte{regPwrCtrl, pressEnable | tempEnable | modeForced}, nil); err != nil {
+		return fmt.Errorf("bmp3xx: Error while starting measurement: %w", err)
+	}
+	time.Sleep(d.MeasurementTime())
+	for i := 0; ; i++ {
+		var status [1]byte
+		if err := d.i2c.Tx([]byte{regStatus}, status[:]); err != nil {
+			return fmt.Errorf("bmp3xx: Error while reading status: %w", err)
+		}
+		if status[0]&statusDrdy == statusDrdy {
+			break
+		}
+		if i == maxDrdyChecks {
+			return fmt.Errorf("bmp3xx: Timeout waiting for measurement")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	var data [6]byte
+	if err := d.i2c.Tx([]byte{regData}, data[:]); err != nil {
+		return fmt.Errorf("bmp3xx: Error while reading measurement: %w", err)
+	}
+	rawP := uint32(data[0]) | uint32(data[1])<<8 | uint32(data[2])<<16
+	rawT := uint32(data[3]) | uint32(data[4])<<8 | uint32(data[5])<<16
+
+	t := d.calib.temperature(rawT)
+	env.Temperature = physic.ZeroCelsius + physic.Temperature(math.Round(t*1000))*physic.MilliCelsius
+	p := d.calib.pressure(rawP, t)
+	env.Pressure = physic.Pressure(math.Round(p*1000)) * physic.MilliPascal
+	return nil
+}
+
+// MeasurementTime returns the time a measurement takes, using the datasheet's formula
+// for the oversampling settings
+func (d *Dev) MeasurementTime() time.Duration {
+	// 234µs + (392µs + 2^osr_p * 2020µs) + (163µs + 2^osr_t * 2020µs)
+	t := 234 + 392 + (1<<d.osrP)*2020 + 163 + (1<<d.osrT)*2020
+	return time.Duration(t) * time.Microsecond
+}
+
+// configure writes the oversampling and filter settings
+func (d *Dev) configure() error {
+	w := []byte{
+		regOSR, uint8(d.osrT)<<3 | uint8(d.osrP),
+		regConfig, uint8(d.filter) << 1,
+	}
+	if err := d.i2c.Tx(w, nil); err != nil {
+		return fmt.Errorf("bmp3xx: Error while writing configuration: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package bmp3xx
+
+import (
+	"testing"
+	"time"
+
+	"periph.io/x/periph/conn/i2c/i2ctest"
+	"periph.io/x/periph/conn/physic"
+)
+
+var (
+	// Calibration with typical values, and a measurement made with it
+	CalibrationData = []byte{0xbc, 0x6b, 0x76, 0x4b, 0xf9, 0x8e, 0xf3, 0xd3, 0xeb, 0x23, 0x01,
+		0x38, 0x61, 0x8a, 0x76, 0x03, 0xfa, 0x17, 0x3f, 0x04, 0xc4}
+	MeasurementData = []byte{0x00, 0x00, 0x57, 0x00, 0x80, 0x81}
+)
+
+func TestNew(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the calibration
+			{Addr: 0x76, W: []byte{0x00}, R: []byte{0x60}},
+			{Addr: 0x76, W: []byte{0x31}, R: CalibrationData},
+			{Addr: 0x76, W: []byte{0x1c, 0x02, 0x1f, 0x00}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if d.String() != "bmp3xx{playback(118)}" {
+		t.Fatalf("String Error: %s", d.String())
+	}
+	if d.ChipID() != ChipIDBMP390 {
+		t.Fatalf("ChipID Error: 0x%02x", d.ChipID())
+	}
+	if d.calib.t1 != 27580*256 || d.calib.p5 != 24888*8 {
+		t.Fatalf("Calibration Error: %#v", d.calib)
+	}
+}
+
+func TestWrongChipID(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x76, W: []byte{0x00}, R: []byte{0x58}},
+		},
+	}
+	if _, err := New(&bus); err == nil {
+		t.Fatal("Wrong chip ID Error")
+	}
+}
+
+func TestBadOptions(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops:       []i2ctest.IO{},
+		DontPanic: true,
+	}
+	if _, err := New(&bus, WithOversampling(O1x, O32x+1)); err == nil {
+		t.Error("Invalid oversampling Error")
+	}
+	if _, err := New(&bus, WithFilter(Filter127+1)); err == nil {
+		t.Error("Invalid filter Error")
+	}
+}
+
+func TestOptions(t *testing.T) {
+	bus := &i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x77, W: []byte{0x00}, R: []byte{0x50}},
+			{Addr: 0x77, W: []byte{0x31}, R: CalibrationData},
+			{Addr: 0x77, W: []byte{0x1c, 0x0c, 0x1f, 0x04}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(bus, WithAddress(AltAddr), WithOversampling(O2x, O16x), WithFilter(Filter3))
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if d.ChipID() != ChipIDBMP388 {
+		t.Errorf("ChipID Error: 0x%02x", d.ChipID())
+	}
+	if mt := d.MeasurementTime(); mt != 37149*time.Microsecond {
+		t.Errorf("MeasurementTime Error: %s", mt)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSense(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the calibration
+			{Addr: 0x76, W: []byte{0x00}, R: []byte{0x60}},
+			{Addr: 0x76, W: []byte{0x31}, R: CalibrationData},
+			{Addr: 0x76, W: []byte{0x1c, 0x02, 0x1f, 0x00}},
+			{Addr: 0x76, W: []byte{0x1b, 0x13}},
+			{Addr: 0x76, W: []byte{0x03}, R: []byte{0x10}},
+			{Addr: 0x76, W: []byte{0x03}, R: []byte{0x70}},
+			{Addr: 0x76, W: []byte{0x04}, R: MeasurementData},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	var env physic.Env
+	if err := d.Sense(&env); err != nil {
+		t.Fatalf("Sense Error: %s", err)
+	}
+	// Expected values are from the datasheet's formulas
+	if env.Temperature != physic.ZeroCelsius+25613*physic.MilliCelsius {
+		t.Errorf("Temperature Error: %s", env.Temperature)
+	}
+	if env.Pressure != 100586540*physic.MilliPascal {
+		t.Errorf("Pressure Error: %s", env.Pressure)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReset(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the calibration
+			{Addr: 0x76, W: []byte{0x00}, R: []byte{0x60}},
+			{Addr: 0x76, W: []byte{0x31}, R: CalibrationData},
+			{Addr: 0x76, W: []byte{0x1c, 0x02, 0x1f, 0x00}},
+			{Addr: 0x76, W: []byte{0x7e, 0xb6}},
+			{Addr: 0x76, W: []byte{0x1c, 0x02, 0x1f, 0x00}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if err := d.Reset(); err != nil {
+		t.Fatalf("Reset Error: %s", err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAltitude(t *testing.T) {
+	if a := Altitude(SeaLevelPressure, SeaLevelPressure); a != 0 {
+		t.Errorf("Sea level Altitude Error: %s", a)
+	}
+	if a := Altitude(100586540*physic.MilliPascal, SeaLevelPressure); a != 61662*physic.MilliMetre {
+		t.Errorf("Altitude Error: %s", a)
+	}
+	p := SeaLevel(100586540*physic.MilliPascal, 100*physic.Metre)
+	if p != 101787379*physic.MilliPascal {
+		t.Errorf("SeaLevel Error: %s", p)
+	}
+	if a := Altitude(100586540*physic.MilliPascal, p); a != 100*physic.Metre {
+		t.Errorf("Altitude Error: %s", a)
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package bmp3xx
+
+import (
+	"math"
+)
+
+// calibration holds the sensor's factory calibration, scaled by the datasheet's
+// factors for the floating point formulas
+type calibration struct {
+	t1, t2, t3                                   float64
+	p1, p2, p3, p4, p5, p6, p7, p8, p9, p10, p11 float64
+}
+
+// newCalibration returns the calibration from the 21 bytes starting at register 0x31
+func newCalibration(nvm []byte) calibration {
+	u16 := func(i int) float64 { return float64(uint16(nvm[i]) | uint16(nvm[i+1])<<8) }
+	s16 := func(i int) float64 { return float64(int16(uint16(nvm[i]) | uint16(nvm[i+1])<<8)) }
+	s8 := func(i int) float64 { return float64(int8(nvm[i])) }
+
+	return calibration{
+		t1:  u16(0) * math.Pow(2, 8),
+		t2:  u16(2) / math.Pow(2, 30),
+		t3:  s8(4) / math.Pow(2, 48),
+		p1:  (s16(5) - math.Pow(2, 14)) / math.Pow(2, 20),
+		p2:  (s16(7) - math.Pow(2, 14)) / math.Pow(2, 29),
+		p3:  s8(9) / math.Pow(2, 32),
+		p4:  s8(10) / math.Pow(2, 37),
+		p5:  u16(11) * math.Pow(2, 3),
+		p6:  u16(13) / math.Pow(2, 6),
+		p7:  s8(15) / math.Pow(2, 8),
+		p8:  s8(16) / math.Pow(2, 15),
+		p9:  s16(17) / math.Pow(2, 48),
+		p10: s8(19) / math.Pow(2, 48),
+		p11: s8(20) / math.Pow(2, 65),
+	}
+}
+
+// temperature returns the temperature in °C
+func (c *calibration) temperature(raw uint32) float64 {
+	d := float64(raw) - c.t1
+	return d*c.t2 + d*d*c.t3
+}
+
+// pressure returns the pressure in Pa, using the temperature in °C
+func (c *calibration) pressure(raw uint32, t float64) float64 {
+	p := float64(raw)
+	out1 := c.p5 + c.p6*t + c.p7*t*t + c.p8*t*t*t
+	out2 := p * (c.p1 + c.p2*t + c.p3*t*t + c.p4*t*t*t)
+	out3 := p*p*(c.p9+c.p10*t) + p*p*p*c.p11
+	return out1 + out2 + out3
+}
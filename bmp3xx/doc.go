@@ -0,0 +1,18 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package bmp3xx controls a Bosch BMP388 or BMP390 barometric pressure sensor over
+// I²C.
+//
+// The raw readings are compensated with the sensor's factory calibration using the
+// floating point formulas from the datasheet. Sense makes a forced mode measurement
+// of the temperature and pressure, which can be passed to the CO2 sensors for their
+// pressure compensation, or converted to an altitude with Altitude.
+//
+// Datasheet
+//
+// https://www.bosch-sensortec.com/media/boschsensortec/downloads/datasheets/bst-bmp390-ds002.pdf
+//
+// https://www.bosch-sensortec.com/media/boschsensortec/downloads/datasheets/bst-bmp388-ds001.pdf
+package bmp3xx
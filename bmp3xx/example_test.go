@@ -0,0 +1,52 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package bmp3xx_test
+
+import (
+	"fmt"
+	"log"
+
+	"periph.io/x/periph/conn/i2c/i2creg"
+	"periph.io/x/periph/conn/physic"
+	"periph.io/x/periph/host"
+
+	"github.com/bcl/air-sensors/bmp3xx"
+	"github.com/bcl/air-sensors/scd4x"
+)
+
+func Example() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Open a handle to the first available I²C bus:
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer bus.Close()
+
+	d, err := bmp3xx.New(bus, bmp3xx.WithOversampling(bmp3xx.O2x, bmp3xx.O16x))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer d.Halt() //nolint
+
+	var env physic.Env
+	if err := d.Sense(&env); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%8s %10s %s\n", env.Temperature, env.Pressure, bmp3xx.Altitude(env.Pressure, bmp3xx.SeaLevelPressure))
+
+	// Compensate the SCD4x's CO2 readings for the pressure
+	co2, err := scd4x.New(bus)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := co2.SetAmbientPressure(env.Pressure); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package bmp3xx
+
+const (
+	// DefaultAddr is the I²C address of the BMP3xx with its SDO pin low
+	DefaultAddr uint16 = 0x76
+
+	// AltAddr is the I²C address of the BMP3xx with its SDO pin high
+	AltAddr uint16 = 0x77
+)
+
+// Oversampling selects how many samples are averaged for each measurement, more
+// samples reduce the noise but take longer
+type Oversampling uint8
+
+const (
+	// O1x makes 1 sample
+	O1x Oversampling = iota
+	// O2x averages 2 samples
+	O2x
+	// O4x averages 4 samples
+	O4x
+	// O8x averages 8 samples
+	O8x
+	// O16x averages 16 samples
+	O16x
+	// O32x averages 32 samples
+	O32x
+)
+
+// Filter sets the IIR filter coefficient for the temperature and pressure
+type Filter uint8
+
+const (
+	// FilterOff disables the filter
+	FilterOff Filter = iota
+	// Filter1 uses a coefficient of 1
+	Filter1
+	// Filter3 uses a coefficient of 3
+	Filter3
+	// Filter7 uses a coefficient of 7
+	Filter7
+	// Filter15 uses a coefficient of 15
+	Filter15
+	// Filter31 uses a coefficient of 31
+	Filter31
+	// Filter63 uses a coefficient of 63
+	Filter63
+	// Filter127 uses a coefficient of 127
+	Filter127
+)
+
+// Option configures the Dev returned by New
+type Option func(*Dev)
+
+// WithAddress sets the I²C address of the sensor, the default is DefaultAddr
+func WithAddress(addr uint16) Option {
+	return func(d *Dev) {
+		d.addr = addr
+	}
+}
+
+// WithOversampling sets the oversampling of the temperature and pressure
+// measurements, the default is the datasheet's standard resolution of O1x and O4x.
+func WithOversampling(temperature, pressure Oversampling) Option {
+	return func(d *Dev) {
+		d.osrT = temperature
+		d.osrP = pressure
+	}
+}
+
+// WithFilter sets the IIR filter coefficient, the default is FilterOff
+func WithFilter(f Filter) Option {
+	return func(d *Dev) {
+		d.filter = f
+	}
+}
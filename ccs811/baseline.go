@@ -0,0 +1,120 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ccs811
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/bcl/air-sensors/internal/atomicfile"
+)
+
+// MaxBaselineAge is how long a saved baseline is valid for
+// The baseline drifts as the sensor ages, one that hasn't been saved for a week is not
+// restored and the sensor starts over with its own baseline instead.
+const MaxBaselineAge = 7 * 24 * time.Hour
+
+// Baseline holds the sensor's baseline and when it was read
+type Baseline struct {
+	Data      [2]byte   // Baseline register, as returned by ReadBaseline
+	Timestamp time.Time // When the baseline was read from the sensor
+}
+
+// Stale returns true if the baseline is too old to be restored, or if it is
+// missing the timestamp.
+func (b Baseline) Stale(now time.Time) bool {
+	return b.Timestamp.IsZero() || now.Sub(b.Timestamp) > MaxBaselineAge
+}
+
+// BaselineStore is used to persist the sensor's baseline between restarts
+type BaselineStore interface {
+	// Load returns the saved baseline, or nil if no baseline has been saved
+	Load() (*Baseline, error)
+	// Save stores the baseline
+	Save(baseline Baseline) error
+}
+
+// FileStore stores the baseline in a file
+//
+// The file holds the 2 bytes of baseline data followed by the timestamp as 64 bit
+// big endian Unix seconds. Files with only the baseline data use the file's
+// modification time as the timestamp.
+type FileStore struct {
+	Path string // Path and filename for storing the baseline
+}
+
+// NewFileStore returns a BaselineStore that stores the baseline in a file
+func NewFileStore(path string) *FileStore {
+	return &FileStore{Path: path}
+}
+
+// Load reads the baseline from the file, a missing file is not an error
+func (f *FileStore) Load() (*Baseline, error) {
+	data, err := ioutil.ReadFile(f.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var b Baseline
+	switch len(data) {
+	case 2:
+		fi, err := os.Stat(f.Path)
+		if err != nil {
+			return nil, err
+		}
+		b.Timestamp = fi.ModTime()
+	case 10:
+		b.Timestamp = time.Unix(int64(binary.BigEndian.Uint64(data[2:10])), 0)
+	default:
+		return nil, fmt.Errorf("ccs811: Baseline file %s is the wrong size: %d", f.Path, len(data))
+	}
+	copy(b.Data[:], data[0:2])
+	return &b, nil
+}
+
+// Save writes the baseline to the file, replacing it atomically like the SGP30's
+// FileStore does
+func (f *FileStore) Save(baseline Baseline) error {
+	data := make([]byte, 10)
+	copy(data[0:2], baseline.Data[:])
+	binary.BigEndian.PutUint64(data[2:10], uint64(baseline.Timestamp.Unix()))
+	return atomicfile.WriteFile(f.Path, data, 0644)
+}
+
+// MemoryStore stores the baseline in memory
+type MemoryStore struct {
+	mu       sync.Mutex
+	baseline *Baseline
+}
+
+// NewMemoryStore returns a BaselineStore that keeps the baseline in memory
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// Load returns a copy of the baseline
+func (m *MemoryStore) Load() (*Baseline, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.baseline == nil {
+		return nil, nil
+	}
+	b := *m.baseline
+	return &b, nil
+}
+
+// Save stores a copy of the baseline
+func (m *MemoryStore) Save(baseline Baseline) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.baseline = &baseline
+	return nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ccs811
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ccs811")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	f := NewFileStore(filepath.Join(dir, "baseline"))
+	if b, err := f.Load(); err != nil || b != nil {
+		t.Fatalf("Missing file Error: %v %s", b, err)
+	}
+	want := Baseline{Data: [2]byte{0x84, 0xb1}, Timestamp: time.Unix(1606818600, 0)}
+	if err := f.Save(want); err != nil {
+		t.Fatalf("Save Error: %s", err)
+	}
+	b, err := f.Load()
+	if err != nil {
+		t.Fatalf("Load Error: %s", err)
+	}
+	if b.Data != want.Data || !b.Timestamp.Equal(want.Timestamp) {
+		t.Errorf("Load Error: %#v", b)
+	}
+
+	// Only the baseline data uses the modification time
+	if err := ioutil.WriteFile(f.Path, want.Data[:], 0644); err != nil {
+		t.Fatal(err)
+	}
+	if b, err = f.Load(); err != nil || b.Data != want.Data || time.Since(b.Timestamp) > time.Minute {
+		t.Errorf("Load Error: %#v %s", b, err)
+	}
+
+	if err := ioutil.WriteFile(f.Path, []byte{0x01}, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = f.Load(); err == nil {
+		t.Error("Wrong size Error")
+	}
+}
@@ -0,0 +1,346 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ccs811
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"periph.io/x/periph/conn"
+	"periph.io/x/periph/conn/gpio"
+	"periph.io/x/periph/conn/i2c"
+	"periph.io/x/periph/conn/physic"
+)
+
+// HardwareID is the value of the CCS811's HW_ID register
+const HardwareID uint8 = 0x81
+
+// CCS811 registers from the datasheet
+const (
+	regStatus    uint8 = 0x00 // Status
+	regMeasMode  uint8 = 0x01 // Drive mode and interrupts
+	regAlgResult uint8 = 0x02 // eCO2, TVOC, status, error ID, and raw data
+	regEnvData   uint8 = 0x05 // Humidity and temperature compensation
+	regBaseline  uint8 = 0x11 // Baseline, 2 bytes
+	regHWID      uint8 = 0x20 // Hardware ID
+	regErrorID   uint8 = 0xe0 // Error ID
+	regAppStart  uint8 = 0xf4 // Starts the application firmware
+	regSWReset   uint8 = 0xff // Write resetSequence to reset the sensor
+)
+
+// Status register bits
+const (
+	statusFWMode    uint8 = 0x80 // The application firmware is running
+	statusAppValid  uint8 = 0x10 // A valid application firmware is loaded
+	statusDataReady uint8 = 0x08 // A new reading is ready
+	statusError     uint8 = 0x01 // There is an error in regErrorID
+)
+
+// resetSequence resets the sensor when written to regSWReset
+var resetSequence = []byte{0x11, 0xe5, 0x72, 0x8a}
+
+// errorNames are the names of the regErrorID bits
+var errorNames = []string{
+	"WRITE_REG_INVALID",
+	"READ_REG_INVALID",
+	"MEASMODE_INVALID",
+	"MAX_RESISTANCE",
+	"HEATER_FAULT",
+	"HEATER_SUPPLY",
+}
+
+// ErrNotReady is returned by ReadAirQuality when there is no new reading
+var ErrNotReady = errors.New("ccs811: Reading is not ready")
+
+// Reading holds the air quality readings from the CCS811
+type Reading struct {
+	ECO2      uint16    `json:"eco2"`      // CO2 equivalent in ppm
+	TVOC      uint16    `json:"tvoc"`      // TVOC in ppb
+	Current   uint8     `json:"current"`   // Current through the sensor in µA
+	Raw       uint16    `json:"raw"`       // Voltage across the sensor, 10 bit ADC of 1.65V
+	Timestamp time.Time `json:"timestamp"` // When the reading was made
+}
+
+// Dev holds the connection to the CCS811, and its baseline store
+type Dev struct {
+	i2c          conn.Conn     // i2c device handle for the ccs811
+	addr         uint16        // I²C address of the ccs811
+	mode         DriveMode     // Drive mode set by New
+	wakePin      gpio.PinOut   // Optional nWAKE pin, low wakes the I²C interface
+	store        BaselineStore // Storage for the baseline
+	saveInterval time.Duration // How often to save the baseline
+	started      time.Time     // When the measurements were started
+	lastSave     time.Time     // Last time the baseline was saved
+	restore      *Baseline     // Baseline to restore after WarmupTime
+}
+
+var _ conn.Resource = &Dev{}
+
+// New returns a CCS811 device struct for communicating with the device
+//
+// It checks the hardware ID, starts the application firmware, and sets the drive mode.
+// If a baseline store is passed with WithBaselineFile or WithBaselineStore the baseline
+// is loaded from it, restored by ReadAirQuality after WarmupTime if it is not older than
+// MaxBaselineAge, and saved every save interval.
+func New(i i2c.Bus, opts ...Option) (*Dev, error) {
+	d := &Dev{
+		addr:         DefaultAddr,
+		mode:         Drive1s,
+		saveInterval: DefaultSaveInterval,
+	}
+	for _, o := range opts {
+		o(d)
+	}
+	if d.mode > Drive250ms {
+		return nil, fmt.Errorf("ccs811: Invalid drive mode: %d", d.mode)
+	}
+	d.i2c = &i2c.Dev{Bus: i, Addr: d.addr}
+	if d.wakePin != nil {
+		if err := d.wakePin.Out(gpio.High); err != nil {
+			return nil, fmt.Errorf("ccs811: Error while setting the nWAKE pin: %w", err)
+		}
+	}
+
+	var id [1]byte
+	if err := d.tx([]byte{regHWID}, id[:]); err != nil {
+		return nil, fmt.Errorf("ccs811: Error while reading hardware ID: %w", err)
+	}
+	if id[0] != HardwareID {
+		return nil, fmt.Errorf("ccs811: CCS811 not found, hardware ID is 0x%02X", id[0])
+	}
+	if err := d.startApp(); err != nil {
+		return nil, err
+	}
+	if err := d.SetDriveMode(d.mode); err != nil {
+		return nil, err
+	}
+
+	if d.store != nil {
+		baseline, err := d.store.Load()
+		if err != nil {
+			return nil, fmt.Errorf("ccs811: Error while loading baseline: %w", err)
+		}
+		// Don't seed the sensor with a baseline that is too old
+		if baseline != nil && !baseline.Stale(time.Now()) {
+			d.restore = baseline
+		}
+		d.lastSave = time.Now()
+	}
+	return d, nil
+}
+
+// String implements conn.Resource.
+func (d *Dev) String() string {
+	return fmt.Sprintf("ccs811{%s}", d.i2c)
+}
+
+// Halt implements conn.Resource.
+//
+// It stops the measurements by setting the drive mode to DriveIdle.
+func (d *Dev) Halt() error {
+	return d.SetDriveMode(DriveIdle)
+}
+
+// Reset resets the sensor, and starts the application firmware and the drive mode
+// set by New
+func (d *Dev) Reset() error {
+	if err := d.tx(append([]byte{regSWReset}, resetSequence...), nil); err != nil {
+		return fmt.Errorf("ccs811: Error while resetting: %w", err)
+	}
+	// Requires a 2ms delay before the boot mode accepts commands
+	time.Sleep(2 * time.Millisecond)
+	if err := d.startApp(); err != nil {
+		return err
+	}
+	return d.SetDriveMode(d.mode)
+}
+
+// SetDriveMode sets how often the sensor measures, DriveIdle stops the measurements
+//
+// The datasheet recommends running the sensor in the new mode for 10 minutes before
+// using the readings when changing to a faster mode.
+func (d *Dev) SetDriveMode(mode DriveMode) error {
+	if mode > Drive250ms {
+		return fmt.Errorf("ccs811: Invalid drive mode: %d", mode)
+	}
+	if err := d.tx([]byte{regMeasMode, uint8(mode) << 4}, nil); err != nil {
+		return fmt.Errorf("ccs811: Error while setting drive mode: %w", err)
+	}
+	if mode != DriveIdle && d.started.IsZero() {
+		d.started = time.Now()
+	} else if mode == DriveIdle {
+		d.started = time.Time{}
+	}
+	return nil
+}
+
+// DataReady returns true if a new reading is ready
+func (d *Dev) DataReady() (bool, error) {
+	status, err := d.status()
+	if err != nil {
+		return false, err
+	}
+	return status&statusDataReady != 0, nil
+}
+
+// ReadAirQuality returns the eCO2 and TVOC readings, or ErrNotReady if there is no
+// new reading since the last one
+//
+// If a baseline was loaded from the baseline store it is restored once the sensor
+// has been measuring for WarmupTime, and the baseline is saved to the store every
+// save interval. It is not saved while waiting to restore it, that would overwrite the
+// saved baseline with the one the sensor started over with.
+func (d *Dev) ReadAirQuality() (Reading, error) {
+	var data [8]byte
+	if err := d.tx([]byte{regAlgResult}, data[:]); err != nil {
+		return Reading{}, fmt.Errorf("ccs811: Error while reading air quality: %w", err)
+	}
+	if data[4]&statusError != 0 {
+		return Reading{}, d.sensorError(data[5])
+	}
+	if data[4]&statusDataReady == 0 {
+		return Reading{}, ErrNotReady
+	}
+	r := Reading{
+		ECO2:      uint16(data[0])<<8 | uint16(data[1]),
+		TVOC:      uint16(data[2])<<8 | uint16(data[3]),
+		Current:   data[6] >> 2,
+		Raw:       uint16(data[6]&0x03)<<8 | uint16(data[7]),
+		Timestamp: time.Now(),
+	}
+
+	if d.restore != nil && !d.started.IsZero() && time.Since(d.started) >= WarmupTime {
+		if err := d.SetBaseline(d.restore.Data); err != nil {
+			return Reading{}, err
+		}
+		d.restore = nil
+	}
+	if d.store != nil && d.restore == nil && time.Since(d.lastSave) >= d.saveInterval {
+		d.lastSave = time.Now()
+		baseline, err := d.ReadBaseline()
+		if err != nil {
+			return Reading{}, err
+		}
+		if err := d.store.Save(Baseline{Data: baseline, Timestamp: d.lastSave}); err != nil {
+			return Reading{}, fmt.Errorf("ccs811: Error while saving baseline: %w", err)
+		}
+	}
+	return r, nil
+}
+
+// ReadBaseline returns the sensor's baseline, it should be saved after the sensor
+// has been running for a day, and restored with SetBaseline after a restart.
+func (d *Dev) ReadBaseline() ([2]byte, error) {
+	var data [2]byte
+	if err := d.tx([]byte{regBaseline}, data[:]); err != nil {
+		return [2]byte{}, fmt.Errorf("ccs811: Error while reading baseline: %w", err)
+	}
+	return data, nil
+}
+
+// SetBaseline restores the baseline returned by ReadBaseline
+// The application note recommends restoring it after the sensor has been running for
+// WarmupTime.
+func (d *Dev) SetBaseline(baseline [2]byte) error {
+	if err := d.tx([]byte{regBaseline, baseline[0], baseline[1]}, nil); err != nil {
+		return fmt.Errorf("ccs811: Error while setting baseline: %w", err)
+	}
+	return nil
+}
+
+// CompensateFromEnv sets the temperature and humidity compensation from the readings
+// of another sensor, eg. one of the periph.io environmental sensors.
+func (d *Dev) CompensateFromEnv(env physic.Env) error {
+	// Both are in units of 1/512, the temperature has an offset of 25°C
+	rh := math.Round(float64(env.Humidity) / float64(physic.PercentRH) * 512)
+	t := math.Round((env.Temperature.Celsius() + 25) * 512)
+	h := uint16(math.Max(0, math.Min(rh, 100*512)))
+	c := uint16(math.Max(0, math.Min(t, math.MaxUint16)))
+	if err := d.tx([]byte{regEnvData, uint8(h >> 8), uint8(h), uint8(c >> 8), uint8(c)}, nil); err != nil {
+		return fmt.Errorf("ccs811: Error while setting environment data: %w", err)
+	}
+	return nil
+}
+
+// startApp starts the application firmware, if it isn't already running
+func (d *Dev) startApp() error {
+	status, err := d.status()
+	if err != nil {
+		return err
+	}
+	if status&statusFWMode != 0 {
+		return nil
+	}
+	if status&statusAppValid == 0 {
+		return fmt.Errorf("ccs811: No valid application firmware")
+	}
+	if err := d.tx([]byte{regAppStart}, nil); err != nil {
+		return fmt.Errorf("ccs811: Error while starting application: %w", err)
+	}
+	// Requires a 1ms delay before the application accepts commands
+	time.Sleep(time.Millisecond)
+	if status, err = d.status(); err != nil {
+		return err
+	}
+	if status&statusError != 0 {
+		return d.readError()
+	}
+	if status&statusFWMode == 0 {
+		return fmt.Errorf("ccs811: Application failed to start, status is 0x%02X", status)
+	}
+	return nil
+}
+
+// status returns the status register
+func (d *Dev) status() (uint8, error) {
+	var status [1]byte
+	if err := d.tx([]byte{regStatus}, status[:]); err != nil {
+		return 0, fmt.Errorf("ccs811: Error while reading status: %w", err)
+	}
+	return status[0], nil
+}
+
+// readError reads the error ID register, and returns the errors
+func (d *Dev) readError() error {
+	var id [1]byte
+	if err := d.tx([]byte{regErrorID}, id[:]); err != nil {
+		return fmt.Errorf("ccs811: Error while reading error ID: %w", err)
+	}
+	return d.sensorError(id[0])
+}
+
+// sensorError returns an error with the names of the error ID bits
+func (d *Dev) sensorError(id uint8) error {
+	var names []string
+	for i, n := range errorNames {
+		if id&(1<<i) != 0 {
+			names = append(names, n)
+		}
+	}
+	return fmt.Errorf("ccs811: Sensor error 0x%02X: %s", id, strings.Join(names, ", "))
+}
+
+// tx wakes the sensor's I²C interface with the nWAKE pin, if it was set, and runs
+// the transaction
+func (d *Dev) tx(w, r []byte) error {
+	if d.wakePin == nil {
+		return d.i2c.Tx(w, r)
+	}
+	if err := d.wakePin.Out(gpio.Low); err != nil {
+		return fmt.Errorf("ccs811: Error while setting the nWAKE pin: %w", err)
+	}
+	// Requires 50µs after nWAKE is asserted before the transaction
+	time.Sleep(50 * time.Microsecond)
+	err := d.i2c.Tx(w, r)
+	if perr := d.wakePin.Out(gpio.High); err == nil && perr != nil {
+		err = fmt.Errorf("ccs811: Error while setting the nWAKE pin: %w", perr)
+	}
+	// Requires 20µs after nWAKE is deasserted before it is asserted again
+	time.Sleep(20 * time.Microsecond)
+	return err
+}
@@ -0,0 +1,339 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ccs811
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"periph.io/x/periph/conn/gpio"
+	"periph.io/x/periph/conn/gpio/gpiotest"
+	"periph.io/x/periph/conn/i2c/i2ctest"
+	"periph.io/x/periph/conn/physic"
+)
+
+var (
+	// 412ppm eCO2, 1ppb TVOC, 6µA and raw ADC 0x1a3
+	GoodReading = []byte{0x01, 0x9c, 0x00, 0x01, 0x98, 0x00, 0x19, 0xa3}
+)
+
+func TestNew(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Start the application
+			{Addr: 0x5a, W: []byte{0x20}, R: []byte{0x81}},
+			{Addr: 0x5a, W: []byte{0x00}, R: []byte{0x10}},
+			{Addr: 0x5a, W: []byte{0xf4}},
+			{Addr: 0x5a, W: []byte{0x00}, R: []byte{0x90}},
+			{Addr: 0x5a, W: []byte{0x01, 0x10}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if d.String() != "ccs811{playback(90)}" {
+		t.Fatalf("String Error: %s", d.String())
+	}
+	if d.started.IsZero() {
+		t.Fatal("started Error")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	for _, ops := range [][]i2ctest.IO{
+		{{Addr: 0x5a, W: []byte{0x20}, R: []byte{0x55}}},
+		{
+			{Addr: 0x5a, W: []byte{0x20}, R: []byte{0x81}},
+			{Addr: 0x5a, W: []byte{0x00}, R: []byte{0x00}},
+		},
+		{
+			{Addr: 0x5a, W: []byte{0x20}, R: []byte{0x81}},
+			{Addr: 0x5a, W: []byte{0x00}, R: []byte{0x10}},
+			{Addr: 0x5a, W: []byte{0xf4}},
+			{Addr: 0x5a, W: []byte{0x00}, R: []byte{0x11}},
+			{Addr: 0x5a, W: []byte{0xe0}, R: []byte{0x10}},
+		},
+	} {
+		bus := &i2ctest.Playback{Ops: ops, DontPanic: true}
+		if _, err := New(bus); err == nil {
+			t.Errorf("New Error: %v", ops)
+		}
+	}
+	bus := &i2ctest.Playback{DontPanic: true}
+	if _, err := New(bus, WithDriveMode(Drive250ms+1)); err == nil {
+		t.Error("Invalid drive mode Error")
+	}
+}
+
+func TestAppRunning(t *testing.T) {
+	bus := &i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x5b, W: []byte{0x20}, R: []byte{0x81}},
+			{Addr: 0x5b, W: []byte{0x00}, R: []byte{0x90}},
+			{Addr: 0x5b, W: []byte{0x01, 0x30}},
+		},
+		DontPanic: true,
+	}
+	if _, err := New(bus, WithAddress(AltAddr), WithDriveMode(Drive60s)); err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadAirQuality(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Start the application
+			{Addr: 0x5a, W: []byte{0x20}, R: []byte{0x81}},
+			{Addr: 0x5a, W: []byte{0x00}, R: []byte{0x10}},
+			{Addr: 0x5a, W: []byte{0xf4}},
+			{Addr: 0x5a, W: []byte{0x00}, R: []byte{0x90}},
+			{Addr: 0x5a, W: []byte{0x01, 0x10}},
+			{Addr: 0x5a, W: []byte{0x02}, R: GoodReading},
+			{Addr: 0x5a, W: []byte{0x02}, R: []byte{0, 0, 0, 0, 0x90, 0, 0, 0}},
+			{Addr: 0x5a, W: []byte{0x02}, R: []byte{0, 0, 0, 0, 0x91, 0x08, 0, 0}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	r, err := d.ReadAirQuality()
+	if err != nil {
+		t.Fatalf("ReadAirQuality Error: %s", err)
+	}
+	if r.ECO2 != 412 || r.TVOC != 1 || r.Current != 6 || r.Raw != 0x1a3 {
+		t.Errorf("ReadAirQuality Error: %#v", r)
+	}
+	if _, err := d.ReadAirQuality(); !errors.Is(err, ErrNotReady) {
+		t.Errorf("Not ready Error: %v", err)
+	}
+	if _, err := d.ReadAirQuality(); err == nil || err.Error() != "ccs811: Sensor error 0x08: MAX_RESISTANCE" {
+		t.Errorf("Sensor error Error: %v", err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDataReady(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Start the application
+			{Addr: 0x5a, W: []byte{0x20}, R: []byte{0x81}},
+			{Addr: 0x5a, W: []byte{0x00}, R: []byte{0x10}},
+			{Addr: 0x5a, W: []byte{0xf4}},
+			{Addr: 0x5a, W: []byte{0x00}, R: []byte{0x90}},
+			{Addr: 0x5a, W: []byte{0x01, 0x10}},
+			{Addr: 0x5a, W: []byte{0x00}, R: []byte{0x98}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if ready, err := d.DataReady(); err != nil || !ready {
+		t.Errorf("DataReady Error: %v %s", ready, err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCompensateFromEnv(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Start the application
+			{Addr: 0x5a, W: []byte{0x20}, R: []byte{0x81}},
+			{Addr: 0x5a, W: []byte{0x00}, R: []byte{0x10}},
+			{Addr: 0x5a, W: []byte{0xf4}},
+			{Addr: 0x5a, W: []byte{0x00}, R: []byte{0x90}},
+			{Addr: 0x5a, W: []byte{0x01, 0x10}},
+			// 48.5%rH and 23.5°C, from the datasheet's example
+			{Addr: 0x5a, W: []byte{0x05, 0x61, 0x00, 0x61, 0x00}},
+			// Clamped to 100%rH and -25°C
+			{Addr: 0x5a, W: []byte{0x05, 0xc8, 0x00, 0x00, 0x00}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	env := physic.Env{
+		Temperature: physic.ZeroCelsius + 23500*physic.MilliCelsius,
+		Humidity:    485 * physic.PercentRH / 10,
+	}
+	if err := d.CompensateFromEnv(env); err != nil {
+		t.Fatalf("CompensateFromEnv Error: %s", err)
+	}
+	env = physic.Env{
+		Temperature: physic.ZeroCelsius - 40*physic.Celsius,
+		Humidity:    101 * physic.PercentRH,
+	}
+	if err := d.CompensateFromEnv(env); err != nil {
+		t.Fatalf("CompensateFromEnv Error: %s", err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHaltReset(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Start the application
+			{Addr: 0x5a, W: []byte{0x20}, R: []byte{0x81}},
+			{Addr: 0x5a, W: []byte{0x00}, R: []byte{0x10}},
+			{Addr: 0x5a, W: []byte{0xf4}},
+			{Addr: 0x5a, W: []byte{0x00}, R: []byte{0x90}},
+			{Addr: 0x5a, W: []byte{0x01, 0x10}},
+			{Addr: 0x5a, W: []byte{0x01, 0x00}},
+			{Addr: 0x5a, W: []byte{0xff, 0x11, 0xe5, 0x72, 0x8a}},
+			{Addr: 0x5a, W: []byte{0x00}, R: []byte{0x10}},
+			{Addr: 0x5a, W: []byte{0xf4}},
+			{Addr: 0x5a, W: []byte{0x00}, R: []byte{0x90}},
+			{Addr: 0x5a, W: []byte{0x01, 0x10}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatalf("Halt Error: %s", err)
+	}
+	if !d.started.IsZero() {
+		t.Error("Halt started Error")
+	}
+	if err := d.Reset(); err != nil {
+		t.Fatalf("Reset Error: %s", err)
+	}
+	if d.started.IsZero() {
+		t.Error("Reset started Error")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBaselineRestoreSave(t *testing.T) {
+	store := NewMemoryStore()
+	if err := store.Save(Baseline{Data: [2]byte{0x84, 0xb1}, Timestamp: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Start the application
+			{Addr: 0x5a, W: []byte{0x20}, R: []byte{0x81}},
+			{Addr: 0x5a, W: []byte{0x00}, R: []byte{0x10}},
+			{Addr: 0x5a, W: []byte{0xf4}},
+			{Addr: 0x5a, W: []byte{0x00}, R: []byte{0x90}},
+			{Addr: 0x5a, W: []byte{0x01, 0x10}},
+			// Warming up, the baseline isn't restored or saved
+			{Addr: 0x5a, W: []byte{0x02}, R: GoodReading},
+			// Restore the baseline, and save the new one
+			{Addr: 0x5a, W: []byte{0x02}, R: GoodReading},
+			{Addr: 0x5a, W: []byte{0x11, 0x84, 0xb1}},
+			{Addr: 0x5a, W: []byte{0x11}, R: []byte{0x85, 0x02}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus, WithBaselineStore(store), WithSaveInterval(time.Hour))
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	d.lastSave = d.lastSave.Add(-time.Hour)
+	if _, err := d.ReadAirQuality(); err != nil {
+		t.Fatalf("ReadAirQuality Error: %s", err)
+	}
+	d.started = d.started.Add(-WarmupTime)
+	if _, err := d.ReadAirQuality(); err != nil {
+		t.Fatalf("ReadAirQuality Error: %s", err)
+	}
+	b, err := store.Load()
+	if err != nil || b == nil || b.Data != [2]byte{0x85, 0x02} {
+		t.Errorf("Saved baseline Error: %v %s", b, err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStaleBaseline(t *testing.T) {
+	store := NewMemoryStore()
+	if err := store.Save(Baseline{Data: [2]byte{0x84, 0xb1}, Timestamp: time.Now().Add(-MaxBaselineAge - time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Start the application
+			{Addr: 0x5a, W: []byte{0x20}, R: []byte{0x81}},
+			{Addr: 0x5a, W: []byte{0x00}, R: []byte{0x10}},
+			{Addr: 0x5a, W: []byte{0xf4}},
+			{Addr: 0x5a, W: []byte{0x00}, R: []byte{0x90}},
+			{Addr: 0x5a, W: []byte{0x01, 0x10}},
+			// The stale baseline isn't restored, the new one is saved
+			{Addr: 0x5a, W: []byte{0x02}, R: GoodReading},
+			{Addr: 0x5a, W: []byte{0x11}, R: []byte{0x85, 0x02}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus, WithBaselineStore(store), WithSaveInterval(time.Hour))
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	d.lastSave = d.lastSave.Add(-time.Hour)
+	if _, err := d.ReadAirQuality(); err != nil {
+		t.Fatalf("ReadAirQuality Error: %s", err)
+	}
+	b, err := store.Load()
+	if err != nil || b == nil || b.Data != [2]byte{0x85, 0x02} {
+		t.Errorf("Saved baseline Error: %v %s", b, err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWakePin(t *testing.T) {
+	wake := &gpiotest.Pin{N: "nWAKE"}
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Start the application
+			{Addr: 0x5a, W: []byte{0x20}, R: []byte{0x81}},
+			{Addr: 0x5a, W: []byte{0x00}, R: []byte{0x10}},
+			{Addr: 0x5a, W: []byte{0xf4}},
+			{Addr: 0x5a, W: []byte{0x00}, R: []byte{0x90}},
+			{Addr: 0x5a, W: []byte{0x01, 0x10}},
+			{Addr: 0x5a, W: []byte{0x11}, R: []byte{0x85, 0x02}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus, WithWakePin(wake))
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if _, err := d.ReadBaseline(); err != nil {
+		t.Fatalf("ReadBaseline Error: %s", err)
+	}
+	if wake.Read() != gpio.High {
+		t.Error("nWAKE is still asserted")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
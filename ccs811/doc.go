@@ -0,0 +1,25 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package ccs811 controls an ams CCS811 eCO2 and TVOC sensor over I²C.
+//
+// New starts the sensor's application firmware and sets the drive mode, which
+// selects how often it measures. The readings can be compensated for the temperature
+// and humidity with CompensateFromEnv.
+//
+// The sensor's baseline should be saved once it has been running for a day, and
+// restored after it has been running for 20 minutes after a restart. Pass
+// WithBaselineFile or WithBaselineStore to New to have ReadAirQuality do this.
+//
+// If the sensor's nWAKE pin is connected to a GPIO pass it to New with WithWakePin,
+// otherwise nWAKE needs to be tied low.
+//
+// Datasheet
+//
+// https://www.sciosense.com/wp-content/uploads/2020/01/CCS811-Datasheet.pdf
+//
+// Baseline application note
+//
+// https://www.sciosense.com/wp-content/uploads/2020/01/Application-Note-Baseline-Save-and-Restore-on-CCS811.pdf
+package ccs811
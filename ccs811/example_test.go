@@ -0,0 +1,48 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ccs811_test
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"periph.io/x/periph/conn/i2c/i2creg"
+	"periph.io/x/periph/host"
+
+	"github.com/bcl/air-sensors/ccs811"
+)
+
+func Example() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Open a handle to the first available I²C bus:
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer bus.Close()
+
+	d, err := ccs811.New(bus, ccs811.WithBaselineFile(".ccs811_baseline"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer d.Halt() //nolint
+
+	for {
+		time.Sleep(time.Second)
+		r, err := d.ReadAirQuality()
+		if errors.Is(err, ccs811.ErrNotReady) {
+			continue
+		} else if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("CO2 : %d ppm\nTVOC: %d ppb\n", r.ECO2, r.TVOC)
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ccs811
+
+import (
+	"time"
+
+	"periph.io/x/periph/conn/gpio"
+)
+
+const (
+	// DefaultAddr is the I²C address of the CCS811 with its ADDR pin low
+	DefaultAddr uint16 = 0x5a
+
+	// AltAddr is the I²C address of the CCS811 with its ADDR pin high
+	AltAddr uint16 = 0x5b
+
+	// DefaultSaveInterval is how often the baseline is saved when a baseline store
+	// is used. The application note recommends saving it once a day.
+	DefaultSaveInterval = 24 * time.Hour
+
+	// WarmupTime is how long the sensor needs to run before a saved baseline can
+	// be restored
+	WarmupTime = 20 * time.Minute
+)
+
+// DriveMode selects how often the sensor measures
+type DriveMode uint8
+
+const (
+	// DriveIdle stops the measurements
+	DriveIdle DriveMode = iota
+	// Drive1s measures every second
+	Drive1s
+	// Drive10s measures every 10 seconds
+	Drive10s
+	// Drive60s measures every 60 seconds
+	Drive60s
+	// Drive250ms measures every 250ms, only the raw data is updated
+	Drive250ms
+)
+
+// Option configures the Dev returned by New
+type Option func(*Dev)
+
+// WithAddress sets the I²C address of the sensor, the default is DefaultAddr
+func WithAddress(addr uint16) Option {
+	return func(d *Dev) {
+		d.addr = addr
+	}
+}
+
+// WithDriveMode sets the drive mode, the default is Drive1s
+func WithDriveMode(mode DriveMode) Option {
+	return func(d *Dev) {
+		d.mode = mode
+	}
+}
+
+// WithWakePin sets the GPIO connected to the sensor's nWAKE pin
+// It is driven low during each I²C transaction, and high between them to let the
+// sensor's I²C interface sleep.
+func WithWakePin(p gpio.PinOut) Option {
+	return func(d *Dev) {
+		d.wakePin = p
+	}
+}
+
+// WithBaselineFile loads the baseline from path at startup, and saves the new
+// baseline to it every save interval when ReadAirQuality is called.
+func WithBaselineFile(path string) Option {
+	return func(d *Dev) {
+		d.store = NewFileStore(path)
+	}
+}
+
+// WithBaselineStore loads the baseline from store at startup, and saves the new
+// baseline to it every save interval when ReadAirQuality is called.
+func WithBaselineStore(store BaselineStore) Option {
+	return func(d *Dev) {
+		d.store = store
+	}
+}
+
+// WithSaveInterval sets how often the baseline is saved to the baseline store, the
+// default is DefaultSaveInterval.
+func WithSaveInterval(interval time.Duration) Option {
+	return func(d *Dev) {
+		d.saveInterval = interval
+	}
+}
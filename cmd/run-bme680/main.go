@@ -0,0 +1,49 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"periph.io/x/periph/conn/i2c/i2creg"
+	"periph.io/x/periph/host"
+
+	"github.com/bcl/air-sensors/bme680"
+)
+
+func main() {
+	addr := flag.Uint("addr", uint(bme680.DefaultAddr), "I²C address of the BME680")
+	flag.Parse()
+
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Open a handle to the first available I²C bus:
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer bus.Close()
+
+	d, err := bme680.New(bus, bme680.WithAddress(uint16(*addr)))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer d.Halt() //nolint
+
+	r, err := d.Read()
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%8s %10s %9s %7dΩ\n", r.Temperature, r.Pressure, r.Humidity, r.GasResistance)
+	if !r.GasValid {
+		log.Fatal("BME680: Gas resistance is not valid")
+	}
+	fmt.Printf("BME680: Good readings detected\n")
+}
@@ -0,0 +1,47 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"periph.io/x/periph/conn/i2c/i2creg"
+	"periph.io/x/periph/conn/physic"
+	"periph.io/x/periph/host"
+
+	"github.com/bcl/air-sensors/bmp3xx"
+)
+
+func main() {
+	addr := flag.Uint("addr", uint(bmp3xx.DefaultAddr), "I²C address of the BMP3xx")
+	flag.Parse()
+
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Open a handle to the first available I²C bus:
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer bus.Close()
+
+	d, err := bmp3xx.New(bus, bmp3xx.WithAddress(uint16(*addr)))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer d.Halt() //nolint
+
+	var env physic.Env
+	if err := d.Sense(&env); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%8s %10s %s\n", env.Temperature, env.Pressure, bmp3xx.Altitude(env.Pressure, bmp3xx.SeaLevelPressure))
+	fmt.Printf("BMP3xx: Good readings detected\n")
+}
@@ -0,0 +1,58 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"periph.io/x/periph/conn/i2c/i2creg"
+	"periph.io/x/periph/host"
+
+	"github.com/bcl/air-sensors/ens160"
+)
+
+func main() {
+	addr := flag.Uint("addr", uint(ens160.DefaultAddr), "I²C address of the ENS160")
+	flag.Parse()
+
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Open a handle to the first available I²C bus:
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer bus.Close()
+
+	d, err := ens160.New(bus, ens160.WithAddress(uint16(*addr)))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer d.Halt() //nolint
+
+	major, minor, release := d.FirmwareVersion()
+	fmt.Printf("Firmware: %d.%d.%d\n", major, minor, release)
+
+	for i := 0; i < 5; i++ {
+		time.Sleep(time.Second)
+		r, err := d.ReadAirQuality()
+		if errors.Is(err, ens160.ErrNotReady) {
+			continue
+		} else if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("AQI : %d (%s)\nTVOC: %d ppb\nCO2 : %d ppm\n", r.AQI, r.Validity, r.TVOC, r.ECO2)
+		fmt.Printf("ENS160: Good readings detected\n")
+		return
+	}
+	log.Fatal("ENS160: No readings")
+}
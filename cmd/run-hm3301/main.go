@@ -0,0 +1,46 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"periph.io/x/periph/conn/i2c/i2creg"
+	"periph.io/x/periph/host"
+
+	"github.com/bcl/air-sensors/hm3301"
+)
+
+func main() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Open a handle to the first available I²C bus:
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer bus.Close()
+
+	d, err := hm3301.New(bus)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer d.Halt() //nolint
+
+	for i := 0; i < 5; i++ {
+		r, err := d.ReadSensor()
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("PM1.0: %3d PM2.5: %3d PM10: %3d μg/m3  >0.3μm: %5d /L\n", r.EnvPm1, r.EnvPm2_5, r.EnvPm10, r.Cnt0_3)
+		time.Sleep(time.Second)
+	}
+	fmt.Printf("HM3301: Good readings detected\n")
+}
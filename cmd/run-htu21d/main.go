@@ -0,0 +1,56 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"periph.io/x/periph/conn/i2c/i2creg"
+	"periph.io/x/periph/conn/physic"
+	"periph.io/x/periph/host"
+
+	"github.com/bcl/air-sensors/htu21d"
+)
+
+func main() {
+	hold := flag.Bool("hold", false, "Measure in hold master mode, using clock stretching")
+	flag.Parse()
+
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Open a handle to the first available I²C bus:
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer bus.Close()
+
+	var opts []htu21d.Option
+	if *hold {
+		opts = append(opts, htu21d.WithHoldMaster())
+	}
+	d, err := htu21d.New(bus, opts...)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer d.Halt() //nolint
+
+	var env physic.Env
+	if err := d.Sense(&env); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%8s %9s\n", env.Temperature, env.Humidity)
+	if low, err := d.LowBattery(); err != nil {
+		log.Fatal(err)
+	} else if low {
+		fmt.Printf("Supply voltage is below 2.25V\n")
+	}
+	fmt.Printf("HTU21D: Good readings detected\n")
+}
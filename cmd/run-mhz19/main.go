@@ -0,0 +1,43 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/bcl/air-sensors/mhz19"
+)
+
+func main() {
+	// The port needs to be configured first, eg. stty -F /dev/ttyS0 9600 raw
+	path := flag.String("port", "/dev/ttyS0", "UART the sensor is connected to, configured for 9600 baud")
+	flag.Parse()
+
+	port, err := os.OpenFile(*path, os.O_RDWR, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer port.Close()
+
+	d, err := mhz19.New(port)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer d.Halt() //nolint
+
+	for i := 0; i < 5; i++ {
+		r, err := d.ReadCO2()
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("CO2: %d ppm\n", r.CO2)
+		time.Sleep(5 * time.Second)
+	}
+	fmt.Printf("MH-Z19: Good readings detected\n")
+}
@@ -5,6 +5,8 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"log"
 	"time"
@@ -16,6 +18,10 @@ import (
 )
 
 func main() {
+	addr := flag.Uint("addr", uint(pmsa003i.DefaultAddr), "I²C address of the PMSA003i")
+	retries := flag.Int("retries", 2, "Number of times to re-read a corrupted frame")
+	flag.Parse()
+
 	// Make sure periph is initialized.
 	if _, err := host.Init(); err != nil {
 		log.Fatal(err)
@@ -28,31 +34,30 @@ func main() {
 	}
 	defer bus.Close()
 
-	d, err := pmsa003i.New(bus)
+	d, err := pmsa003i.New(bus,
+		pmsa003i.WithAddress(uint16(*addr)),
+		pmsa003i.WithRetries(*retries))
 	if err != nil {
 		log.Fatal(err)
 	}
-
-	for start := time.Now(); time.Since(start) < time.Second*30; {
-		time.Sleep(1 * time.Second)
-
-		// Read the PMSA003i sensor data
-		r, err := d.ReadSensor()
-		if err != nil {
-			// Checksum failures could be transient
-			fmt.Println(err)
-		} else {
-			fmt.Println()
-			fmt.Printf("PM1.0  %3d μg/m3\n", r.EnvPm1)
-			fmt.Printf("PM2.5  %3d μg/m3\n", r.EnvPm2_5)
-			fmt.Printf("PM10   %3d μg/m3\n", r.EnvPm10)
-			fmt.Println("Counters in 0.1L of air")
-			fmt.Printf("%d > 0.3μm\n", r.Cnt0_3)
-			fmt.Printf("%d > 0.5μm\n", r.Cnt0_5)
-			fmt.Printf("%d > 1.0μm\n", r.Cnt1)
-			fmt.Printf("%d > 2.5μm\n", r.Cnt2_5)
-			fmt.Printf("%d > 5.0μm\n", r.Cnt5)
-			fmt.Printf("%d > 10μm\n", r.Cnt10)
-		}
+	defer d.Halt() //nolint
+
+	// Read the PMSA003i sensor data every second for 30s
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	for r := range d.Poll(ctx, time.Second) {
+		fmt.Println()
+		fmt.Printf("PM1.0  %3d μg/m3\n", r.EnvPm1)
+		fmt.Printf("PM2.5  %3d μg/m3\n", r.EnvPm2_5)
+		fmt.Printf("PM10   %3d μg/m3\n", r.EnvPm10)
+		i, cat := r.AQI()
+		fmt.Printf("AQI    %3d %s\n", i, cat)
+		fmt.Println("Counters in 0.1L of air")
+		fmt.Printf("%d > 0.3μm\n", r.Cnt0_3)
+		fmt.Printf("%d > 0.5μm\n", r.Cnt0_5)
+		fmt.Printf("%d > 1.0μm\n", r.Cnt1)
+		fmt.Printf("%d > 2.5μm\n", r.Cnt2_5)
+		fmt.Printf("%d > 5.0μm\n", r.Cnt5)
+		fmt.Printf("%d > 10μm\n", r.Cnt10)
 	}
 }
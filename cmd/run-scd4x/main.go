@@ -0,0 +1,60 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"periph.io/x/periph/conn/i2c/i2creg"
+	"periph.io/x/periph/host"
+
+	"github.com/bcl/air-sensors/scd4x"
+)
+
+func main() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Open a handle to the first available I²C bus:
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer bus.Close()
+
+	d, err := scd4x.New(bus)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer d.Halt() //nolint
+
+	fmt.Printf("Serial Number: %X\n", d.SerialNumber())
+	if err := d.StartMeasurements(); err != nil {
+		log.Fatal(err)
+	}
+
+	// The first measurement is ready after 5 seconds
+	// This exits with a positive result once a measurement is read.
+	for range time.Tick(time.Second) {
+		ready, err := d.DataReady()
+		if err != nil {
+			log.Fatal(err)
+		}
+		if !ready {
+			continue
+		}
+		r, err := d.ReadMeasurement()
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("CO2 : %d ppm\n%8s %9s\n", r.CO2, r.Temperature, r.Humidity)
+		fmt.Printf("SCD4x: Good readings detected\n")
+		break
+	}
+}
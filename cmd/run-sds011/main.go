@@ -0,0 +1,54 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/bcl/air-sensors/sds011"
+)
+
+func main() {
+	// The port needs to be configured first, eg. stty -F /dev/ttyUSB0 9600 raw
+	path := flag.String("port", "/dev/ttyUSB0", "UART the sensor is connected to, configured for 9600 baud")
+	id := flag.Uint("id", uint(sds011.BroadcastID), "Device ID of the sensor")
+	flag.Parse()
+
+	port, err := os.OpenFile(*path, os.O_RDWR, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer port.Close()
+
+	d, err := sds011.New(port, sds011.WithDeviceID(uint16(*id)))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer d.Halt() //nolint
+
+	if err := d.Wake(); err != nil {
+		log.Fatal(err)
+	}
+	if err := d.SetQueryMode(true); err != nil {
+		log.Fatal(err)
+	}
+	year, month, day, err := d.FirmwareVersion()
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Firmware: %d-%02d-%02d\n", year, month, day)
+
+	for i := 0; i < 5; i++ {
+		r, err := d.ReadSensor()
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("ID: %04X PM2.5: %5.1f μg/m3 PM10: %5.1f μg/m3\n", r.DeviceID, r.PM2_5, r.PM10)
+	}
+	fmt.Printf("SDS011: Good readings detected\n")
+}
@@ -0,0 +1,79 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"periph.io/x/periph/conn/i2c/i2creg"
+	"periph.io/x/periph/host"
+
+	"github.com/bcl/air-sensors/sen5x"
+)
+
+func main() {
+	clean := flag.Bool("clean", false, "Clean the fan before reading")
+	flag.Parse()
+
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Open a handle to the first available I²C bus:
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer bus.Close()
+
+	d, err := sen5x.New(bus)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer d.Halt() //nolint
+
+	major, minor := d.FirmwareVersion()
+	fmt.Printf("%s Serial Number: %s Firmware: %d.%d\n", d.ProductName(), d.SerialNumber(), major, minor)
+
+	if err := d.StartMeasurement(); err != nil {
+		log.Fatal(err)
+	}
+	if *clean {
+		if err := d.StartFanCleaning(); err != nil {
+			log.Fatal(err)
+		}
+		time.Sleep(sen5x.FanCleaningTime)
+	}
+
+	good := 0
+	for i := 0; i < 10; i++ {
+		time.Sleep(time.Second)
+		r, err := d.ReadMeasurement()
+		if errors.Is(err, sen5x.ErrNotReady) {
+			continue
+		} else if err != nil {
+			log.Fatal(err)
+		}
+		if !r.PMValid || !r.EnvValid {
+			continue
+		}
+		fmt.Printf("PM1.0: %5.1f PM2.5: %5.1f PM4.0: %5.1f PM10: %5.1f μg/m3 %8s %9s VOC: %3.0f NOx: %3.0f\n",
+			r.MassPM1, r.MassPM2_5, r.MassPM4, r.MassPM10, r.Temperature, r.Humidity, r.VOCIndex, r.NOxIndex)
+		good++
+	}
+	status, err := d.ReadStatus(false)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Device status: %s\n", status)
+	if good > 0 && !status.Errors() {
+		fmt.Printf("SEN5x: Good readings detected\n")
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/bcl/air-sensors/senseair"
+)
+
+func main() {
+	// The port needs to be configured first, eg. stty -F /dev/ttyS0 9600 raw
+	path := flag.String("port", "/dev/ttyS0", "UART the sensor is connected to, configured for 9600 baud")
+	addr := flag.Uint("addr", uint(senseair.AnyAddress), "Modbus address of the sensor")
+	flag.Parse()
+
+	port, err := os.OpenFile(*path, os.O_RDWR, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer port.Close()
+
+	d, err := senseair.New(port, senseair.WithAddress(byte(*addr)))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer d.Halt() //nolint
+
+	id, err := d.SensorID()
+	if err != nil {
+		log.Fatal(err)
+	}
+	major, minor, err := d.FirmwareVersion()
+	if err != nil {
+		log.Fatal(err)
+	}
+	period, err := d.ABCPeriod()
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Sensor ID: %08X Firmware: %d.%d ABC period: %s\n", id, major, minor, period)
+
+	for i := 0; i < 5; i++ {
+		r, err := d.ReadCO2()
+		if err != nil {
+			log.Fatal(err)
+		}
+		if r.Status != 0 {
+			log.Fatalf("Sensor status: 0x%04X", uint16(r.Status))
+		}
+		fmt.Printf("CO2: %d ppm\n", r.CO2)
+		time.Sleep(4 * time.Second)
+	}
+	fmt.Printf("Senseair S8: Good readings detected\n")
+}
@@ -5,6 +5,8 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"log"
 	"time"
@@ -16,6 +18,9 @@ import (
 )
 
 func main() {
+	addr := flag.Uint("addr", uint(sgp30.DefaultAddr), "I²C address of the SGP30")
+	flag.Parse()
+
 	// Make sure periph is initialized.
 	if _, err := host.Init(); err != nil {
 		log.Fatal(err)
@@ -28,7 +33,11 @@ func main() {
 	}
 	defer bus.Close()
 
-	d, err := sgp30.New(bus, ".sgp30_baseline", 30*time.Second)
+	d, err := sgp30.New(bus,
+		sgp30.WithAddress(uint16(*addr)),
+		sgp30.WithBaselineFile(".sgp30_baseline"),
+		sgp30.WithSaveInterval(30*time.Second),
+		sgp30.WithWarmupPolicy(sgp30.WarmupFlag))
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -41,25 +50,25 @@ func main() {
 	fmt.Printf("Serial Number: %X\n", sn)
 
 	// Start measuring air quality
-	if err = d.StartMeasurements(); err != nil {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	readings, err := d.Start(ctx)
+	if err != nil {
 		log.Fatal(err)
 	}
 
 	// The SGP30 returns 400ppm, 0ppb for 15 seconds at startup
-	// This exits with a positive result if non-default values are read
-	// But it cannot detect an error from just the readings since 400,0
-	// may be normal for the environment.
-	for start := time.Now(); time.Since(start) < time.Second*30; {
-		time.Sleep(1 * time.Second)
-		if co2, tvoc, err := d.ReadAirQuality(); err != nil {
-			log.Fatal(err)
-		} else {
-			fmt.Printf("CO2 : %d ppm\nTVOC: %d ppb\n", co2, tvoc)
-
-			if co2 > 400 && tvoc > 0 {
-				fmt.Printf("SGP30: Good readings detected\n")
-				break
-			}
+	// This exits with a positive result once a reading is made after the warm-up.
+	for aq := range readings {
+		if aq.Err != nil {
+			log.Fatal(aq.Err)
+		}
+		if aq.Warmup {
+			fmt.Printf("SGP30: Warming up\n")
+			continue
 		}
+		fmt.Printf("CO2 : %d ppm\nTVOC: %d ppb\n", aq.ECO2, aq.TVOC)
+		fmt.Printf("SGP30: Good readings detected\n")
+		break
 	}
 }
@@ -0,0 +1,57 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"periph.io/x/periph/conn/i2c/i2creg"
+	"periph.io/x/periph/host"
+
+	"github.com/bcl/air-sensors/sgp41"
+)
+
+func main() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Open a handle to the first available I²C bus:
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer bus.Close()
+
+	d, err := sgp41.New(bus)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer d.Halt() //nolint
+
+	fmt.Printf("Serial Number: %X\n", d.SerialNumber())
+	if err := d.SelfTest(); err != nil {
+		log.Fatal(err)
+	}
+
+	// The indexes are 0 for the first 45 seconds
+	// This exits with a positive result once a VOC Index is calculated.
+	for range time.Tick(time.Second) {
+		r, err := d.ReadAirQuality(nil)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if r.VOCIndex == 0 {
+			fmt.Printf("SGP41: Raw VOC %d, NOx %d\n", r.SrawVOC, r.SrawNOx)
+			continue
+		}
+		fmt.Printf("VOC Index: %d\nNOx Index: %d\n", r.VOCIndex, r.NOxIndex)
+		fmt.Printf("SGP41: Good readings detected\n")
+		break
+	}
+}
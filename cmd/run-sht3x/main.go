@@ -0,0 +1,52 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"periph.io/x/periph/conn/i2c/i2creg"
+	"periph.io/x/periph/conn/physic"
+	"periph.io/x/periph/host"
+
+	"github.com/bcl/air-sensors/sht3x"
+)
+
+func main() {
+	addr := flag.Uint("addr", uint(sht3x.DefaultAddr), "I²C address of the SHT3x")
+	flag.Parse()
+
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Open a handle to the first available I²C bus:
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer bus.Close()
+
+	d, err := sht3x.New(bus, sht3x.WithAddress(uint16(*addr)))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	status, err := d.Status()
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Status: 0x%04X\n", status)
+
+	var env physic.Env
+	if err := d.Sense(&env); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%8s %9s\n", env.Temperature, env.Humidity)
+	fmt.Printf("SHT3x: Good readings detected\n")
+}
@@ -0,0 +1,52 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"periph.io/x/periph/conn/i2c/i2creg"
+	"periph.io/x/periph/conn/physic"
+	"periph.io/x/periph/host"
+
+	"github.com/bcl/air-sensors/shtc3"
+)
+
+func main() {
+	stretch := flag.Bool("stretch", false, "Read the measurement with clock stretching")
+	flag.Parse()
+
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Open a handle to the first available I²C bus:
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer bus.Close()
+
+	var opts []shtc3.Option
+	if *stretch {
+		opts = append(opts, shtc3.WithClockStretching())
+	}
+	d, err := shtc3.New(bus, opts...)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer d.Halt() //nolint
+	fmt.Printf("ID: 0x%04X\n", d.ID())
+
+	var env physic.Env
+	if err := d.Sense(&env); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%8s %9s\n", env.Temperature, env.Humidity)
+	fmt.Printf("SHTC3: Good readings detected\n")
+}
@@ -0,0 +1,83 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"periph.io/x/periph/conn/i2c/i2creg"
+	"periph.io/x/periph/host"
+
+	"github.com/bcl/air-sensors/sps30"
+)
+
+func main() {
+	clean := flag.Bool("clean", false, "Clean the fan before reading")
+	flag.Parse()
+
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Open a handle to the first available I²C bus:
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer bus.Close()
+
+	d, err := sps30.New(bus)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer d.Halt() //nolint
+
+	major, minor := d.FirmwareVersion()
+	fmt.Printf("Serial Number: %s Firmware: %d.%d\n", d.SerialNumber(), major, minor)
+	interval, err := d.AutoCleanInterval()
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Auto cleaning interval: %s\n", interval)
+
+	if err := d.StartMeasurement(); err != nil {
+		log.Fatal(err)
+	}
+	if *clean {
+		if err := d.StartFanCleaning(); err != nil {
+			log.Fatal(err)
+		}
+		time.Sleep(sps30.FanCleaningTime)
+	}
+
+	good := 0
+	for i := 0; i < 10; i++ {
+		time.Sleep(time.Second)
+		r, err := d.ReadMeasurement()
+		if errors.Is(err, sps30.ErrNotReady) {
+			continue
+		} else if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("PM1.0: %5.1f PM2.5: %5.1f PM4.0: %5.1f PM10: %5.1f μg/m3  size: %0.2f μm\n",
+			r.MassPM1, r.MassPM2_5, r.MassPM4, r.MassPM10, r.TypicalSize)
+		good++
+	}
+	status, err := d.ReadStatus(false)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if status != 0 {
+		fmt.Printf("Device status: 0x%08X\n", uint32(status))
+	}
+	if good > 0 {
+		fmt.Printf("SPS30: Good readings detected\n")
+	}
+}
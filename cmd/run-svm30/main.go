@@ -0,0 +1,67 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"periph.io/x/periph/conn/i2c/i2creg"
+	"periph.io/x/periph/host"
+
+	"github.com/bcl/air-sensors/sgp30"
+	"github.com/bcl/air-sensors/svm30"
+)
+
+func main() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Open a handle to the first available I²C bus:
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer bus.Close()
+
+	d, err := svm30.New(bus,
+		sgp30.WithBaselineFile(".svm30_baseline"),
+		sgp30.WithSaveInterval(30*time.Second),
+		sgp30.WithWarmupPolicy(sgp30.WarmupFlag),
+		sgp30.WithAutoStart(true))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer d.Halt() //nolint
+
+	// The SGP30 returns 400ppm, 0ppb for 15 seconds at startup
+	// This exits with a positive result once a reading is made after the warm-up.
+	timeout := time.After(30 * time.Second)
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-timeout:
+			log.Fatal("SVM30: No readings after the warm-up")
+		case <-ticker.C:
+		}
+
+		r, err := d.ReadAirQuality()
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Temperature: %s\nHumidity: %s\n", r.Temperature, r.Humidity)
+		if r.Warmup {
+			fmt.Printf("SVM30: Warming up\n")
+			continue
+		}
+		fmt.Printf("CO2 : %d ppm\nTVOC: %d ppb\n", r.ECO2, r.TVOC)
+		fmt.Printf("SVM30: Good readings detected\n")
+		break
+	}
+}
@@ -0,0 +1,18 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package ens160 controls a ScioSense ENS160 digital metal oxide gas sensor over I²C.
+//
+// In its standard operating mode the sensor returns an air quality index from 1 to 5
+// using the German Umweltbundesamt's (UBA) scale, TVOC, and equivalent CO2 every
+// second. The readings can be compensated for the temperature and humidity with
+// CompensateFromEnv, eg. from the ENS210 that is on many ENS160 breakout boards.
+//
+// The sensor needs 3 minutes to warm up after it is started, and an hour to finish
+// its initial start-up the first time it is used, Reading.Validity reports this.
+//
+// Datasheet
+//
+// https://www.sciosense.com/wp-content/uploads/documents/SC-001224-DS-9-ENS160-Datasheet.pdf
+package ens160
@@ -0,0 +1,217 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ens160
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"periph.io/x/periph/conn"
+	"periph.io/x/periph/conn/i2c"
+	"periph.io/x/periph/conn/physic"
+)
+
+// PartID is the value of the ENS160's PART_ID register
+const PartID uint16 = 0x0160
+
+// ENS160 registers from the datasheet, multi-byte values are little endian
+const (
+	regPartID  uint8 = 0x00 // Part ID, 2 bytes
+	regOpMode  uint8 = 0x10 // Operating mode
+	regCommand uint8 = 0x12 // Commands, only run in Idle mode
+	regTempIn  uint8 = 0x13 // Temperature compensation, followed by regRHIn
+	regStatus  uint8 = 0x20 // Device status, followed by the readings
+	regGPRRead uint8 = 0x48 // General purpose read registers, 8 bytes
+)
+
+const (
+	opModeReset   uint8 = 0xf0 // Resets the sensor when written to regOpMode
+	cmdGetAppVer  uint8 = 0x0e // Put the firmware version in regGPRRead 4-6
+	statusError   uint8 = 0x40 // An invalid operating mode was set
+	statusNewData uint8 = 0x02 // A new reading is ready
+)
+
+// ErrNotReady is returned by ReadAirQuality when there is no new reading
+var ErrNotReady = errors.New("ens160: Reading is not ready")
+
+// Validity is the state of the sensor's readings
+type Validity uint8
+
+const (
+	// Normal readings are valid
+	Normal Validity = iota
+	// WarmUp is the first 3 minutes after the sensor is started
+	WarmUp
+	// InitialStartUp is the first hour of the sensor's use
+	InitialStartUp
+	// Invalid readings should not be used
+	Invalid
+)
+
+// String returns the name of the validity
+func (v Validity) String() string {
+	switch v {
+	case Normal:
+		return "normal"
+	case WarmUp:
+		return "warm-up"
+	case InitialStartUp:
+		return "initial start-up"
+	}
+	return "invalid"
+}
+
+// Reading holds the air quality readings from the ENS160
+type Reading struct {
+	AQI       uint8     `json:"aqi"`       // Air quality index from 1 (excellent) to 5 (unhealthy)
+	TVOC      uint16    `json:"tvoc"`      // TVOC in ppb
+	ECO2      uint16    `json:"eco2"`      // CO2 equivalent in ppm
+	Validity  Validity  `json:"validity"`  // Whether the sensor is warming up
+	Timestamp time.Time `json:"timestamp"` // When the reading was made
+}
+
+// Dev holds the connection to the ENS160
+type Dev struct {
+	i2c     conn.Conn // i2c device handle for the ens160
+	addr    uint16    // I²C address of the ens160
+	mode    OpMode    // Operating mode set by New
+	version [3]uint8  // Firmware version
+}
+
+var _ conn.Resource = &Dev{}
+
+// New returns an ENS160 device struct for communicating with the device
+//
+// It checks the part ID, reads the firmware version, and sets the operating mode.
+func New(i i2c.Bus, opts ...Option) (*Dev, error) {
+	d := &Dev{
+		addr: DefaultAddr,
+		mode: Standard,
+	}
+	for _, o := range opts {
+		o(d)
+	}
+	if d.mode > Standard {
+		return nil, fmt.Errorf("ens160: Invalid operating mode: %d", d.mode)
+	}
+	d.i2c = &i2c.Dev{Bus: i, Addr: d.addr}
+
+	var id [2]byte
+	if err := d.i2c.Tx([]byte{regPartID}, id[:]); err != nil {
+		return nil, fmt.Errorf("ens160: Error while reading part ID: %w", err)
+	}
+	if pid := uint16(id[0]) | uint16(id[1])<<8; pid != PartID {
+		return nil, fmt.Errorf("ens160: ENS160 not found, part ID is 0x%04X", pid)
+	}
+	if err := d.init(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// String implements conn.Resource.
+func (d *Dev) String() string {
+	return fmt.Sprintf("ens160{%s}", d.i2c)
+}
+
+// Halt implements conn.Resource.
+//
+// It puts the sensor into DeepSleep.
+func (d *Dev) Halt() error {
+	return d.SetOpMode(DeepSleep)
+}
+
+// Reset resets the sensor, and sets the operating mode set by New
+func (d *Dev) Reset() error {
+	if err := d.i2c.Tx([]byte{regOpMode, opModeReset}, nil); err != nil {
+		return fmt.Errorf("ens160: Error while resetting: %w", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	return d.init()
+}
+
+// FirmwareVersion returns the major, minor, and release numbers of the firmware
+func (d *Dev) FirmwareVersion() (uint8, uint8, uint8) {
+	return d.version[0], d.version[1], d.version[2]
+}
+
+// SetOpMode sets the operating mode, Standard starts the measurements
+func (d *Dev) SetOpMode(mode OpMode) error {
+	if mode > Standard {
+		return fmt.Errorf("ens160: Invalid operating mode: %d", mode)
+	}
+	if err := d.i2c.Tx([]byte{regOpMode, uint8(mode)}, nil); err != nil {
+		return fmt.Errorf("ens160: Error while setting operating mode: %w", err)
+	}
+	return nil
+}
+
+// DataReady returns true if a new reading is ready
+func (d *Dev) DataReady() (bool, error) {
+	var status [1]byte
+	if err := d.i2c.Tx([]byte{regStatus}, status[:]); err != nil {
+		return false, fmt.Errorf("ens160: Error while reading status: %w", err)
+	}
+	return status[0]&statusNewData != 0, nil
+}
+
+// ReadAirQuality returns the AQI, TVOC, and eCO2 readings, or ErrNotReady if there is
+// no new reading since the last one
+func (d *Dev) ReadAirQuality() (Reading, error) {
+	// Status, AQI, TVOC, and eCO2
+	var data [6]byte
+	if err := d.i2c.Tx([]byte{regStatus}, data[:]); err != nil {
+		return Reading{}, fmt.Errorf("ens160: Error while reading air quality: %w", err)
+	}
+	if data[0]&statusError != 0 {
+		return Reading{}, fmt.Errorf("ens160: Sensor error, status is 0x%02X", data[0])
+	}
+	if data[0]&statusNewData == 0 {
+		return Reading{}, ErrNotReady
+	}
+	return Reading{
+		AQI:       data[1] & 0x07,
+		TVOC:      uint16(data[2]) | uint16(data[3])<<8,
+		ECO2:      uint16(data[4]) | uint16(data[5])<<8,
+		Validity:  Validity(data[0]>>2) & 0x03,
+		Timestamp: time.Now(),
+	}, nil
+}
+
+// CompensateFromEnv sets the temperature and humidity compensation from the readings
+// of another sensor, eg. an ENS210 or one of the periph.io environmental sensors.
+func (d *Dev) CompensateFromEnv(env physic.Env) error {
+	// The temperature is in 1/64 K, and the humidity is in 1/512 %rH
+	t := math.Round(float64(env.Temperature) / float64(physic.Kelvin) * 64)
+	rh := math.Round(float64(env.Humidity) / float64(physic.PercentRH) * 512)
+	tIn := uint16(math.Max(0, math.Min(t, math.MaxUint16)))
+	rhIn := uint16(math.Max(0, math.Min(rh, 100*512)))
+	w := []byte{regTempIn, uint8(tIn), uint8(tIn >> 8), uint8(rhIn), uint8(rhIn >> 8)}
+	if err := d.i2c.Tx(w, nil); err != nil {
+		return fmt.Errorf("ens160: Error while setting compensation: %w", err)
+	}
+	return nil
+}
+
+// init reads the firmware version in Idle mode, and then sets the operating mode
+func (d *Dev) init() error {
+	if err := d.SetOpMode(Idle); err != nil {
+		return err
+	}
+	if err := d.i2c.Tx([]byte{regCommand, cmdGetAppVer}, nil); err != nil {
+		return fmt.Errorf("ens160: Error while requesting firmware version: %w", err)
+	}
+	var gpr [3]byte
+	if err := d.i2c.Tx([]byte{regGPRRead + 4}, gpr[:]); err != nil {
+		return fmt.Errorf("ens160: Error while reading firmware version: %w", err)
+	}
+	d.version = gpr
+	if d.mode == Idle {
+		return nil
+	}
+	return d.SetOpMode(d.mode)
+}
@@ -0,0 +1,203 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ens160
+
+import (
+	"errors"
+	"testing"
+
+	"periph.io/x/periph/conn/i2c/i2ctest"
+	"periph.io/x/periph/conn/physic"
+)
+
+var ()
+
+func TestNew(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Start the measurements
+			{Addr: 0x52, W: []byte{0x00}, R: []byte{0x60, 0x01}},
+			{Addr: 0x52, W: []byte{0x10, 0x01}},
+			{Addr: 0x52, W: []byte{0x12, 0x0e}},
+			{Addr: 0x52, W: []byte{0x4c}, R: []byte{0x05, 0x04, 0x06}},
+			{Addr: 0x52, W: []byte{0x10, 0x02}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if d.String() != "ens160{playback(82)}" {
+		t.Fatalf("String Error: %s", d.String())
+	}
+	if major, minor, release := d.FirmwareVersion(); major != 5 || minor != 4 || release != 6 {
+		t.Errorf("FirmwareVersion Error: %d.%d.%d", major, minor, release)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	bus := &i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x52, W: []byte{0x00}, R: []byte{0x61, 0x01}},
+		},
+		DontPanic: true,
+	}
+	if _, err := New(bus); err == nil {
+		t.Error("Wrong part ID Error")
+	}
+	if _, err := New(bus, WithOpMode(Standard+1)); err == nil {
+		t.Error("Invalid operating mode Error")
+	}
+}
+
+func TestIdle(t *testing.T) {
+	bus := &i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x53, W: []byte{0x00}, R: []byte{0x60, 0x01}},
+			{Addr: 0x53, W: []byte{0x10, 0x01}},
+			{Addr: 0x53, W: []byte{0x12, 0x0e}},
+			{Addr: 0x53, W: []byte{0x4c}, R: []byte{0x05, 0x04, 0x06}},
+		},
+		DontPanic: true,
+	}
+	if _, err := New(bus, WithAddress(AltAddr), WithOpMode(Idle)); err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadAirQuality(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Start the measurements
+			{Addr: 0x52, W: []byte{0x00}, R: []byte{0x60, 0x01}},
+			{Addr: 0x52, W: []byte{0x10, 0x01}},
+			{Addr: 0x52, W: []byte{0x12, 0x0e}},
+			{Addr: 0x52, W: []byte{0x4c}, R: []byte{0x05, 0x04, 0x06}},
+			{Addr: 0x52, W: []byte{0x10, 0x02}},
+			{Addr: 0x52, W: []byte{0x20}, R: []byte{0x86, 0x02, 0x7b, 0x00, 0x58, 0x02}},
+			{Addr: 0x52, W: []byte{0x20}, R: []byte{0x80, 0x02, 0x7b, 0x00, 0x58, 0x02}},
+			{Addr: 0x52, W: []byte{0x20}, R: []byte{0xc0, 0x00, 0x00, 0x00, 0x00, 0x00}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	r, err := d.ReadAirQuality()
+	if err != nil {
+		t.Fatalf("ReadAirQuality Error: %s", err)
+	}
+	if r.AQI != 2 || r.TVOC != 123 || r.ECO2 != 600 || r.Validity != WarmUp {
+		t.Errorf("ReadAirQuality Error: %#v", r)
+	}
+	if r.Validity.String() != "warm-up" {
+		t.Errorf("Validity String Error: %s", r.Validity)
+	}
+	if _, err := d.ReadAirQuality(); !errors.Is(err, ErrNotReady) {
+		t.Errorf("Not ready Error: %v", err)
+	}
+	if _, err := d.ReadAirQuality(); err == nil {
+		t.Error("Sensor error Error")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDataReady(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Start the measurements
+			{Addr: 0x52, W: []byte{0x00}, R: []byte{0x60, 0x01}},
+			{Addr: 0x52, W: []byte{0x10, 0x01}},
+			{Addr: 0x52, W: []byte{0x12, 0x0e}},
+			{Addr: 0x52, W: []byte{0x4c}, R: []byte{0x05, 0x04, 0x06}},
+			{Addr: 0x52, W: []byte{0x10, 0x02}},
+			{Addr: 0x52, W: []byte{0x20}, R: []byte{0x82}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if ready, err := d.DataReady(); err != nil || !ready {
+		t.Errorf("DataReady Error: %v %s", ready, err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCompensateFromEnv(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Start the measurements
+			{Addr: 0x52, W: []byte{0x00}, R: []byte{0x60, 0x01}},
+			{Addr: 0x52, W: []byte{0x10, 0x01}},
+			{Addr: 0x52, W: []byte{0x12, 0x0e}},
+			{Addr: 0x52, W: []byte{0x4c}, R: []byte{0x05, 0x04, 0x06}},
+			{Addr: 0x52, W: []byte{0x10, 0x02}},
+			// 298.15K * 64 and 50%rH * 512
+			{Addr: 0x52, W: []byte{0x13, 0x8a, 0x4a, 0x00, 0x64}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	env := physic.Env{
+		Temperature: physic.ZeroCelsius + 25*physic.Celsius,
+		Humidity:    50 * physic.PercentRH,
+	}
+	if err := d.CompensateFromEnv(env); err != nil {
+		t.Fatalf("CompensateFromEnv Error: %s", err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHaltReset(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Start the measurements
+			{Addr: 0x52, W: []byte{0x00}, R: []byte{0x60, 0x01}},
+			{Addr: 0x52, W: []byte{0x10, 0x01}},
+			{Addr: 0x52, W: []byte{0x12, 0x0e}},
+			{Addr: 0x52, W: []byte{0x4c}, R: []byte{0x05, 0x04, 0x06}},
+			{Addr: 0x52, W: []byte{0x10, 0x02}},
+			{Addr: 0x52, W: []byte{0x10, 0x00}},
+			{Addr: 0x52, W: []byte{0x10, 0xf0}},
+			{Addr: 0x52, W: []byte{0x10, 0x01}},
+			{Addr: 0x52, W: []byte{0x12, 0x0e}},
+			{Addr: 0x52, W: []byte{0x4c}, R: []byte{0x05, 0x04, 0x06}},
+			{Addr: 0x52, W: []byte{0x10, 0x02}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatalf("Halt Error: %s", err)
+	}
+	if err := d.Reset(); err != nil {
+		t.Fatalf("Reset Error: %s", err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ens160_test
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"periph.io/x/periph/conn/i2c/i2creg"
+	"periph.io/x/periph/conn/physic"
+	"periph.io/x/periph/host"
+
+	"github.com/bcl/air-sensors/ens160"
+)
+
+func Example() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Open a handle to the first available I²C bus:
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer bus.Close()
+
+	d, err := ens160.New(bus)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer d.Halt() //nolint
+
+	// Compensate the readings, eg. with the temperature and humidity from an ENS210
+	env := physic.Env{
+		Temperature: physic.ZeroCelsius + 22*physic.Celsius,
+		Humidity:    45 * physic.PercentRH,
+	}
+	if err := d.CompensateFromEnv(env); err != nil {
+		log.Fatal(err)
+	}
+
+	for {
+		time.Sleep(time.Second)
+		r, err := d.ReadAirQuality()
+		if errors.Is(err, ens160.ErrNotReady) {
+			continue
+		} else if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("AQI : %d (%s)\nTVOC: %d ppb\nCO2 : %d ppm\n", r.AQI, r.Validity, r.TVOC, r.ECO2)
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ens160
+
+const (
+	// DefaultAddr is the I²C address of the ENS160 with its ADDR pin low
+	DefaultAddr uint16 = 0x52
+
+	// AltAddr is the I²C address of the ENS160 with its ADDR pin high
+	AltAddr uint16 = 0x53
+)
+
+// OpMode is the sensor's operating mode
+type OpMode uint8
+
+const (
+	// DeepSleep is the low power standby mode
+	DeepSleep OpMode = 0x00
+	// Idle is the low power mode, used to run commands
+	Idle OpMode = 0x01
+	// Standard measures every second
+	Standard OpMode = 0x02
+)
+
+// Option configures the Dev returned by New
+type Option func(*Dev)
+
+// WithAddress sets the I²C address of the sensor, the default is DefaultAddr
+func WithAddress(addr uint16) Option {
+	return func(d *Dev) {
+		d.addr = addr
+	}
+}
+
+// WithOpMode sets the operating mode that New leaves the sensor in, the default is
+// Standard
+func WithOpMode(mode OpMode) Option {
+	return func(d *Dev) {
+		d.mode = mode
+	}
+}
@@ -0,0 +1,18 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package ens210 controls a ScioSense ENS210 temperature and humidity sensor over
+// I²C.
+//
+// Sense makes a single shot measurement, or StartContinuous makes the sensor measure
+// continuously and Sense returns the latest measurement. Each reading is checked with
+// its CRC7 and valid flag.
+//
+// It is often on the same breakout board as the ENS160, whose readings can be
+// compensated with the ENS210's using ens160.CompensateFromEnv.
+//
+// Datasheet
+//
+// https://www.sciosense.com/wp-content/uploads/documents/SC-001777-DS-4-ENS210-Datasheet.pdf
+package ens210
@@ -0,0 +1,198 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ens210
+
+import (
+	"fmt"
+	"time"
+
+	"periph.io/x/periph/conn"
+	"periph.io/x/periph/conn/i2c"
+	"periph.io/x/periph/conn/physic"
+)
+
+// Addr is the I²C address of the ENS210, it cannot be changed
+const Addr uint16 = 0x43
+
+// PartID is the value of the ENS210's PART_ID register
+const PartID uint16 = 0x0210
+
+// ConversionTime is the time a single shot temperature and humidity measurement takes
+const ConversionTime = 130 * time.Millisecond
+
+// ENS210 registers from the datasheet, multi-byte values are little endian
+const (
+	regPartID    uint8 = 0x00 // Part ID, 2 bytes
+	regUID       uint8 = 0x04 // Unique ID, 8 bytes
+	regSysCtrl   uint8 = 0x10 // Reset and low power
+	regSysStat   uint8 = 0x11 // Active or standby
+	regSensRun   uint8 = 0x21 // Single shot or continuous, followed by regSensStart
+	regSensStop  uint8 = 0x23 // Stops continuous measurements
+	regTVal      uint8 = 0x30 // Temperature, followed by the humidity, 3 bytes each
+	sysCtrlReset uint8 = 0x80 // Resets the sensor when written to regSysCtrl
+	sysCtrlLowPw uint8 = 0x01 // Enter standby when not measuring
+	sysActive    uint8 = 0x01 // The sensor is active
+	sensTH       uint8 = 0x03 // Both the temperature and the humidity sensors
+)
+
+// Dev holds the connection to the ENS210
+type Dev struct {
+	i2c        conn.Conn // i2c device handle for the ens210
+	uid        uint64    // Unique ID
+	continuous bool      // Continuous measurements are running
+}
+
+var _ conn.Resource = &Dev{}
+
+// New returns an ENS210 device struct for communicating with the device
+//
+// It resets the sensor, checks the part ID, and reads the unique ID. The sensor is
+// left in its low power mode, entering standby when it is not measuring.
+func New(i i2c.Bus) (*Dev, error) {
+	d := &Dev{i2c: &i2c.Dev{Bus: i, Addr: Addr}}
+	if err := d.i2c.Tx([]byte{regSysCtrl, sysCtrlReset}, nil); err != nil {
+		return nil, fmt.Errorf("ens210: Error while resetting: %w", err)
+	}
+	time.Sleep(2 * time.Millisecond)
+
+	// The IDs can only be read while the sensor is active
+	if err := d.i2c.Tx([]byte{regSysCtrl, 0}, nil); err != nil {
+		return nil, fmt.Errorf("ens210: Error while setting active mode: %w", err)
+	}
+	if err := d.waitActive(); err != nil {
+		return nil, err
+	}
+	var id [2]byte
+	if err := d.i2c.Tx([]byte{regPartID}, id[:]); err != nil {
+		return nil, fmt.Errorf("ens210: Error while reading part ID: %w", err)
+	}
+	if pid := uint16(id[0]) | uint16(id[1])<<8; pid != PartID {
+		return nil, fmt.Errorf("ens210: ENS210 not found, part ID is 0x%04X", pid)
+	}
+	var uid [8]byte
+	if err := d.i2c.Tx([]byte{regUID}, uid[:]); err != nil {
+		return nil, fmt.Errorf("ens210: Error while reading unique ID: %w", err)
+	}
+	for i := 7; i >= 0; i-- {
+		d.uid = d.uid<<8 | uint64(uid[i])
+	}
+
+	if err := d.i2c.Tx([]byte{regSysCtrl, sysCtrlLowPw}, nil); err != nil {
+		return nil, fmt.Errorf("ens210: Error while setting low power mode: %w", err)
+	}
+	return d, nil
+}
+
+// String implements conn.Resource.
+func (d *Dev) String() string {
+	return fmt.Sprintf("ens210{%s}", d.i2c)
+}
+
+// Halt implements conn.Resource.
+//
+// It stops the continuous measurements.
+func (d *Dev) Halt() error {
+	if !d.continuous {
+		return nil
+	}
+	return d.StopContinuous()
+}
+
+// UID returns the sensor's unique ID
+func (d *Dev) UID() uint64 {
+	return d.uid
+}
+
+// StartContinuous makes the sensor measure continuously, Sense returns the latest
+// measurement. The first one is ready after ConversionTime.
+func (d *Dev) StartContinuous() error {
+	if err := d.i2c.Tx([]byte{regSensRun, sensTH, sensTH}, nil); err != nil {
+		return fmt.Errorf("ens210: Error while starting continuous measurements: %w", err)
+	}
+	d.continuous = true
+	return nil
+}
+
+// StopContinuous stops the continuous measurements
+func (d *Dev) StopContinuous() error {
+	if err := d.i2c.Tx([]byte{regSensStop, sensTH}, nil); err != nil {
+		return fmt.Errorf("ens210: Error while stopping continuous measurements: %w", err)
+	}
+	d.continuous = false
+	return nil
+}
+
+// Sense returns the temperature and relative humidity
+//
+// It makes a single shot measurement, and waits ConversionTime for it to finish,
+// unless continuous measurements are running, then it returns the latest measurement.
+func (d *Dev) Sense(env *physic.Env) error {
+	if !d.continuous {
+		if err := d.i2c.Tx([]byte{regSensRun, 0, sensTH}, nil); err != nil {
+			return fmt.Errorf("ens210: Error while starting measurement: %w", err)
+		}
+		time.Sleep(ConversionTime)
+	}
+
+	var data [6]byte
+	if err := d.i2c.Tx([]byte{regTVal}, data[:]); err != nil {
+		return fmt.Errorf("ens210: Error while reading measurement: %w", err)
+	}
+	t, err := value("temperature", data[0:3])
+	if err != nil {
+		return err
+	}
+	h, err := value("humidity", data[3:6])
+	if err != nil {
+		return err
+	}
+	// The temperature is in 1/64 K, and the humidity is in 1/512 %rH
+	env.Temperature = physic.Temperature(t) * physic.Kelvin / 64
+	env.Humidity = physic.RelativeHumidity(int64(h) * int64(physic.PercentRH) / 512)
+	return nil
+}
+
+// value checks the CRC7 and valid flag of a 3 byte reading, and returns its value
+func value(name string, data []byte) (uint16, error) {
+	v := uint32(data[0]) | uint32(data[1])<<8 | uint32(data[2])<<16
+	if crc7(v&0x1ffff) != uint8(v>>17) {
+		return 0, fmt.Errorf("ens210: %s CRC7 failed on: %v", name, data)
+	}
+	if v&0x10000 == 0 {
+		return 0, fmt.Errorf("ens210: %s is not valid", name)
+	}
+	return uint16(v), nil
+}
+
+// crc7 returns the CRC7 of the 17 bits of data and valid flag, using the polynomial
+// x^7+x^3+1 with an initial value of 0x7F
+func crc7(val uint32) uint8 {
+	const width = 7
+	pol := uint32(0x89) << (17 - 1)
+	bit := uint32(1) << (16 + width)
+	val = val<<width | 0x7f
+	for ; bit&(0x1ffff<<width) != 0; bit >>= 1 {
+		if bit&val != 0 {
+			val ^= pol
+		}
+		pol >>= 1
+	}
+	return uint8(val)
+}
+
+// waitActive waits for the sensor to leave standby
+func (d *Dev) waitActive() error {
+	for i := 0; i < 10; i++ {
+		var stat [1]byte
+		if err := d.i2c.Tx([]byte{regSysStat}, stat[:]); err != nil {
+			return fmt.Errorf("ens210: Error while reading status: %w", err)
+		}
+		if stat[0]&sysActive != 0 {
+			return nil
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return fmt.Errorf("ens210: Timeout waiting for active mode")
+}
@@ -0,0 +1,156 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package ens210
+
+import (
+	"testing"
+
+	"periph.io/x/periph/conn/i2c/i2ctest"
+	"periph.io/x/periph/conn/physic"
+)
+
+var (
+	// 19082/64 K and 50%rH, with their CRC7
+	GoodData = []byte{0x8a, 0x4a, 0xd1, 0x00, 0x64, 0xc7}
+)
+
+func TestNew(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the IDs
+			{Addr: 0x43, W: []byte{0x10, 0x80}},
+			{Addr: 0x43, W: []byte{0x10, 0x00}},
+			{Addr: 0x43, W: []byte{0x11}, R: []byte{0x00}},
+			{Addr: 0x43, W: []byte{0x11}, R: []byte{0x01}},
+			{Addr: 0x43, W: []byte{0x00}, R: []byte{0x10, 0x02}},
+			{Addr: 0x43, W: []byte{0x04}, R: []byte{0x08, 0x07, 0x06, 0x05, 0x04, 0x03, 0x02, 0x01}},
+			{Addr: 0x43, W: []byte{0x10, 0x01}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if d.String() != "ens210{playback(67)}" {
+		t.Fatalf("String Error: %s", d.String())
+	}
+	if d.UID() != 0x0102030405060708 {
+		t.Errorf("UID Error: %016X", d.UID())
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWrongPartID(t *testing.T) {
+	bus := &i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x43, W: []byte{0x10, 0x80}},
+			{Addr: 0x43, W: []byte{0x10, 0x00}},
+			{Addr: 0x43, W: []byte{0x11}, R: []byte{0x01}},
+			{Addr: 0x43, W: []byte{0x00}, R: []byte{0x60, 0x01}},
+		},
+		DontPanic: true,
+	}
+	if _, err := New(bus); err == nil {
+		t.Fatal("Wrong part ID Error")
+	}
+}
+
+func TestCRC7(t *testing.T) {
+	for _, tt := range []struct {
+		val  uint32
+		want uint8
+	}{
+		{0x14a8a, 0x68},
+		{0x16400, 0x63},
+		{0x10000, 0x50},
+	} {
+		if crc := crc7(tt.val); crc != tt.want {
+			t.Errorf("crc7(0x%05x) Error: 0x%02x != 0x%02x", tt.val, crc, tt.want)
+		}
+	}
+}
+
+func TestSense(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the IDs
+			{Addr: 0x43, W: []byte{0x10, 0x80}},
+			{Addr: 0x43, W: []byte{0x10, 0x00}},
+			{Addr: 0x43, W: []byte{0x11}, R: []byte{0x00}},
+			{Addr: 0x43, W: []byte{0x11}, R: []byte{0x01}},
+			{Addr: 0x43, W: []byte{0x00}, R: []byte{0x10, 0x02}},
+			{Addr: 0x43, W: []byte{0x04}, R: []byte{0x08, 0x07, 0x06, 0x05, 0x04, 0x03, 0x02, 0x01}},
+			{Addr: 0x43, W: []byte{0x10, 0x01}},
+			{Addr: 0x43, W: []byte{0x21, 0x00, 0x03}},
+			{Addr: 0x43, W: []byte{0x30}, R: GoodData},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	var env physic.Env
+	if err := d.Sense(&env); err != nil {
+		t.Fatalf("Sense Error: %s", err)
+	}
+	if env.Temperature != 298156250*physic.MicroKelvin {
+		t.Errorf("Temperature Error: %s", env.Temperature)
+	}
+	if env.Humidity != 50*physic.PercentRH {
+		t.Errorf("Humidity Error: %s", env.Humidity)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSenseErrors(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the IDs
+			{Addr: 0x43, W: []byte{0x10, 0x80}},
+			{Addr: 0x43, W: []byte{0x10, 0x00}},
+			{Addr: 0x43, W: []byte{0x11}, R: []byte{0x00}},
+			{Addr: 0x43, W: []byte{0x11}, R: []byte{0x01}},
+			{Addr: 0x43, W: []byte{0x00}, R: []byte{0x10, 0x02}},
+			{Addr: 0x43, W: []byte{0x04}, R: []byte{0x08, 0x07, 0x06, 0x05, 0x04, 0x03, 0x02, 0x01}},
+			{Addr: 0x43, W: []byte{0x10, 0x01}},
+			{Addr: 0x43, W: []byte{0x21, 0x03, 0x03}},
+			// Bad temperature CRC
+			{Addr: 0x43, W: []byte{0x30}, R: []byte{0x8a, 0x4a, 0xd3, 0x00, 0x64, 0xc7}},
+			// Humidity is not valid
+			{Addr: 0x43, W: []byte{0x30}, R: []byte{0x8a, 0x4a, 0xd1, 0x00, 0x00, 0xfe}},
+			{Addr: 0x43, W: []byte{0x23, 0x03}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if err := d.StartContinuous(); err != nil {
+		t.Fatalf("StartContinuous Error: %s", err)
+	}
+	var env physic.Env
+	if err := d.Sense(&env); err == nil {
+		t.Error("CRC Error")
+	}
+	if err := d.Sense(&env); err == nil {
+		t.Error("Valid Error")
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatalf("Halt Error: %s", err)
+	}
+	if d.continuous {
+		t.Error("Halt continuous Error")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,15 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package hdc1080 controls a Texas Instruments HDC1080 temperature and humidity
+// sensor over I²C.
+//
+// The sensor is configured to measure the temperature and the humidity in one
+// acquisition. Its heater only runs while measuring, Heat makes repeated measurements
+// with the heater on to drive off condensation.
+//
+// Datasheet
+//
+// https://www.ti.com/lit/ds/symlink/hdc1080.pdf
+package hdc1080
@@ -0,0 +1,215 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package hdc1080
+
+import (
+	"fmt"
+	"time"
+
+	"periph.io/x/periph/conn"
+	"periph.io/x/periph/conn/i2c"
+	"periph.io/x/periph/conn/physic"
+)
+
+// Addr is the I²C address of the HDC1080, it cannot be changed
+const Addr uint16 = 0x40
+
+// IDs returned by the sensor
+const (
+	ManufacturerID uint16 = 0x5449 // Texas Instruments
+	DeviceID       uint16 = 0x1050 // HDC1080
+)
+
+// HDC1080 registers
+const (
+	regTemperature    uint8 = 0x00 // Temperature measurement, or both in acquisition mode
+	regConfig         uint8 = 0x02 // Configuration, only the upper byte is used
+	regSerialID       uint8 = 0xfb // First of 3 serial ID registers
+	regManufacturerID uint8 = 0xfe // Manufacturer ID
+	regDeviceID       uint8 = 0xff // Device ID
+)
+
+// Configuration register bits, in the upper byte
+const (
+	cfgReset      uint8 = 0x80 // Software reset, self clearing
+	cfgHeater     uint8 = 0x20 // Heater enabled
+	cfgMode       uint8 = 0x10 // Acquire the temperature and humidity in sequence
+	cfgLowBattery uint8 = 0x08 // The supply voltage is below 2.8V
+)
+
+// Dev holds the connection to the HDC1080
+type Dev struct {
+	i2c     conn.Conn          // i2c device handle for the hdc1080
+	tempRes TempResolution     // Temperature measurement resolution
+	rhRes   HumidityResolution // Humidity measurement resolution
+	heater  bool               // Heater is enabled
+	serial  uint64             // 41 bit serial ID
+}
+
+var _ conn.Resource = &Dev{}
+
+// New returns an HDC1080 device struct for communicating with the device
+//
+// It checks the manufacturer and device IDs, reads the serial ID, and configures the
+// sensor to acquire the temperature and humidity together.
+func New(i i2c.Bus, opts ...Option) (*Dev, error) {
+	d := &Dev{i2c: &i2c.Dev{Bus: i, Addr: Addr}}
+	for _, o := range opts {
+		o(d)
+	}
+
+	id, err := d.readReg(regManufacturerID)
+	if err != nil {
+		return nil, err
+	}
+	if id != ManufacturerID {
+		return nil, fmt.Errorf("hdc1080: Wrong manufacturer ID: 0x%04X", id)
+	}
+	if id, err = d.readReg(regDeviceID); err != nil {
+		return nil, err
+	}
+	if id != DeviceID {
+		return nil, fmt.Errorf("hdc1080: Wrong device ID: 0x%04X", id)
+	}
+
+	// The serial ID is 16 bits from 0xfb, 16 bits from 0xfc and the top 9 bits of 0xfd
+	for r := uint8(0); r < 3; r++ {
+		w, err := d.readReg(regSerialID + r)
+		if err != nil {
+			return nil, err
+		}
+		d.serial = d.serial<<16 | uint64(w)
+	}
+	d.serial >>= 7
+
+	if err := d.writeConfig(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// String implements conn.Resource.
+func (d *Dev) String() string {
+	return fmt.Sprintf("hdc1080{%s}", d.i2c)
+}
+
+// Halt implements conn.Resource.
+//
+// The HDC1080 sleeps after each measurement, so there is nothing to halt.
+func (d *Dev) Halt() error {
+	return nil
+}
+
+// SerialNumber returns the sensor's 41 bit serial ID
+func (d *Dev) SerialNumber() uint64 {
+	return d.serial
+}
+
+// Reset resets the sensor and restores the configuration
+func (d *Dev) Reset() error {
+	if err := d.i2c.Tx([]byte{regConfig, cfgReset, 0x00}, nil); err != nil {
+		return fmt.Errorf("hdc1080: Error while resetting: %w", err)
+	}
+	// Requires a 15ms delay after the reset
+	time.Sleep(15 * time.Millisecond)
+	return d.writeConfig()
+}
+
+// Sense measures the temperature and the relative humidity
+func (d *Dev) Sense(env *physic.Env) error {
+	// Writing the temperature register pointer starts the acquisition
+	if err := d.i2c.Tx([]byte{regTemperature}, nil); err != nil {
+		return fmt.Errorf("hdc1080: Error while requesting measurement: %w", err)
+	}
+	time.Sleep(d.conversionTime())
+	// Receive the temperature word and then the humidity word
+	var data [4]byte
+	if err := d.i2c.Tx(nil, data[:]); err != nil {
+		return fmt.Errorf("hdc1080: Error while reading measurement: %w", err)
+	}
+
+	// T = -40 + 165 * raw / 2^16, RH = 100 * raw / 2^16
+	t := int64(data[0])<<8 | int64(data[1])
+	env.Temperature = physic.ZeroCelsius - 40*physic.Celsius + physic.Temperature(t*165000/65536)*physic.MilliCelsius
+	rh := int64(data[2])<<8 | int64(data[3])
+	env.Humidity = physic.RelativeHumidity(rh * int64(100*physic.PercentRH) / 65536)
+	return nil
+}
+
+// SetHeater turns the heater on or off
+//
+// The heater only runs while the sensor is measuring, the readings made with it on
+// are not the ambient temperature and humidity.
+func (d *Dev) SetHeater(on bool) error {
+	d.heater = on
+	return d.writeConfig()
+}
+
+// Heat drives off condensation by measuring with the heater on for the duration,
+// making at least one measurement, and then turns the heater off.
+func (d *Dev) Heat(duration time.Duration) error {
+	if err := d.SetHeater(true); err != nil {
+		return err
+	}
+	var env physic.Env
+	for end := time.Now().Add(duration); ; {
+		if err := d.Sense(&env); err != nil {
+			d.SetHeater(false) //nolint
+			return err
+		}
+		if !time.Now().Before(end) {
+			break
+		}
+	}
+	return d.SetHeater(false)
+}
+
+// LowBattery returns true if the supply voltage is below 2.8V
+func (d *Dev) LowBattery() (bool, error) {
+	cfg, err := d.readReg(regConfig)
+	if err != nil {
+		return false, err
+	}
+	return uint8(cfg>>8)&cfgLowBattery != 0, nil
+}
+
+// conversionTime returns the time needed to measure the temperature and humidity
+// at the selected resolutions
+func (d *Dev) conversionTime() time.Duration {
+	t := 6350 * time.Microsecond
+	if d.tempRes == T11 {
+		t = 3650 * time.Microsecond
+	}
+	switch d.rhRes {
+	case RH11:
+		t += 3850 * time.Microsecond
+	case RH8:
+		t += 2500 * time.Microsecond
+	default:
+		t += 6500 * time.Microsecond
+	}
+	return t
+}
+
+// writeConfig writes the configuration register, the lower byte is reserved
+func (d *Dev) writeConfig() error {
+	cfg := cfgMode | uint8(d.tempRes) | uint8(d.rhRes)
+	if d.heater {
+		cfg |= cfgHeater
+	}
+	if err := d.i2c.Tx([]byte{regConfig, cfg, 0x00}, nil); err != nil {
+		return fmt.Errorf("hdc1080: Error while writing configuration: %w", err)
+	}
+	return nil
+}
+
+// readReg returns a 16 bit register
+func (d *Dev) readReg(reg uint8) (uint16, error) {
+	var data [2]byte
+	if err := d.i2c.Tx([]byte{reg}, data[:]); err != nil {
+		return 0, fmt.Errorf("hdc1080: Error while reading register 0x%02X: %w", reg, err)
+	}
+	return uint16(data[0])<<8 | uint16(data[1]), nil
+}
@@ -0,0 +1,177 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package hdc1080
+
+import (
+	"testing"
+	"time"
+
+	"periph.io/x/periph/conn/i2c/i2ctest"
+	"periph.io/x/periph/conn/physic"
+)
+
+func TestNew(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Check the IDs
+			{Addr: 0x40, W: []byte{0xfe}, R: []byte{0x54, 0x49}},
+			{Addr: 0x40, W: []byte{0xff}, R: []byte{0x10, 0x50}},
+			{Addr: 0x40, W: []byte{0xfb}, R: []byte{0x01, 0x23}},
+			{Addr: 0x40, W: []byte{0xfc}, R: []byte{0x45, 0x67}},
+			{Addr: 0x40, W: []byte{0xfd}, R: []byte{0x89, 0x80}},
+			{Addr: 0x40, W: []byte{0x02, 0x10, 0x00}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if d.String() != "hdc1080{playback(64)}" {
+		t.Fatalf("String Error: %s", d.String())
+	}
+	if d.SerialNumber() != 0x2468acf13 {
+		t.Errorf("SerialNumber Error: 0x%X", d.SerialNumber())
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewWrongID(t *testing.T) {
+	bus := &i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x40, W: []byte{0xfe}, R: []byte{0x54, 0x49}},
+			{Addr: 0x40, W: []byte{0xff}, R: []byte{0x10, 0x00}},
+		},
+		DontPanic: true,
+	}
+	if _, err := New(bus); err == nil {
+		t.Fatal("Device ID Error")
+	}
+}
+
+func TestResolution(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Check the IDs
+			{Addr: 0x40, W: []byte{0xfe}, R: []byte{0x54, 0x49}},
+			{Addr: 0x40, W: []byte{0xff}, R: []byte{0x10, 0x50}},
+			{Addr: 0x40, W: []byte{0xfb}, R: []byte{0x01, 0x23}},
+			{Addr: 0x40, W: []byte{0xfc}, R: []byte{0x45, 0x67}},
+			{Addr: 0x40, W: []byte{0xfd}, R: []byte{0x89, 0x80}},
+			{Addr: 0x40, W: []byte{0x02, 0x16, 0x00}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus, WithResolution(T11, RH8))
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if d.conversionTime() != 6150*time.Microsecond {
+		t.Errorf("Conversion time Error: %s", d.conversionTime())
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSense(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Check the IDs
+			{Addr: 0x40, W: []byte{0xfe}, R: []byte{0x54, 0x49}},
+			{Addr: 0x40, W: []byte{0xff}, R: []byte{0x10, 0x50}},
+			{Addr: 0x40, W: []byte{0xfb}, R: []byte{0x01, 0x23}},
+			{Addr: 0x40, W: []byte{0xfc}, R: []byte{0x45, 0x67}},
+			{Addr: 0x40, W: []byte{0xfd}, R: []byte{0x89, 0x80}},
+			{Addr: 0x40, W: []byte{0x02, 0x10, 0x00}},
+			{Addr: 0x40, W: []byte{0x00}},
+			{Addr: 0x40, W: []byte{}, R: []byte{0x6a, 0x3c, 0x7c, 0x80}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	var env physic.Env
+	if err := d.Sense(&env); err != nil {
+		t.Fatalf("Sense Error: %s", err)
+	}
+	if env.Humidity != 4863281*physic.TenthMicroRH {
+		t.Errorf("Humidity Error: %s", env.Humidity)
+	}
+	if env.Temperature != physic.ZeroCelsius+28471*physic.MilliCelsius {
+		t.Errorf("Temperature Error: %s", env.Temperature)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHeat(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Check the IDs
+			{Addr: 0x40, W: []byte{0xfe}, R: []byte{0x54, 0x49}},
+			{Addr: 0x40, W: []byte{0xff}, R: []byte{0x10, 0x50}},
+			{Addr: 0x40, W: []byte{0xfb}, R: []byte{0x01, 0x23}},
+			{Addr: 0x40, W: []byte{0xfc}, R: []byte{0x45, 0x67}},
+			{Addr: 0x40, W: []byte{0xfd}, R: []byte{0x89, 0x80}},
+			{Addr: 0x40, W: []byte{0x02, 0x10, 0x00}},
+			// Heater on, measure, heater off
+			{Addr: 0x40, W: []byte{0x02, 0x30, 0x00}},
+			{Addr: 0x40, W: []byte{0x00}},
+			{Addr: 0x40, W: []byte{}, R: []byte{0x6a, 0x3c, 0x7c, 0x80}},
+			{Addr: 0x40, W: []byte{0x02, 0x10, 0x00}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if err := d.Heat(0); err != nil {
+		t.Fatalf("Heat Error: %s", err)
+	}
+	if d.heater {
+		t.Error("Heater still on Error")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLowBatteryReset(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Check the IDs
+			{Addr: 0x40, W: []byte{0xfe}, R: []byte{0x54, 0x49}},
+			{Addr: 0x40, W: []byte{0xff}, R: []byte{0x10, 0x50}},
+			{Addr: 0x40, W: []byte{0xfb}, R: []byte{0x01, 0x23}},
+			{Addr: 0x40, W: []byte{0xfc}, R: []byte{0x45, 0x67}},
+			{Addr: 0x40, W: []byte{0xfd}, R: []byte{0x89, 0x80}},
+			{Addr: 0x40, W: []byte{0x02, 0x10, 0x00}},
+			{Addr: 0x40, W: []byte{0x02}, R: []byte{0x18, 0x00}},
+			{Addr: 0x40, W: []byte{0x02, 0x80, 0x00}},
+			{Addr: 0x40, W: []byte{0x02, 0x10, 0x00}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if low, err := d.LowBattery(); err != nil || !low {
+		t.Errorf("LowBattery Error: %v %s", low, err)
+	}
+	if err := d.Reset(); err != nil {
+		t.Fatalf("Reset Error: %s", err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package hdc1080
+
+// TempResolution selects the temperature measurement resolution
+type TempResolution uint8
+
+const (
+	// T14 measures the temperature with 14 bits, the default
+	T14 TempResolution = 0x00
+	// T11 measures the temperature with 11 bits
+	T11 TempResolution = 0x04
+)
+
+// HumidityResolution selects the humidity measurement resolution
+type HumidityResolution uint8
+
+const (
+	// RH14 measures the humidity with 14 bits, the default
+	RH14 HumidityResolution = 0x00
+	// RH11 measures the humidity with 11 bits
+	RH11 HumidityResolution = 0x01
+	// RH8 measures the humidity with 8 bits
+	RH8 HumidityResolution = 0x02
+)
+
+// Option configures the Dev returned by New
+type Option func(*Dev)
+
+// WithResolution sets the temperature and humidity measurement resolutions, the
+// default is T14 and RH14
+func WithResolution(t TempResolution, rh HumidityResolution) Option {
+	return func(d *Dev) {
+		d.tempRes = t
+		d.rhRes = rh
+	}
+}
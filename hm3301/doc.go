@@ -0,0 +1,15 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package hm3301 controls a Seeed HM3301 laser particulate matter sensor over I²C.
+//
+// The sensor returns a 29 byte frame with the PM1.0, PM2.5 and PM10 concentrations,
+// in standard particle and atmospheric environment units, and the particle counts,
+// like the Plantower sensors. It uses the same I²C address, 0x40, as the HTU21D,
+// Si7021, and HDC1080 so it cannot share a bus with them without a multiplexer.
+//
+// Datasheet
+//
+// https://files.seeedstudio.com/wiki/Grove-Laser_PM2.5_Sensor-HM3301/res/HM-3300%263600_V2.1.pdf
+package hm3301
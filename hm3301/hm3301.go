@@ -0,0 +1,119 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package hm3301
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"periph.io/x/periph/conn"
+	"periph.io/x/periph/conn/i2c"
+)
+
+// Addr is the I²C address of the HM3301, it cannot be changed
+const Addr uint16 = 0x40
+
+// cmdSelectI2C switches the sensor to I²C mode
+const cmdSelectI2C byte = 0x88
+
+// frameSize is the size of the frame read from the sensor, including the checksum
+const frameSize = 29
+
+// ErrChecksum is returned when the frame's checksum does not match
+var ErrChecksum = errors.New("hm3301: Bad checksum")
+
+// Results holds the readings from the HM3301
+type Results struct {
+	CfPm1        uint16    `json:"cf_pm1"`        // PM1.0 in μg/m3 standard particle
+	CfPm2_5      uint16    `json:"cf_pm2_5"`      // PM2.5 in μg/m3 standard particle
+	CfPm10       uint16    `json:"cf_pm10"`       // PM10 in μg/m3 standard particle
+	EnvPm1       uint16    `json:"env_pm1"`       // PM1.0 in μg/m3 atmospheric environment
+	EnvPm2_5     uint16    `json:"env_pm2_5"`     // PM2.5 in μg/m3 atmospheric environment
+	EnvPm10      uint16    `json:"env_pm10"`      // PM10 in μg/m3 atmospheric environment
+	Cnt0_3       uint16    `json:"cnt0_3"`        // Count of particles > 0.3μm in 1L of air
+	Cnt0_5       uint16    `json:"cnt0_5"`        // Count of particles > 0.5μm in 1L of air
+	Cnt1         uint16    `json:"cnt1"`          // Count of particles > 1.0μm in 1L of air
+	Cnt2_5       uint16    `json:"cnt2_5"`        // Count of particles > 2.5μm in 1L of air
+	Cnt5         uint16    `json:"cnt5"`          // Count of particles > 5.0μm in 1L of air
+	Cnt10        uint16    `json:"cnt10"`         // Count of particles > 10.0μm in 1L of air
+	SensorNumber uint16    `json:"sensor_number"` // Sensor number
+	Timestamp    time.Time `json:"timestamp"`     // When the frame was read
+}
+
+// Dev holds the connection to the HM3301
+type Dev struct {
+	i2c conn.Conn // i2c device handle for the hm3301
+}
+
+var _ conn.Resource = &Dev{}
+
+// New returns a HM3301 device struct for communicating with the device
+//
+// It switches the sensor to I²C mode.
+func New(i i2c.Bus) (*Dev, error) {
+	d := &Dev{i2c: &i2c.Dev{Bus: i, Addr: Addr}}
+	if err := d.i2c.Tx([]byte{cmdSelectI2C}, nil); err != nil {
+		return nil, fmt.Errorf("hm3301: Error while selecting I²C mode: %w", err)
+	}
+	return d, nil
+}
+
+// String implements conn.Resource.
+func (d *Dev) String() string {
+	return fmt.Sprintf("hm3301{%s}", d.i2c)
+}
+
+// Halt implements conn.Resource.
+//
+// The HM3301 measures continuously and cannot be halted.
+func (d *Dev) Halt() error {
+	return nil
+}
+
+// ReadSensor returns the latest readings, or ErrChecksum if the frame is corrupt
+func (d *Dev) ReadSensor() (Results, error) {
+	var data [frameSize]byte
+	if err := d.i2c.Tx(nil, data[:]); err != nil {
+		return Results{}, fmt.Errorf("hm3301: Error while reading the sensor: %w", err)
+	}
+	return parse(data)
+}
+
+// parse returns the readings from a frame
+//
+// The frame is 2 reserved bytes, the sensor number, the 12 readings as 16 bit big
+// endian words, and the 8 bit sum of the other bytes.
+func parse(data [frameSize]byte) (Results, error) {
+	var sum byte
+	for _, b := range data[:frameSize-1] {
+		sum += b
+	}
+	if sum != data[frameSize-1] {
+		return Results{}, ErrChecksum
+	}
+
+	return Results{
+		SensorNumber: word(data[:], 2),
+		CfPm1:        word(data[:], 4),
+		CfPm2_5:      word(data[:], 6),
+		CfPm10:       word(data[:], 8),
+		EnvPm1:       word(data[:], 10),
+		EnvPm2_5:     word(data[:], 12),
+		EnvPm10:      word(data[:], 14),
+		Cnt0_3:       word(data[:], 16),
+		Cnt0_5:       word(data[:], 18),
+		Cnt1:         word(data[:], 20),
+		Cnt2_5:       word(data[:], 22),
+		Cnt5:         word(data[:], 24),
+		Cnt10:        word(data[:], 26),
+		Timestamp:    time.Now(),
+	}, nil
+}
+
+// word returns 16 bits from the byte stream, starting at index i
+func word(data []byte, i int) uint16 {
+	return uint16(data[i])<<8 + uint16(data[i+1])
+}
@@ -0,0 +1,83 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package hm3301
+
+import (
+	"errors"
+	"testing"
+
+	"periph.io/x/periph/conn/i2c/i2ctest"
+)
+
+var (
+	GoodSensorData = []byte{
+		0x00, 0x00, 0x00, 0x01, 0x00, 0x05, 0x00, 0x08, 0x00, 0x0a, 0x00, 0x04, 0x00, 0x07,
+		0x00, 0x09, 0x03, 0xe8, 0x01, 0x2c, 0x00, 0x50, 0x00, 0x0c, 0x00, 0x03, 0x00, 0x01,
+		0xa4,
+	}
+	BadChecksumSensorData = []byte{
+		0x00, 0x00, 0x00, 0x01, 0x00, 0x05, 0x00, 0x08, 0x00, 0x0a, 0x00, 0x04, 0x00, 0x07,
+		0x00, 0x09, 0x03, 0xe8, 0x01, 0x2c, 0x00, 0x50, 0x00, 0x0c, 0x00, 0x03, 0x00, 0x01,
+		0xa5,
+	}
+)
+
+func TestNew(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Select I²C mode
+			{Addr: 0x40, W: []byte{0x88}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if d.String() != "hm3301{playback(64)}" {
+		t.Fatalf("String Error: %s", d.String())
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadSensor(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Select I²C mode
+			{Addr: 0x40, W: []byte{0x88}},
+			{Addr: 0x40, W: []byte{}, R: GoodSensorData},
+			{Addr: 0x40, W: []byte{}, R: BadChecksumSensorData},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	r, err := d.ReadSensor()
+	if err != nil {
+		t.Fatalf("ReadSensor Error: %s", err)
+	}
+	if r.SensorNumber != 1 {
+		t.Errorf("SensorNumber Error: %d", r.SensorNumber)
+	}
+	if r.CfPm1 != 5 || r.CfPm2_5 != 8 || r.CfPm10 != 10 {
+		t.Errorf("Standard particle Error: %#v", r)
+	}
+	if r.EnvPm1 != 4 || r.EnvPm2_5 != 7 || r.EnvPm10 != 9 {
+		t.Errorf("Atmospheric environment Error: %#v", r)
+	}
+	if r.Cnt0_3 != 1000 || r.Cnt0_5 != 300 || r.Cnt1 != 80 || r.Cnt2_5 != 12 || r.Cnt5 != 3 || r.Cnt10 != 1 {
+		t.Errorf("Counts Error: %#v", r)
+	}
+	if _, err := d.ReadSensor(); !errors.Is(err, ErrChecksum) {
+		t.Errorf("Checksum Error: %v", err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,16 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package htu21d controls a TE Connectivity HTU21D temperature and humidity sensor
+// over I²C.
+//
+// The measurements are made in no hold master mode by default, polling for the
+// result after the conversion time, or in hold master mode with WithHoldMaster, where
+// the sensor stretches the clock until the result is ready. The resolution is set in
+// the user register with WithResolution or SetResolution.
+//
+// Datasheet
+//
+// https://www.te.com/commerce/DocumentDelivery/DDEController?Action=showdoc&DocId=Data+Sheet%7FHPC199_6%7FA6%7Fpdf%7FEnglish%7FENG_DS_HPC199_6_A6.pdf
+package htu21d
@@ -0,0 +1,199 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package htu21d
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sigurn/crc8"
+	"periph.io/x/periph/conn"
+	"periph.io/x/periph/conn/i2c"
+	"periph.io/x/periph/conn/physic"
+)
+
+// Addr is the I²C address of the HTU21D, it cannot be changed
+const Addr uint16 = 0x40
+
+// HTU21D commands from the datasheet
+const (
+	cmdTempHold    uint8 = 0xe3 // Measure the temperature, hold master mode
+	cmdRHHold      uint8 = 0xe5 // Measure the humidity, hold master mode
+	cmdTempNoHold  uint8 = 0xf3 // Measure the temperature, no hold master mode
+	cmdRHNoHold    uint8 = 0xf5 // Measure the humidity, no hold master mode
+	cmdWriteUser   uint8 = 0xe6 // Write the user register
+	cmdReadUser    uint8 = 0xe7 // Read the user register
+	cmdSoftReset   uint8 = 0xfe // Resets the sensor
+	userResolution uint8 = 0x81 // Resolution bits in the user register
+	userLowBattery uint8 = 0x40 // The supply voltage is below 2.25V
+)
+
+var (
+	crc8htu21d = crc8.MakeTable(crc8.Params{
+		Poly:   0x31,
+		Init:   0x00,
+		RefIn:  false,
+		RefOut: false,
+		XorOut: 0x00,
+		Check:  0xA2,
+		Name:   "CRC-8/HTU21D",
+	})
+)
+
+// Dev holds the connection to the HTU21D
+type Dev struct {
+	i2c        conn.Conn  // i2c device handle for the htu21d
+	resolution Resolution // Measurement resolution
+	hold       bool       // Use hold master mode
+}
+
+var _ conn.Resource = &Dev{}
+
+// New returns an HTU21D device struct for communicating with the device
+//
+// It sets the resolution in the user register.
+func New(i i2c.Bus, opts ...Option) (*Dev, error) {
+	d := &Dev{i2c: &i2c.Dev{Bus: i, Addr: Addr}}
+	for _, o := range opts {
+		o(d)
+	}
+	if err := d.SetResolution(d.resolution); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// String implements conn.Resource.
+func (d *Dev) String() string {
+	return fmt.Sprintf("htu21d{%s}", d.i2c)
+}
+
+// Halt implements conn.Resource.
+//
+// The HTU21D sleeps after each measurement, so there is nothing to halt.
+func (d *Dev) Halt() error {
+	return nil
+}
+
+// SoftReset resets the sensor, which resets the resolution to RH12T14
+func (d *Dev) SoftReset() error {
+	if err := d.i2c.Tx([]byte{cmdSoftReset}, nil); err != nil {
+		return fmt.Errorf("htu21d: Error while resetting: %w", err)
+	}
+	// Requires a 15ms delay after the reset
+	time.Sleep(15 * time.Millisecond)
+	d.resolution = RH12T14
+	return nil
+}
+
+// SetResolution sets the measurement resolution in the user register
+func (d *Dev) SetResolution(r Resolution) error {
+	if uint8(r)&^userResolution != 0 {
+		return fmt.Errorf("htu21d: Invalid resolution: 0x%02X", uint8(r))
+	}
+	user, err := d.readUser()
+	if err != nil {
+		return err
+	}
+	user = user&^userResolution | uint8(r)
+	if err := d.i2c.Tx([]byte{cmdWriteUser, user}, nil); err != nil {
+		return fmt.Errorf("htu21d: Error while writing user register: %w", err)
+	}
+	d.resolution = r
+	return nil
+}
+
+// LowBattery returns true if the supply voltage is below 2.25V
+func (d *Dev) LowBattery() (bool, error) {
+	user, err := d.readUser()
+	if err != nil {
+		return false, err
+	}
+	return user&userLowBattery != 0, nil
+}
+
+// Sense measures the temperature and the relative humidity
+func (d *Dev) Sense(env *physic.Env) error {
+	t, err := d.measure("temperature", cmdTempHold, cmdTempNoHold, d.tempTime())
+	if err != nil {
+		return err
+	}
+	h, err := d.measure("humidity", cmdRHHold, cmdRHNoHold, d.rhTime())
+	if err != nil {
+		return err
+	}
+
+	// T = -46.85 + 175.72 * raw / 2^16
+	env.Temperature = physic.ZeroCelsius - 46850*physic.MilliCelsius + physic.Temperature(int64(t)*175720/65536)*physic.MilliCelsius
+
+	// RH = -6 + 125 * raw / 2^16, clamped to 0-100%
+	humidity := physic.RelativeHumidity(int64(h)*int64(125*physic.PercentRH)/65536) - 6*physic.PercentRH
+	if humidity < 0 {
+		humidity = 0
+	} else if humidity > 100*physic.PercentRH {
+		humidity = 100 * physic.PercentRH
+	}
+	env.Humidity = humidity
+	return nil
+}
+
+// measure makes a measurement, in hold or no hold master mode, and returns the raw
+// value with the status bits cleared
+func (d *Dev) measure(name string, hold, noHold uint8, wait time.Duration) (uint16, error) {
+	var data [3]byte
+	if d.hold {
+		if err := d.i2c.Tx([]byte{hold}, data[:]); err != nil {
+			return 0, fmt.Errorf("htu21d: Error while measuring %s: %w", name, err)
+		}
+	} else {
+		if err := d.i2c.Tx([]byte{noHold}, nil); err != nil {
+			return 0, fmt.Errorf("htu21d: Error while requesting %s: %w", name, err)
+		}
+		time.Sleep(wait)
+		if err := d.i2c.Tx(nil, data[:]); err != nil {
+			return 0, fmt.Errorf("htu21d: Error while reading %s: %w", name, err)
+		}
+	}
+	if crc8.Checksum(data[0:2], crc8htu21d) != data[2] {
+		return 0, fmt.Errorf("htu21d: %s CRC8 failed on: %v", name, data)
+	}
+	// The lower 2 bits are status bits
+	return (uint16(data[0])<<8 | uint16(data[1])) &^ 0x03, nil
+}
+
+// tempTime returns the maximum temperature conversion time for the resolution
+func (d *Dev) tempTime() time.Duration {
+	switch d.resolution {
+	case RH8T12:
+		return 13 * time.Millisecond
+	case RH10T13:
+		return 25 * time.Millisecond
+	case RH11T11:
+		return 7 * time.Millisecond
+	}
+	return 50 * time.Millisecond
+}
+
+// rhTime returns the maximum humidity conversion time for the resolution
+func (d *Dev) rhTime() time.Duration {
+	switch d.resolution {
+	case RH8T12:
+		return 3 * time.Millisecond
+	case RH10T13:
+		return 5 * time.Millisecond
+	case RH11T11:
+		return 8 * time.Millisecond
+	}
+	return 16 * time.Millisecond
+}
+
+// readUser returns the user register
+func (d *Dev) readUser() (uint8, error) {
+	var user [1]byte
+	if err := d.i2c.Tx([]byte{cmdReadUser}, user[:]); err != nil {
+		return 0, fmt.Errorf("htu21d: Error while reading user register: %w", err)
+	}
+	return user[0], nil
+}
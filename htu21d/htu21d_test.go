@@ -0,0 +1,178 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package htu21d
+
+import (
+	"testing"
+	"time"
+
+	"periph.io/x/periph/conn/i2c/i2ctest"
+	"periph.io/x/periph/conn/physic"
+)
+
+var ()
+
+func TestNew(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Set the resolution
+			{Addr: 0x40, W: []byte{0xe7}, R: []byte{0x02}},
+			{Addr: 0x40, W: []byte{0xe6, 0x02}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if d.String() != "htu21d{playback(64)}" {
+		t.Fatalf("String Error: %s", d.String())
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResolution(t *testing.T) {
+	bus := &i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x40, W: []byte{0xe7}, R: []byte{0x02}},
+			{Addr: 0x40, W: []byte{0xe6, 0x83}},
+			{Addr: 0x40, W: []byte{0xe7}, R: []byte{0x83}},
+			{Addr: 0x40, W: []byte{0xe6, 0x82}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(bus, WithResolution(RH11T11))
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if d.tempTime() != 7*time.Millisecond || d.rhTime() != 8*time.Millisecond {
+		t.Errorf("Conversion time Error: %s %s", d.tempTime(), d.rhTime())
+	}
+	if err := d.SetResolution(RH10T13); err != nil {
+		t.Fatalf("SetResolution Error: %s", err)
+	}
+	if err := d.SetResolution(0x02); err == nil {
+		t.Error("Invalid resolution Error")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSenseNoHold(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Set the resolution
+			{Addr: 0x40, W: []byte{0xe7}, R: []byte{0x02}},
+			{Addr: 0x40, W: []byte{0xe6, 0x02}},
+			{Addr: 0x40, W: []byte{0xf3}},
+			{Addr: 0x40, W: []byte{}, R: []byte{0x6a, 0x3c, 0x03}},
+			{Addr: 0x40, W: []byte{0xf5}},
+			{Addr: 0x40, W: []byte{}, R: []byte{0x7c, 0x82, 0x97}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	var env physic.Env
+	if err := d.Sense(&env); err != nil {
+		t.Fatalf("Sense Error: %s", err)
+	}
+	if env.Humidity != 5479101*physic.TenthMicroRH {
+		t.Errorf("Humidity Error: %s", env.Humidity)
+	}
+	if env.Temperature != physic.ZeroCelsius+26069*physic.MilliCelsius {
+		t.Errorf("Temperature Error: %s", env.Temperature)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSenseHold(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Set the resolution
+			{Addr: 0x40, W: []byte{0xe7}, R: []byte{0x02}},
+			{Addr: 0x40, W: []byte{0xe6, 0x02}},
+			{Addr: 0x40, W: []byte{0xe3}, R: []byte{0x6a, 0x3c, 0x03}},
+			{Addr: 0x40, W: []byte{0xe5}, R: []byte{0x7c, 0x82, 0x97}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus, WithHoldMaster())
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	var env physic.Env
+	if err := d.Sense(&env); err != nil {
+		t.Fatalf("Sense Error: %s", err)
+	}
+	if env.Humidity != 5479101*physic.TenthMicroRH {
+		t.Errorf("Humidity Error: %s", env.Humidity)
+	}
+	if env.Temperature != physic.ZeroCelsius+26069*physic.MilliCelsius {
+		t.Errorf("Temperature Error: %s", env.Temperature)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSenseCRC(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Set the resolution
+			{Addr: 0x40, W: []byte{0xe7}, R: []byte{0x02}},
+			{Addr: 0x40, W: []byte{0xe6, 0x02}},
+			{Addr: 0x40, W: []byte{0xe3}, R: []byte{0x6a, 0x3c, 0x04}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus, WithHoldMaster())
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	var env physic.Env
+	if err := d.Sense(&env); err == nil {
+		t.Fatal("CRC Error")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLowBatteryReset(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Set the resolution
+			{Addr: 0x40, W: []byte{0xe7}, R: []byte{0x02}},
+			{Addr: 0x40, W: []byte{0xe6, 0x02}},
+			{Addr: 0x40, W: []byte{0xe7}, R: []byte{0x43}},
+			{Addr: 0x40, W: []byte{0xfe}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if low, err := d.LowBattery(); err != nil || !low {
+		t.Errorf("LowBattery Error: %v %s", low, err)
+	}
+	if err := d.SoftReset(); err != nil {
+		t.Fatalf("SoftReset Error: %s", err)
+	}
+	if d.resolution != RH12T14 {
+		t.Errorf("SoftReset resolution Error: 0x%02X", uint8(d.resolution))
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
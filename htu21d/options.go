@@ -0,0 +1,39 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package htu21d
+
+// Resolution selects the humidity and temperature measurement resolutions, higher
+// resolutions take longer
+type Resolution uint8
+
+const (
+	// RH12T14 measures the humidity with 12 bits and the temperature with 14 bits
+	RH12T14 Resolution = 0x00
+	// RH8T12 measures the humidity with 8 bits and the temperature with 12 bits
+	RH8T12 Resolution = 0x01
+	// RH10T13 measures the humidity with 10 bits and the temperature with 13 bits
+	RH10T13 Resolution = 0x80
+	// RH11T11 measures the humidity with 11 bits and the temperature with 11 bits
+	RH11T11 Resolution = 0x81
+)
+
+// Option configures the Dev returned by New
+type Option func(*Dev)
+
+// WithResolution sets the measurement resolution, the default is RH12T14
+func WithResolution(r Resolution) Option {
+	return func(d *Dev) {
+		d.resolution = r
+	}
+}
+
+// WithHoldMaster makes the measurements in hold master mode, the sensor stretches the
+// clock until the measurement is finished. The I²C bus needs to support clock
+// stretching, which the Raspberry Pi's does not do reliably.
+func WithHoldMaster() Option {
+	return func(d *Dev) {
+		d.hold = true
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package atomicfile
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "atomicfile.")
+	if err != nil {
+		t.Fatalf("TempDir Error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "baseline")
+	for _, data := range [][]byte{{0x01, 0x02, 0x03}, {0x04, 0x05}} {
+		if err := WriteFile(path, data, 0644); err != nil {
+			t.Fatalf("WriteFile Error: %s", err)
+		}
+		if b, err := ioutil.ReadFile(path); err != nil || !bytes.Equal(b, data) {
+			t.Errorf("ReadFile Error: %v %v", b, err)
+		}
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat Error: %s", err)
+	}
+	if fi.Mode().Perm() != 0644 {
+		t.Errorf("Permissions Error: %s", fi.Mode())
+	}
+	// Only the file is left, the temporary files are removed
+	if files, err := ioutil.ReadDir(dir); err != nil || len(files) != 1 {
+		t.Errorf("Temporary file Error: %d %v", len(files), err)
+	}
+
+	if err := WriteFile(filepath.Join(dir, "missing", "baseline"), nil, 0644); err == nil {
+		t.Error("Missing directory Error")
+	}
+}
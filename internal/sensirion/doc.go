@@ -0,0 +1,10 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package sensirion holds the CRC8 and command helpers shared by the drivers for
+// Sensirion's I²C sensors.
+//
+// The sensors use 16 bit commands, and send and receive 16 bit words that are each
+// followed by a CRC8 of the word.
+package sensirion
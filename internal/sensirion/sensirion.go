@@ -0,0 +1,110 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sensirion
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sigurn/crc8"
+	"periph.io/x/periph/conn"
+)
+
+var (
+	crc8sensirion = crc8.MakeTable(crc8.Params{
+		Poly:   0x31,
+		Init:   0xFF,
+		RefIn:  false,
+		RefOut: false,
+		XorOut: 0x00,
+		Check:  0xF7,
+		Name:   "CRC-8/Sensirion",
+	})
+)
+
+// CRC8 returns the CRC8 of the data
+func CRC8(data []byte) uint8 {
+	return crc8.Checksum(data, crc8sensirion)
+}
+
+// CheckCRC8 returns true if the 2 data bytes match the CRC8 in the 3rd byte
+func CheckCRC8(data []byte) bool {
+	return crc8.Checksum(data, crc8sensirion) == 0x00
+}
+
+// WordCRC returns the words, each followed by its CRC8
+func WordCRC(words ...uint16) []byte {
+	var data []byte
+	for _, w := range words {
+		b := []byte{byte(w >> 8), byte(w)}
+		data = append(append(data, b...), crc8.Checksum(b, crc8sensirion))
+	}
+	return data
+}
+
+// Word returns 16 bits from the byte stream, starting at index i
+func Word(data []byte, i int) uint16 {
+	return uint16(data[i])<<8 + uint16(data[i+1])
+}
+
+// String returns the NUL terminated string packed into the response words, used for
+// serial numbers and product names
+func String(words []uint16) string {
+	var b strings.Builder
+	for _, w := range words {
+		for _, c := range []byte{byte(w >> 8), byte(w)} {
+			if c == 0 {
+				return b.String()
+			}
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// Encode returns the command followed by the argument words and their CRC8
+func Encode(cmd uint16, args ...uint16) []byte {
+	return append([]byte{byte(cmd >> 8), byte(cmd)}, WordCRC(args...)...)
+}
+
+// Command sends a command, with optional argument words, waits for it to execute,
+// and then reads respWords words, checking and removing their CRC8
+//
+// The errors are prefixed with name, the driver's package name.
+func Command(c conn.Conn, name string, cmd uint16, wait time.Duration, respWords int, args ...uint16) ([]uint16, error) {
+	return transfer(c, name, Encode(cmd, args...), fmt.Sprintf("0x%04X", cmd), wait, respWords)
+}
+
+// Command8 sends an 8 bit command, used by the SHT4x, waits for it to execute, and
+// then reads respWords words, checking and removing their CRC8
+func Command8(c conn.Conn, name string, cmd uint8, wait time.Duration, respWords int) ([]uint16, error) {
+	return transfer(c, name, []byte{cmd}, fmt.Sprintf("0x%02X", cmd), wait, respWords)
+}
+
+// transfer writes the command bytes, waits, and reads the response words
+// id identifies the command in the errors.
+func transfer(c conn.Conn, name string, w []byte, id string, wait time.Duration, respWords int) ([]uint16, error) {
+	if err := c.Tx(w, nil); err != nil {
+		return nil, fmt.Errorf("%s: Error while sending command %s: %w", name, id, err)
+	}
+	time.Sleep(wait)
+	if respWords == 0 {
+		return nil, nil
+	}
+
+	data := make([]byte, respWords*3)
+	if err := c.Tx(nil, data); err != nil {
+		return nil, fmt.Errorf("%s: Error while reading command %s: %w", name, id, err)
+	}
+	resp := make([]uint16, respWords)
+	for i := range resp {
+		if !CheckCRC8(data[i*3 : i*3+3]) {
+			return nil, fmt.Errorf("%s: Command %s CRC8 failed on: %v", name, id, data[i*3:i*3+3])
+		}
+		resp[i] = Word(data, i*3)
+	}
+	return resp, nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sensirion
+
+import (
+	"bytes"
+	"testing"
+
+	"periph.io/x/periph/conn/i2c"
+	"periph.io/x/periph/conn/i2c/i2ctest"
+)
+
+func TestWord(t *testing.T) {
+	data := []byte{0x00, 0x01, 0x80, 0x0A, 0x55, 0xAA, 0xFF, 0x7F}
+	result := []uint16{0x0001, 0x800A, 0x55AA, 0xFF7F}
+	for i := 0; i < len(result); i++ {
+		if Word(data, i*2) != result[i] {
+			t.Errorf("Word error: i == %d", i)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	if String([]uint16{0x5345, 0x4e35, 0x3500, 0x4142}) != "SEN55" {
+		t.Errorf("String error: %q", String([]uint16{0x5345, 0x4e35, 0x3500, 0x4142}))
+	}
+	if String([]uint16{0x4142}) != "AB" {
+		t.Errorf("String error: %q", String([]uint16{0x4142}))
+	}
+}
+
+func TestCRC8(t *testing.T) {
+	// The datasheets' example, 0xBEEF has a CRC8 of 0x92
+	if CRC8([]byte{0xBE, 0xEF}) != 0x92 {
+		t.Fatalf("CRC8 error: 0x%02X", CRC8([]byte{0xBE, 0xEF}))
+	}
+	if !CheckCRC8([]byte{0xBE, 0xEF, 0x92}) {
+		t.Fatal("CheckCRC8 error")
+	}
+	if CheckCRC8([]byte{0xBE, 0xEF, 0x00}) {
+		t.Fatal("CheckCRC8 bad CRC error")
+	}
+	if !bytes.Equal(WordCRC(0xBEEF), []byte{0xBE, 0xEF, 0x92}) {
+		t.Fatalf("WordCRC error: %v", WordCRC(0xBEEF))
+	}
+	if !bytes.Equal(WordCRC(0xBEEF, 0xBEEF), []byte{0xBE, 0xEF, 0x92, 0xBE, 0xEF, 0x92}) {
+		t.Fatalf("WordCRC error: %v", WordCRC(0xBEEF, 0xBEEF))
+	}
+}
+
+func TestEncode(t *testing.T) {
+	if !bytes.Equal(Encode(0x3682), []byte{0x36, 0x82}) {
+		t.Fatalf("Encode error: %v", Encode(0x3682))
+	}
+	if !bytes.Equal(Encode(0x2061, 0xBEEF), []byte{0x20, 0x61, 0xBE, 0xEF, 0x92}) {
+		t.Fatalf("Encode args error: %v", Encode(0x2061, 0xBEEF))
+	}
+}
+
+func TestCommand(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x10, W: []byte{0x20, 0x61, 0xBE, 0xEF, 0x92}},
+			{Addr: 0x10, W: []byte{0x36, 0x82}},
+			{Addr: 0x10, R: []byte{0xBE, 0xEF, 0x92, 0x00, 0x00, 0x81}},
+			{Addr: 0x10, W: []byte{0x36, 0x82}},
+			{Addr: 0x10, R: []byte{0xBE, 0xEF, 0x00}},
+		},
+		DontPanic: true,
+	}
+	c := &i2c.Dev{Bus: &bus, Addr: 0x10}
+	if _, err := Command(c, "test", 0x2061, 0, 0, 0xBEEF); err != nil {
+		t.Fatalf("Command Error: %s", err)
+	}
+	resp, err := Command(c, "test", 0x3682, 0, 2)
+	if err != nil {
+		t.Fatalf("Command Error: %s", err)
+	}
+	if len(resp) != 2 || resp[0] != 0xBEEF || resp[1] != 0 {
+		t.Fatalf("Command response Error: %v", resp)
+	}
+	if _, err := Command(c, "test", 0x3682, 0, 1); err == nil {
+		t.Fatal("Command bad CRC Error")
+	}
+	if _, err := Command(c, "test", 0x3682, 0, 1); err == nil {
+		t.Fatal("Command bus Error")
+	}
+}
+
+func TestCommand8(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x44, W: []byte{0x89}},
+			{Addr: 0x44, R: []byte{0xBE, 0xEF, 0x92}},
+		},
+	}
+	c := &i2c.Dev{Bus: &bus, Addr: 0x44}
+	resp, err := Command8(c, "test", 0x89, 0, 1)
+	if err != nil {
+		t.Fatalf("Command8 Error: %s", err)
+	}
+	if len(resp) != 1 || resp[0] != 0xBEEF {
+		t.Fatalf("Command8 response Error: %v", resp)
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package uart has the helpers shared by the drivers for sensors connected to a
+// UART, passed to them as an io.ReadWriter configured for the sensor's baud rate.
+package uart
+
+import (
+	"io"
+)
+
+// Sync reads from r until it has read the start bytes, discarding any bytes before
+// them, to find the start of the next frame.
+func Sync(r io.Reader, start ...byte) error {
+	window := make([]byte, len(start))
+	var b [1]byte
+	for n := 0; ; n++ {
+		if n >= len(start) && string(window) == string(start) {
+			return nil
+		}
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return err
+		}
+		copy(window, window[1:])
+		window[len(window)-1] = b[0]
+	}
+}
+
+// ReadFrame reads the next frame into frame, which starts with the start bytes
+// It discards any bytes before the start bytes.
+func ReadFrame(r io.Reader, frame []byte, start ...byte) error {
+	if err := Sync(r, start...); err != nil {
+		return err
+	}
+	copy(frame, start)
+	_, err := io.ReadFull(r, frame[len(start):])
+	return err
+}
+
+// Sum returns the 8 bit sum of the bytes, the checksum used by many sensors
+func Sum(data []byte) uint8 {
+	var sum uint8
+	for _, b := range data {
+		sum += b
+	}
+	return sum
+}
@@ -0,0 +1,39 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package uart
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestReadFrame(t *testing.T) {
+	// Noise, a partial start, and then 2 frames
+	port := bytes.NewBuffer([]byte{0x01, 0xaa, 0xaa, 0xc0, 0x01, 0x02, 0xaa, 0xc0, 0x03, 0x04})
+	var frame [4]byte
+	if err := ReadFrame(port, frame[:], 0xaa, 0xc0); err != nil {
+		t.Fatalf("ReadFrame Error: %s", err)
+	}
+	if !bytes.Equal(frame[:], []byte{0xaa, 0xc0, 0x01, 0x02}) {
+		t.Errorf("ReadFrame Error: %v", frame)
+	}
+	if err := ReadFrame(port, frame[:], 0xaa, 0xc0); err != nil {
+		t.Fatalf("ReadFrame Error: %s", err)
+	}
+	if !bytes.Equal(frame[:], []byte{0xaa, 0xc0, 0x03, 0x04}) {
+		t.Errorf("ReadFrame Error: %v", frame)
+	}
+	if err := ReadFrame(port, frame[:], 0xaa, 0xc0); !errors.Is(err, io.EOF) {
+		t.Errorf("EOF Error: %v", err)
+	}
+}
+
+func TestSum(t *testing.T) {
+	if Sum([]byte{0xff, 0x02, 0x10}) != 0x11 {
+		t.Errorf("Sum Error: 0x%02X", Sum([]byte{0xff, 0x02, 0x10}))
+	}
+}
@@ -0,0 +1,27 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package uarttest has a fake UART for testing the drivers for sensors connected to
+// a UART.
+package uarttest
+
+import (
+	"bytes"
+)
+
+// Port is a UART that returns the data in R, and records the data written to it in W
+type Port struct {
+	R bytes.Buffer // Data returned by Read
+	W bytes.Buffer // Data written by Write
+}
+
+// Read implements io.Reader, it returns io.EOF when R is empty
+func (p *Port) Read(b []byte) (int, error) {
+	return p.R.Read(b)
+}
+
+// Write implements io.Writer
+func (p *Port) Write(b []byte) (int, error) {
+	return p.W.Write(b)
+}
@@ -0,0 +1,16 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package mhz19 controls a Winsen MH-Z19B or MH-Z19C NDIR CO2 sensor over a UART.
+//
+// The sensor uses 9 byte commands and replies, starting with 0xff and ending with a
+// checksum. It reads the CO2 concentration, calibrates the zero point and the span,
+// turns the automatic baseline correction on or off, and sets the detection range.
+//
+// Datasheet
+//
+// https://www.winsen-sensor.com/d/files/infrared-gas-sensor/mh-z19b-co2-ver1_0.pdf
+//
+// https://www.winsen-sensor.com/d/files/infrared-gas-sensor/mh-z19c-pins-type-co2-manual-ver1_0.pdf
+package mhz19
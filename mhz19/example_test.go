@@ -0,0 +1,39 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package mhz19_test
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/bcl/air-sensors/mhz19"
+)
+
+func Example() {
+	// The port needs to be configured first, eg. stty -F /dev/ttyS0 9600 raw
+	port, err := os.OpenFile("/dev/ttyS0", os.O_RDWR, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer port.Close()
+
+	d, err := mhz19.New(port)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer d.Halt() //nolint
+
+	// Indoors without fresh air every day, turn off the automatic baseline correction
+	if err := d.SetABC(false); err != nil {
+		log.Fatal(err)
+	}
+
+	r, err := d.ReadCO2()
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("CO2: %d ppm\n", r.CO2)
+}
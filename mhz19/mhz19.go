@@ -0,0 +1,163 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package mhz19
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"periph.io/x/periph/conn"
+	"periph.io/x/periph/conn/physic"
+
+	"github.com/bcl/air-sensors/internal/uart"
+)
+
+// WarmupTime is how long the sensor needs after power on before its readings are
+// accurate
+const WarmupTime = 3 * time.Minute
+
+// Detection ranges supported by SetRange, in ppm
+const (
+	Range2000  uint16 = 2000
+	Range5000  uint16 = 5000
+	Range10000 uint16 = 10000
+)
+
+// MH-Z19 commands from the datasheet
+const (
+	cmdReadCO2        byte = 0x86 // Returns the CO2 concentration
+	cmdCalibrateZero  byte = 0x87 // Calibrate the zero point to 400ppm
+	cmdCalibrateSpan  byte = 0x88 // Calibrate the span to the concentration in bytes 3-4
+	cmdABC            byte = 0x79 // Automatic baseline correction on (0xa0) or off (0x00)
+	cmdDetectionRange byte = 0x99 // Set the detection range in bytes 6-7
+
+	frameStart byte = 0xff // First byte of every command and reply
+	sensorID   byte = 0x01 // Second byte of every command
+)
+
+// maxFrames is how many replies are read while waiting for the CO2 reading, skipping
+// replies to earlier commands
+const maxFrames = 4
+
+// ErrChecksum is returned when a reply's checksum does not match
+var ErrChecksum = errors.New("mhz19: Bad checksum")
+
+// Reading holds the readings from the MH-Z19
+type Reading struct {
+	CO2         uint16             `json:"co2"`         // CO2 in ppm
+	Temperature physic.Temperature `json:"temperature"` // Undocumented, approximate, sensor temperature
+	Timestamp   time.Time          `json:"timestamp"`   // When the reading was made
+}
+
+// Dev holds the connection to the MH-Z19
+type Dev struct {
+	port io.ReadWriter // UART connected to the sensor
+}
+
+var _ conn.Resource = &Dev{}
+
+// New returns a MH-Z19 device struct for communicating with the sensor over a UART
+//
+// The port needs to be configured for 9600 baud 8N1 before calling New.
+func New(port io.ReadWriter) (*Dev, error) {
+	return &Dev{port: port}, nil
+}
+
+// String implements conn.Resource.
+func (d *Dev) String() string {
+	return fmt.Sprintf("mhz19{%v}", d.port)
+}
+
+// Halt implements conn.Resource.
+//
+// The MH-Z19 measures continuously and cannot be halted.
+func (d *Dev) Halt() error {
+	return nil
+}
+
+// ReadCO2 returns the CO2 concentration
+func (d *Dev) ReadCO2() (Reading, error) {
+	if err := d.command(cmdReadCO2, 0, 0); err != nil {
+		return Reading{}, err
+	}
+	for i := 0; i < maxFrames; i++ {
+		var frame [9]byte
+		if err := uart.ReadFrame(d.port, frame[:], frameStart); err != nil {
+			return Reading{}, fmt.Errorf("mhz19: Error while reading the sensor: %w", err)
+		}
+		if checksum(frame[:]) != frame[8] {
+			return Reading{}, ErrChecksum
+		}
+		if frame[1] != cmdReadCO2 {
+			continue
+		}
+		return Reading{
+			CO2:         uint16(frame[2])<<8 | uint16(frame[3]),
+			Temperature: physic.ZeroCelsius + physic.Temperature(int(frame[4])-40)*physic.Celsius,
+			Timestamp:   time.Now(),
+		}, nil
+	}
+	return Reading{}, fmt.Errorf("mhz19: No CO2 reading")
+}
+
+// CalibrateZero sets the current concentration as the 400ppm zero point
+//
+// The sensor needs to have been in fresh outdoor air, or a 400ppm reference gas, for
+// at least 20 minutes.
+func (d *Dev) CalibrateZero() error {
+	return d.command(cmdCalibrateZero, 0, 0)
+}
+
+// CalibrateSpan sets the current concentration as the span point, in ppm
+//
+// Calibrate the zero point first, then put the sensor in the reference gas for at
+// least 20 minutes. A span of 2000ppm or more is recommended.
+func (d *Dev) CalibrateSpan(ppm uint16) error {
+	if ppm < 1000 {
+		return fmt.Errorf("mhz19: Invalid span: %d", ppm)
+	}
+	return d.command(cmdCalibrateSpan, ppm, 0)
+}
+
+// SetABC turns the automatic baseline correction on or off. It is on by default, and
+// calibrates the zero point to the lowest concentration seen in each 24 hours, which
+// needs the sensor to see fresh air every day.
+func (d *Dev) SetABC(on bool) error {
+	var abc uint16
+	if on {
+		abc = 0xa000
+	}
+	return d.command(cmdABC, abc, 0)
+}
+
+// SetRange sets the detection range, Range2000, Range5000 or Range10000 ppm
+func (d *Dev) SetRange(ppm uint16) error {
+	switch ppm {
+	case Range2000, Range5000, Range10000:
+	default:
+		return fmt.Errorf("mhz19: Invalid detection range: %d", ppm)
+	}
+	return d.command(cmdDetectionRange, 0, ppm)
+}
+
+// command sends a command to the sensor
+//
+// The command is 0xff, 0x01, the command byte, data in bytes 3-4, a 0, data2 in
+// bytes 6-7, and the checksum.
+func (d *Dev) command(cmd byte, data, data2 uint16) error {
+	buf := []byte{frameStart, sensorID, cmd, byte(data >> 8), byte(data), 0, byte(data2 >> 8), byte(data2), 0}
+	buf[8] = checksum(buf)
+	if _, err := d.port.Write(buf); err != nil {
+		return fmt.Errorf("mhz19: Error while sending command 0x%02x: %w", cmd, err)
+	}
+	return nil
+}
+
+// checksum returns the checksum of a 9 byte frame, the negated sum of bytes 1-7
+func checksum(frame []byte) byte {
+	return -uart.Sum(frame[1:8])
+}
@@ -0,0 +1,91 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package mhz19
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"periph.io/x/periph/conn/physic"
+
+	"github.com/bcl/air-sensors/internal/uart/uarttest"
+)
+
+var (
+	ReadCO2Cmd   = []byte{0xff, 0x01, 0x86, 0x00, 0x00, 0x00, 0x00, 0x00, 0x79}
+	GoodCO2Reply = []byte{0xff, 0x86, 0x02, 0x60, 0x47, 0x00, 0x00, 0x00, 0xd1}
+	BadCO2Reply  = []byte{0xff, 0x86, 0x02, 0x60, 0x47, 0x00, 0x00, 0x00, 0xd2}
+	ABCReply     = []byte{0xff, 0x79, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x86}
+)
+
+func TestReadCO2(t *testing.T) {
+	var port uarttest.Port
+	// Noise, and the reply to an earlier command, before the reading
+	port.R.Write([]byte{0x00, 0x12})
+	port.R.Write(ABCReply)
+	port.R.Write(GoodCO2Reply)
+	port.R.Write(BadCO2Reply)
+
+	d, err := New(&port)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	r, err := d.ReadCO2()
+	if err != nil {
+		t.Fatalf("ReadCO2 Error: %s", err)
+	}
+	if r.CO2 != 608 {
+		t.Errorf("CO2 Error: %d", r.CO2)
+	}
+	if r.Temperature != physic.ZeroCelsius+31*physic.Celsius {
+		t.Errorf("Temperature Error: %s", r.Temperature)
+	}
+	if _, err := d.ReadCO2(); !errors.Is(err, ErrChecksum) {
+		t.Errorf("Checksum Error: %v", err)
+	}
+	if !bytes.Equal(port.W.Bytes(), append(append([]byte{}, ReadCO2Cmd...), ReadCO2Cmd...)) {
+		t.Errorf("ReadCO2 command Error: % x", port.W.Bytes())
+	}
+}
+
+func TestCommands(t *testing.T) {
+	var port uarttest.Port
+	d, err := New(&port)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if err := d.CalibrateZero(); err != nil {
+		t.Fatalf("CalibrateZero Error: %s", err)
+	}
+	if err := d.CalibrateSpan(2000); err != nil {
+		t.Fatalf("CalibrateSpan Error: %s", err)
+	}
+	if err := d.CalibrateSpan(400); err == nil {
+		t.Error("Invalid span Error")
+	}
+	if err := d.SetABC(true); err != nil {
+		t.Fatalf("SetABC Error: %s", err)
+	}
+	if err := d.SetABC(false); err != nil {
+		t.Fatalf("SetABC Error: %s", err)
+	}
+	if err := d.SetRange(Range5000); err != nil {
+		t.Fatalf("SetRange Error: %s", err)
+	}
+	if err := d.SetRange(3000); err == nil {
+		t.Error("Invalid range Error")
+	}
+	cmds := []byte{
+		0xff, 0x01, 0x87, 0x00, 0x00, 0x00, 0x00, 0x00, 0x78,
+		0xff, 0x01, 0x88, 0x07, 0xd0, 0x00, 0x00, 0x00, 0xa0,
+		0xff, 0x01, 0x79, 0xa0, 0x00, 0x00, 0x00, 0x00, 0xe6,
+		0xff, 0x01, 0x79, 0x00, 0x00, 0x00, 0x00, 0x00, 0x86,
+		0xff, 0x01, 0x99, 0x00, 0x00, 0x00, 0x13, 0x88, 0xcb,
+	}
+	if !bytes.Equal(port.W.Bytes(), cmds) {
+		t.Errorf("Commands Error: % x", port.W.Bytes())
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package pmsa003i
+
+// Averager keeps a rolling window of Results and returns their average
+//
+// Single readings from the sensor are noisy, averaging a few of them gives more
+// stable values. It is not safe for concurrent use.
+type Averager struct {
+	window []Results
+	next   int  // Index to store the next Results at
+	full   bool // The window has been filled
+}
+
+// NewAverager returns an Averager for a window of size Results
+func NewAverager(size int) *Averager {
+	if size < 1 {
+		size = 1
+	}
+	return &Averager{window: make([]Results, size)}
+}
+
+// Add adds r to the window, replacing the oldest Results if it is full, and returns
+// the new average
+func (a *Averager) Add(r Results) Results {
+	a.window[a.next] = r
+	a.next = (a.next + 1) % len(a.window)
+	if a.next == 0 {
+		a.full = true
+	}
+	return a.Average()
+}
+
+// Len returns the number of Results in the window
+func (a *Averager) Len() int {
+	if a.full {
+		return len(a.window)
+	}
+	return a.next
+}
+
+// Reset empties the window
+func (a *Averager) Reset() {
+	a.next = 0
+	a.full = false
+}
+
+// Average returns the average of the Results in the window, rounded to the nearest
+// integer. The Version and Timestamp are from the newest Results.
+func (a *Averager) Average() Results {
+	n := a.Len()
+	if n == 0 {
+		return Results{}
+	}
+
+	var sum [12]uint32
+	for _, r := range a.window[:n] {
+		for i, v := range r.values() {
+			sum[i] += uint32(v)
+		}
+	}
+	var avg [12]uint16
+	for i := range sum {
+		avg[i] = uint16((sum[i] + uint32(n)/2) / uint32(n))
+	}
+
+	newest := (a.next + len(a.window) - 1) % len(a.window)
+	return Results{
+		CfPm1:     avg[0],
+		CfPm2_5:   avg[1],
+		CfPm10:    avg[2],
+		EnvPm1:    avg[3],
+		EnvPm2_5:  avg[4],
+		EnvPm10:   avg[5],
+		Cnt0_3:    avg[6],
+		Cnt0_5:    avg[7],
+		Cnt1:      avg[8],
+		Cnt2_5:    avg[9],
+		Cnt5:      avg[10],
+		Cnt10:     avg[11],
+		Version:   a.window[newest].Version,
+		Timestamp: a.window[newest].Timestamp,
+	}
+}
+
+// values returns the measurements in the same order as the sensor's frame
+func (r Results) values() [12]uint16 {
+	return [12]uint16{
+		r.CfPm1, r.CfPm2_5, r.CfPm10,
+		r.EnvPm1, r.EnvPm2_5, r.EnvPm10,
+		r.Cnt0_3, r.Cnt0_5, r.Cnt1, r.Cnt2_5, r.Cnt5, r.Cnt10,
+	}
+}
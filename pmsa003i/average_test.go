@@ -0,0 +1,41 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package pmsa003i
+
+import (
+	"testing"
+)
+
+func TestAverager(t *testing.T) {
+	a := NewAverager(3)
+	if a.Len() != 0 || a.Average() != (Results{}) {
+		t.Fatalf("Empty Averager Error: %d %v", a.Len(), a.Average())
+	}
+
+	a.Add(Results{EnvPm2_5: 10, Cnt0_3: 100, Version: 1})
+	r := a.Add(Results{EnvPm2_5: 11, Cnt0_3: 200, Version: 2})
+	if r.EnvPm2_5 != 11 || r.Cnt0_3 != 150 || r.Version != 2 {
+		t.Errorf("Average of 2 is wrong: %+v", r)
+	}
+
+	a.Add(Results{EnvPm2_5: 30, Cnt0_3: 300, Version: 3})
+	if a.Len() != 3 {
+		t.Fatalf("Len is wrong: %d", a.Len())
+	}
+
+	// The oldest Results is replaced
+	r = a.Add(Results{EnvPm2_5: 40, Cnt0_3: 65535, Version: 4})
+	if r.EnvPm2_5 != 27 || r.Cnt0_3 != 22012 || r.Version != 4 {
+		t.Errorf("Rolling average is wrong: %+v", r)
+	}
+	if a.Len() != 3 {
+		t.Fatalf("Len is wrong: %d", a.Len())
+	}
+
+	a.Reset()
+	if a.Len() != 0 {
+		t.Fatalf("Reset Error: %d", a.Len())
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package pmsa003i
+
+import (
+	"periph.io/x/periph/conn/physic"
+)
+
+// EPACorrectedPM25 returns the PM2.5 in μg/m3 corrected with the US EPA's equation for
+// PurpleAir sensors, which use the same Plantower sensor, and the relative humidity
+// of the air.
+//
+// The sensor overestimates PM2.5, especially in humid air. The correction uses the
+// standard particle (CF=1) PM2.5 reading, and the extended equation for smoke
+// concentrations above 343 μg/m3. It is described in
+// https://www.epa.gov/sites/default/files/2021-05/documents/toolsresourceswebinar_purpleairsmoke_210519b.pdf
+//
+// The result is never negative. Use aqi.PM25 to calculate the AQI for it.
+func (r Results) EPACorrectedPM25(rh physic.RelativeHumidity) float64 {
+	return epaCorrection(float64(r.CfPm2_5), float64(rh)/float64(physic.PercentRH))
+}
+
+// epaCorrection applies the EPA correction to the CF=1 PM2.5 x at rh percent
+func epaCorrection(x, rh float64) float64 {
+	var pm float64
+	switch {
+	case x < 30:
+		pm = 0.524*x - 0.0862*rh + 5.75
+	case x < 50:
+		// Blend of the 0-30 and 50-210 slopes
+		w := x/20 - 3.0/2
+		pm = (0.786*w+0.524*(1-w))*x - 0.0862*rh + 5.75
+	case x < 210:
+		pm = 0.786*x - 0.0862*rh + 5.75
+	case x < 260:
+		// Blend of the 50-210 and the quadratic smoke equations
+		w := x/50 - 21.0/5
+		pm = (0.69*w+0.786*(1-w))*x - 0.0862*rh*(1-w) + 2.966*w + 5.75*(1-w) + 8.84e-4*x*x*w
+	default:
+		pm = 2.966 + 0.69*x + 8.84e-4*x*x
+	}
+	if pm < 0 {
+		return 0
+	}
+	return pm
+}
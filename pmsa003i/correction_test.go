@@ -0,0 +1,34 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package pmsa003i
+
+import (
+	"math"
+	"testing"
+
+	"periph.io/x/periph/conn/physic"
+)
+
+func TestEPACorrectedPM25(t *testing.T) {
+	tests := []struct {
+		pm25     uint16
+		rh       physic.RelativeHumidity
+		expected float64
+	}{
+		{0, 0, 5.75},
+		{0, 100 * physic.PercentRH, 0},
+		{20, 50 * physic.PercentRH, 11.92},
+		{40, 50 * physic.PercentRH, 27.64},
+		{100, 50 * physic.PercentRH, 80.04},
+		{235, 50 * physic.PercentRH, 200.04},
+		{300, 50 * physic.PercentRH, 289.53},
+	}
+	for _, tt := range tests {
+		pm := Results{CfPm2_5: tt.pm25}.EPACorrectedPM25(tt.rh)
+		if math.Abs(pm-tt.expected) > 0.01 {
+			t.Errorf("EPA correction of %d at %s Error: %.2f != %.2f", tt.pm25, tt.rh, pm, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package pmsa003i
+
+// Counts holds the cumulative particle counts scaled to a different volume of air
+type Counts struct {
+	Cnt0_3 uint32 // Count of particles > 0.3μm
+	Cnt0_5 uint32 // Count of particles > 0.5μm
+	Cnt1   uint32 // Count of particles > 1.0μm
+	Cnt2_5 uint32 // Count of particles > 2.5μm
+	Cnt5   uint32 // Count of particles > 5.0μm
+	Cnt10  uint32 // Count of particles > 10.0μm
+}
+
+// Bins holds the count of particles in each size range, in 0.1L of air
+type Bins struct {
+	Bin0_3 uint16 // Count of particles 0.3μm to 0.5μm
+	Bin0_5 uint16 // Count of particles 0.5μm to 1.0μm
+	Bin1   uint16 // Count of particles 1.0μm to 2.5μm
+	Bin2_5 uint16 // Count of particles 2.5μm to 5.0μm
+	Bin5   uint16 // Count of particles 5.0μm to 10.0μm
+	Bin10  uint16 // Count of particles > 10.0μm
+}
+
+// PerLiter returns the particle counts in 1L of air
+func (r Results) PerLiter() Counts {
+	return r.scaleCounts(10)
+}
+
+// PerCubicMeter returns the particle counts in 1m³ of air
+func (r Results) PerCubicMeter() Counts {
+	return r.scaleCounts(10000)
+}
+
+// scaleCounts returns the 0.1L counts multiplied by scale
+func (r Results) scaleCounts(scale uint32) Counts {
+	return Counts{
+		Cnt0_3: uint32(r.Cnt0_3) * scale,
+		Cnt0_5: uint32(r.Cnt0_5) * scale,
+		Cnt1:   uint32(r.Cnt1) * scale,
+		Cnt2_5: uint32(r.Cnt2_5) * scale,
+		Cnt5:   uint32(r.Cnt5) * scale,
+		Cnt10:  uint32(r.Cnt10) * scale,
+	}
+}
+
+// Bins returns the count of particles in each size range, calculated from the
+// cumulative counts. A range is 0 if the count of larger particles is higher, which
+// can happen with noisy readings.
+func (r Results) Bins() Bins {
+	return Bins{
+		Bin0_3: diff(r.Cnt0_3, r.Cnt0_5),
+		Bin0_5: diff(r.Cnt0_5, r.Cnt1),
+		Bin1:   diff(r.Cnt1, r.Cnt2_5),
+		Bin2_5: diff(r.Cnt2_5, r.Cnt5),
+		Bin5:   diff(r.Cnt5, r.Cnt10),
+		Bin10:  r.Cnt10,
+	}
+}
+
+// diff returns a-b, or 0 if b is larger
+func diff(a, b uint16) uint16 {
+	if b > a {
+		return 0
+	}
+	return a - b
+}
@@ -0,0 +1,35 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package pmsa003i
+
+import (
+	"testing"
+)
+
+func TestCounts(t *testing.T) {
+	r := Results{Cnt0_3: 126, Cnt0_5: 42, Cnt1: 15, Cnt2_5: 9, Cnt5: 3, Cnt10: 3}
+
+	if c := r.PerLiter(); c != (Counts{1260, 420, 150, 90, 30, 30}) {
+		t.Errorf("PerLiter Error: %v", c)
+	}
+	if c := r.PerCubicMeter(); c != (Counts{1260000, 420000, 150000, 90000, 30000, 30000}) {
+		t.Errorf("PerCubicMeter Error: %v", c)
+	}
+	if b := r.Bins(); b != (Bins{84, 27, 6, 6, 0, 3}) {
+		t.Errorf("Bins Error: %v", b)
+	}
+
+	// Noisy counts do not wrap around
+	r.Cnt5 = 10
+	if b := r.Bins(); b != (Bins{84, 27, 6, 0, 7, 3}) {
+		t.Errorf("Noisy Bins Error: %v", b)
+	}
+
+	// The largest count does not overflow
+	r.Cnt0_3 = 65535
+	if c := r.PerCubicMeter(); c.Cnt0_3 != 655350000 {
+		t.Errorf("PerCubicMeter overflow Error: %v", c)
+	}
+}
@@ -4,6 +4,9 @@
 
 // Package pmsa003i 30 controls a PMSA003i particle sensor over I²C.
 //
+// The serial variants of the sensor, the PMS5003, PMS7003, and PMSA003, use the same
+// frame over a UART, use NewSerial to read them.
+//
 // Datasheet
 //
 // https://cdn-shop.adafruit.com/product-files/4632/4505_PMSA003I_series_data_manual_English_V2.6.pdf
@@ -0,0 +1,79 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package pmsa003i
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DutyCycle runs the sensor's fan and laser only while measuring, to extend their
+// lifetime. The sensor is woken up, and after the wake time it is read samples times,
+// every interval, and put back to sleep for the sleep time. The average of the
+// samples is sent on the returned channel.
+//
+// The sensor needs a SET pin set with WithSetPin, or to be connected with NewSerial.
+// It is left asleep and the channel is closed when ctx is cancelled, or if the sensor
+// cannot be woken up or put to sleep. Frames that fail to read are left out of the
+// average, if all of them fail the cycle is skipped.
+func (d *Dev) DutyCycle(ctx context.Context, samples int, interval, sleep time.Duration) (<-chan Results, error) {
+	if !d.canSleep() {
+		return nil, fmt.Errorf("pmsa003i: DutyCycle requires the SET pin or a UART")
+	}
+
+	ch := make(chan Results)
+	go func() {
+		defer close(ch)
+		for {
+			r, ok := d.sampleCycle(ctx, samples, interval)
+			if err := d.Sleep(); err != nil || ctx.Err() != nil {
+				return
+			}
+			if ok {
+				select {
+				case <-ctx.Done():
+					return
+				case ch <- r:
+				}
+			}
+			if !wait(ctx, sleep) {
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// sampleCycle wakes the sensor, and returns the average of the samples once it has
+// stabilized. It returns false if no samples were read.
+func (d *Dev) sampleCycle(ctx context.Context, samples int, interval time.Duration) (Results, bool) {
+	if err := d.wake(); err != nil || !wait(ctx, d.wakeTime) {
+		return Results{}, false
+	}
+
+	a := NewAverager(samples)
+	for i := 0; i < samples; i++ {
+		if i > 0 && !wait(ctx, interval) {
+			return Results{}, false
+		}
+		if r, err := d.ReadSensorContext(ctx); err == nil {
+			a.Add(r)
+		}
+	}
+	return a.Average(), a.Len() > 0
+}
+
+// wait waits for the delay, it returns false if ctx is done first
+func wait(ctx context.Context, delay time.Duration) bool {
+	t := time.NewTimer(delay)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package pmsa003i
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"periph.io/x/periph/conn/gpio"
+	"periph.io/x/periph/conn/gpio/gpiotest"
+	"periph.io/x/periph/conn/i2c/i2ctest"
+)
+
+func TestDutyCycle(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x12, W: []byte{}, R: GoodSensorData},
+			// First cycle, the bad frame is left out of the average
+			{Addr: 0x12, W: []byte{}, R: GoodSensorData},
+			{Addr: 0x12, W: []byte{}, R: BadChecksumSensorData},
+			{Addr: 0x12, W: []byte{}, R: NewSensorData},
+			// Second cycle
+			{Addr: 0x12, W: []byte{}, R: NewSensorData},
+			{Addr: 0x12, W: []byte{}, R: NewSensorData},
+			{Addr: 0x12, W: []byte{}, R: NewSensorData},
+		},
+		DontPanic: true,
+	}
+	set := &gpiotest.Pin{N: "SET"}
+	d, err := New(&bus, WithSetPin(set), WithWakeTime(time.Millisecond))
+	if err != nil {
+		t.Fatalf("Good sensor data Error: %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, err := d.DutyCycle(ctx, 3, time.Millisecond, time.Millisecond)
+	if err != nil {
+		t.Fatalf("DutyCycle Error: %s", err)
+	}
+	if r := <-ch; r.Cnt0_3 != 127 {
+		t.Fatalf("First cycle Data Error: %v", r)
+	}
+	if r := <-ch; r.Cnt0_3 != 128 {
+		t.Fatalf("Second cycle Data Error: %v", r)
+	}
+	cancel()
+	for range ch {
+	}
+	if set.Read() != gpio.Low {
+		t.Fatal("DutyCycle did not leave the sensor asleep")
+	}
+}
+
+func TestDutyCycleWithoutPin(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x12, W: []byte{}, R: GoodSensorData},
+		},
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("Good sensor data Error: %s", err)
+	}
+	if _, err := d.DutyCycle(context.Background(), 1, time.Second, time.Minute); err == nil {
+		t.Fatal("DutyCycle without SET pin Error")
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package pmsa003i
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrBadStartWord is returned when the frame read from the sensor does not start
+	// with 0x424d
+	ErrBadStartWord = errors.New("pmsa003i: Bad start word")
+
+	// ErrChecksum is returned when the frame's checksum does not match. This is
+	// usually transient.
+	ErrChecksum = errors.New("pmsa003i: Bad checksum")
+
+	// ErrDeviceCode is returned when the sensor reports an error code in the frame,
+	// the code is available from the DeviceCodeError.
+	ErrDeviceCode = errors.New("pmsa003i: Error code")
+
+	// ErrImplausible is returned by Results.Validate and Validator.Check when the
+	// readings are physically impossible
+	ErrImplausible = errors.New("pmsa003i: Implausible readings")
+
+	// ErrStuck is returned by Validator.Check when the sensor's readings have stopped
+	// changing, or it has not counted any particles, for too long
+	ErrStuck = errors.New("pmsa003i: Sensor appears to be stuck")
+
+	// ErrWarmingUp is returned by ReadSensor during warm-up when the WarmupSuppress
+	// policy is used
+	ErrWarmingUp = errors.New("pmsa003i: warming up")
+)
+
+// DeviceCodeError holds the error code reported by the sensor
+//
+// It matches ErrDeviceCode with errors.Is.
+type DeviceCodeError struct {
+	Code uint8 // The error code byte from the frame
+}
+
+func (e *DeviceCodeError) Error() string {
+	return fmt.Sprintf("pmsa003i: Error code %x", e.Code)
+}
+
+// Is returns true if target is ErrDeviceCode
+func (e *DeviceCodeError) Is(target error) bool {
+	return target == ErrDeviceCode
+}
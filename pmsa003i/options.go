@@ -0,0 +1,97 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package pmsa003i
+
+import (
+	"time"
+
+	"periph.io/x/periph/conn/gpio"
+)
+
+// DefaultAddr is the I²C address of the sensor
+const DefaultAddr uint16 = 0x12
+
+// WakeTime is how long the sensor's fan needs to run after waking up before the
+// readings are stable
+const WakeTime = 30 * time.Second
+
+// ResetPulse is how long Reset holds the RESET pin low
+const ResetPulse = 100 * time.Millisecond
+
+// FrameInterval is about how often the sensor updates its frame
+const FrameInterval = 2300 * time.Millisecond
+
+// FramePoll is how often ReadNewSensor reads the sensor while waiting for a new frame
+const FramePoll = 200 * time.Millisecond
+
+// WarmupPolicy selects how ReadSensor handles readings made during warm-up
+type WarmupPolicy int
+
+const (
+	// WarmupIgnore returns the warm-up readings unchanged
+	WarmupIgnore WarmupPolicy = iota
+	// WarmupFlag sets Results.Warmup on the warm-up readings
+	WarmupFlag
+	// WarmupSuppress returns ErrWarmingUp instead of the warm-up readings
+	WarmupSuppress
+)
+
+// Option configures the Dev returned by New
+type Option func(*Dev)
+
+// WithSetPin sets the GPIO connected to the sensor's SET pin, used by Sleep and Wake
+// New drives it high to make sure that the sensor is awake.
+func WithSetPin(p gpio.PinOut) Option {
+	return func(d *Dev) {
+		d.setPin = p
+	}
+}
+
+// WithResetPin sets the GPIO connected to the sensor's RESET pin, used by Reset
+// New drives it high to make sure that the sensor is not held in reset.
+func WithResetPin(p gpio.PinOut) Option {
+	return func(d *Dev) {
+		d.resetPin = p
+	}
+}
+
+// WithWakeTime sets how long Wake and Reset wait for the readings to stabilize, the
+// default is WakeTime.
+func WithWakeTime(wait time.Duration) Option {
+	return func(d *Dev) {
+		d.wakeTime = wait
+	}
+}
+
+// WithRetries sets the number of times ReadSensor re-reads a frame that has a bad start
+// word or checksum, which are usually transient.
+func WithRetries(n int) Option {
+	return func(d *Dev) {
+		d.retries = n
+	}
+}
+
+// WithAddress sets the I²C address of the sensor, the default is DefaultAddr
+func WithAddress(addr uint16) Option {
+	return func(d *Dev) {
+		d.addr = addr
+	}
+}
+
+// WithWarmupPolicy sets how ReadSensor handles the readings made while the fan spins
+// up, during the wake time after New, Wake, or Reset. The default is WarmupIgnore.
+func WithWarmupPolicy(policy WarmupPolicy) Option {
+	return func(d *Dev) {
+		d.warmup = policy
+	}
+}
+
+// WithRuntimeFile loads the fan runtime from path at startup, and saves it to path so
+// that FanRuntime includes the time from earlier runs. A missing file is not an error.
+func WithRuntimeFile(path string) Option {
+	return func(d *Dev) {
+		d.runtimeFile = path
+	}
+}
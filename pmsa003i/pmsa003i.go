@@ -5,10 +5,19 @@
 package pmsa003i
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"sync"
+	"time"
 
 	"periph.io/x/periph/conn"
+	"periph.io/x/periph/conn/gpio"
 	"periph.io/x/periph/conn/i2c"
+
+	"github.com/bcl/air-sensors/aqi"
 )
 
 func checksum(data []byte) bool {
@@ -21,75 +30,451 @@ func checksum(data []byte) bool {
 
 // Results contains the measurements from the PMSA003i
 type Results struct {
-	CfPm1    uint16 // PM1.0 in μg/m3 standard particle
-	CfPm2_5  uint16 // PM2.5 in μg/m3 standard particle
-	CfPm10   uint16 // PM10 in μg/m3 standard particle
-	EnvPm1   uint16 // PM1.0 in μg/m3 atmospheric environment
-	EnvPm2_5 uint16 // PM2.5 in μg/m3 atmospheric environment
-	EnvPm10  uint16 // PM10 in μg/m3 atmospheric environment
-	Cnt0_3   uint16 // Count of particles > 0.3μm in 0.1L of air
-	Cnt0_5   uint16 // Count of particles > 0.5μm in 0.1L of air
-	Cnt1     uint16 // Count of particles > 1.0μm in 0.1L of air
-	Cnt2_5   uint16 // Count of particles > 2.5μm in 0.1L of air
-	Cnt5     uint16 // Count of particles > 5.0μm in 0.1L of air
-	Cnt10    uint16 // Count of particles > 10.0μm in 0.1L of air
-	Version  uint8
+	CfPm1     uint16    `json:"cf_pm1"`    // PM1.0 in μg/m3 standard particle
+	CfPm2_5   uint16    `json:"cf_pm2_5"`  // PM2.5 in μg/m3 standard particle
+	CfPm10    uint16    `json:"cf_pm10"`   // PM10 in μg/m3 standard particle
+	EnvPm1    uint16    `json:"env_pm1"`   // PM1.0 in μg/m3 atmospheric environment
+	EnvPm2_5  uint16    `json:"env_pm2_5"` // PM2.5 in μg/m3 atmospheric environment
+	EnvPm10   uint16    `json:"env_pm10"`  // PM10 in μg/m3 atmospheric environment
+	Cnt0_3    uint16    `json:"cnt0_3"`    // Count of particles > 0.3μm in 0.1L of air
+	Cnt0_5    uint16    `json:"cnt0_5"`    // Count of particles > 0.5μm in 0.1L of air
+	Cnt1      uint16    `json:"cnt1"`      // Count of particles > 1.0μm in 0.1L of air
+	Cnt2_5    uint16    `json:"cnt2_5"`    // Count of particles > 2.5μm in 0.1L of air
+	Cnt5      uint16    `json:"cnt5"`      // Count of particles > 5.0μm in 0.1L of air
+	Cnt10     uint16    `json:"cnt10"`     // Count of particles > 10.0μm in 0.1L of air
+	Version   uint8     `json:"version"`
+	Timestamp time.Time `json:"timestamp"`        // When the frame was read
+	Stale     bool      `json:"stale,omitempty"`  // The frame is the same as the previous one
+	Warmup    bool      `json:"warmup,omitempty"` // Read during warm-up, with WarmupFlag
 }
 
-// New returns a PMSA003I device struct for communicating with the device
+// results has the same fields as Results without its methods, so that MarshalJSON
+// can use the default encoding for them
+type results Results
+
+// MarshalJSON encodes the Results with the units of the measurements, eg.
 //
-func New(i i2c.Bus) (*Dev, error) {
-	d := &Dev{i2c: &i2c.Dev{Bus: i, Addr: 0x12}}
+//	{"cf_pm1":0, ... ,"timestamp":"2020-11-14T10:39:58Z","units":{"pm":"μg/m3","count":"0.1L"}}
+func (r Results) MarshalJSON() ([]byte, error) {
+	type units struct {
+		PM    string `json:"pm"`
+		Count string `json:"count"`
+	}
+	return json.Marshal(struct {
+		results
+		Units units `json:"units"`
+	}{
+		results: results(r),
+		Units:   units{PM: "μg/m3", Count: "0.1L"},
+	})
+}
 
-	_, err := d.ReadSensor()
-	if err != nil {
+// AQI returns the US EPA Air Quality Index, and its category, for the atmospheric
+// environment PM2.5 and PM10 readings, whichever is higher.
+//
+// The index is defined for 24 hour averages, so the AQI of a single reading is only
+// an indication of the current air quality.
+func (r Results) AQI() (int, aqi.Category) {
+	i := aqi.PM25(float64(r.EnvPm2_5))
+	if pm10 := aqi.PM10(float64(r.EnvPm10)); pm10 > i {
+		i = pm10
+	}
+	return i, aqi.CategoryOf(i)
+}
+
+// IndexFor returns the air quality index, and the name of its category, for the
+// atmospheric environment PM2.5 and PM10 readings using the selected standard.
+func (r Results) IndexFor(s aqi.Standard) (int, string) {
+	i := s.Index(float64(r.EnvPm2_5), float64(r.EnvPm10))
+	return i, s.Category(i)
+}
+
+// New returns a PMSA003I device struct for communicating with the device
+//
+// The options can change the I²C address with WithAddress, retry corrupted frames
+// with WithRetries, handle the readings made while the fan spins up with
+// WithWarmupPolicy, and set the GPIOs connected to the SET and RESET pins with
+// WithSetPin and WithResetPin.
+func New(i i2c.Bus, opts ...Option) (*Dev, error) {
+	d := newDev(opts)
+	d.i2c = &i2c.Dev{Bus: i, Addr: d.addr}
+	if err := d.init(); err != nil {
 		return nil, err
 	}
 	return d, nil
 }
 
+// newDev returns a Dev with the options applied
+func newDev(opts []Option) *Dev {
+	d := &Dev{addr: DefaultAddr, wakeTime: WakeTime}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// init releases the SET and RESET pins, and checks that the sensor returns a valid
+// frame
+func (d *Dev) init() error {
+	if d.setPin != nil {
+		if err := d.setPin.Out(gpio.High); err != nil {
+			return fmt.Errorf("pmsa003i: Error while setting the SET pin: %w", err)
+		}
+	}
+	if d.resetPin != nil {
+		if err := d.resetPin.Out(gpio.High); err != nil {
+			return fmt.Errorf("pmsa003i: Error while setting the RESET pin: %w", err)
+		}
+	}
+	d.warmupEnd = time.Now().Add(d.wakeTime)
+	if err := d.loadRuntime(); err != nil {
+		return err
+	}
+	d.fanStarted()
+	d.runtimeSaved = time.Now()
+
+	_, err := d.readRetries(context.Background())
+	return err
+}
+
 // Dev holds the connection and error details for the device
+//
+// It is safe for concurrent use, the sensor is only accessed by one goroutine at a
+// time.
 type Dev struct {
-	i2c conn.Conn // i2c device handle for the pmsa003i
-	err error     //nolint
+	i2c          conn.Conn     // i2c device handle for the pmsa003i
+	serial       io.ReadWriter // UART connected to a serial variant, instead of i2c
+	passive      bool          // The serial variant is in passive mode
+	setPin       gpio.PinOut   // Optional SET pin, low puts the sensor to sleep
+	resetPin     gpio.PinOut   // Optional RESET pin, low resets the sensor
+	addr         uint16        // I²C address of the sensor
+	wakeTime     time.Duration // How long to wait after waking up the sensor
+	warmup       WarmupPolicy  // How to handle the readings made during warm-up
+	warmupEnd    time.Time     // When the readings are stable after New, Wake, or Reset
+	retries      int           // Number of times to re-read a corrupted frame
+	last         [32]byte      // The last valid frame read from the sensor
+	raw          []byte        // The last frame read from the sensor, valid or not
+	asleep       bool          // The sensor has been put to sleep
+	runtimeFile  string        // File used to persist the fan runtime
+	runtime      time.Duration // Fan runtime before fanOn
+	fanOn        time.Time     // When the fan was started, zero when it is stopped
+	runtimeSaved time.Time     // When the runtime was last saved
+	mu           sync.Mutex    // Serializes access to the sensor, protects the state fields
+	err          error         //nolint
+}
+
+var _ conn.Resource = &Dev{}
+
+// String implements conn.Resource.
+func (d *Dev) String() string {
+	if d.serial != nil {
+		return fmt.Sprintf("pmsa003i{%v}", d.serial)
+	}
+	return fmt.Sprintf("pmsa003i{%s}", d.i2c)
 }
 
 // Halt implements conn.Resource.
+//
+// It puts the sensor to sleep if the SET pin was set with WithSetPin, or it is
+// connected with NewSerial, stopping the fan and laser until Wake is called, and saves
+// the fan runtime.
 func (d *Dev) Halt() error {
+	if !d.canSleep() {
+		return d.SaveRuntime()
+	}
+	return d.Sleep()
+}
+
+// Sleep stops the sensor's fan and laser, and saves the fan runtime. It drives the SET
+// pin low, or sends the sleep command to a sensor connected with NewSerial.
+func (d *Dev) Sleep() error {
+	if !d.canSleep() {
+		return fmt.Errorf("pmsa003i: Sleep requires the SET pin or a UART")
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.setAwake(false); err != nil {
+		return err
+	}
+	d.asleep = true
+	d.fanStopped()
+	return d.saveRuntime()
+}
+
+// Wake starts the sensor's fan and laser, and then waits 30s for the readings to
+// stabilize. It drives the SET pin high, or sends the wake up command to a sensor
+// connected with NewSerial.
+//
+// The sensor can be read by other goroutines while Wake is waiting, the readings are
+// handled by the warm-up policy and IsReady returns false until it returns.
+func (d *Dev) Wake() error {
+	if !d.canSleep() {
+		return fmt.Errorf("pmsa003i: Wake requires the SET pin or a UART")
+	}
+	if err := d.wake(); err != nil {
+		return err
+	}
+	time.Sleep(d.wakeTime)
+	return nil
+}
+
+// wake wakes up the sensor and starts the warm-up
+func (d *Dev) wake() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.setAwake(true); err != nil {
+		return err
+	}
+	d.asleep = false
+	d.warmupEnd = time.Now().Add(d.wakeTime)
+	d.fanStarted()
+	return nil
+}
+
+// canSleep returns true if the sensor can be put to sleep, with the SET pin or a UART
+// command
+func (d *Dev) canSleep() bool {
+	return d.setPin != nil || d.serial != nil
+}
+
+// setAwake wakes up the sensor, or puts it to sleep, using the SET pin if it was set
+// or the UART, the caller needs to hold mu
+func (d *Dev) setAwake(awake bool) error {
+	if d.setPin == nil {
+		return d.standby(!awake)
+	}
+	if err := d.setPin.Out(gpio.Level(awake)); err != nil {
+		return fmt.Errorf("pmsa003i: Error while setting the SET pin: %w", err)
+	}
+	return nil
+}
+
+// Reset resets the sensor by pulsing the RESET pin low, waits 30s for the readings to
+// stabilize, and then checks that the sensor returns a valid frame. This can recover
+// a wedged sensor without power cycling it.
+//
+// A sensor that was put to sleep with Sleep stays asleep.
+func (d *Dev) Reset() error {
+	if d.resetPin == nil {
+		return fmt.Errorf("pmsa003i: Reset requires the RESET pin")
+	}
+	asleep, err := d.reset()
+	if err != nil || asleep {
+		// A sleeping sensor stays asleep, there is no frame to check
+		return err
+	}
+	time.Sleep(d.wakeTime)
+
+	if _, err := d.readRetries(context.Background()); err != nil {
+		return fmt.Errorf("pmsa003i: Sensor did not recover after reset: %w", err)
+	}
 	return nil
 }
 
+// reset pulses the RESET pin and restarts the warm-up, it returns true if the sensor
+// is asleep
+func (d *Dev) reset() (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.resetPin.Out(gpio.Low); err != nil {
+		return false, fmt.Errorf("pmsa003i: Error while setting the RESET pin: %w", err)
+	}
+	time.Sleep(ResetPulse)
+	if err := d.resetPin.Out(gpio.High); err != nil {
+		return false, fmt.Errorf("pmsa003i: Error while setting the RESET pin: %w", err)
+	}
+	d.warmupEnd = time.Now().Add(d.wakeTime)
+	return d.asleep, nil
+}
+
+// IsReady returns true if the sensor is awake and its readings have stabilized, 30s
+// after New, Wake, or Reset.
+func (d *Dev) IsReady() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return !d.asleep && !time.Now().Before(d.warmupEnd)
+}
+
 // ReadSensor returns particle measurement results
+//
+// Use errors.Is with ErrBadStartWord, ErrChecksum, or ErrDeviceCode to check why the
+// frame was rejected. If WithRetries was used, frames with a bad start word or
+// checksum are read again before returning an error.
+//
+// The readings made during the 30s warm-up after New, Wake, or Reset are returned
+// unchanged unless WithWarmupPolicy was used to flag or suppress them.
 func (d *Dev) ReadSensor() (Results, error) {
+	return d.ReadSensorContext(context.Background())
+}
+
+// ReadSensorContext returns particle measurement results, like ReadSensor, or the
+// context's error if it is cancelled or its deadline passes first.
+//
+// The I²C transaction cannot be interrupted, when ctx is done it finishes in the
+// background and its results are discarded.
+func (d *Dev) ReadSensorContext(ctx context.Context) (Results, error) {
+	r, err := d.readRetries(ctx)
+	if err != nil || d.warmup == WarmupIgnore || d.IsReady() {
+		return r, err
+	}
+	if d.warmup == WarmupSuppress {
+		return Results{}, ErrWarmingUp
+	}
+	r.Warmup = true
+	return r, nil
+}
+
+// readRetries reads a frame, retrying corrupted frames if WithRetries was used
+func (d *Dev) readRetries(ctx context.Context) (Results, error) {
+	r, err := d.readSensorContext(ctx)
+	for retry := 0; err != nil && retry < d.retries; retry++ {
+		// Only corrupted frames are worth retrying
+		if !errors.Is(err, ErrBadStartWord) && !errors.Is(err, ErrChecksum) {
+			break
+		}
+		r, err = d.readSensorContext(ctx)
+	}
+	return r, err
+}
+
+// ReadNewSensor returns particle measurement results from a new frame, waiting for the
+// sensor to update its readings if the frame has already been read.
+//
+// The sensor only updates its frame every FrameInterval, but it can be read faster.
+// In clean air a new frame can have the same readings as the previous one, so after
+// waiting for 2 frame intervals the reading is returned with Stale set.
+func (d *Dev) ReadNewSensor(ctx context.Context) (Results, error) {
+	deadline := time.Now().Add(2 * FrameInterval)
+	for {
+		r, err := d.ReadSensorContext(ctx)
+		if err != nil || !r.Stale || time.Now().After(deadline) {
+			return r, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return Results{}, ctx.Err()
+		case <-time.After(FramePoll):
+		}
+	}
+}
+
+// LastFrame returns a copy of the last 32 byte frame read from the sensor, including
+// frames that were rejected by ReadSensor, or nil if no frame has been read. It can be
+// logged to debug checksum failures or firmware quirks.
+func (d *Dev) LastFrame() []byte {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.raw == nil {
+		return nil
+	}
+	return append([]byte(nil), d.raw...)
+}
+
+// readSensorContext reads one frame, returning early if ctx is done
+func (d *Dev) readSensorContext(ctx context.Context) (Results, error) {
+	if err := ctx.Err(); err != nil {
+		return Results{}, err
+	}
+	if ctx.Done() == nil {
+		// The context can never be cancelled
+		return d.readSensor()
+	}
+
+	type result struct {
+		r   Results
+		err error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		r, err := d.readSensor()
+		ch <- result{r, err}
+	}()
+	select {
+	case <-ctx.Done():
+		return Results{}, ctx.Err()
+	case res := <-ch:
+		return res.r, res.err
+	}
+}
+
+// readSensor reads and parses one frame from the sensor
+func (d *Dev) readSensor() (Results, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.asleep {
+		return Results{}, fmt.Errorf("pmsa003i: Sensor is asleep")
+	}
+	if time.Since(d.runtimeSaved) >= RuntimeSaveInterval {
+		// Errors are ignored here, SaveRuntime, Sleep, and Halt report them
+		d.saveRuntime() //nolint
+	}
+
+	var data [32]byte
+	var err error
+	if d.serial != nil {
+		data, err = d.readSerialFrame()
+	} else {
+		data, err = d.readI2CFrame()
+	}
+	if err != nil {
+		return Results{}, err
+	}
+	d.raw = append(d.raw[:0], data[:]...)
+
+	r, err := ParseFrame(data[:])
+	if err != nil {
+		return Results{}, err
+	}
+	r.Timestamp = time.Now()
+	r.Stale = data == d.last
+	d.last = data
+	return r, nil
+}
+
+// readI2CFrame reads a frame from the sensor's I²C registers
+func (d *Dev) readI2CFrame() ([32]byte, error) {
 	// Receive 32 bytes
 	var data [32]byte
 	if err := d.i2c.Tx(nil, data[:]); err != nil {
-		return Results{}, fmt.Errorf("pmsa003i: Error while reading the sensor: %w", err)
+		return data, fmt.Errorf("pmsa003i: Error while reading the sensor: %w", err)
 	}
+	return data, nil
+}
 
-	if word(data[:], 0) != 0x424d {
-		return Results{}, fmt.Errorf("pmsa003i: Bad start word")
+// ParseFrame checks a 32 byte Plantower frame and returns its measurements
+//
+// The frame is the same when it is read over I²C or UART, it can be used to parse
+// frames read some other way, eg. relayed over a network. Use errors.Is with
+// ErrBadStartWord, ErrChecksum, or ErrDeviceCode to check why the frame was rejected.
+// The Timestamp is not set.
+func ParseFrame(data []byte) (Results, error) {
+	if len(data) != 32 {
+		return Results{}, fmt.Errorf("pmsa003i: Frame is %d bytes, not 32", len(data))
+	}
+	if word(data, 0) != 0x424d {
+		return Results{}, ErrBadStartWord
 	}
-	if !checksum(data[:]) {
-		return Results{}, fmt.Errorf("pmsa003i: Bad checksum")
+	if !checksum(data) {
+		return Results{}, ErrChecksum
 	}
 	if data[0x1d] != 0x00 {
-		return Results{}, fmt.Errorf("pmsa003i: Error code %x", data[0x1d])
+		return Results{}, &DeviceCodeError{Code: data[0x1d]}
 	}
 
 	return Results{
-		CfPm1:    word(data[:], 0x04),
-		CfPm2_5:  word(data[:], 0x06),
-		CfPm10:   word(data[:], 0x08),
-		EnvPm1:   word(data[:], 0x0a),
-		EnvPm2_5: word(data[:], 0x0c),
-		EnvPm10:  word(data[:], 0x0e),
-		Cnt0_3:   word(data[:], 0x10),
-		Cnt0_5:   word(data[:], 0x12),
-		Cnt1:     word(data[:], 0x14),
-		Cnt2_5:   word(data[:], 0x16),
-		Cnt5:     word(data[:], 0x18),
-		Cnt10:    word(data[:], 0x1a),
+		CfPm1:    word(data, 0x04),
+		CfPm2_5:  word(data, 0x06),
+		CfPm10:   word(data, 0x08),
+		EnvPm1:   word(data, 0x0a),
+		EnvPm2_5: word(data, 0x0c),
+		EnvPm10:  word(data, 0x0e),
+		Cnt0_3:   word(data, 0x10),
+		Cnt0_5:   word(data, 0x12),
+		Cnt1:     word(data, 0x14),
+		Cnt2_5:   word(data, 0x16),
+		Cnt5:     word(data, 0x18),
+		Cnt10:    word(data, 0x1a),
 		Version:  data[0x1c],
 	}, nil
 }
@@ -5,11 +5,18 @@
 package pmsa003i
 
 import (
-	"fmt"
-	"strings"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"testing"
+	"time"
 
+	"periph.io/x/periph/conn/gpio"
+	"periph.io/x/periph/conn/gpio/gpiotest"
 	"periph.io/x/periph/conn/i2c/i2ctest"
+
+	"github.com/bcl/air-sensors/aqi"
 )
 
 var (
@@ -28,6 +35,16 @@ var (
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+	NewSensorData = []byte{
+		0x42, 0x4d, 0x00, 0x1c, 0x00, 0x00, 0x00, 0x01,
+		0x00, 0x05, 0x00, 0x00, 0x00, 0x01, 0x00, 0x05,
+		0x00, 0x80, 0x00, 0x2a, 0x00, 0x0f, 0x00, 0x09,
+		0x00, 0x03, 0x00, 0x03, 0x97, 0x00, 0x02, 0x16}
+	ErrorCodeSensorData = []byte{
+		0x42, 0x4d, 0x00, 0x1c, 0x00, 0x00, 0x00, 0x01,
+		0x00, 0x05, 0x00, 0x00, 0x00, 0x01, 0x00, 0x05,
+		0x00, 0x7e, 0x00, 0x2a, 0x00, 0x0f, 0x00, 0x09,
+		0x00, 0x03, 0x00, 0x03, 0x97, 0x05, 0x02, 0x19}
 )
 
 func TestWord(t *testing.T) {
@@ -97,7 +114,7 @@ func TestReadSensorBadStart(t *testing.T) {
 	if err == nil {
 		t.Fatal("Read Sensor bad start Error")
 	}
-	if !strings.Contains(fmt.Sprintf("%s", err), "Bad start word") {
+	if !errors.Is(err, ErrBadStartWord) {
 		t.Fatalf("Not bad start Error: %s", err)
 	}
 }
@@ -118,11 +135,33 @@ func TestReadSensorBadChecksum(t *testing.T) {
 	if err == nil {
 		t.Fatal("Read Sensor bad checksum Error")
 	}
-	if !strings.Contains(fmt.Sprintf("%s", err), "Bad checksum") {
+	if !errors.Is(err, ErrChecksum) {
 		t.Fatalf("Not bad checksum Error: %s", err)
 	}
 }
 
+func TestReadSensorErrorCode(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Sensor data with an error code
+			{Addr: 0x12, W: []byte{}, R: GoodSensorData},
+			{Addr: 0x12, W: []byte{}, R: ErrorCodeSensorData},
+		},
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("Good sensor data Error: %s", err)
+	}
+	_, err = d.ReadSensor()
+	if !errors.Is(err, ErrDeviceCode) {
+		t.Fatalf("Not error code Error: %s", err)
+	}
+	var e *DeviceCodeError
+	if !errors.As(err, &e) || e.Code != 0x05 {
+		t.Fatalf("Wrong error code Error: %s", err)
+	}
+}
+
 func TestReadSensor(t *testing.T) {
 	bus := i2ctest.Playback{
 		Ops: []i2ctest.IO{
@@ -153,8 +192,397 @@ func TestReadSensor(t *testing.T) {
 		Cnt5:     3,
 		Cnt10:    3,
 		Version:  151,
+		// New already read the same frame
+		Stale: true,
 	}
+	if r.Timestamp.IsZero() {
+		t.Fatal("Read Sensor Timestamp Error")
+	}
+	expected.Timestamp = r.Timestamp
 	if r != expected {
 		t.Fatalf("Read Sensor Data Error: %v", r)
 	}
 }
+
+func TestAQI(t *testing.T) {
+	// PM2.5 is higher
+	r := Results{EnvPm2_5: 12, EnvPm10: 20}
+	if i, cat := r.AQI(); i != 56 || cat != aqi.Moderate {
+		t.Errorf("AQI is wrong: %d %s", i, cat)
+	}
+	// PM10 is higher
+	r = Results{EnvPm2_5: 5, EnvPm10: 200}
+	if i, cat := r.AQI(); i != 123 || cat != aqi.UnhealthyForSensitiveGroups {
+		t.Errorf("AQI is wrong: %d %s", i, cat)
+	}
+}
+
+func TestIndexFor(t *testing.T) {
+	r := Results{EnvPm2_5: 20, EnvPm10: 30}
+	if i, cat := r.IndexFor(aqi.CAQI); i != 33 || cat != "Low" {
+		t.Errorf("CAQI is wrong: %d %s", i, cat)
+	}
+	if i, cat := r.IndexFor(aqi.ChinaHJ633); i != 30 || cat != "Excellent" {
+		t.Errorf("China HJ 633 index is wrong: %d %s", i, cat)
+	}
+}
+
+func TestSleepWake(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x12, W: []byte{}, R: GoodSensorData},
+			{Addr: 0x12, W: []byte{}, R: GoodSensorData},
+		},
+	}
+	set := &gpiotest.Pin{N: "SET"}
+	d, err := New(&bus, WithSetPin(set), WithWakeTime(time.Millisecond))
+	if err != nil {
+		t.Fatalf("Good sensor data Error: %s", err)
+	}
+	if set.Read() != gpio.High {
+		t.Fatal("New did not wake the sensor")
+	}
+
+	if err := d.Sleep(); err != nil {
+		t.Fatalf("Sleep Error: %s", err)
+	}
+	if set.Read() != gpio.Low {
+		t.Fatal("Sleep did not set the SET pin low")
+	}
+	if _, err := d.ReadSensor(); err == nil {
+		t.Fatal("Read Sensor while asleep Error")
+	}
+
+	if err := d.Wake(); err != nil {
+		t.Fatalf("Wake Error: %s", err)
+	}
+	if set.Read() != gpio.High {
+		t.Fatal("Wake did not set the SET pin high")
+	}
+	if _, err := d.ReadSensor(); err != nil {
+		t.Fatalf("Read Sensor Error: %s", err)
+	}
+}
+
+func TestSleepWithoutPin(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x12, W: []byte{}, R: GoodSensorData},
+		},
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("Good sensor data Error: %s", err)
+	}
+	if err := d.Sleep(); err == nil {
+		t.Fatal("Sleep without a SET pin Error")
+	}
+}
+
+func TestReset(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x12, W: []byte{}, R: GoodSensorData},
+			{Addr: 0x12, W: []byte{}, R: GoodSensorData},
+			{Addr: 0x12, W: []byte{}, R: BadStartSensorData},
+		},
+	}
+	reset := &gpiotest.Pin{N: "RESET"}
+	d, err := New(&bus, WithResetPin(reset), WithWakeTime(time.Millisecond))
+	if err != nil {
+		t.Fatalf("Good sensor data Error: %s", err)
+	}
+	if reset.Read() != gpio.High {
+		t.Fatal("New did not release the RESET pin")
+	}
+	if err := d.Reset(); err != nil {
+		t.Fatalf("Reset Error: %s", err)
+	}
+	if reset.Read() != gpio.High {
+		t.Fatal("Reset did not release the RESET pin")
+	}
+
+	// The first frame after the reset is bad
+	if err := d.Reset(); err == nil {
+		t.Fatal("Reset with a bad frame Error")
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	r := Results{EnvPm2_5: 12, Cnt0_3: 126, Version: 151, Timestamp: time.Date(2020, 11, 14, 10, 39, 58, 0, time.UTC)}
+	data, err := json.Marshal(r)
+	if err != nil {
+		t.Fatalf("MarshalJSON Error: %s", err)
+	}
+	expected := `{"cf_pm1":0,"cf_pm2_5":0,"cf_pm10":0,"env_pm1":0,"env_pm2_5":12,"env_pm10":0,` +
+		`"cnt0_3":126,"cnt0_5":0,"cnt1":0,"cnt2_5":0,"cnt5":0,"cnt10":0,"version":151,` +
+		`"timestamp":"2020-11-14T10:39:58Z","units":{"pm":"μg/m3","count":"0.1L"}}`
+	if string(data) != expected {
+		t.Fatalf("MarshalJSON Data Error: %s", data)
+	}
+
+	var u Results
+	if err := json.Unmarshal(data, &u); err != nil {
+		t.Fatalf("Unmarshal Error: %s", err)
+	}
+	if !u.Timestamp.Equal(r.Timestamp) {
+		t.Fatalf("Unmarshal Timestamp Error: %v", u.Timestamp)
+	}
+	u.Timestamp = r.Timestamp
+	if u != r {
+		t.Fatalf("Unmarshal Data Error: %v", u)
+	}
+}
+
+func TestRetries(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x12, W: []byte{}, R: GoodSensorData},
+			{Addr: 0x12, W: []byte{}, R: BadChecksumSensorData},
+			{Addr: 0x12, W: []byte{}, R: BadStartSensorData},
+			{Addr: 0x12, W: []byte{}, R: GoodSensorData},
+			// Device errors are not retried
+			{Addr: 0x12, W: []byte{}, R: ErrorCodeSensorData},
+			{Addr: 0x12, W: []byte{}, R: BadChecksumSensorData},
+			{Addr: 0x12, W: []byte{}, R: BadChecksumSensorData},
+			{Addr: 0x12, W: []byte{}, R: BadChecksumSensorData},
+		},
+	}
+	d, err := New(&bus, WithRetries(2))
+	if err != nil {
+		t.Fatalf("Good sensor data Error: %s", err)
+	}
+	if _, err := d.ReadSensor(); err != nil {
+		t.Fatalf("Read Sensor with retries Error: %s", err)
+	}
+	if _, err := d.ReadSensor(); !errors.Is(err, ErrDeviceCode) {
+		t.Fatalf("Not error code Error: %s", err)
+	}
+	// Gives up after 2 retries
+	if _, err := d.ReadSensor(); !errors.Is(err, ErrChecksum) {
+		t.Fatalf("Not bad checksum Error: %s", err)
+	}
+}
+
+func TestReadSensorContext(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x12, W: []byte{}, R: GoodSensorData},
+			{Addr: 0x12, W: []byte{}, R: GoodSensorData},
+		},
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("Good sensor data Error: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := d.ReadSensorContext(ctx); err != nil {
+		t.Fatalf("Read Sensor Context Error: %s", err)
+	}
+
+	// A cancelled context does not read the sensor
+	cancel()
+	if _, err := d.ReadSensorContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Not cancelled Error: %s", err)
+	}
+}
+
+func TestReadNewSensor(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x12, W: []byte{}, R: GoodSensorData},
+			// The same frame is read until the sensor updates it
+			{Addr: 0x12, W: []byte{}, R: GoodSensorData},
+			{Addr: 0x12, W: []byte{}, R: NewSensorData},
+		},
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("Good sensor data Error: %s", err)
+	}
+	r, err := d.ReadNewSensor(context.Background())
+	if err != nil {
+		t.Fatalf("Read New Sensor Error: %s", err)
+	}
+	if r.Stale || r.Cnt0_3 != 128 {
+		t.Fatalf("Read New Sensor Data Error: %v", r)
+	}
+}
+
+func TestConcurrentReads(t *testing.T) {
+	ops := []i2ctest.IO{{Addr: 0x12, W: []byte{}, R: GoodSensorData}}
+	for i := 0; i < 10; i++ {
+		ops = append(ops, i2ctest.IO{Addr: 0x12, W: []byte{}, R: GoodSensorData})
+	}
+	bus := i2ctest.Playback{Ops: ops}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("Good sensor data Error: %s", err)
+	}
+
+	errs := make(chan error, 10)
+	for g := 0; g < 2; g++ {
+		go func() {
+			for i := 0; i < 5; i++ {
+				_, err := d.ReadSensor()
+				errs <- err
+			}
+		}()
+	}
+	for i := 0; i < 10; i++ {
+		if err := <-errs; err != nil {
+			t.Fatalf("Concurrent Read Sensor Error: %s", err)
+		}
+	}
+}
+
+func TestWithAddress(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x13, W: []byte{}, R: GoodSensorData},
+		},
+	}
+	if _, err := New(&bus, WithAddress(0x13)); err != nil {
+		t.Fatalf("WithAddress Error: %s", err)
+	}
+}
+
+func TestWarmupPolicy(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x12, W: []byte{}, R: GoodSensorData},
+			{Addr: 0x12, W: []byte{}, R: GoodSensorData},
+			{Addr: 0x12, W: []byte{}, R: GoodSensorData},
+			{Addr: 0x12, W: []byte{}, R: GoodSensorData},
+			{Addr: 0x12, W: []byte{}, R: GoodSensorData},
+		},
+	}
+	d, err := New(&bus, WithWarmupPolicy(WarmupFlag), WithWakeTime(50*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Good sensor data Error: %s", err)
+	}
+	r, err := d.ReadSensor()
+	if err != nil || !r.Warmup {
+		t.Fatalf("Warmup Flag Error: %v %s", r, err)
+	}
+
+	d, err = New(&bus, WithWarmupPolicy(WarmupSuppress), WithWakeTime(50*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Good sensor data Error: %s", err)
+	}
+	if _, err := d.ReadSensor(); !errors.Is(err, ErrWarmingUp) {
+		t.Fatalf("Not warming up Error: %s", err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	r, err = d.ReadSensor()
+	if err != nil || r.Warmup {
+		t.Fatalf("After warm-up Error: %v %s", r, err)
+	}
+}
+
+func TestHalt(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x12, W: []byte{}, R: GoodSensorData},
+			{Addr: 0x12, W: []byte{}, R: GoodSensorData},
+		},
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("Good sensor data Error: %s", err)
+	}
+	if d.String() != "pmsa003i{playback(18)}" {
+		t.Fatalf("String Error: %s", d)
+	}
+	// Without a SET pin there is nothing to halt
+	if err := d.Halt(); err != nil {
+		t.Fatalf("Halt Error: %s", err)
+	}
+
+	set := &gpiotest.Pin{N: "SET"}
+	d, err = New(&bus, WithSetPin(set))
+	if err != nil {
+		t.Fatalf("Good sensor data Error: %s", err)
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatalf("Halt Error: %s", err)
+	}
+	if set.Read() != gpio.Low {
+		t.Fatal("Halt did not put the sensor to sleep")
+	}
+}
+
+func TestIsReady(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x12, W: []byte{}, R: GoodSensorData},
+		},
+	}
+	set := &gpiotest.Pin{N: "SET"}
+	d, err := New(&bus, WithSetPin(set), WithWakeTime(50*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Good sensor data Error: %s", err)
+	}
+	if d.IsReady() {
+		t.Fatal("IsReady during warm-up Error")
+	}
+	time.Sleep(50 * time.Millisecond)
+	if !d.IsReady() {
+		t.Fatal("IsReady after warm-up Error")
+	}
+
+	if err := d.Sleep(); err != nil {
+		t.Fatalf("Sleep Error: %s", err)
+	}
+	if d.IsReady() {
+		t.Fatal("IsReady while asleep Error")
+	}
+	if err := d.Wake(); err != nil {
+		t.Fatalf("Wake Error: %s", err)
+	}
+	if !d.IsReady() {
+		t.Fatal("IsReady after Wake Error")
+	}
+}
+
+func TestParseFrame(t *testing.T) {
+	r, err := ParseFrame(GoodSensorData)
+	if err != nil {
+		t.Fatalf("ParseFrame Error: %s", err)
+	}
+	if r.Cnt0_3 != 126 || r.Version != 151 || !r.Timestamp.IsZero() {
+		t.Fatalf("ParseFrame Data Error: %v", r)
+	}
+	if _, err := ParseFrame(BadChecksumSensorData); !errors.Is(err, ErrChecksum) {
+		t.Fatalf("Not bad checksum Error: %s", err)
+	}
+	if _, err := ParseFrame(GoodSensorData[:30]); err == nil {
+		t.Fatal("ParseFrame short frame Error")
+	}
+}
+
+func TestLastFrame(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x12, W: []byte{}, R: GoodSensorData},
+			{Addr: 0x12, W: []byte{}, R: BadChecksumSensorData},
+		},
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("Good sensor data Error: %s", err)
+	}
+	if !bytes.Equal(d.LastFrame(), GoodSensorData) {
+		t.Fatalf("LastFrame Error: %v", d.LastFrame())
+	}
+	// Rejected frames are returned too
+	if _, err := d.ReadSensor(); err == nil {
+		t.Fatal("Read Sensor bad checksum Error")
+	}
+	if !bytes.Equal(d.LastFrame(), BadChecksumSensorData) {
+		t.Fatalf("LastFrame Error: %v", d.LastFrame())
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package pmsa003i
+
+import (
+	"context"
+	"time"
+)
+
+// Poll reads the sensor every interval and sends the Results on the returned channel
+// until ctx is cancelled, when the channel is closed.
+//
+// Frames that fail to read, eg. with a bad checksum, are skipped. Use ReadSensor
+// directly when the errors are needed.
+func (d *Dev) Poll(ctx context.Context, interval time.Duration) <-chan Results {
+	ch := make(chan Results)
+	go func() {
+		defer close(ch)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			r, err := d.ReadSensorContext(ctx)
+			if err != nil {
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case ch <- r:
+			}
+		}
+	}()
+	return ch
+}
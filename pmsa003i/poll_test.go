@@ -0,0 +1,45 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package pmsa003i
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"periph.io/x/periph/conn/i2c/i2ctest"
+)
+
+func TestPoll(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x12, W: []byte{}, R: GoodSensorData},
+			// The bad frames are skipped
+			{Addr: 0x12, W: []byte{}, R: BadChecksumSensorData},
+			{Addr: 0x12, W: []byte{}, R: GoodSensorData},
+			{Addr: 0x12, W: []byte{}, R: BadStartSensorData},
+			{Addr: 0x12, W: []byte{}, R: GoodSensorData},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("Good sensor data Error: %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := d.Poll(ctx, time.Millisecond)
+	for i := 0; i < 2; i++ {
+		r := <-ch
+		if r.Cnt0_3 != 126 {
+			t.Fatalf("Poll Data Error: %v", r)
+		}
+	}
+	cancel()
+
+	// The channel is closed after cancelling
+	for range ch {
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package pmsa003i
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FanMTTF is the typical lifetime of the sensor's fan and laser, from the datasheet's
+// MTTF of 8000 hours
+const FanMTTF = 8000 * time.Hour
+
+// RuntimeSaveInterval is how often ReadSensor saves the fan runtime to the file set
+// with WithRuntimeFile, in case the program does not exit cleanly.
+const RuntimeSaveInterval = time.Hour
+
+// runtimeJSON is the runtime file contents
+type runtimeJSON struct {
+	Seconds   int64     `json:"fan_seconds"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// FanRuntime returns how long the sensor's fan has been running, including the time
+// saved to the runtime file by earlier runs if WithRuntimeFile was used.
+//
+// Compare it with FanMTTF to schedule replacing the sensor.
+func (d *Dev) FanRuntime() time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.fanRuntime()
+}
+
+// SaveRuntime saves the fan runtime to the file set with WithRuntimeFile
+//
+// It is also saved by Sleep and Halt, and every RuntimeSaveInterval by ReadSensor.
+func (d *Dev) SaveRuntime() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.saveRuntime()
+}
+
+// fanRuntime returns the total runtime, the caller needs to hold mu
+func (d *Dev) fanRuntime() time.Duration {
+	if d.fanOn.IsZero() {
+		return d.runtime
+	}
+	return d.runtime + time.Since(d.fanOn)
+}
+
+// fanStarted starts counting the fan's runtime, the caller needs to hold mu
+func (d *Dev) fanStarted() {
+	if d.fanOn.IsZero() {
+		d.fanOn = time.Now()
+	}
+}
+
+// fanStopped adds the time the fan was running to the runtime, the caller needs to
+// hold mu
+func (d *Dev) fanStopped() {
+	d.runtime = d.fanRuntime()
+	d.fanOn = time.Time{}
+}
+
+// loadRuntime reads the runtime from the runtime file, a missing file is not an error
+func (d *Dev) loadRuntime() error {
+	if d.runtimeFile == "" {
+		return nil
+	}
+	data, err := ioutil.ReadFile(d.runtimeFile)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("pmsa003i: Error while reading the runtime file: %w", err)
+	}
+
+	var rj runtimeJSON
+	if err := json.Unmarshal(data, &rj); err != nil {
+		return fmt.Errorf("pmsa003i: Error while parsing the runtime file %s: %w", d.runtimeFile, err)
+	}
+	d.runtime = time.Duration(rj.Seconds) * time.Second
+	return nil
+}
+
+// saveRuntime writes the runtime to the runtime file, the caller needs to hold mu
+//
+// The data is written to a temporary file in the same directory which is then renamed
+// over the original, so that a crash or power loss cannot leave a partial file behind.
+func (d *Dev) saveRuntime() error {
+	if d.runtimeFile == "" {
+		return nil
+	}
+	data, err := json.Marshal(runtimeJSON{
+		Seconds:   int64(d.fanRuntime() / time.Second),
+		Timestamp: time.Now().UTC().Truncate(time.Second),
+	})
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(d.runtimeFile), filepath.Base(d.runtimeFile)+".")
+	if err != nil {
+		return fmt.Errorf("pmsa003i: Error while saving the runtime file: %w", err)
+	}
+	// Cleanup the temporary file if anything fails, ignore errors after the rename
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(append(data, '\n')); err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), 0644)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), d.runtimeFile)
+	}
+	if err != nil {
+		return fmt.Errorf("pmsa003i: Error while saving the runtime file: %w", err)
+	}
+	d.runtimeSaved = time.Now()
+	return nil
+}
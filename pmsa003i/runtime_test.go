@@ -0,0 +1,83 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package pmsa003i
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"periph.io/x/periph/conn/gpio/gpiotest"
+	"periph.io/x/periph/conn/i2c/i2ctest"
+)
+
+func TestFanRuntime(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pmsa003i-")
+	if err != nil {
+		t.Fatalf("TempDir Error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "runtime.json")
+	if err := ioutil.WriteFile(path, []byte(`{"fan_seconds":36000}`), 0644); err != nil {
+		t.Fatalf("WriteFile Error: %s", err)
+	}
+
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x12, W: []byte{}, R: GoodSensorData},
+		},
+	}
+	set := &gpiotest.Pin{N: "SET"}
+	d, err := New(&bus, WithSetPin(set), WithRuntimeFile(path))
+	if err != nil {
+		t.Fatalf("Good sensor data Error: %s", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if err := d.Sleep(); err != nil {
+		t.Fatalf("Sleep Error: %s", err)
+	}
+
+	// The fan is not running while asleep
+	runtime := d.FanRuntime()
+	if runtime < 10*time.Hour+10*time.Millisecond || runtime > 11*time.Hour {
+		t.Fatalf("FanRuntime Error: %s", runtime)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if d.FanRuntime() != runtime {
+		t.Fatalf("FanRuntime while asleep Error: %s", d.FanRuntime())
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile Error: %s", err)
+	}
+	var rj runtimeJSON
+	if err := json.Unmarshal(data, &rj); err != nil {
+		t.Fatalf("Unmarshal Error: %s", err)
+	}
+	if rj.Seconds != 36000 || rj.Timestamp.IsZero() {
+		t.Fatalf("Saved runtime Error: %s", data)
+	}
+}
+
+func TestBadRuntimeFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pmsa003i-")
+	if err != nil {
+		t.Fatalf("TempDir Error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "runtime.json")
+	if err := ioutil.WriteFile(path, []byte("bad data"), 0644); err != nil {
+		t.Fatalf("WriteFile Error: %s", err)
+	}
+
+	bus := i2ctest.Playback{DontPanic: true}
+	if _, err := New(&bus, WithRuntimeFile(path)); err == nil {
+		t.Fatal("Bad runtime file Error")
+	}
+}
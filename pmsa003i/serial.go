@@ -0,0 +1,117 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package pmsa003i
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/bcl/air-sensors/internal/uart"
+)
+
+// Commands for the serial variants of the sensor
+const (
+	cmdRead    byte = 0xe2 // Request a frame in passive mode
+	cmdMode    byte = 0xe1 // Set passive (0) or active (1) mode
+	cmdStandby byte = 0xe4 // Sleep (0) or wake up (1)
+)
+
+// NewSerial returns a device struct for communicating with a serial variant of the
+// sensor, the PMS5003, PMS7003, or PMSA003, over a UART.
+//
+// The port needs to be configured for 9600 baud 8N1 before calling NewSerial. In its
+// default active mode the sensor sends a frame every time its readings are updated,
+// ReadSensor returns the next frame that is received. Use SetPassiveMode to only read
+// a frame when ReadSensor is called.
+//
+// Without a SET pin, Sleep and Wake send the standby commands over the UART.
+func NewSerial(port io.ReadWriter, opts ...Option) (*Dev, error) {
+	d := newDev(opts)
+	d.serial = port
+	if err := d.init(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// SetPassiveMode switches a sensor connected with NewSerial between passive mode,
+// where ReadSensor requests each frame, and the default active mode, where the sensor
+// sends a frame every time its readings are updated.
+func (d *Dev) SetPassiveMode(passive bool) error {
+	if d.serial == nil {
+		return fmt.Errorf("pmsa003i: Passive mode requires a UART")
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var mode uint16 = 1
+	if passive {
+		mode = 0
+	}
+	if err := d.command(cmdMode, mode); err != nil {
+		return err
+	}
+	d.passive = passive
+	return nil
+}
+
+// standby sends the sleep or wake up command, the caller needs to hold mu
+func (d *Dev) standby(sleep bool) error {
+	if d.serial == nil {
+		return fmt.Errorf("pmsa003i: Standby requires the SET pin or a UART")
+	}
+	var data uint16 = 1
+	if sleep {
+		data = 0
+	}
+	return d.command(cmdStandby, data)
+}
+
+// command sends a command to the sensor over the UART
+//
+// The command is the 0x42 0x4d start word, the command byte, 2 bytes of data, and the
+// 16 bit sum of the other bytes. The sensor's replies to the mode and standby commands
+// are skipped by readSerialFrame.
+func (d *Dev) command(cmd byte, data uint16) error {
+	buf := []byte{0x42, 0x4d, cmd, byte(data >> 8), byte(data), 0, 0}
+	var sum uint16
+	for _, b := range buf[:5] {
+		sum += uint16(b)
+	}
+	buf[5], buf[6] = byte(sum>>8), byte(sum)
+	if _, err := d.serial.Write(buf); err != nil {
+		return fmt.Errorf("pmsa003i: Error while sending command 0x%02x: %w", cmd, err)
+	}
+	return nil
+}
+
+// readSerialFrame reads the next frame from the UART, skipping any bytes before its
+// start word and any replies to commands
+func (d *Dev) readSerialFrame() ([32]byte, error) {
+	var data [32]byte
+	if d.passive {
+		if err := d.command(cmdRead, 0); err != nil {
+			return data, err
+		}
+	}
+
+	for {
+		// Look for the 0x42 0x4d start word, and read the frame length
+		if err := uart.ReadFrame(d.serial, data[:4], 0x42, 0x4d); err != nil {
+			return data, fmt.Errorf("pmsa003i: Error while reading the sensor: %w", err)
+		}
+
+		// Replies to commands have 4 more bytes, skip them
+		if word(data[:], 2) != 4 {
+			break
+		}
+		if _, err := io.ReadFull(d.serial, data[4:8]); err != nil {
+			return data, fmt.Errorf("pmsa003i: Error while reading the sensor: %w", err)
+		}
+	}
+	if _, err := io.ReadFull(d.serial, data[4:]); err != nil {
+		return data, fmt.Errorf("pmsa003i: Error while reading the sensor: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,95 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package pmsa003i
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestSerial(t *testing.T) {
+	var port bytes.Buffer
+	// A partial frame, and noise, before the first start word
+	port.Write(GoodSensorData[20:])
+	port.Write([]byte{0x42, 0x00, 0x4d})
+	port.Write(GoodSensorData)
+	port.Write(NewSensorData)
+	port.Write(BadChecksumSensorData)
+	port.Write(GoodSensorData[:16])
+
+	d, err := NewSerial(&port)
+	if err != nil {
+		t.Fatalf("Good sensor data Error: %s", err)
+	}
+	r, err := d.ReadSensor()
+	if err != nil {
+		t.Fatalf("Read Sensor Error: %s", err)
+	}
+	if r.Cnt0_3 != 128 {
+		t.Fatalf("Read Sensor Data Error: %v", r)
+	}
+	if _, err := d.ReadSensor(); !errors.Is(err, ErrChecksum) {
+		t.Fatalf("Not bad checksum Error: %s", err)
+	}
+	// The last frame is incomplete
+	if _, err := d.ReadSensor(); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("Not unexpected EOF Error: %s", err)
+	}
+}
+
+// fakePort is a UART that returns the data in r, and records the data written to it
+type fakePort struct {
+	r bytes.Buffer
+	w bytes.Buffer
+}
+
+func (p *fakePort) Read(b []byte) (int, error) {
+	return p.r.Read(b)
+}
+
+func (p *fakePort) Write(b []byte) (int, error) {
+	return p.w.Write(b)
+}
+
+func TestSerialCommands(t *testing.T) {
+	var port fakePort
+	port.r.Write(GoodSensorData)
+	// The reply to the passive mode command is skipped
+	port.r.Write([]byte{0x42, 0x4d, 0x00, 0x04, 0xe1, 0x00, 0x01, 0x74})
+	port.r.Write(NewSensorData)
+
+	d, err := NewSerial(&port, WithWakeTime(0))
+	if err != nil {
+		t.Fatalf("Good sensor data Error: %s", err)
+	}
+	if err := d.SetPassiveMode(true); err != nil {
+		t.Fatalf("SetPassiveMode Error: %s", err)
+	}
+	r, err := d.ReadSensor()
+	if err != nil {
+		t.Fatalf("Read Sensor Error: %s", err)
+	}
+	if r.Cnt0_3 != 128 {
+		t.Fatalf("Read Sensor Data Error: %v", r)
+	}
+	if err := d.Sleep(); err != nil {
+		t.Fatalf("Sleep Error: %s", err)
+	}
+	if err := d.Wake(); err != nil {
+		t.Fatalf("Wake Error: %s", err)
+	}
+
+	expected := []byte{
+		0x42, 0x4d, 0xe1, 0x00, 0x00, 0x01, 0x70, // Passive mode
+		0x42, 0x4d, 0xe2, 0x00, 0x00, 0x01, 0x71, // Read
+		0x42, 0x4d, 0xe4, 0x00, 0x00, 0x01, 0x73, // Sleep
+		0x42, 0x4d, 0xe4, 0x00, 0x01, 0x01, 0x74, // Wake up
+	}
+	if !bytes.Equal(port.w.Bytes(), expected) {
+		t.Fatalf("Commands Error: % x", port.w.Bytes())
+	}
+}
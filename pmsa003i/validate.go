@@ -0,0 +1,80 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package pmsa003i
+
+import (
+	"fmt"
+	"time"
+)
+
+// Validate returns an error matching ErrImplausible if the readings are physically
+// impossible, eg. a higher PM1.0 than PM2.5, or more particles > 1.0μm than > 0.5μm
+func (r Results) Validate() error {
+	if r.CfPm1 > r.CfPm2_5 || r.CfPm2_5 > r.CfPm10 {
+		return fmt.Errorf("%w: standard particle PM1.0 %d, PM2.5 %d, PM10 %d",
+			ErrImplausible, r.CfPm1, r.CfPm2_5, r.CfPm10)
+	}
+	if r.EnvPm1 > r.EnvPm2_5 || r.EnvPm2_5 > r.EnvPm10 {
+		return fmt.Errorf("%w: atmospheric environment PM1.0 %d, PM2.5 %d, PM10 %d",
+			ErrImplausible, r.EnvPm1, r.EnvPm2_5, r.EnvPm10)
+	}
+	counts := []uint16{r.Cnt0_3, r.Cnt0_5, r.Cnt1, r.Cnt2_5, r.Cnt5, r.Cnt10}
+	for i := 1; i < len(counts); i++ {
+		if counts[i] > counts[i-1] {
+			return fmt.Errorf("%w: particle counts are not cumulative: %v", ErrImplausible, counts)
+		}
+	}
+	return nil
+}
+
+// Validator checks a sequence of Results for signs of a failing sensor
+//
+// As well as the checks done by Results.Validate it detects a sensor that reports no
+// particles at all, or returns identical readings, for too long. Even clean air has
+// some particles > 0.3μm, and the readings normally change from frame to frame.
+type Validator struct {
+	zeroLimit  time.Duration
+	stuckLimit time.Duration
+
+	last       Results   // The previous Results
+	zeroSince  time.Time // When the counters started reading zero
+	stuckSince time.Time // When the readings stopped changing
+}
+
+// NewValidator returns a Validator that reports a stuck sensor if all of the counters
+// are zero for zeroLimit, or the readings are identical for stuckLimit. A limit of 0
+// disables that check.
+func NewValidator(zeroLimit, stuckLimit time.Duration) *Validator {
+	return &Validator{zeroLimit: zeroLimit, stuckLimit: stuckLimit}
+}
+
+// Check returns an error matching ErrImplausible for impossible readings, or ErrStuck
+// if the sensor appears to be stuck. It uses the Results' Timestamp to measure how
+// long the readings have been stuck, the Results need to be passed in the order they
+// were read.
+func (v *Validator) Check(r Results) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+
+	if r.Cnt0_3 != 0 {
+		v.zeroSince = time.Time{}
+	} else if v.zeroSince.IsZero() {
+		v.zeroSince = r.Timestamp
+	}
+	// The first Results may match the zero value of last
+	if v.stuckSince.IsZero() || r.values() != v.last.values() || r.Version != v.last.Version {
+		v.stuckSince = r.Timestamp
+	}
+	v.last = r
+
+	if v.zeroLimit > 0 && !v.zeroSince.IsZero() && r.Timestamp.Sub(v.zeroSince) >= v.zeroLimit {
+		return fmt.Errorf("%w: no particles counted since %s", ErrStuck, v.zeroSince.Format(time.RFC3339))
+	}
+	if v.stuckLimit > 0 && r.Timestamp.Sub(v.stuckSince) >= v.stuckLimit {
+		return fmt.Errorf("%w: readings unchanged since %s", ErrStuck, v.stuckSince.Format(time.RFC3339))
+	}
+	return nil
+}
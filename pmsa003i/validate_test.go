@@ -0,0 +1,84 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package pmsa003i
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	good := Results{
+		CfPm1: 0, CfPm2_5: 1, CfPm10: 5,
+		EnvPm1: 0, EnvPm2_5: 1, EnvPm10: 5,
+		Cnt0_3: 126, Cnt0_5: 42, Cnt1: 15, Cnt2_5: 9, Cnt5: 3, Cnt10: 3,
+	}
+	if err := good.Validate(); err != nil {
+		t.Fatalf("Validate Error: %s", err)
+	}
+
+	bad := []Results{good, good, good}
+	bad[0].CfPm1 = 2
+	bad[1].EnvPm10 = 0
+	bad[2].Cnt1 = 50
+	for _, r := range bad {
+		if err := r.Validate(); !errors.Is(err, ErrImplausible) {
+			t.Errorf("Not implausible Error for %v: %s", r, err)
+		}
+	}
+}
+
+func TestValidator(t *testing.T) {
+	start := time.Date(2020, 11, 14, 10, 0, 0, 0, time.UTC)
+	v := NewValidator(time.Minute, 5*time.Minute)
+
+	// Changing readings are fine
+	for i := 0; i < 10; i++ {
+		r := Results{Cnt0_3: uint16(100 + i), Timestamp: start.Add(time.Duration(i) * time.Minute)}
+		if err := v.Check(r); err != nil {
+			t.Fatalf("Check Error: %s", err)
+		}
+	}
+
+	// No particles for a minute
+	start = start.Add(time.Hour)
+	if err := v.Check(Results{EnvPm10: 1, Timestamp: start}); err != nil {
+		t.Fatalf("Check Error: %s", err)
+	}
+	if err := v.Check(Results{EnvPm10: 2, Timestamp: start.Add(time.Minute)}); !errors.Is(err, ErrStuck) {
+		t.Fatalf("Not zero counters Error: %s", err)
+	}
+
+	// Identical readings for 5 minutes
+	start = start.Add(time.Hour)
+	for i := 0; i < 5; i++ {
+		r := Results{Cnt0_3: 100, Timestamp: start.Add(time.Duration(i) * time.Minute)}
+		if err := v.Check(r); err != nil {
+			t.Fatalf("Check Error: %s", err)
+		}
+	}
+	if err := v.Check(Results{Cnt0_3: 100, Timestamp: start.Add(5 * time.Minute)}); !errors.Is(err, ErrStuck) {
+		t.Fatalf("Not identical readings Error: %s", err)
+	}
+
+	// Implausible readings are reported first
+	if err := v.Check(Results{Cnt0_3: 1, Cnt10: 2}); !errors.Is(err, ErrImplausible) {
+		t.Fatalf("Not implausible Error: %s", err)
+	}
+}
+
+func TestValidatorZeroFirst(t *testing.T) {
+	start := time.Date(2020, 11, 14, 10, 0, 0, 0, time.UTC)
+	v := NewValidator(0, 5*time.Minute)
+
+	// An all zero first frame is not stuck since the zero time
+	if err := v.Check(Results{Timestamp: start}); err != nil {
+		t.Fatalf("Check Error: %s", err)
+	}
+	if err := v.Check(Results{Timestamp: start.Add(5 * time.Minute)}); !errors.Is(err, ErrStuck) {
+		t.Fatalf("Not identical readings Error: %s", err)
+	}
+}
@@ -0,0 +1,17 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package scd4x controls a Sensirion SCD40 or SCD41 CO2 sensor over I²C.
+//
+// The SCD4x is a photoacoustic CO2 sensor that also measures the temperature and
+// humidity. In periodic mode it makes a new measurement every 5 seconds.
+//
+// Settings changed with the Set methods are lost when the sensor is powered off
+// unless PersistSettings is called, and most commands can only be used while the
+// periodic measurements are stopped.
+//
+// Datasheet
+//
+// https://sensirion.com/media/documents/E0F04247/631EF271/CD_DS_SCD40_SCD41_Datasheet_D1.pdf
+package scd4x
@@ -0,0 +1,77 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package scd4x_test
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"periph.io/x/periph/conn/i2c/i2creg"
+	"periph.io/x/periph/conn/physic"
+	"periph.io/x/periph/host"
+
+	"github.com/bcl/air-sensors/scd4x"
+)
+
+func Example() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Open a handle to the first available I²C bus:
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer bus.Close()
+
+	d, err := scd4x.New(bus)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer d.Halt() //nolint
+
+	if err := d.StartMeasurements(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Read each new measurement
+	for range time.Tick(d.MeasurementInterval()) {
+		r, err := d.ReadMeasurement()
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("CO2 : %d ppm\n%8s %9s\n", r.CO2, r.Temperature, r.Humidity)
+	}
+}
+
+func ExampleDev_SetAltitude() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Open a handle to the first available I²C bus:
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer bus.Close()
+
+	d, err := scd4x.New(bus)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Set the altitude and save it, only needed once as it is kept in the EEPROM
+	if err := d.SetAltitude(1600 * physic.Metre); err != nil {
+		log.Fatal(err)
+	}
+	if err := d.PersistSettings(); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package scd4x
+
+import (
+	"fmt"
+	"time"
+)
+
+// StartLowPowerMeasurements starts the periodic measurements in low power mode
+// A new measurement is ready every LowPowerInterval.
+func (d *Dev) StartLowPowerMeasurements() error {
+	if _, err := d.command(cmdStartLowPower, 0, 0); err != nil {
+		return err
+	}
+	d.measuring = true
+	d.lowPower = true
+	return nil
+}
+
+// MeasurementInterval returns how often a new measurement is made by the running
+// periodic measurements, PeriodicInterval or LowPowerInterval.
+func (d *Dev) MeasurementInterval() time.Duration {
+	if d.lowPower {
+		return LowPowerInterval
+	}
+	return PeriodicInterval
+}
+
+// MeasureSingleShot makes one measurement and returns it, it takes 5s
+//
+// It is only supported by the SCD41, and needs the periodic measurements to be
+// stopped. After WakeUp the first measurement is discarded as the datasheet
+// requires, making the call take 10s.
+func (d *Dev) MeasureSingleShot() (Reading, error) {
+	if d.measuring {
+		return Reading{}, fmt.Errorf("scd4x: Single shot measurement while periodic measurements are running")
+	}
+	if d.woken {
+		if _, err := d.command(cmdSingleShot, 5000*time.Millisecond, 0); err != nil {
+			return Reading{}, err
+		}
+		if _, err := d.ReadMeasurement(); err != nil {
+			return Reading{}, err
+		}
+		d.woken = false
+	}
+	if _, err := d.command(cmdSingleShot, 5000*time.Millisecond, 0); err != nil {
+		return Reading{}, err
+	}
+	return d.ReadMeasurement()
+}
+
+// MeasureSingleShotRHT measures only the temperature and humidity, it takes 50ms
+// The CO2 in the Reading is 0. It is only supported by the SCD41.
+func (d *Dev) MeasureSingleShotRHT() (Reading, error) {
+	if d.measuring {
+		return Reading{}, fmt.Errorf("scd4x: Single shot measurement while periodic measurements are running")
+	}
+	if _, err := d.command(cmdSingleShotRHT, 50*time.Millisecond, 0); err != nil {
+		return Reading{}, err
+	}
+	return d.ReadMeasurement()
+}
+
+// PowerDown puts the sensor into sleep mode, between single shot measurements
+// It is only supported by the SCD41, call WakeUp before using the sensor again.
+func (d *Dev) PowerDown() error {
+	if _, err := d.command(cmdPowerDown, time.Millisecond, 0); err != nil {
+		return err
+	}
+	d.asleep = true
+	return nil
+}
+
+// WakeUp wakes the sensor up after PowerDown, it takes 30ms
+// The sensor does not acknowledge the wake up command, so there is no error to return.
+func (d *Dev) WakeUp() {
+	_, _ = d.command(cmdWakeUp, 30*time.Millisecond, 0)
+	d.asleep = false
+	d.woken = true
+}
@@ -0,0 +1,140 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package scd4x
+
+import (
+	"testing"
+
+	"periph.io/x/periph/conn/i2c/i2ctest"
+)
+
+func TestLowPowerInterval(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Stop the measurements and read the serial number
+			{Addr: 0x62, W: []byte{0x3f, 0x86}},
+			{Addr: 0x62, W: []byte{0x36, 0x82}},
+			{Addr: 0x62, R: GoodSerialNumber},
+			{Addr: 0x62, W: []byte{0x21, 0xac}},
+			{Addr: 0x62, W: []byte{0x3f, 0x86}},
+			{Addr: 0x62, W: []byte{0x21, 0xb1}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if d.MeasurementInterval() != PeriodicInterval {
+		t.Fatalf("MeasurementInterval Error: %s", d.MeasurementInterval())
+	}
+	if err := d.StartLowPowerMeasurements(); err != nil {
+		t.Fatalf("StartLowPowerMeasurements Error: %s", err)
+	}
+	if d.MeasurementInterval() != LowPowerInterval {
+		t.Fatalf("MeasurementInterval low power Error: %s", d.MeasurementInterval())
+	}
+	if err := d.StopMeasurements(); err != nil {
+		t.Fatalf("StopMeasurements Error: %s", err)
+	}
+	if err := d.StartMeasurements(); err != nil {
+		t.Fatalf("StartMeasurements Error: %s", err)
+	}
+	if d.MeasurementInterval() != PeriodicInterval {
+		t.Fatalf("MeasurementInterval periodic Error: %s", d.MeasurementInterval())
+	}
+}
+
+func TestSingleShotWhileMeasuring(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Stop the measurements and read the serial number
+			{Addr: 0x62, W: []byte{0x3f, 0x86}},
+			{Addr: 0x62, W: []byte{0x36, 0x82}},
+			{Addr: 0x62, R: GoodSerialNumber},
+			{Addr: 0x62, W: []byte{0x21, 0xb1}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if err := d.StartMeasurements(); err != nil {
+		t.Fatalf("StartMeasurements Error: %s", err)
+	}
+	if _, err := d.MeasureSingleShot(); err == nil {
+		t.Fatal("MeasureSingleShot while measuring Error")
+	}
+	if _, err := d.MeasureSingleShotRHT(); err == nil {
+		t.Fatal("MeasureSingleShotRHT while measuring Error")
+	}
+}
+
+func TestSingleShotRHT(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Stop the measurements and read the serial number
+			{Addr: 0x62, W: []byte{0x3f, 0x86}},
+			{Addr: 0x62, W: []byte{0x36, 0x82}},
+			{Addr: 0x62, R: GoodSerialNumber},
+			{Addr: 0x62, W: []byte{0x21, 0x96}},
+			{Addr: 0x62, W: []byte{0xec, 0x05}},
+			{Addr: 0x62, R: []byte{0x00, 0x00, 0x81, 0x66, 0x67, 0xa2, 0x5e, 0xb9, 0x3c}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	r, err := d.MeasureSingleShotRHT()
+	if err != nil {
+		t.Fatalf("MeasureSingleShotRHT Error: %s", err)
+	}
+	if r.CO2 != 0 || r.Temperature == 0 || r.Humidity == 0 {
+		t.Fatalf("MeasureSingleShotRHT Error: %#v", r)
+	}
+}
+
+func TestPowerDownWakeUp(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Stop the measurements and read the serial number
+			{Addr: 0x62, W: []byte{0x3f, 0x86}},
+			{Addr: 0x62, W: []byte{0x36, 0x82}},
+			{Addr: 0x62, R: GoodSerialNumber},
+			{Addr: 0x62, W: []byte{0x21, 0xac}},
+			{Addr: 0x62, W: []byte{0x3f, 0x86}},
+			{Addr: 0x62, W: []byte{0x36, 0xe0}},
+			// The wake up is not acknowledged
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if err := d.StartLowPowerMeasurements(); err != nil {
+		t.Fatalf("StartLowPowerMeasurements Error: %s", err)
+	}
+	if err := d.StopMeasurements(); err != nil {
+		t.Fatalf("StopMeasurements Error: %s", err)
+	}
+	if err := d.PowerDown(); err != nil {
+		t.Fatalf("PowerDown Error: %s", err)
+	}
+	// Halt does not talk to the sleeping sensor
+	if err := d.Halt(); err != nil {
+		t.Fatalf("Halt Error: %s", err)
+	}
+	d.WakeUp()
+	if !d.woken || d.asleep {
+		t.Fatal("WakeUp Error")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatalf("Close Error: %s", err)
+	}
+}
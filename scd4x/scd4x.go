@@ -0,0 +1,301 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package scd4x
+
+import (
+	"fmt"
+	"time"
+
+	"periph.io/x/periph/conn"
+	"periph.io/x/periph/conn/i2c"
+	"periph.io/x/periph/conn/physic"
+
+	"github.com/bcl/air-sensors/internal/sensirion"
+)
+
+// DefaultAddr is the I²C address of the SCD4x
+const DefaultAddr uint16 = 0x62
+
+// Measurement intervals of the periodic modes
+const (
+	PeriodicInterval = 5 * time.Second  // StartMeasurements interval
+	LowPowerInterval = 30 * time.Second // StartLowPowerMeasurements interval
+)
+
+// SCD4x commands from the datasheet
+const (
+	cmdStartPeriodic   uint16 = 0x21b1 // Start periodic measurements
+	cmdReadMeasurement uint16 = 0xec05 // Returns the CO2, temperature, and humidity
+	cmdStopPeriodic    uint16 = 0x3f86 // Stop periodic measurements
+	cmdSetTempOffset   uint16 = 0x241d // Set the temperature offset
+	cmdGetTempOffset   uint16 = 0x2318 // Returns the temperature offset
+	cmdSetAltitude     uint16 = 0x2427 // Set the altitude in meters
+	cmdGetAltitude     uint16 = 0x2322 // Returns the altitude in meters
+	cmdSetPressure     uint16 = 0xe000 // Set the ambient pressure in hPa
+	cmdForcedRecal     uint16 = 0x362f // Recalibrate to a reference CO2 concentration
+	cmdSetASC          uint16 = 0x2416 // Enable or disable automatic self-calibration
+	cmdGetASC          uint16 = 0x2313 // Returns automatic self-calibration state
+	cmdDataReady       uint16 = 0xe4b8 // Returns the data ready status
+	cmdPersistSettings uint16 = 0x3615 // Save the settings to EEPROM
+	cmdGetSerialNumber uint16 = 0x3682 // Returns the 48 bit serial number
+	cmdSelfTest        uint16 = 0x3639 // Run the self test
+	cmdFactoryReset    uint16 = 0x3632 // Reset the settings and calibration
+	cmdReinit          uint16 = 0x3646 // Reload the settings from EEPROM
+	cmdStartLowPower   uint16 = 0x21ac // Start low power periodic measurements
+	cmdSingleShot      uint16 = 0x219d // Make one measurement, SCD41 only
+	cmdSingleShotRHT   uint16 = 0x2196 // Make one temperature and humidity measurement, SCD41 only
+	cmdPowerDown       uint16 = 0x36e0 // Power down the sensor, SCD41 only
+	cmdWakeUp          uint16 = 0x36f6 // Wake up the sensor, SCD41 only
+)
+
+const (
+	forcedRecalFailed    uint16 = 0xffff // Forced recalibration failed
+	forcedRecalOffset           = 0x8000 // Offset of the forced recalibration correction
+	dataReadyMask        uint16 = 0x07ff // Lower 11 bits are 0 when no data is ready
+	tempOffsetFullScale         = 175    // Temperature offset ticks are 175°C/65535
+	measurementFullScale        = 65535  // Full scale of the temperature and humidity ticks
+)
+
+// Reading holds the CO2, temperature, and humidity from the SCD4x
+type Reading struct {
+	CO2         uint16                  `json:"co2"`         // CO2 in ppm
+	Temperature physic.Temperature      `json:"temperature"` // Temperature
+	Humidity    physic.RelativeHumidity `json:"humidity"`    // Relative humidity
+	Timestamp   time.Time               `json:"timestamp"`   // When the reading was made
+}
+
+// Dev holds the connection to the SCD4x
+type Dev struct {
+	i2c       conn.Conn // i2c device handle for the scd4x
+	serial    uint64    // 48 bit serial number
+	measuring bool      // Periodic measurements are running
+	lowPower  bool      // Periodic measurements are in low power mode
+	woken     bool      // Woken up, the next single shot measurement is discarded
+	asleep    bool      // Powered down with PowerDown
+}
+
+var _ conn.Resource = &Dev{}
+
+// New returns a SCD4x device struct for communicating with the device
+//
+// It stops any periodic measurements left running, so that the sensor is ready for
+// commands, and reads the serial number.
+func New(i i2c.Bus) (*Dev, error) {
+	d := &Dev{
+		i2c: &i2c.Dev{Bus: i, Addr: DefaultAddr},
+	}
+	if err := d.StopMeasurements(); err != nil {
+		return nil, err
+	}
+
+	resp, err := d.command(cmdGetSerialNumber, time.Millisecond, 3)
+	if err != nil {
+		return nil, err
+	}
+	d.serial = uint64(resp[0])<<32 | uint64(resp[1])<<16 | uint64(resp[2])
+	return d, nil
+}
+
+// String implements conn.Resource.
+func (d *Dev) String() string {
+	return fmt.Sprintf("scd4x{%s}", d.i2c)
+}
+
+// Halt implements conn.Resource.
+//
+// It stops the periodic measurements.
+func (d *Dev) Halt() error {
+	if !d.measuring || d.asleep {
+		return nil
+	}
+	return d.StopMeasurements()
+}
+
+// SerialNumber returns the 48 bit serial number of the sensor
+func (d *Dev) SerialNumber() uint64 {
+	return d.serial
+}
+
+// StartMeasurements starts the periodic measurements
+// A new measurement is ready every PeriodicInterval, the first one 5s after starting.
+func (d *Dev) StartMeasurements() error {
+	if _, err := d.command(cmdStartPeriodic, 0, 0); err != nil {
+		return err
+	}
+	d.measuring = true
+	d.lowPower = false
+	return nil
+}
+
+// StopMeasurements stops the periodic measurements, it takes 500ms
+func (d *Dev) StopMeasurements() error {
+	if _, err := d.command(cmdStopPeriodic, 500*time.Millisecond, 0); err != nil {
+		return err
+	}
+	d.measuring = false
+	d.lowPower = false
+	return nil
+}
+
+// DataReady returns true when a new measurement can be read
+func (d *Dev) DataReady() (bool, error) {
+	resp, err := d.command(cmdDataReady, time.Millisecond, 1)
+	if err != nil {
+		return false, err
+	}
+	return resp[0]&dataReadyMask != 0, nil
+}
+
+// ReadMeasurement returns the latest CO2, temperature, and humidity measurement
+// Each measurement can only be read once, check DataReady before calling it.
+func (d *Dev) ReadMeasurement() (Reading, error) {
+	resp, err := d.command(cmdReadMeasurement, time.Millisecond, 3)
+	if err != nil {
+		return Reading{}, err
+	}
+	temp, rh := convertEnv(resp[1], resp[2])
+	return Reading{
+		CO2:         resp[0],
+		Temperature: temp,
+		Humidity:    rh,
+		Timestamp:   time.Now(),
+	}, nil
+}
+
+// convertEnv returns the temperature and humidity from the sensor's ticks
+func convertEnv(t, rh uint16) (physic.Temperature, physic.RelativeHumidity) {
+	// T = -45 + 175 * raw / (2^16 - 1), RH = 100 * raw / (2^16 - 1)
+	return physic.ZeroCelsius - 45*physic.Celsius + physic.Temperature(int64(t)*175000/measurementFullScale)*physic.MilliCelsius,
+		physic.RelativeHumidity(int64(rh) * int64(100*physic.PercentRH) / measurementFullScale)
+}
+
+// SetTemperatureOffset sets how much the sensor's own heating raises the measured
+// temperature, it also affects the humidity. The default is 4°C.
+func (d *Dev) SetTemperatureOffset(offset physic.Temperature) error {
+	ticks := int64(offset) * measurementFullScale / int64(tempOffsetFullScale*physic.Celsius)
+	if ticks < 0 || ticks > measurementFullScale {
+		return fmt.Errorf("scd4x: Temperature offset out of range: %s", offset)
+	}
+	_, err := d.command(cmdSetTempOffset, time.Millisecond, 0, uint16(ticks))
+	return err
+}
+
+// TemperatureOffset returns the temperature offset
+func (d *Dev) TemperatureOffset() (physic.Temperature, error) {
+	resp, err := d.command(cmdGetTempOffset, time.Millisecond, 1)
+	if err != nil {
+		return 0, err
+	}
+	return physic.Temperature(int64(resp[0]) * int64(tempOffsetFullScale*physic.Celsius) / measurementFullScale), nil
+}
+
+// SetAltitude sets the sensor's altitude above sea level, for pressure compensation
+// It is ignored when SetAmbientPressure is used.
+func (d *Dev) SetAltitude(altitude physic.Distance) error {
+	m := int64(altitude / physic.Metre)
+	if m < 0 || m > 0xffff {
+		return fmt.Errorf("scd4x: Altitude out of range: %s", altitude)
+	}
+	_, err := d.command(cmdSetAltitude, time.Millisecond, 0, uint16(m))
+	return err
+}
+
+// Altitude returns the sensor's altitude above sea level
+func (d *Dev) Altitude() (physic.Distance, error) {
+	resp, err := d.command(cmdGetAltitude, time.Millisecond, 1)
+	if err != nil {
+		return 0, err
+	}
+	return physic.Distance(resp[0]) * physic.Metre, nil
+}
+
+// SetAmbientPressure sets the ambient pressure, for pressure compensation
+// It can be called during periodic measurements, and overrides the altitude.
+func (d *Dev) SetAmbientPressure(pressure physic.Pressure) error {
+	hpa := int64(pressure / (100 * physic.Pascal))
+	if hpa < 0 || hpa > 0xffff {
+		return fmt.Errorf("scd4x: Ambient pressure out of range: %s", pressure)
+	}
+	_, err := d.command(cmdSetPressure, time.Millisecond, 0, uint16(hpa))
+	return err
+}
+
+// SetAutomaticSelfCalibration enables or disables the automatic self-calibration
+//
+// It is enabled by default, and assumes that the sensor is exposed to fresh air
+// (about 400ppm CO2) at least once a week.
+func (d *Dev) SetAutomaticSelfCalibration(enable bool) error {
+	var arg uint16
+	if enable {
+		arg = 1
+	}
+	_, err := d.command(cmdSetASC, time.Millisecond, 0, arg)
+	return err
+}
+
+// AutomaticSelfCalibration returns true if the automatic self-calibration is enabled
+func (d *Dev) AutomaticSelfCalibration() (bool, error) {
+	resp, err := d.command(cmdGetASC, time.Millisecond, 1)
+	if err != nil {
+		return false, err
+	}
+	return resp[0] != 0, nil
+}
+
+// ForcedRecalibration recalibrates the sensor to a reference CO2 concentration in ppm
+//
+// The sensor needs to have been measuring in the reference air for at least 3
+// minutes, and the measurements stopped, before calling it. It returns the
+// correction that was applied in ppm.
+func (d *Dev) ForcedRecalibration(ppm uint16) (int, error) {
+	resp, err := d.command(cmdForcedRecal, 400*time.Millisecond, 1, ppm)
+	if err != nil {
+		return 0, err
+	}
+	if resp[0] == forcedRecalFailed {
+		return 0, fmt.Errorf("scd4x: Forced recalibration failed")
+	}
+	return int(resp[0]) - forcedRecalOffset, nil
+}
+
+// PersistSettings saves the temperature offset, altitude, and automatic self-calibration
+// settings to the sensor's EEPROM, it takes 800ms
+//
+// The EEPROM is rated for 2000 writes, only call it when the settings have changed.
+func (d *Dev) PersistSettings() error {
+	_, err := d.command(cmdPersistSettings, 800*time.Millisecond, 0)
+	return err
+}
+
+// Reinit reloads the settings from the sensor's EEPROM
+func (d *Dev) Reinit() error {
+	_, err := d.command(cmdReinit, 20*time.Millisecond, 0)
+	return err
+}
+
+// FactoryReset resets the settings and calibration history to the factory defaults
+func (d *Dev) FactoryReset() error {
+	_, err := d.command(cmdFactoryReset, 1200*time.Millisecond, 0)
+	return err
+}
+
+// SelfTest runs the sensor's built in self test, it takes 10s
+// It returns an error if the sensor has a malfunction.
+func (d *Dev) SelfTest() error {
+	resp, err := d.command(cmdSelfTest, 10*time.Second, 1)
+	if err != nil {
+		return err
+	}
+	if resp[0] != 0 {
+		return fmt.Errorf("scd4x: Self test failed: 0x%04X", resp[0])
+	}
+	return nil
+}
+
+// command sends a command, with optional argument words, waits for it to execute,
+// and then returns the response words
+func (d *Dev) command(cmd uint16, wait time.Duration, respWords int, args ...uint16) ([]uint16, error) {
+	return sensirion.Command(d.i2c, "scd4x", cmd, wait, respWords, args...)
+}
@@ -0,0 +1,336 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package scd4x
+
+import (
+	"testing"
+
+	"periph.io/x/periph/conn/i2c/i2ctest"
+	"periph.io/x/periph/conn/physic"
+)
+
+var (
+	GoodSerialNumber    = []byte{0x00, 0x01, 0xb0, 0x02, 0x03, 0x0b, 0x04, 0x05, 0xf7}
+	BadSerialNumber     = []byte{0, 0, 0, 0, 0, 0, 0, 0, 0}
+	GoodMeasurementData = []byte{0x01, 0xf4, 0x33, 0x66, 0x67, 0xa2, 0x5e, 0xb9, 0x3c}
+	BadMeasurementData  = []byte{0x01, 0xf4, 0x33, 0x66, 0x67, 0xa2, 0x5e, 0xb9, 0x00}
+)
+
+func TestGoodSerialNumber(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Stop the measurements and read the serial number
+			{Addr: 0x62, W: []byte{0x3f, 0x86}},
+			{Addr: 0x62, W: []byte{0x36, 0x82}},
+			{Addr: 0x62, R: GoodSerialNumber},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if d.SerialNumber() != 0x000102030405 {
+		t.Fatalf("SerialNumber Error: 0x%X", d.SerialNumber())
+	}
+}
+
+func TestBadSerialNumber(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x62, W: []byte{0x3f, 0x86}},
+			{Addr: 0x62, W: []byte{0x36, 0x82}},
+			{Addr: 0x62, R: BadSerialNumber},
+		},
+	}
+	if _, err := New(&bus); err == nil {
+		t.Fatal("Bad serial number Error")
+	}
+}
+
+func TestFailNew(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops:       []i2ctest.IO{},
+		DontPanic: true,
+	}
+	if _, err := New(&bus); err == nil {
+		t.Fatal("Failed New Error")
+	}
+}
+
+func TestString(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Stop the measurements and read the serial number
+			{Addr: 0x62, W: []byte{0x3f, 0x86}},
+			{Addr: 0x62, W: []byte{0x36, 0x82}},
+			{Addr: 0x62, R: GoodSerialNumber},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if d.String() != "scd4x{playback(98)}" {
+		t.Fatalf("String Error: %s", d.String())
+	}
+}
+
+func TestStartHalt(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Stop the measurements and read the serial number
+			{Addr: 0x62, W: []byte{0x3f, 0x86}},
+			{Addr: 0x62, W: []byte{0x36, 0x82}},
+			{Addr: 0x62, R: GoodSerialNumber},
+			{Addr: 0x62, W: []byte{0x21, 0xb1}},
+			{Addr: 0x62, W: []byte{0x3f, 0x86}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if err := d.StartMeasurements(); err != nil {
+		t.Fatalf("StartMeasurements Error: %s", err)
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatalf("Halt Error: %s", err)
+	}
+	// Halt does nothing when the measurements are stopped
+	if err := d.Halt(); err != nil {
+		t.Fatalf("Halt Error: %s", err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatalf("Close Error: %s", err)
+	}
+}
+
+func TestDataReady(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Stop the measurements and read the serial number
+			{Addr: 0x62, W: []byte{0x3f, 0x86}},
+			{Addr: 0x62, W: []byte{0x36, 0x82}},
+			{Addr: 0x62, R: GoodSerialNumber},
+			{Addr: 0x62, W: []byte{0xe4, 0xb8}},
+			{Addr: 0x62, R: []byte{0x80, 0x00, 0xa2}},
+			{Addr: 0x62, W: []byte{0xe4, 0xb8}},
+			{Addr: 0x62, R: []byte{0x80, 0x06, 0x04}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if ready, err := d.DataReady(); err != nil || ready {
+		t.Fatalf("DataReady not ready Error: %v %s", ready, err)
+	}
+	if ready, err := d.DataReady(); err != nil || !ready {
+		t.Fatalf("DataReady ready Error: %v %s", ready, err)
+	}
+}
+
+func TestReadMeasurement(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Stop the measurements and read the serial number
+			{Addr: 0x62, W: []byte{0x3f, 0x86}},
+			{Addr: 0x62, W: []byte{0x36, 0x82}},
+			{Addr: 0x62, R: GoodSerialNumber},
+			{Addr: 0x62, W: []byte{0xec, 0x05}},
+			{Addr: 0x62, R: GoodMeasurementData},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	r, err := d.ReadMeasurement()
+	if err != nil {
+		t.Fatalf("ReadMeasurement Error: %s", err)
+	}
+	if r.CO2 != 500 {
+		t.Errorf("CO2 Error: %d", r.CO2)
+	}
+	if r.Temperature != physic.ZeroCelsius+25002*physic.MilliCelsius {
+		t.Errorf("Temperature Error: %s", r.Temperature)
+	}
+	if r.Humidity != 3700160*physic.TenthMicroRH {
+		t.Errorf("Humidity Error: %s", r.Humidity)
+	}
+}
+
+func TestReadBadMeasurement(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Stop the measurements and read the serial number
+			{Addr: 0x62, W: []byte{0x3f, 0x86}},
+			{Addr: 0x62, W: []byte{0x36, 0x82}},
+			{Addr: 0x62, R: GoodSerialNumber},
+			{Addr: 0x62, W: []byte{0xec, 0x05}},
+			{Addr: 0x62, R: BadMeasurementData},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if _, err := d.ReadMeasurement(); err == nil {
+		t.Fatal("ReadMeasurement bad CRC Error")
+	}
+}
+
+func TestTemperatureOffset(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Stop the measurements and read the serial number
+			{Addr: 0x62, W: []byte{0x3f, 0x86}},
+			{Addr: 0x62, W: []byte{0x36, 0x82}},
+			{Addr: 0x62, R: GoodSerialNumber},
+			{Addr: 0x62, W: []byte{0x24, 0x1d, 0x05, 0xd9, 0x7a}},
+			{Addr: 0x62, W: []byte{0x23, 0x18}},
+			{Addr: 0x62, R: []byte{0x05, 0xd9, 0x7a}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if err := d.SetTemperatureOffset(4 * physic.Celsius); err != nil {
+		t.Fatalf("SetTemperatureOffset Error: %s", err)
+	}
+	offset, err := d.TemperatureOffset()
+	if err != nil {
+		t.Fatalf("TemperatureOffset Error: %s", err)
+	}
+	if offset < 3990*physic.MilliCelsius || offset > 4*physic.Celsius {
+		t.Errorf("TemperatureOffset Error: %s", offset)
+	}
+	if err := d.SetTemperatureOffset(-physic.Celsius); err == nil {
+		t.Error("SetTemperatureOffset range Error")
+	}
+}
+
+func TestAltitudePressure(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Stop the measurements and read the serial number
+			{Addr: 0x62, W: []byte{0x3f, 0x86}},
+			{Addr: 0x62, W: []byte{0x36, 0x82}},
+			{Addr: 0x62, R: GoodSerialNumber},
+			{Addr: 0x62, W: []byte{0x24, 0x27, 0x00, 0x64, 0xfe}},
+			{Addr: 0x62, W: []byte{0x23, 0x22}},
+			{Addr: 0x62, R: []byte{0x00, 0x64, 0xfe}},
+			{Addr: 0x62, W: []byte{0xe0, 0x00, 0x03, 0xf5, 0xdb}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if err := d.SetAltitude(100 * physic.Metre); err != nil {
+		t.Fatalf("SetAltitude Error: %s", err)
+	}
+	alt, err := d.Altitude()
+	if err != nil {
+		t.Fatalf("Altitude Error: %s", err)
+	}
+	if alt != 100*physic.Metre {
+		t.Errorf("Altitude Error: %s", alt)
+	}
+	if err := d.SetAmbientPressure(101325 * physic.Pascal); err != nil {
+		t.Fatalf("SetAmbientPressure Error: %s", err)
+	}
+}
+
+func TestAutomaticSelfCalibration(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Stop the measurements and read the serial number
+			{Addr: 0x62, W: []byte{0x3f, 0x86}},
+			{Addr: 0x62, W: []byte{0x36, 0x82}},
+			{Addr: 0x62, R: GoodSerialNumber},
+			{Addr: 0x62, W: []byte{0x24, 0x16, 0x00, 0x00, 0x81}},
+			{Addr: 0x62, W: []byte{0x23, 0x13}},
+			{Addr: 0x62, R: []byte{0x00, 0x00, 0x81}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if err := d.SetAutomaticSelfCalibration(false); err != nil {
+		t.Fatalf("SetAutomaticSelfCalibration Error: %s", err)
+	}
+	if asc, err := d.AutomaticSelfCalibration(); err != nil || asc {
+		t.Fatalf("AutomaticSelfCalibration Error: %v %s", asc, err)
+	}
+}
+
+func TestForcedRecalibration(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Stop the measurements and read the serial number
+			{Addr: 0x62, W: []byte{0x3f, 0x86}},
+			{Addr: 0x62, W: []byte{0x36, 0x82}},
+			{Addr: 0x62, R: GoodSerialNumber},
+			{Addr: 0x62, W: []byte{0x36, 0x2f, 0x01, 0x90, 0x4c}},
+			{Addr: 0x62, R: []byte{0x80, 0x06, 0x04}},
+			{Addr: 0x62, W: []byte{0x36, 0x2f, 0x01, 0x90, 0x4c}},
+			{Addr: 0x62, R: []byte{0xff, 0xff, 0xac}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	correction, err := d.ForcedRecalibration(400)
+	if err != nil {
+		t.Fatalf("ForcedRecalibration Error: %s", err)
+	}
+	if correction != 6 {
+		t.Errorf("ForcedRecalibration correction Error: %d", correction)
+	}
+	if _, err := d.ForcedRecalibration(400); err == nil {
+		t.Fatal("ForcedRecalibration failure Error")
+	}
+}
+
+func TestPersistSettings(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Stop the measurements and read the serial number
+			{Addr: 0x62, W: []byte{0x3f, 0x86}},
+			{Addr: 0x62, W: []byte{0x36, 0x82}},
+			{Addr: 0x62, R: GoodSerialNumber},
+			{Addr: 0x62, W: []byte{0x36, 0x15}},
+			{Addr: 0x62, W: []byte{0x36, 0x46}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if err := d.PersistSettings(); err != nil {
+		t.Fatalf("PersistSettings Error: %s", err)
+	}
+	if err := d.Reinit(); err != nil {
+		t.Fatalf("Reinit Error: %s", err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatalf("Close Error: %s", err)
+	}
+}
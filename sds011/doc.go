@@ -0,0 +1,18 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package sds011 controls a Nova Fitness SDS011 particulate matter sensor over a UART.
+//
+// The sensor measures PM2.5 and PM10. In its default active mode it sends a reading
+// every second, or every working period set with SetWorkingPeriod. In query mode it
+// only sends a reading when ReadSensor asks for one.
+//
+// Datasheet
+//
+// https://cdn-reichelt.de/documents/datenblatt/X200/SDS011-DATASHEET.pdf
+//
+// Control Protocol
+//
+// https://cdn.sparkfun.com/assets/parts/1/2/2/7/5/Laser_Dust_Sensor_Control_Protocol_V1.3.pdf
+package sds011
@@ -0,0 +1,42 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sds011_test
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/bcl/air-sensors/sds011"
+)
+
+func Example() {
+	// The port needs to be configured first, eg. stty -F /dev/ttyUSB0 9600 raw
+	port, err := os.OpenFile("/dev/ttyUSB0", os.O_RDWR, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer port.Close()
+
+	d, err := sds011.New(port)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer d.Halt() //nolint
+
+	// Only read when asked, and work for 30s every 5 minutes
+	if err := d.SetQueryMode(true); err != nil {
+		log.Fatal(err)
+	}
+	if err := d.SetWorkingPeriod(5); err != nil {
+		log.Fatal(err)
+	}
+
+	r, err := d.ReadSensor()
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("PM2.5: %0.1f μg/m3 PM10: %0.1f μg/m3\n", r.PM2_5, r.PM10)
+}
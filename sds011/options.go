@@ -0,0 +1,20 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sds011
+
+// BroadcastID addresses the commands to every sensor, and accepts readings from any
+// sensor
+const BroadcastID uint16 = 0xffff
+
+// Option configures the Dev returned by New
+type Option func(*Dev)
+
+// WithDeviceID addresses the commands to the sensor with the ID, and skips readings
+// from other sensors. The default is BroadcastID.
+func WithDeviceID(id uint16) Option {
+	return func(d *Dev) {
+		d.id = id
+	}
+}
@@ -0,0 +1,249 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sds011
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"periph.io/x/periph/conn"
+
+	"github.com/bcl/air-sensors/internal/uart"
+)
+
+// MaxWorkingPeriod is the longest working period in minutes
+const MaxWorkingPeriod = 30
+
+// SDS011 command IDs, sent in the 0xb4 command frame
+const (
+	cmdReportMode byte = 0x02 // Query or set active (0) or query (1) mode
+	cmdQuery      byte = 0x04 // Request a reading in query mode
+	cmdSetID      byte = 0x05 // Set the device ID
+	cmdWork       byte = 0x06 // Query or set sleep (0) or work (1)
+	cmdFirmware   byte = 0x07 // Query the firmware version
+	cmdPeriod     byte = 0x08 // Query or set the working period
+
+	frameHead    byte = 0xaa // First byte of every frame
+	frameTail    byte = 0xab // Last byte of every frame
+	frameCommand byte = 0xb4 // Command frames sent to the sensor
+	frameData    byte = 0xc0 // Readings sent by the sensor
+	frameReply   byte = 0xc5 // Replies to commands
+)
+
+// maxFrames is how many frames are read while waiting for a reply to a command, in
+// active mode the readings can arrive before it
+const maxFrames = 10
+
+// ErrChecksum is returned when a frame's checksum, or its tail, does not match
+var ErrChecksum = errors.New("sds011: Bad checksum")
+
+// Reading holds the readings from the SDS011
+type Reading struct {
+	PM2_5     float32   `json:"pm2_5"`     // PM2.5 in μg/m3
+	PM10      float32   `json:"pm10"`      // PM10 in μg/m3
+	DeviceID  uint16    `json:"device_id"` // ID of the sensor that sent the reading
+	Timestamp time.Time `json:"timestamp"` // When the reading was received
+}
+
+// Dev holds the connection to the SDS011
+type Dev struct {
+	port  io.ReadWriter // UART connected to the sensor
+	id    uint16        // Device ID, or BroadcastID
+	query bool          // The sensor is in query mode
+}
+
+var _ conn.Resource = &Dev{}
+
+// New returns a SDS011 device struct for communicating with the sensor over a UART
+//
+// The port needs to be configured for 9600 baud 8N1 before calling New. The sensor
+// starts in active mode, sending a reading every second, use SetQueryMode to only
+// read when ReadSensor is called.
+func New(port io.ReadWriter, opts ...Option) (*Dev, error) {
+	d := &Dev{port: port, id: BroadcastID}
+	for _, o := range opts {
+		o(d)
+	}
+	return d, nil
+}
+
+// String implements conn.Resource.
+func (d *Dev) String() string {
+	return fmt.Sprintf("sds011{%v}", d.port)
+}
+
+// Halt implements conn.Resource.
+//
+// It puts the sensor to sleep, turning off the fan and the laser.
+func (d *Dev) Halt() error {
+	return d.Sleep()
+}
+
+// DeviceID returns the ID the commands are addressed to
+func (d *Dev) DeviceID() uint16 {
+	return d.id
+}
+
+// ReadSensor returns the next reading from the sensor, skipping readings from other
+// sensors when a device ID is set. In query mode it requests the reading first.
+func (d *Dev) ReadSensor() (Reading, error) {
+	if d.query {
+		if err := d.command(cmdQuery, nil); err != nil {
+			return Reading{}, err
+		}
+	}
+	for {
+		frame, err := d.readFrame()
+		if err != nil {
+			return Reading{}, err
+		}
+		if frame[1] != frameData || !d.fromDevice(frame) {
+			continue
+		}
+		return Reading{
+			PM2_5:     float32(uint16(frame[3])<<8|uint16(frame[2])) / 10,
+			PM10:      float32(uint16(frame[5])<<8|uint16(frame[4])) / 10,
+			DeviceID:  frameID(frame),
+			Timestamp: time.Now(),
+		}, nil
+	}
+}
+
+// SetQueryMode switches between query mode, where ReadSensor requests each reading,
+// and the default active mode, where the sensor sends a reading every working period.
+// The mode is saved in the sensor.
+func (d *Dev) SetQueryMode(query bool) error {
+	var mode byte
+	if query {
+		mode = 1
+	}
+	if _, err := d.set(cmdReportMode, mode); err != nil {
+		return err
+	}
+	d.query = query
+	return nil
+}
+
+// Sleep turns off the fan and the laser
+func (d *Dev) Sleep() error {
+	_, err := d.set(cmdWork, 0)
+	return err
+}
+
+// Wake turns on the fan and the laser, the readings take about 30s to stabilize
+func (d *Dev) Wake() error {
+	_, err := d.set(cmdWork, 1)
+	return err
+}
+
+// SetWorkingPeriod sets the working period in minutes, 0 is continuous.
+//
+// With a period of n minutes the sensor works for 30s and then sleeps for the rest
+// of the period, extending the laser's lifetime. The period is saved in the sensor.
+func (d *Dev) SetWorkingPeriod(minutes int) error {
+	if minutes < 0 || minutes > MaxWorkingPeriod {
+		return fmt.Errorf("sds011: Invalid working period: %d", minutes)
+	}
+	_, err := d.set(cmdPeriod, byte(minutes))
+	return err
+}
+
+// WorkingPeriod returns the working period in minutes, 0 is continuous
+func (d *Dev) WorkingPeriod() (int, error) {
+	reply, err := d.request(cmdPeriod, []byte{0})
+	if err != nil {
+		return 0, err
+	}
+	return int(reply[4]), nil
+}
+
+// SetDeviceID changes the sensor's device ID, the new ID is used for the following
+// commands and readings.
+func (d *Dev) SetDeviceID(id uint16) error {
+	data := make([]byte, 12)
+	data[10], data[11] = byte(id>>8), byte(id)
+	if _, err := d.request(cmdSetID, data); err != nil {
+		return err
+	}
+	d.id = id
+	return nil
+}
+
+// FirmwareVersion returns the firmware's year, month and day
+func (d *Dev) FirmwareVersion() (int, int, int, error) {
+	reply, err := d.request(cmdFirmware, nil)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return 2000 + int(reply[3]), int(reply[4]), int(reply[5]), nil
+}
+
+// set sends a command that sets a value, and returns the sensor's reply
+func (d *Dev) set(cmd byte, value byte) ([10]byte, error) {
+	return d.request(cmd, []byte{1, value})
+}
+
+// request sends a command and returns the sensor's reply, skipping any readings
+// received before it
+func (d *Dev) request(cmd byte, data []byte) ([10]byte, error) {
+	if err := d.command(cmd, data); err != nil {
+		return [10]byte{}, err
+	}
+	for i := 0; i < maxFrames; i++ {
+		frame, err := d.readFrame()
+		if err != nil {
+			return frame, err
+		}
+		// The reply to cmdSetID comes from the new ID
+		if frame[1] == frameReply && frame[2] == cmd && (cmd == cmdSetID || d.fromDevice(frame)) {
+			return frame, nil
+		}
+	}
+	return [10]byte{}, fmt.Errorf("sds011: No reply to command 0x%02x", cmd)
+}
+
+// command sends a command to the sensor
+//
+// The command frame is 0xaa 0xb4, the command ID, 12 bytes of data, the device ID,
+// the 8 bit sum of the command ID, data and device ID, and 0xab.
+func (d *Dev) command(cmd byte, data []byte) error {
+	buf := make([]byte, 19)
+	buf[0], buf[1], buf[2] = frameHead, frameCommand, cmd
+	copy(buf[3:15], data)
+	buf[15], buf[16] = byte(d.id>>8), byte(d.id)
+	buf[17] = uart.Sum(buf[2:17])
+	buf[18] = frameTail
+	if _, err := d.port.Write(buf); err != nil {
+		return fmt.Errorf("sds011: Error while sending command 0x%02x: %w", cmd, err)
+	}
+	return nil
+}
+
+// readFrame reads the next 10 byte frame from the sensor, skipping any bytes before it
+//
+// The frame is 0xaa, the frame type, 6 bytes of data ending with the device ID, the
+// 8 bit sum of the data, and 0xab.
+func (d *Dev) readFrame() ([10]byte, error) {
+	var frame [10]byte
+	if err := uart.ReadFrame(d.port, frame[:], frameHead); err != nil {
+		return frame, fmt.Errorf("sds011: Error while reading the sensor: %w", err)
+	}
+	if frame[9] != frameTail || uart.Sum(frame[2:8]) != frame[8] {
+		return frame, ErrChecksum
+	}
+	return frame, nil
+}
+
+// fromDevice returns true if the frame is from the sensor with the device ID
+func (d *Dev) fromDevice(frame [10]byte) bool {
+	return d.id == BroadcastID || frameID(frame) == d.id
+}
+
+// frameID returns the device ID of the sensor that sent the frame
+func frameID(frame [10]byte) uint16 {
+	return uint16(frame[6])<<8 | uint16(frame[7])
+}
@@ -0,0 +1,157 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sds011
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/bcl/air-sensors/internal/uart/uarttest"
+)
+
+// frame returns a frame from the sensor, with its checksum
+func frame(kind byte, data ...byte) []byte {
+	f := []byte{0xaa, kind}
+	f = append(f, data...)
+	var sum byte
+	for _, b := range data {
+		sum += b
+	}
+	return append(f, sum, 0xab)
+}
+
+func TestReadSensor(t *testing.T) {
+	var port uarttest.Port
+	port.R.Write([]byte{0x01, 0x02})
+	port.R.Write(frame(0xc0, 0xd4, 0x04, 0x3a, 0x0a, 0xa1, 0x60))
+	port.R.Write([]byte{0xaa, 0xc0, 0xd4, 0x04, 0x3a, 0x0a, 0xa1, 0x60, 0x00, 0xab})
+
+	d, err := New(&port)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	r, err := d.ReadSensor()
+	if err != nil {
+		t.Fatalf("ReadSensor Error: %s", err)
+	}
+	if r.PM2_5 != 123.6 || r.PM10 != 261.8 || r.DeviceID != 0xa160 {
+		t.Errorf("ReadSensor Error: %#v", r)
+	}
+	if _, err := d.ReadSensor(); !errors.Is(err, ErrChecksum) {
+		t.Errorf("Checksum Error: %v", err)
+	}
+}
+
+func TestDeviceID(t *testing.T) {
+	var port uarttest.Port
+	port.R.Write(frame(0xc0, 0x10, 0x00, 0x20, 0x00, 0xa1, 0x60))
+	port.R.Write(frame(0xc0, 0x11, 0x00, 0x21, 0x00, 0xb2, 0x70))
+	port.R.Write(frame(0xc5, 0x05, 0x00, 0x00, 0x00, 0x12, 0x34))
+
+	d, err := New(&port, WithDeviceID(0xb270))
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	r, err := d.ReadSensor()
+	if err != nil {
+		t.Fatalf("ReadSensor Error: %s", err)
+	}
+	if r.PM2_5 != 1.7 || r.DeviceID != 0xb270 {
+		t.Errorf("Device ID filter Error: %#v", r)
+	}
+
+	if err := d.SetDeviceID(0x1234); err != nil {
+		t.Fatalf("SetDeviceID Error: %s", err)
+	}
+	if d.DeviceID() != 0x1234 {
+		t.Errorf("DeviceID Error: 0x%04X", d.DeviceID())
+	}
+	cmd := []byte{0xaa, 0xb4, 0x05, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x12, 0x34, 0xb2, 0x70, 0x6d, 0xab}
+	if !bytes.Equal(port.W.Bytes(), cmd) {
+		t.Errorf("SetDeviceID command Error: % x", port.W.Bytes())
+	}
+}
+
+func TestQueryMode(t *testing.T) {
+	var port uarttest.Port
+	// A reading sent in active mode before the reply
+	port.R.Write(frame(0xc0, 0x10, 0x00, 0x20, 0x00, 0xa1, 0x60))
+	port.R.Write(frame(0xc5, 0x02, 0x01, 0x01, 0x00, 0xa1, 0x60))
+	port.R.Write(frame(0xc0, 0x64, 0x00, 0xc8, 0x00, 0xa1, 0x60))
+
+	d, err := New(&port)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if err := d.SetQueryMode(true); err != nil {
+		t.Fatalf("SetQueryMode Error: %s", err)
+	}
+	r, err := d.ReadSensor()
+	if err != nil {
+		t.Fatalf("ReadSensor Error: %s", err)
+	}
+	if r.PM2_5 != 10 || r.PM10 != 20 {
+		t.Errorf("ReadSensor Error: %#v", r)
+	}
+	cmds := []byte{
+		0xaa, 0xb4, 0x02, 0x01, 0x01, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xff, 0xff, 0x02, 0xab,
+		0xaa, 0xb4, 0x04, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xff, 0xff, 0x02, 0xab,
+	}
+	if !bytes.Equal(port.W.Bytes(), cmds) {
+		t.Errorf("Query mode commands Error: % x", port.W.Bytes())
+	}
+}
+
+func TestSleepPeriodFirmware(t *testing.T) {
+	var port uarttest.Port
+	port.R.Write(frame(0xc5, 0x06, 0x01, 0x00, 0x00, 0xa1, 0x60))
+	port.R.Write(frame(0xc5, 0x06, 0x01, 0x01, 0x00, 0xa1, 0x60))
+	port.R.Write(frame(0xc5, 0x08, 0x01, 0x05, 0x00, 0xa1, 0x60))
+	port.R.Write(frame(0xc5, 0x08, 0x00, 0x05, 0x00, 0xa1, 0x60))
+	port.R.Write(frame(0xc5, 0x07, 0x0f, 0x07, 0x0a, 0xa1, 0x60))
+
+	d, err := New(&port)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatalf("Halt Error: %s", err)
+	}
+	if err := d.Wake(); err != nil {
+		t.Fatalf("Wake Error: %s", err)
+	}
+	if err := d.SetWorkingPeriod(MaxWorkingPeriod + 1); err == nil {
+		t.Error("Invalid working period Error")
+	}
+	if err := d.SetWorkingPeriod(5); err != nil {
+		t.Fatalf("SetWorkingPeriod Error: %s", err)
+	}
+	if period, err := d.WorkingPeriod(); err != nil || period != 5 {
+		t.Errorf("WorkingPeriod Error: %d %v", period, err)
+	}
+	if y, m, day, err := d.FirmwareVersion(); err != nil || y != 2015 || m != 7 || day != 10 {
+		t.Errorf("FirmwareVersion Error: %d-%d-%d %v", y, m, day, err)
+	}
+	// Sleep is the 0x06 command with 1, 0
+	sleep := []byte{0xaa, 0xb4, 0x06, 0x01, 0x00, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xff, 0xff, 0x05, 0xab}
+	if !bytes.Equal(port.W.Bytes()[:19], sleep) {
+		t.Errorf("Sleep command Error: % x", port.W.Bytes()[:19])
+	}
+}
+
+func TestNoReply(t *testing.T) {
+	var port uarttest.Port
+	for i := 0; i < maxFrames; i++ {
+		port.R.Write(frame(0xc0, 0x10, 0x00, 0x20, 0x00, 0xa1, 0x60))
+	}
+	d, err := New(&port)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if err := d.Wake(); err == nil {
+		t.Fatal("No reply Error")
+	}
+}
@@ -0,0 +1,16 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package sen5x controls a Sensirion SEN54 or SEN55 environmental sensor node over
+// I²C.
+//
+// The SEN5x measures PM1.0, PM2.5, PM4.0 and PM10, the relative humidity and
+// temperature, and the VOC Index. The SEN55 also measures the NOx Index. All of them
+// are read in one transaction by ReadMeasurement, every second after
+// StartMeasurement.
+//
+// Datasheet
+//
+// https://sensirion.com/media/documents/6791EFA0/62A1F68F/Sensirion_Datasheet_Environmental_Node_SEN5x.pdf
+package sen5x
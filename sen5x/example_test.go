@@ -0,0 +1,53 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sen5x_test
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"periph.io/x/periph/conn/i2c/i2creg"
+	"periph.io/x/periph/host"
+
+	"github.com/bcl/air-sensors/sen5x"
+)
+
+func Example() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Open a handle to the first available I²C bus:
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer bus.Close()
+
+	d, err := sen5x.New(bus)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer d.Halt() //nolint
+
+	if err := d.StartMeasurement(); err != nil {
+		log.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		time.Sleep(time.Second)
+		r, err := d.ReadMeasurement()
+		if errors.Is(err, sen5x.ErrNotReady) {
+			continue
+		} else if err != nil {
+			log.Fatal(err)
+		}
+		if r.PMValid && r.EnvValid {
+			fmt.Printf("PM2.5: %0.1f μg/m3 %8s %9s VOC Index: %0.0f\n", r.MassPM2_5, r.Temperature, r.Humidity, r.VOCIndex)
+		}
+	}
+}
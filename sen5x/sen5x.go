@@ -0,0 +1,261 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sen5x
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"periph.io/x/periph/conn"
+	"periph.io/x/periph/conn/i2c"
+	"periph.io/x/periph/conn/physic"
+
+	"github.com/bcl/air-sensors/internal/sensirion"
+)
+
+// Addr is the I²C address of the SEN5x, it cannot be changed
+const Addr uint16 = 0x69
+
+// DefaultAutoCleanInterval is the fan auto cleaning interval set at the factory
+const DefaultAutoCleanInterval = 168 * time.Hour
+
+// FanCleaningTime is how long the fan cleaning runs
+const FanCleaningTime = 10 * time.Second
+
+// SEN5x commands from the datasheet
+const (
+	cmdStartMeasurement   uint16 = 0x0021 // Start measuring everything
+	cmdStartRHTGas        uint16 = 0x0037 // Start measuring without the PM sensor
+	cmdStopMeasurement    uint16 = 0x0104 // Stop measuring and go idle
+	cmdReadDataReady      uint16 = 0x0202 // Returns 1 when new readings are ready
+	cmdReadMeasuredValues uint16 = 0x03c4 // Returns the 8 readings
+	cmdStartFanCleaning   uint16 = 0x5607 // Clean the fan, while measuring
+	cmdAutoClean          uint16 = 0x8004 // Read or write the auto cleaning interval in seconds
+	cmdReadProductName    uint16 = 0xd014 // Returns the product name as a string
+	cmdReadSerialNumber   uint16 = 0xd033 // Returns the serial number as a string
+	cmdReadVersion        uint16 = 0xd100 // Returns the firmware major and minor version
+	cmdReadStatus         uint16 = 0xd206 // Returns the device status register
+	cmdReadAndClearStatus uint16 = 0xd210 // Returns and clears the device status register
+	cmdReset              uint16 = 0xd304 // Resets the sensor
+)
+
+// Values returned for readings that are not available
+const (
+	unknownPM     uint16 = 0xffff // PM readings
+	unknownSigned uint16 = 0x7fff // RH, T, VOC and NOx readings
+)
+
+// ErrNotReady is returned by ReadMeasurement when there is no new reading
+var ErrNotReady = errors.New("sen5x: Reading is not ready")
+
+// Reading holds the readings from the SEN5x
+//
+// Readings that are not available, during the first seconds of measuring, without
+// the PM sensor, or the NOx Index on the SEN54, are 0 and their Valid field is false.
+type Reading struct {
+	MassPM1     float32                 `json:"mass_pm1"`    // PM1.0 in μg/m3
+	MassPM2_5   float32                 `json:"mass_pm2_5"`  // PM2.5 in μg/m3
+	MassPM4     float32                 `json:"mass_pm4"`    // PM4.0 in μg/m3
+	MassPM10    float32                 `json:"mass_pm10"`   // PM10 in μg/m3
+	Temperature physic.Temperature      `json:"temperature"` // Temperature
+	Humidity    physic.RelativeHumidity `json:"humidity"`    // Relative humidity
+	VOCIndex    float32                 `json:"voc_index"`   // VOC Index, 1-500, 100 is the average
+	NOxIndex    float32                 `json:"nox_index"`   // NOx Index, 1-500, 1 is the average
+	PMValid     bool                    `json:"pm_valid"`    // The PM readings are available
+	EnvValid    bool                    `json:"env_valid"`   // The humidity and temperature are available
+	VOCValid    bool                    `json:"voc_valid"`   // The VOC Index is available
+	NOxValid    bool                    `json:"nox_valid"`   // The NOx Index is available
+	Timestamp   time.Time               `json:"timestamp"`   // When the reading was made
+}
+
+// Dev holds the connection to the SEN5x
+type Dev struct {
+	i2c     conn.Conn // i2c device handle for the sen5x
+	product string    // Product name, SEN54 or SEN55
+	serial  string    // Serial number
+	major   uint8     // Firmware major version
+	minor   uint8     // Firmware minor version
+}
+
+var _ conn.Resource = &Dev{}
+
+// New returns a SEN5x device struct for communicating with the device
+//
+// It reads the product name, serial number and firmware version, StartMeasurement
+// needs to be called before reading the measurements.
+func New(i i2c.Bus) (*Dev, error) {
+	d := &Dev{i2c: &i2c.Dev{Bus: i, Addr: Addr}}
+
+	resp, err := d.command(cmdReadProductName, 20*time.Millisecond, 16)
+	if err != nil {
+		return nil, err
+	}
+	d.product = sensirion.String(resp)
+
+	if resp, err = d.command(cmdReadSerialNumber, 20*time.Millisecond, 16); err != nil {
+		return nil, err
+	}
+	d.serial = sensirion.String(resp)
+
+	if resp, err = d.command(cmdReadVersion, 20*time.Millisecond, 1); err != nil {
+		return nil, err
+	}
+	d.major, d.minor = uint8(resp[0]>>8), uint8(resp[0])
+	return d, nil
+}
+
+// String implements conn.Resource.
+func (d *Dev) String() string {
+	return fmt.Sprintf("sen5x{%s}", d.i2c)
+}
+
+// Halt implements conn.Resource.
+//
+// It stops the measurements, turning off the fan, laser and gas sensor.
+func (d *Dev) Halt() error {
+	return d.StopMeasurement()
+}
+
+// ProductName returns the product name, SEN54 or SEN55
+func (d *Dev) ProductName() string {
+	return d.product
+}
+
+// SerialNumber returns the serial number of the sensor
+func (d *Dev) SerialNumber() string {
+	return d.serial
+}
+
+// FirmwareVersion returns the major and minor firmware version
+func (d *Dev) FirmwareVersion() (uint8, uint8) {
+	return d.major, d.minor
+}
+
+// StartMeasurement starts measuring everything, new readings are ready every second
+func (d *Dev) StartMeasurement() error {
+	_, err := d.command(cmdStartMeasurement, 50*time.Millisecond, 0)
+	return err
+}
+
+// StartRHTGasMeasurement starts measuring the humidity, temperature, VOC and NOx
+// without the PM sensor, using less power
+func (d *Dev) StartRHTGasMeasurement() error {
+	_, err := d.command(cmdStartRHTGas, 50*time.Millisecond, 0)
+	return err
+}
+
+// StopMeasurement stops the measurements
+func (d *Dev) StopMeasurement() error {
+	_, err := d.command(cmdStopMeasurement, 200*time.Millisecond, 0)
+	return err
+}
+
+// DataReady returns true when there is a new reading
+func (d *Dev) DataReady() (bool, error) {
+	resp, err := d.command(cmdReadDataReady, 20*time.Millisecond, 1)
+	if err != nil {
+		return false, err
+	}
+	return resp[0]&0x01 == 0x01, nil
+}
+
+// ReadMeasurement returns all of the readings, or ErrNotReady if there is no new
+// reading since the last one
+func (d *Dev) ReadMeasurement() (Reading, error) {
+	ready, err := d.DataReady()
+	if err != nil {
+		return Reading{}, err
+	}
+	if !ready {
+		return Reading{}, ErrNotReady
+	}
+
+	resp, err := d.command(cmdReadMeasuredValues, 20*time.Millisecond, 8)
+	if err != nil {
+		return Reading{}, err
+	}
+	r := Reading{Timestamp: time.Now()}
+
+	// PM = raw / 10
+	if resp[0] != unknownPM {
+		r.PMValid = true
+		r.MassPM1 = float32(resp[0]) / 10
+		r.MassPM2_5 = float32(resp[1]) / 10
+		r.MassPM4 = float32(resp[2]) / 10
+		r.MassPM10 = float32(resp[3]) / 10
+	}
+
+	// RH = raw / 100, T = raw / 200
+	if resp[4] != unknownSigned && resp[5] != unknownSigned {
+		r.EnvValid = true
+		r.Humidity = physic.RelativeHumidity(int64(int16(resp[4])) * int64(physic.PercentRH) / 100)
+		r.Temperature = physic.ZeroCelsius + physic.Temperature(int64(int16(resp[5]))*5)*physic.MilliCelsius
+	}
+
+	// VOC and NOx Index = raw / 10
+	if resp[6] != unknownSigned {
+		r.VOCValid = true
+		r.VOCIndex = float32(int16(resp[6])) / 10
+	}
+	if resp[7] != unknownSigned {
+		r.NOxValid = true
+		r.NOxIndex = float32(int16(resp[7])) / 10
+	}
+	return r, nil
+}
+
+// StartFanCleaning runs the fan at full speed for FanCleaningTime, it only works
+// while measuring
+func (d *Dev) StartFanCleaning() error {
+	_, err := d.command(cmdStartFanCleaning, 20*time.Millisecond, 0)
+	return err
+}
+
+// AutoCleanInterval returns the fan auto cleaning interval, 0 if it is disabled
+func (d *Dev) AutoCleanInterval() (time.Duration, error) {
+	resp, err := d.command(cmdAutoClean, 20*time.Millisecond, 2)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(uint32(resp[0])<<16|uint32(resp[1])) * time.Second, nil
+}
+
+// SetAutoCleanInterval sets the fan auto cleaning interval, with a resolution of 1s,
+// 0 disables it. It is saved in the sensor.
+func (d *Dev) SetAutoCleanInterval(interval time.Duration) error {
+	s := interval / time.Second
+	if s < 0 || s > math.MaxUint32 {
+		return fmt.Errorf("sen5x: Invalid auto cleaning interval: %s", interval)
+	}
+	_, err := d.command(cmdAutoClean, 20*time.Millisecond, 0, uint16(s>>16), uint16(s))
+	return err
+}
+
+// ReadStatus returns the device status register, and clears it if clear is true
+func (d *Dev) ReadStatus(clear bool) (Status, error) {
+	cmd := cmdReadStatus
+	if clear {
+		cmd = cmdReadAndClearStatus
+	}
+	resp, err := d.command(cmd, 20*time.Millisecond, 2)
+	if err != nil {
+		return 0, err
+	}
+	return Status(uint32(resp[0])<<16 | uint32(resp[1])), nil
+}
+
+// Reset resets the sensor, it needs StartMeasurement to start measuring again
+func (d *Dev) Reset() error {
+	_, err := d.command(cmdReset, 100*time.Millisecond, 0)
+	return err
+}
+
+// command sends a command, with optional argument words, waits for it to execute,
+// and then returns the response words
+func (d *Dev) command(cmd uint16, wait time.Duration, respWords int, args ...uint16) ([]uint16, error) {
+	return sensirion.Command(d.i2c, "sen5x", cmd, wait, respWords, args...)
+}
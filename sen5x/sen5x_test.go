@@ -0,0 +1,211 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sen5x
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"periph.io/x/periph/conn/i2c/i2ctest"
+	"periph.io/x/periph/conn/physic"
+
+	"github.com/bcl/air-sensors/internal/sensirion"
+)
+
+func TestNew(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the product name
+			{Addr: 0x69, W: []byte{0xd0, 0x14}},
+			{Addr: 0x69, W: []byte{}, R: sensirion.WordCRC(0x5345, 0x4e35, 0x3500, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0)},
+			{Addr: 0x69, W: []byte{0xd0, 0x33}},
+			{Addr: 0x69, W: []byte{}, R: sensirion.WordCRC(0x3132, 0x3334, 0x4142, 0x4344, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0)},
+			{Addr: 0x69, W: []byte{0xd1, 0x00}},
+			{Addr: 0x69, W: []byte{}, R: sensirion.WordCRC(0x0200)},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if d.String() != "sen5x{playback(105)}" {
+		t.Fatalf("String Error: %s", d.String())
+	}
+	if d.ProductName() != "SEN55" {
+		t.Errorf("ProductName Error: %q", d.ProductName())
+	}
+	if d.SerialNumber() != "1234ABCD" {
+		t.Errorf("SerialNumber Error: %q", d.SerialNumber())
+	}
+	if major, minor := d.FirmwareVersion(); major != 2 || minor != 0 {
+		t.Errorf("FirmwareVersion Error: %d.%d", major, minor)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadMeasurement(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the product name
+			{Addr: 0x69, W: []byte{0xd0, 0x14}},
+			{Addr: 0x69, W: []byte{}, R: sensirion.WordCRC(0x5345, 0x4e35, 0x3500, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0)},
+			{Addr: 0x69, W: []byte{0xd0, 0x33}},
+			{Addr: 0x69, W: []byte{}, R: sensirion.WordCRC(0x3132, 0x3334, 0x4142, 0x4344, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0)},
+			{Addr: 0x69, W: []byte{0xd1, 0x00}},
+			{Addr: 0x69, W: []byte{}, R: sensirion.WordCRC(0x0200)},
+			// Start the measurement, nothing is ready yet
+			{Addr: 0x69, W: []byte{0x00, 0x21}},
+			{Addr: 0x69, W: []byte{0x02, 0x02}},
+			{Addr: 0x69, W: []byte{}, R: sensirion.WordCRC(0x0000)},
+			// A reading
+			{Addr: 0x69, W: []byte{0x02, 0x02}},
+			{Addr: 0x69, W: []byte{}, R: sensirion.WordCRC(0x0001)},
+			{Addr: 0x69, W: []byte{0x03, 0xc4}},
+			{Addr: 0x69, W: []byte{}, R: sensirion.WordCRC(45, 62, 70, 75, 4863, 5023, 1000, 10)},
+			// A reading with the unavailable values
+			{Addr: 0x69, W: []byte{0x02, 0x02}},
+			{Addr: 0x69, W: []byte{}, R: sensirion.WordCRC(0x0001)},
+			{Addr: 0x69, W: []byte{0x03, 0xc4}},
+			{Addr: 0x69, W: []byte{}, R: sensirion.WordCRC(0xffff, 0xffff, 0xffff, 0xffff, 2000, 0xfc18, 0x7fff, 0x7fff)},
+			{Addr: 0x69, W: []byte{0x01, 0x04}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if err := d.StartMeasurement(); err != nil {
+		t.Fatalf("StartMeasurement Error: %s", err)
+	}
+	if _, err := d.ReadMeasurement(); !errors.Is(err, ErrNotReady) {
+		t.Errorf("ErrNotReady Error: %v", err)
+	}
+
+	r, err := d.ReadMeasurement()
+	if err != nil {
+		t.Fatalf("ReadMeasurement Error: %s", err)
+	}
+	if !r.PMValid || r.MassPM1 != 4.5 || r.MassPM2_5 != 6.2 || r.MassPM4 != 7 || r.MassPM10 != 7.5 {
+		t.Errorf("PM Error: %#v", r)
+	}
+	if !r.EnvValid || r.Humidity != 4863000*physic.TenthMicroRH || r.Temperature != physic.ZeroCelsius+25115*physic.MilliCelsius {
+		t.Errorf("Env Error: %s %s", r.Humidity, r.Temperature)
+	}
+	if !r.VOCValid || r.VOCIndex != 100 || !r.NOxValid || r.NOxIndex != 1 {
+		t.Errorf("Index Error: %#v", r)
+	}
+
+	// Without the PM readings, and the NOx Index of a SEN54
+	r, err = d.ReadMeasurement()
+	if err != nil {
+		t.Fatalf("ReadMeasurement Error: %s", err)
+	}
+	if r.PMValid || r.VOCValid || r.NOxValid || !r.EnvValid {
+		t.Errorf("Valid Error: %#v", r)
+	}
+	if r.Temperature != physic.ZeroCelsius-5*physic.Celsius {
+		t.Errorf("Negative temperature Error: %s", r.Temperature)
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatalf("Halt Error: %s", err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFanCleaning(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the product name
+			{Addr: 0x69, W: []byte{0xd0, 0x14}},
+			{Addr: 0x69, W: []byte{}, R: sensirion.WordCRC(0x5345, 0x4e35, 0x3500, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0)},
+			{Addr: 0x69, W: []byte{0xd0, 0x33}},
+			{Addr: 0x69, W: []byte{}, R: sensirion.WordCRC(0x3132, 0x3334, 0x4142, 0x4344, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0)},
+			{Addr: 0x69, W: []byte{0xd1, 0x00}},
+			{Addr: 0x69, W: []byte{}, R: sensirion.WordCRC(0x0200)},
+			{Addr: 0x69, W: []byte{0x00, 0x37}},
+			{Addr: 0x69, W: []byte{0x80, 0x04}},
+			{Addr: 0x69, W: []byte{}, R: sensirion.WordCRC(0x0009, 0x3a80)},
+			{Addr: 0x69, W: append([]byte{0x80, 0x04}, sensirion.WordCRC(0x0001, 0x5180)...)},
+			{Addr: 0x69, W: []byte{0x56, 0x07}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if err := d.StartRHTGasMeasurement(); err != nil {
+		t.Fatalf("StartRHTGasMeasurement Error: %s", err)
+	}
+	interval, err := d.AutoCleanInterval()
+	if err != nil {
+		t.Fatalf("AutoCleanInterval Error: %s", err)
+	}
+	if interval != DefaultAutoCleanInterval {
+		t.Errorf("AutoCleanInterval Error: %s", interval)
+	}
+	if err := d.SetAutoCleanInterval(24 * time.Hour); err != nil {
+		t.Fatalf("SetAutoCleanInterval Error: %s", err)
+	}
+	if err := d.StartFanCleaning(); err != nil {
+		t.Fatalf("StartFanCleaning Error: %s", err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the product name
+			{Addr: 0x69, W: []byte{0xd0, 0x14}},
+			{Addr: 0x69, W: []byte{}, R: sensirion.WordCRC(0x5345, 0x4e35, 0x3500, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0)},
+			{Addr: 0x69, W: []byte{0xd0, 0x33}},
+			{Addr: 0x69, W: []byte{}, R: sensirion.WordCRC(0x3132, 0x3334, 0x4142, 0x4344, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0)},
+			{Addr: 0x69, W: []byte{0xd1, 0x00}},
+			{Addr: 0x69, W: []byte{}, R: sensirion.WordCRC(0x0200)},
+			{Addr: 0x69, W: []byte{0xd2, 0x06}},
+			{Addr: 0x69, W: []byte{}, R: sensirion.WordCRC(0x0008, 0x0000)},
+			{Addr: 0x69, W: []byte{0xd2, 0x10}},
+			{Addr: 0x69, W: []byte{}, R: sensirion.WordCRC(0x0020, 0x0090)},
+			{Addr: 0x69, W: []byte{0xd3, 0x04}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	status, err := d.ReadStatus(false)
+	if err != nil {
+		t.Fatalf("ReadStatus Error: %s", err)
+	}
+	if status != StatusFanCleaning || status.Errors() || status.String() != "fan cleaning" {
+		t.Errorf("ReadStatus Error: %s", status)
+	}
+	if status, err = d.ReadStatus(true); err != nil {
+		t.Fatalf("ReadStatus Error: %s", err)
+	}
+	if !status.Errors() || status.String() != "fan speed warning, gas sensor error, fan failure" {
+		t.Errorf("ReadStatus Error: %s", status)
+	}
+	if Status(0).String() != "ok" {
+		t.Errorf("Status String Error: %s", Status(0))
+	}
+	if err := d.Reset(); err != nil {
+		t.Fatalf("Reset Error: %s", err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
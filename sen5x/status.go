@@ -0,0 +1,55 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sen5x
+
+import (
+	"strings"
+)
+
+// Status is the device status register
+type Status uint32
+
+// Device status register bits
+const (
+	StatusFanSpeed    Status = 1 << 21 // The fan speed is too high or too low
+	StatusFanCleaning Status = 1 << 19 // The fan cleaning is running
+	StatusGas         Status = 1 << 7  // The VOC or NOx gas sensor has an error
+	StatusRHT         Status = 1 << 6  // Error communicating with the RH&T sensor
+	StatusLaser       Status = 1 << 5  // The laser current is out of range
+	StatusFan         Status = 1 << 4  // The fan is switched on, but not turning
+)
+
+// statusNames describes the status bits, in order
+var statusNames = []struct {
+	bit  Status
+	name string
+}{
+	{StatusFanSpeed, "fan speed warning"},
+	{StatusFanCleaning, "fan cleaning"},
+	{StatusGas, "gas sensor error"},
+	{StatusRHT, "RH&T communication error"},
+	{StatusLaser, "laser failure"},
+	{StatusFan, "fan failure"},
+}
+
+// Errors returns true if any of the error bits are set, the fan speed warning and
+// fan cleaning are not errors
+func (s Status) Errors() bool {
+	return s&(StatusGas|StatusRHT|StatusLaser|StatusFan) != 0
+}
+
+// String returns the names of the status bits that are set, or ok if none are
+func (s Status) String() string {
+	var names []string
+	for _, n := range statusNames {
+		if s&n.bit != 0 {
+			names = append(names, n.name)
+		}
+	}
+	if len(names) == 0 {
+		return "ok"
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,19 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package senseair controls a Senseair S8 LP NDIR CO2 sensor using Modbus RTU over a
+// UART.
+//
+// It reads the CO2 concentration and the meter status from the input registers, reads
+// and sets the automatic baseline correction (ABC) period, and triggers a background
+// calibration using the holding registers.
+//
+// Datasheet
+//
+// https://rmtplusstoragesenseair.blob.core.windows.net/docs/publicerat/PSP126.pdf
+//
+// Modbus
+//
+// https://rmtplusstoragesenseair.blob.core.windows.net/docs/Dev/publicerat/TDE2067.pdf
+package senseair
@@ -0,0 +1,37 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package senseair_test
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/bcl/air-sensors/senseair"
+)
+
+func Example() {
+	// The port needs to be configured first, eg. stty -F /dev/ttyS0 9600 raw
+	port, err := os.OpenFile("/dev/ttyS0", os.O_RDWR, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer port.Close()
+
+	d, err := senseair.New(port)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer d.Halt() //nolint
+
+	r, err := d.ReadCO2()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if r.Status != 0 {
+		log.Fatalf("Sensor status: 0x%04X", uint16(r.Status))
+	}
+	fmt.Printf("CO2: %d ppm\n", r.CO2)
+}
@@ -0,0 +1,117 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package senseair
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/bcl/air-sensors/internal/uart"
+)
+
+// Modbus function codes used by the sensor
+const (
+	fnReadHolding  byte = 0x03 // Read holding registers
+	fnReadInput    byte = 0x04 // Read input registers
+	fnWriteHolding byte = 0x06 // Write a single holding register
+	fnException    byte = 0x80 // Set in the function code of exception responses
+)
+
+// ErrCRC is returned when a response's CRC does not match
+var ErrCRC = errors.New("senseair: Bad CRC")
+
+// readRegisters reads count registers, starting at reg, with a read holding or read
+// input registers request
+func (d *Dev) readRegisters(fn byte, reg, count uint16) ([]uint16, error) {
+	if err := d.request(fn, reg, count); err != nil {
+		return nil, err
+	}
+	// The response is the address, function, byte count, the registers and the CRC
+	resp, err := d.response(fn, func(hdr []byte) int { return int(hdr[2]) })
+	if err != nil {
+		return nil, err
+	}
+	if int(resp[2]) != int(count)*2 {
+		return nil, fmt.Errorf("senseair: Wrong response length %d for %d registers", resp[2], count)
+	}
+	regs := make([]uint16, count)
+	for i := range regs {
+		regs[i] = uint16(resp[3+i*2])<<8 | uint16(resp[4+i*2])
+	}
+	return regs, nil
+}
+
+// writeRegister writes a single holding register
+func (d *Dev) writeRegister(reg, value uint16) error {
+	if err := d.request(fnWriteHolding, reg, value); err != nil {
+		return err
+	}
+	// The response echoes the request, the address, function, register, value and CRC
+	resp, err := d.response(fnWriteHolding, func(hdr []byte) int { return 3 })
+	if err != nil {
+		return err
+	}
+	if uint16(resp[2])<<8|uint16(resp[3]) != reg || uint16(resp[4])<<8|uint16(resp[5]) != value {
+		return fmt.Errorf("senseair: Wrong response to writing register %d: % x", reg, resp)
+	}
+	return nil
+}
+
+// request sends a request with 2 words, the register, and the register count or value
+func (d *Dev) request(fn byte, reg, value uint16) error {
+	buf := []byte{d.addr, fn, byte(reg >> 8), byte(reg), byte(value >> 8), byte(value)}
+	crc := crc16(buf)
+	buf = append(buf, byte(crc), byte(crc>>8))
+	if _, err := d.port.Write(buf); err != nil {
+		return fmt.Errorf("senseair: Error while sending function 0x%02x: %w", fn, err)
+	}
+	return nil
+}
+
+// response reads the response to the function, and checks its CRC
+//
+// remaining returns how many bytes follow the 3 byte header, not counting the 2 byte
+// CRC. Exception responses return an error.
+func (d *Dev) response(fn byte, remaining func(hdr []byte) int) ([]byte, error) {
+	resp := make([]byte, 3, 3+255+2)
+	if err := uart.ReadFrame(d.port, resp, d.addr); err != nil {
+		return nil, fmt.Errorf("senseair: Error while reading the sensor: %w", err)
+	}
+
+	n := remaining(resp)
+	if resp[1] == fn|fnException {
+		n = 0
+	} else if resp[1] != fn {
+		return nil, fmt.Errorf("senseair: Wrong function in response: 0x%02x", resp[1])
+	}
+	resp = resp[:3+n+2]
+	if _, err := io.ReadFull(d.port, resp[3:]); err != nil {
+		return nil, fmt.Errorf("senseair: Error while reading the sensor: %w", err)
+	}
+	if crc16(resp[:3+n]) != uint16(resp[3+n])|uint16(resp[4+n])<<8 {
+		return nil, ErrCRC
+	}
+	if resp[1] == fn|fnException {
+		return nil, fmt.Errorf("senseair: Modbus exception 0x%02x for function 0x%02x", resp[2], fn)
+	}
+	return resp[:3+n], nil
+}
+
+// crc16 returns the Modbus CRC16 of the data, it is sent low byte first
+func crc16(data []byte) uint16 {
+	crc := uint16(0xffff)
+	for _, b := range data {
+		crc ^= uint16(b)
+		for i := 0; i < 8; i++ {
+			if crc&0x0001 != 0 {
+				crc = crc>>1 ^ 0xa001
+			} else {
+				crc >>= 1
+			}
+		}
+	}
+	return crc
+}
@@ -0,0 +1,22 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package senseair
+
+// AnyAddress is the Modbus address every sensor answers to, use it when there is only
+// one sensor connected to the UART
+const AnyAddress byte = 0xfe
+
+// DefaultAddr is the sensor's Modbus address set at the factory
+const DefaultAddr byte = 0x68
+
+// Option configures the Dev returned by New
+type Option func(*Dev)
+
+// WithAddress sets the Modbus address of the sensor, the default is AnyAddress
+func WithAddress(addr byte) Option {
+	return func(d *Dev) {
+		d.addr = addr
+	}
+}
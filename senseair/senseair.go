@@ -0,0 +1,173 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package senseair
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"periph.io/x/periph/conn"
+)
+
+// CalibrationTime is how long the background calibration takes
+const CalibrationTime = 2 * time.Second
+
+// DefaultABCPeriod is the automatic baseline correction period set at the factory
+const DefaultABCPeriod = 180 * time.Hour
+
+// Input registers, starting at 0 for IR1
+const (
+	irMeterStatus uint16 = 0  // Meter status, followed by alarm and output status
+	irCO2         uint16 = 3  // Space CO2 in ppm
+	irFirmware    uint16 = 28 // Firmware version, main and sub
+	irSensorID    uint16 = 29 // Sensor ID, high and low words
+)
+
+// Holding registers, starting at 0 for HR1
+const (
+	hrAck       uint16 = 0  // Acknowledgement register
+	hrCommand   uint16 = 1  // Special command register
+	hrABCPeriod uint16 = 31 // ABC period in hours, 0 disables it
+)
+
+const (
+	cmdBackgroundCalibration uint16 = 0x7c06 // Calibrate to 400ppm fresh air
+	ackBackgroundCalibration uint16 = 0x0020 // Background calibration is done
+)
+
+// Status is the meter status register
+type Status uint16
+
+// Meter status bits
+const (
+	StatusFatal       Status = 0x0001 // Fatal error
+	StatusOffset      Status = 0x0002 // Offset regulation error
+	StatusAlgorithm   Status = 0x0004 // Algorithm error
+	StatusOutput      Status = 0x0008 // Output error
+	StatusDiagnostics Status = 0x0010 // Self diagnostics error
+	StatusOutOfRange  Status = 0x0020 // The reading is out of range
+	StatusMemory      Status = 0x0040 // Memory error
+)
+
+// Reading holds the readings from the sensor
+type Reading struct {
+	CO2       uint16    `json:"co2"`              // CO2 in ppm
+	Status    Status    `json:"status,omitempty"` // Meter status, 0 when the sensor is ok
+	Timestamp time.Time `json:"timestamp"`        // When the reading was made
+}
+
+// Dev holds the connection to the Senseair sensor
+type Dev struct {
+	port io.ReadWriter // UART connected to the sensor
+	addr byte          // Modbus address of the sensor
+}
+
+var _ conn.Resource = &Dev{}
+
+// New returns a Senseair device struct for communicating with the sensor over a UART
+//
+// The port needs to be configured for 9600 baud 8N1 before calling New.
+func New(port io.ReadWriter, opts ...Option) (*Dev, error) {
+	d := &Dev{port: port, addr: AnyAddress}
+	for _, o := range opts {
+		o(d)
+	}
+	return d, nil
+}
+
+// String implements conn.Resource.
+func (d *Dev) String() string {
+	return fmt.Sprintf("senseair{%v}", d.port)
+}
+
+// Halt implements conn.Resource.
+//
+// The S8 measures continuously and cannot be halted.
+func (d *Dev) Halt() error {
+	return nil
+}
+
+// ReadCO2 returns the CO2 concentration and the meter status
+func (d *Dev) ReadCO2() (Reading, error) {
+	regs, err := d.readRegisters(fnReadInput, irMeterStatus, irCO2+1)
+	if err != nil {
+		return Reading{}, err
+	}
+	return Reading{
+		CO2:       regs[irCO2],
+		Status:    Status(regs[irMeterStatus]),
+		Timestamp: time.Now(),
+	}, nil
+}
+
+// ReadStatus returns the meter status, 0 when the sensor is ok
+func (d *Dev) ReadStatus() (Status, error) {
+	regs, err := d.readRegisters(fnReadInput, irMeterStatus, 1)
+	if err != nil {
+		return 0, err
+	}
+	return Status(regs[0]), nil
+}
+
+// SensorID returns the sensor's 32 bit ID
+func (d *Dev) SensorID() (uint32, error) {
+	regs, err := d.readRegisters(fnReadInput, irSensorID, 2)
+	if err != nil {
+		return 0, err
+	}
+	return uint32(regs[0])<<16 | uint32(regs[1]), nil
+}
+
+// FirmwareVersion returns the firmware's main and sub version
+func (d *Dev) FirmwareVersion() (uint8, uint8, error) {
+	regs, err := d.readRegisters(fnReadInput, irFirmware, 1)
+	if err != nil {
+		return 0, 0, err
+	}
+	return uint8(regs[0] >> 8), uint8(regs[0]), nil
+}
+
+// ABCPeriod returns the automatic baseline correction period, 0 if it is disabled
+func (d *Dev) ABCPeriod() (time.Duration, error) {
+	regs, err := d.readRegisters(fnReadHolding, hrABCPeriod, 1)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(regs[0]) * time.Hour, nil
+}
+
+// SetABCPeriod sets the automatic baseline correction period, with a resolution of
+// 1 hour, 0 disables it. The correction calibrates to the lowest concentration seen
+// in each period, which needs the sensor to see fresh air during it.
+func (d *Dev) SetABCPeriod(period time.Duration) error {
+	h := period / time.Hour
+	if h < 0 || h > 0xffff {
+		return fmt.Errorf("senseair: Invalid ABC period: %s", period)
+	}
+	return d.writeRegister(hrABCPeriod, uint16(h))
+}
+
+// BackgroundCalibration calibrates the sensor to the 400ppm of fresh air
+//
+// The sensor needs to have been in fresh outdoor air for at least a minute. It takes
+// CalibrationTime, and returns an error if the sensor did not acknowledge it.
+func (d *Dev) BackgroundCalibration() error {
+	if err := d.writeRegister(hrAck, 0); err != nil {
+		return err
+	}
+	if err := d.writeRegister(hrCommand, cmdBackgroundCalibration); err != nil {
+		return err
+	}
+	time.Sleep(CalibrationTime)
+	regs, err := d.readRegisters(fnReadHolding, hrAck, 1)
+	if err != nil {
+		return err
+	}
+	if regs[0]&ackBackgroundCalibration == 0 {
+		return fmt.Errorf("senseair: Background calibration failed: 0x%04X", regs[0])
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package senseair
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bcl/air-sensors/internal/uart/uarttest"
+)
+
+func TestCRC16(t *testing.T) {
+	// The read CO2 request from the S8 manual
+	if crc16([]byte{0xfe, 0x04, 0x00, 0x03, 0x00, 0x01}) != 0xc5d5 {
+		t.Errorf("CRC16 Error: 0x%04X", crc16([]byte{0xfe, 0x04, 0x00, 0x03, 0x00, 0x01}))
+	}
+}
+
+func TestReadCO2(t *testing.T) {
+	var port uarttest.Port
+	port.R.Write([]byte{0xfe, 0x04, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0x60, 0x16, 0x52})
+	port.R.Write([]byte{0xfe, 0x04, 0x02, 0x00, 0x20, 0xac, 0xfc})
+	d, err := New(&port)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	r, err := d.ReadCO2()
+	if err != nil {
+		t.Fatalf("ReadCO2 Error: %s", err)
+	}
+	if r.CO2 != 608 || r.Status != 0 {
+		t.Errorf("ReadCO2 Error: %#v", r)
+	}
+	status, err := d.ReadStatus()
+	if err != nil {
+		t.Fatalf("ReadStatus Error: %s", err)
+	}
+	if status != StatusOutOfRange {
+		t.Errorf("ReadStatus Error: 0x%04X", uint16(status))
+	}
+	if requests := []byte{
+		0xfe, 0x04, 0x00, 0x00, 0x00, 0x04, 0xe5, 0xc6,
+		0xfe, 0x04, 0x00, 0x00, 0x00, 0x01, 0x25, 0xc5,
+	}; !bytes.Equal(port.W.Bytes(), requests) {
+		t.Errorf("Requests Error: % x", port.W.Bytes())
+	}
+}
+
+func TestAddress(t *testing.T) {
+	var port uarttest.Port
+	// A response from another sensor is skipped while looking for the address
+	port.R.Write([]byte{0xfe, 0x04, 0x02, 0x00, 0x20, 0xac, 0xfc})
+	port.R.Write([]byte{0x68, 0x04, 0x02, 0x00, 0x00, 0xe5, 0x39})
+	d, err := New(&port, WithAddress(DefaultAddr))
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if status, err := d.ReadStatus(); err != nil || status != 0 {
+		t.Errorf("ReadStatus Error: 0x%04X %v", uint16(status), err)
+	}
+	if requests := []byte{0x68, 0x04, 0x00, 0x00, 0x00, 0x01, 0x38, 0xf3}; !bytes.Equal(port.W.Bytes(), requests) {
+		t.Errorf("Requests Error: % x", port.W.Bytes())
+	}
+}
+
+func TestErrors(t *testing.T) {
+	var port uarttest.Port
+	port.R.Write([]byte{0xfe, 0x04, 0x02, 0x00, 0x20, 0xac, 0xfd})
+	port.R.Write([]byte{0xfe, 0x84, 0x02, 0xf2, 0xf1})
+	d, err := New(&port)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if _, err := d.ReadStatus(); !errors.Is(err, ErrCRC) {
+		t.Errorf("CRC Error: %v", err)
+	}
+	if _, err := d.ReadStatus(); err == nil {
+		t.Error("Exception Error")
+	}
+}
+
+func TestInfo(t *testing.T) {
+	var port uarttest.Port
+	port.R.Write([]byte{0xfe, 0x04, 0x04, 0x01, 0x23, 0x45, 0x67, 0x77, 0xc7})
+	port.R.Write([]byte{0xfe, 0x04, 0x02, 0x04, 0x02, 0x2e, 0x25})
+	d, err := New(&port)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if id, err := d.SensorID(); err != nil || id != 0x01234567 {
+		t.Errorf("SensorID Error: 0x%08X %v", id, err)
+	}
+	if major, minor, err := d.FirmwareVersion(); err != nil || major != 4 || minor != 2 {
+		t.Errorf("FirmwareVersion Error: %d.%d %v", major, minor, err)
+	}
+	if requests := []byte{
+		0xfe, 0x04, 0x00, 0x1d, 0x00, 0x02, 0xf5, 0xc2,
+		0xfe, 0x04, 0x00, 0x1c, 0x00, 0x01, 0xe4, 0x03,
+	}; !bytes.Equal(port.W.Bytes(), requests) {
+		t.Errorf("Requests Error: % x", port.W.Bytes())
+	}
+}
+
+func TestABCPeriod(t *testing.T) {
+	var port uarttest.Port
+	port.R.Write([]byte{0xfe, 0x03, 0x02, 0x00, 0xb4, 0xac, 0x27})
+	port.R.Write([]byte{0xfe, 0x06, 0x00, 0x1f, 0x00, 0xa8, 0xad, 0xbd})
+	d, err := New(&port)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	period, err := d.ABCPeriod()
+	if err != nil {
+		t.Fatalf("ABCPeriod Error: %s", err)
+	}
+	if period != DefaultABCPeriod {
+		t.Errorf("ABCPeriod Error: %s", period)
+	}
+	if err := d.SetABCPeriod(168 * time.Hour); err != nil {
+		t.Fatalf("SetABCPeriod Error: %s", err)
+	}
+	if err := d.SetABCPeriod(-time.Hour); err == nil {
+		t.Error("Invalid ABC period Error")
+	}
+	if requests := []byte{
+		0xfe, 0x03, 0x00, 0x1f, 0x00, 0x01, 0xa1, 0xc3,
+		0xfe, 0x06, 0x00, 0x1f, 0x00, 0xa8, 0xad, 0xbd,
+	}; !bytes.Equal(port.W.Bytes(), requests) {
+		t.Errorf("Requests Error: % x", port.W.Bytes())
+	}
+}
+
+func TestBackgroundCalibration(t *testing.T) {
+	var port uarttest.Port
+	port.R.Write([]byte{0xfe, 0x06, 0x00, 0x00, 0x00, 0x00, 0x9d, 0xc5})
+	port.R.Write([]byte{0xfe, 0x06, 0x00, 0x01, 0x7c, 0x06, 0x6c, 0xc7})
+	port.R.Write([]byte{0xfe, 0x03, 0x02, 0x00, 0x20, 0xad, 0x88})
+	d, err := New(&port)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if err := d.BackgroundCalibration(); err != nil {
+		t.Fatalf("BackgroundCalibration Error: %s", err)
+	}
+	if requests := []byte{
+		0xfe, 0x06, 0x00, 0x00, 0x00, 0x00, 0x9d, 0xc5,
+		0xfe, 0x06, 0x00, 0x01, 0x7c, 0x06, 0x6c, 0xc7,
+		0xfe, 0x03, 0x00, 0x00, 0x00, 0x01, 0x90, 0x05,
+	}; !bytes.Equal(port.W.Bytes(), requests) {
+		t.Errorf("Requests Error: % x", port.W.Bytes())
+	}
+}
@@ -0,0 +1,15 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package sfa30 controls a Sensirion SFA30 formaldehyde sensor over I²C.
+//
+// The SFA30 measures the formaldehyde (HCHO) concentration, the relative humidity and
+// the temperature every 500ms after StartMeasurement.
+//
+// The SEL pin needs to be connected to GND to select the I²C interface.
+//
+// Datasheet
+//
+// https://sensirion.com/media/documents/7AF1AAB8/6188D96F/Sensirion_formaldehyde_sensors_Datasheet_SFA30.pdf
+package sfa30
@@ -0,0 +1,47 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sfa30_test
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"periph.io/x/periph/conn/i2c/i2creg"
+	"periph.io/x/periph/host"
+
+	"github.com/bcl/air-sensors/sfa30"
+)
+
+func Example() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Open a handle to the first available I²C bus:
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer bus.Close()
+
+	d, err := sfa30.New(bus)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer d.Halt() //nolint
+
+	if err := d.StartMeasurement(); err != nil {
+		log.Fatal(err)
+	}
+	time.Sleep(sfa30.WarmupTime)
+
+	r, err := d.ReadMeasurement()
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("HCHO: %0.1f ppb %8s %9s\n", r.HCHO, r.Temperature, r.Humidity)
+}
@@ -0,0 +1,134 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sfa30
+
+import (
+	"fmt"
+	"time"
+
+	"periph.io/x/periph/conn"
+	"periph.io/x/periph/conn/i2c"
+	"periph.io/x/periph/conn/physic"
+
+	"github.com/bcl/air-sensors/internal/sensirion"
+)
+
+// Addr is the I²C address of the SFA30, it cannot be changed
+const Addr uint16 = 0x5d
+
+// WarmupTime is how long the HCHO readings take to be valid after StartMeasurement,
+// they are 0 until then
+const WarmupTime = 10 * time.Second
+
+// SFA30 commands from the datasheet
+const (
+	cmdStartMeasurement uint16 = 0x0006 // Start continuous measurements
+	cmdStopMeasurement  uint16 = 0x0104 // Stop measuring and go idle
+	cmdReadMeasured     uint16 = 0x0327 // Returns the HCHO, RH and T readings
+	cmdGetDeviceMarking uint16 = 0xd060 // Returns the device marking as a string
+	cmdDeviceReset      uint16 = 0xd304 // Resets the sensor
+)
+
+// Reading holds the readings from the SFA30
+type Reading struct {
+	HCHO        float32                 `json:"hcho"`        // Formaldehyde in ppb
+	Temperature physic.Temperature      `json:"temperature"` // Temperature
+	Humidity    physic.RelativeHumidity `json:"humidity"`    // Relative humidity
+	Timestamp   time.Time               `json:"timestamp"`   // When the reading was made
+}
+
+// Dev holds the connection to the SFA30
+type Dev struct {
+	i2c     conn.Conn // i2c device handle for the sfa30
+	marking string    // Device marking, with the serial number
+}
+
+var _ conn.Resource = &Dev{}
+
+// New returns a SFA30 device struct for communicating with the device
+//
+// It reads the device marking, StartMeasurement needs to be called before reading
+// the measurements.
+func New(i i2c.Bus) (*Dev, error) {
+	d := &Dev{i2c: &i2c.Dev{Bus: i, Addr: Addr}}
+
+	resp, err := d.command(cmdGetDeviceMarking, 2*time.Millisecond, 16)
+	if err != nil {
+		return nil, err
+	}
+	d.marking = sensirion.String(resp)
+	return d, nil
+}
+
+// String implements conn.Resource.
+func (d *Dev) String() string {
+	return fmt.Sprintf("sfa30{%s}", d.i2c)
+}
+
+// Halt implements conn.Resource.
+//
+// It stops the measurements.
+func (d *Dev) Halt() error {
+	return d.StopMeasurement()
+}
+
+// DeviceMarking returns the device marking printed on the sensor, which includes its
+// serial number
+func (d *Dev) DeviceMarking() string {
+	return d.marking
+}
+
+// StartMeasurement starts the continuous measurements, the HCHO readings are 0 for
+// the first WarmupTime
+func (d *Dev) StartMeasurement() error {
+	_, err := d.command(cmdStartMeasurement, time.Millisecond, 0)
+	return err
+}
+
+// StopMeasurement stops the measurements
+func (d *Dev) StopMeasurement() error {
+	_, err := d.command(cmdStopMeasurement, 50*time.Millisecond, 0)
+	return err
+}
+
+// ReadMeasurement returns the latest HCHO, humidity and temperature readings
+func (d *Dev) ReadMeasurement() (Reading, error) {
+	resp, err := d.command(cmdReadMeasured, 5*time.Millisecond, 3)
+	if err != nil {
+		return Reading{}, err
+	}
+
+	// HCHO = raw / 5, RH = raw / 100, T = raw / 200
+	var r Reading
+	r.HCHO = float32(int16(resp[0])) / 5
+	r.Humidity = physic.RelativeHumidity(int64(int16(resp[1])) * int64(physic.PercentRH) / 100)
+	r.Temperature = physic.ZeroCelsius + physic.Temperature(int64(int16(resp[2]))*5)*physic.MilliCelsius
+	r.Timestamp = time.Now()
+	return r, nil
+}
+
+// Sense reads the humidity and temperature, it needs StartMeasurement to be called
+// first
+func (d *Dev) Sense(env *physic.Env) error {
+	r, err := d.ReadMeasurement()
+	if err != nil {
+		return err
+	}
+	env.Humidity = r.Humidity
+	env.Temperature = r.Temperature
+	return nil
+}
+
+// Reset resets the sensor, it needs StartMeasurement to start measuring again
+func (d *Dev) Reset() error {
+	_, err := d.command(cmdDeviceReset, 100*time.Millisecond, 0)
+	return err
+}
+
+// command sends a command, with optional argument words, waits for it to execute,
+// and then returns the response words
+func (d *Dev) command(cmd uint16, wait time.Duration, respWords int, args ...uint16) ([]uint16, error) {
+	return sensirion.Command(d.i2c, "sfa30", cmd, wait, respWords, args...)
+}
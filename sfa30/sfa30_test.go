@@ -0,0 +1,125 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sfa30
+
+import (
+	"testing"
+
+	"periph.io/x/periph/conn/i2c/i2ctest"
+	"periph.io/x/periph/conn/physic"
+
+	"github.com/bcl/air-sensors/internal/sensirion"
+)
+
+var ()
+
+func TestNew(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the device marking
+			{Addr: 0x5d, W: []byte{0xd0, 0x60}},
+			{Addr: 0x5d, W: []byte{}, R: sensirion.WordCRC(0x3231, 0x3131, 0x3430, 0x3035, 0x4131, 0x3233, 0x3400, 0, 0, 0, 0, 0, 0, 0, 0, 0)},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if d.String() != "sfa30{playback(93)}" {
+		t.Fatalf("String Error: %s", d.String())
+	}
+	if d.DeviceMarking() != "21114005A1234" {
+		t.Errorf("DeviceMarking Error: %q", d.DeviceMarking())
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMarkingCRC(t *testing.T) {
+	bus := &i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x5d, W: []byte{0xd0, 0x60}},
+			{Addr: 0x5d, W: []byte{}, R: make([]byte, 48)},
+		},
+		DontPanic: true,
+	}
+	if _, err := New(bus); err == nil {
+		t.Fatal("Device marking CRC Error")
+	}
+}
+
+func TestReadMeasurement(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the device marking
+			{Addr: 0x5d, W: []byte{0xd0, 0x60}},
+			{Addr: 0x5d, W: []byte{}, R: sensirion.WordCRC(0x3231, 0x3131, 0x3430, 0x3035, 0x4131, 0x3233, 0x3400, 0, 0, 0, 0, 0, 0, 0, 0, 0)},
+			{Addr: 0x5d, W: []byte{0x00, 0x06}},
+			{Addr: 0x5d, W: []byte{0x03, 0x27}},
+			{Addr: 0x5d, W: []byte{}, R: sensirion.WordCRC(103, 4863, 5023)},
+			{Addr: 0x5d, W: []byte{0x03, 0x27}},
+			{Addr: 0x5d, W: []byte{}, R: sensirion.WordCRC(0, 2000, 0xfc18)},
+			{Addr: 0x5d, W: []byte{0x01, 0x04}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if err := d.StartMeasurement(); err != nil {
+		t.Fatalf("StartMeasurement Error: %s", err)
+	}
+	r, err := d.ReadMeasurement()
+	if err != nil {
+		t.Fatalf("ReadMeasurement Error: %s", err)
+	}
+	if r.HCHO != 20.6 {
+		t.Errorf("HCHO Error: %f", r.HCHO)
+	}
+	if r.Humidity != 4863000*physic.TenthMicroRH {
+		t.Errorf("Humidity Error: %s", r.Humidity)
+	}
+	if r.Temperature != physic.ZeroCelsius+25115*physic.MilliCelsius {
+		t.Errorf("Temperature Error: %s", r.Temperature)
+	}
+	var env physic.Env
+	if err := d.Sense(&env); err != nil {
+		t.Fatalf("Sense Error: %s", err)
+	}
+	if env.Humidity != 20*physic.PercentRH || env.Temperature != physic.ZeroCelsius-5*physic.Celsius {
+		t.Errorf("Sense Error: %s %s", env.Humidity, env.Temperature)
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatalf("Halt Error: %s", err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReset(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the device marking
+			{Addr: 0x5d, W: []byte{0xd0, 0x60}},
+			{Addr: 0x5d, W: []byte{}, R: sensirion.WordCRC(0x3231, 0x3131, 0x3430, 0x3035, 0x4131, 0x3233, 0x3400, 0, 0, 0, 0, 0, 0, 0, 0, 0)},
+			{Addr: 0x5d, W: []byte{0xd3, 0x04}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if err := d.Reset(); err != nil {
+		t.Fatalf("Reset Error: %s", err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,290 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sgp30
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bcl/air-sensors/internal/atomicfile"
+	"github.com/bcl/air-sensors/internal/sensirion"
+)
+
+// MaxBaselineAge is how long a saved baseline is valid for
+// The datasheet specifies that a baseline older than 7 days should not be restored,
+// the sensor should start over with its 12 hour early operation phase instead.
+const MaxBaselineAge = 7 * 24 * time.Hour
+
+// Baseline holds the sensor's baseline data and when it was read
+type Baseline struct {
+	Data      [6]byte   // CO2 and TVOC words with their CRC8, as returned by ReadBaseline
+	Timestamp time.Time // When the baseline was read from the sensor
+	Serial    uint64    // Serial number of the sensor, 0 if it is unknown
+}
+
+// Stale returns true if the baseline is too old to be restored, or if it is
+// missing the timestamp.
+func (b Baseline) Stale(now time.Time) bool {
+	return b.Timestamp.IsZero() || now.Sub(b.Timestamp) > MaxBaselineAge
+}
+
+// CO2 returns the CO2 baseline word
+func (b Baseline) CO2() uint16 {
+	return sensirion.Word(b.Data[:], 0)
+}
+
+// TVOC returns the TVOC baseline word
+func (b Baseline) TVOC() uint16 {
+	return sensirion.Word(b.Data[:], 3)
+}
+
+// BaselineStore is used to persist the sensor's baseline data between restarts
+type BaselineStore interface {
+	// Load returns the saved baseline, or nil if no baseline has been saved
+	Load() (*Baseline, error)
+	// Save stores the baseline
+	Save(baseline Baseline) error
+}
+
+// BaselineFormat selects the file format used by FileStore
+type BaselineFormat int
+
+const (
+	// FormatBinary stores the 6 bytes of baseline data followed by the timestamp as
+	// 64 bit big endian Unix seconds.
+	FormatBinary BaselineFormat = iota
+
+	// FormatHex stores a single line with the baseline data as hex, the timestamp
+	// in RFC 3339 format, and the serial number as hex, separated by spaces.
+	// eg. 88a1588dc461 2020-12-01T10:30:00Z 00000081579C
+	FormatHex
+
+	// FormatJSON stores the baseline data and serial number as hex strings, and the
+	// timestamp in RFC 3339 format.
+	// eg. {"baseline":"88a1588dc461","timestamp":"2020-12-01T10:30:00Z","serial":"00000081579C"}
+	FormatJSON
+)
+
+// baselineJSON is the FormatJSON file contents
+type baselineJSON struct {
+	Baseline  string    `json:"baseline"`
+	Timestamp time.Time `json:"timestamp"`
+	Serial    string    `json:"serial,omitempty"`
+}
+
+// FileStore stores the baseline data in a file
+//
+// The data is written using Format, but files in any of the formats can be read.
+// Binary files with only the 6 bytes of baseline data, as written by earlier versions,
+// use the file's modification time as the timestamp.
+type FileStore struct {
+	Path   string         // Path and filename for storing baseline values
+	Format BaselineFormat // File format used when saving the baseline
+}
+
+// NewFileStore returns a BaselineStore that stores the baseline in a binary file
+func NewFileStore(path string) *FileStore {
+	return &FileStore{Path: path}
+}
+
+// Load reads the baseline data from the file, a missing file is not an error
+func (f *FileStore) Load() (*Baseline, error) {
+	data, err := ioutil.ReadFile(f.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var b *Baseline
+	switch {
+	case len(data) == 6 || len(data) == 14:
+		b, err = parseBinaryBaseline(data)
+	case bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")):
+		b, err = parseJSONBaseline(data)
+	default:
+		b, err = parseHexBaseline(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("baseline file %s: %w", f.Path, err)
+	}
+
+	// Files without a timestamp use the modification time
+	if b.Timestamp.IsZero() {
+		fi, err := os.Stat(f.Path)
+		if err != nil {
+			return nil, err
+		}
+		b.Timestamp = fi.ModTime()
+	}
+	return b, nil
+}
+
+// parseBinaryBaseline parses the 6 bytes of data with an optional 8 byte timestamp
+func parseBinaryBaseline(data []byte) (*Baseline, error) {
+	var b Baseline
+	copy(b.Data[:], data[0:6])
+	if len(data) == 14 {
+		b.Timestamp = time.Unix(int64(binary.BigEndian.Uint64(data[6:14])), 0)
+	}
+	return &b, nil
+}
+
+// parseHexBaseline parses the hex data with an optional timestamp and serial number
+func parseHexBaseline(data []byte) (*Baseline, error) {
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 || len(fields) > 3 {
+		return nil, fmt.Errorf("wrong number of fields: %d", len(fields))
+	}
+
+	var b Baseline
+	if err := decodeBaselineHex(fields[0], &b); err != nil {
+		return nil, err
+	}
+	if len(fields) > 1 {
+		ts, err := time.Parse(time.RFC3339, fields[1])
+		if err != nil {
+			return nil, err
+		}
+		b.Timestamp = ts
+	}
+	if len(fields) > 2 {
+		serial, err := strconv.ParseUint(fields[2], 16, 64)
+		if err != nil {
+			return nil, err
+		}
+		b.Serial = serial
+	}
+	return &b, nil
+}
+
+// parseJSONBaseline parses the JSON baseline data
+func parseJSONBaseline(data []byte) (*Baseline, error) {
+	var bj baselineJSON
+	if err := json.Unmarshal(data, &bj); err != nil {
+		return nil, err
+	}
+
+	b := Baseline{Timestamp: bj.Timestamp}
+	if err := decodeBaselineHex(bj.Baseline, &b); err != nil {
+		return nil, err
+	}
+	if len(bj.Serial) > 0 {
+		serial, err := strconv.ParseUint(bj.Serial, 16, 64)
+		if err != nil {
+			return nil, err
+		}
+		b.Serial = serial
+	}
+	return &b, nil
+}
+
+// decodeBaselineHex decodes the 12 hex digits of baseline data into b.Data
+func decodeBaselineHex(s string, b *Baseline) error {
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	if len(data) != 6 {
+		return fmt.Errorf("baseline is the wrong size: %d", len(data))
+	}
+	copy(b.Data[:], data)
+	return nil
+}
+
+// marshal returns the baseline in the FileStore's format
+func (f *FileStore) marshal(baseline Baseline) ([]byte, error) {
+	switch f.Format {
+	case FormatBinary:
+		data := make([]byte, 14)
+		copy(data[0:6], baseline.Data[:])
+		binary.BigEndian.PutUint64(data[6:14], uint64(baseline.Timestamp.Unix()))
+		return data, nil
+	case FormatHex:
+		return []byte(fmt.Sprintf("%x %s %012X\n", baseline.Data, baseline.Timestamp.UTC().Format(time.RFC3339), baseline.Serial)), nil
+	case FormatJSON:
+		bj := baselineJSON{
+			Baseline:  hex.EncodeToString(baseline.Data[:]),
+			Timestamp: baseline.Timestamp.UTC().Truncate(time.Second),
+		}
+		if baseline.Serial != 0 {
+			bj.Serial = fmt.Sprintf("%012X", baseline.Serial)
+		}
+		data, err := json.Marshal(bj)
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	}
+	return nil, fmt.Errorf("unknown baseline format: %d", f.Format)
+}
+
+// Save writes the baseline data to the file
+//
+// The data is written to a temporary file in the same directory which is then renamed
+// over the original, so that a crash or power loss while saving cannot leave a partial
+// baseline behind.
+func (f *FileStore) Save(baseline Baseline) error {
+	data, err := f.marshal(baseline)
+	if err != nil {
+		return err
+	}
+
+	return atomicfile.WriteFile(f.Path, data, 0644)
+}
+
+// MemoryStore stores the baseline data in memory
+// It can be used to carry the baseline across a Reset, or as a starting point for
+// other storage methods.
+type MemoryStore struct {
+	mu       sync.Mutex
+	baseline *Baseline
+}
+
+// NewMemoryStore returns a BaselineStore that keeps the baseline in memory
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// Load returns a copy of the baseline
+func (m *MemoryStore) Load() (*Baseline, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.baseline == nil {
+		return nil, nil
+	}
+	b := *m.baseline
+	return &b, nil
+}
+
+// Save stores a copy of the baseline
+func (m *MemoryStore) Save(baseline Baseline) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.baseline = &baseline
+	return nil
+}
+
+// NoopStore never returns a baseline and discards the saved data
+type NoopStore struct{}
+
+// Load always returns no baseline
+func (NoopStore) Load() (*Baseline, error) {
+	return nil, nil
+}
+
+// Save discards the baseline
+func (NoopStore) Save(Baseline) error {
+	return nil
+}
@@ -0,0 +1,525 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sgp30
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"periph.io/x/periph/conn/i2c/i2ctest"
+)
+
+// goodBaseline returns GoodBaselineData as a Baseline.Data array
+func goodBaseline() [6]byte {
+	var data [6]byte
+	copy(data[:], GoodBaselineData)
+	return data
+}
+
+func TestFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sgp30.")
+	if err != nil {
+		t.Fatalf("TempDir Error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	fs := NewFileStore(filepath.Join(dir, "baseline"))
+	baseline, err := fs.Load()
+	if err != nil {
+		t.Fatalf("Load missing file Error: %s", err)
+	}
+	if baseline != nil {
+		t.Fatalf("Load missing file returned data: %v", baseline)
+	}
+
+	now := time.Unix(time.Now().Unix(), 0)
+	if err := fs.Save(Baseline{Timestamp: now, Data: goodBaseline()}); err != nil {
+		t.Fatalf("Save Error: %s", err)
+	}
+	baseline, err = fs.Load()
+	if err != nil {
+		t.Fatalf("Load Error: %s", err)
+	}
+	if !bytes.Equal(baseline.Data[:], GoodBaselineData) {
+		t.Fatalf("Load returned wrong data: %v", baseline)
+	}
+	if !baseline.Timestamp.Equal(now) {
+		t.Fatalf("Load returned wrong timestamp: %s", baseline.Timestamp)
+	}
+
+	// Only the baseline file should be left behind
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir Error: %s", err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("Save left temporary files: %d files", len(files))
+	}
+}
+
+func TestFileStoreFormats(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sgp30.")
+	if err != nil {
+		t.Fatalf("TempDir Error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	saved := Baseline{
+		Data:      goodBaseline(),
+		Timestamp: time.Date(2020, 12, 1, 10, 30, 0, 0, time.UTC),
+		Serial:    0x0157ACA2,
+	}
+	tests := []struct {
+		format   BaselineFormat
+		expected string
+	}{
+		{FormatHex, "88a1588dc461 2020-12-01T10:30:00Z 00000157ACA2\n"},
+		{FormatJSON, `{"baseline":"88a1588dc461","timestamp":"2020-12-01T10:30:00Z","serial":"00000157ACA2"}` + "\n"},
+	}
+	for _, tt := range tests {
+		fs := &FileStore{Path: filepath.Join(dir, "baseline"), Format: tt.format}
+		if err := fs.Save(saved); err != nil {
+			t.Fatalf("Save Error: %s", err)
+		}
+		data, err := ioutil.ReadFile(fs.Path)
+		if err != nil {
+			t.Fatalf("ReadFile Error: %s", err)
+		}
+		if string(data) != tt.expected {
+			t.Errorf("Format %d Error: %q", tt.format, data)
+		}
+
+		baseline, err := fs.Load()
+		if err != nil {
+			t.Fatalf("Load Error: %s", err)
+		}
+		if baseline.Data != saved.Data || !baseline.Timestamp.Equal(saved.Timestamp) || baseline.Serial != saved.Serial {
+			t.Errorf("Format %d Load Error: %v", tt.format, baseline)
+		}
+	}
+}
+
+func TestFileStoreHexOnly(t *testing.T) {
+	// A hex baseline without the timestamp uses the file's modification time
+	bf, err := ioutil.TempFile("", "sgp30.")
+	if err != nil {
+		t.Fatalf("TempFile Error: %s", err)
+	}
+	defer os.Remove(bf.Name())
+	if _, err = bf.WriteString("88a1588dc461\n"); err != nil {
+		t.Fatalf("TempFile Write Error: %s", err)
+	}
+	bf.Close()
+
+	baseline, err := NewFileStore(bf.Name()).Load()
+	if err != nil {
+		t.Fatalf("Load Error: %s", err)
+	}
+	if !bytes.Equal(baseline.Data[:], GoodBaselineData) {
+		t.Fatalf("Load returned wrong data: %v", baseline)
+	}
+	if baseline.Stale(time.Now()) {
+		t.Fatalf("Hex baseline is stale: %s", baseline.Timestamp)
+	}
+}
+
+func TestFileStoreSaveError(t *testing.T) {
+	fs := NewFileStore("/does/not/exist/baseline")
+	if err := fs.Save(Baseline{Timestamp: time.Now(), Data: goodBaseline()}); err == nil {
+		t.Fatal("Save to missing directory Error")
+	}
+}
+
+func TestFileStoreLegacy(t *testing.T) {
+	// Baseline files without a timestamp use the file's modification time
+	bf, err := ioutil.TempFile("", "sgp30.")
+	if err != nil {
+		t.Fatalf("TempFile Error: %s", err)
+	}
+	defer os.Remove(bf.Name())
+	if _, err = bf.Write(GoodBaselineData); err != nil {
+		t.Fatalf("TempFile Write Error: %s", err)
+	}
+	bf.Close()
+	mtime := time.Now().Add(-8 * 24 * time.Hour)
+	if err := os.Chtimes(bf.Name(), mtime, mtime); err != nil {
+		t.Fatalf("Chtimes Error: %s", err)
+	}
+
+	baseline, err := NewFileStore(bf.Name()).Load()
+	if err != nil {
+		t.Fatalf("Load Error: %s", err)
+	}
+	if !bytes.Equal(baseline.Data[:], GoodBaselineData) {
+		t.Fatalf("Load returned wrong data: %v", baseline)
+	}
+	if !baseline.Stale(time.Now()) {
+		t.Fatalf("Legacy baseline is not stale: %s", baseline.Timestamp)
+	}
+}
+
+func TestFileStoreBadSize(t *testing.T) {
+	bf, err := ioutil.TempFile("", "sgp30.")
+	if err != nil {
+		t.Fatalf("TempFile Error: %s", err)
+	}
+	defer os.Remove(bf.Name())
+	if _, err = bf.Write(GoodBaselineData[0:3]); err != nil {
+		t.Fatalf("TempFile Write Error: %s", err)
+	}
+	bf.Close()
+
+	if _, err := NewFileStore(bf.Name()).Load(); err == nil {
+		t.Fatal("Load short baseline file Error")
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	ms := NewMemoryStore()
+	if baseline, err := ms.Load(); err != nil || baseline != nil {
+		t.Fatalf("Load empty store Error: %v %s", baseline, err)
+	}
+	if err := ms.Save(Baseline{Timestamp: time.Now(), Data: goodBaseline()}); err != nil {
+		t.Fatalf("Save Error: %s", err)
+	}
+	baseline, err := ms.Load()
+	if err != nil {
+		t.Fatalf("Load Error: %s", err)
+	}
+	if !bytes.Equal(baseline.Data[:], GoodBaselineData) {
+		t.Fatalf("Load returned wrong data: %v", baseline)
+	}
+}
+
+func TestNoopStore(t *testing.T) {
+	var ns NoopStore
+	if err := ns.Save(Baseline{Timestamp: time.Now(), Data: goodBaseline()}); err != nil {
+		t.Fatalf("Save Error: %s", err)
+	}
+	if baseline, err := ns.Load(); err != nil || baseline != nil {
+		t.Fatalf("Load Error: %v %s", baseline, err)
+	}
+}
+
+func TestStoreBaseline(t *testing.T) {
+	ms := NewMemoryStore()
+	if err := ms.Save(Baseline{Timestamp: time.Now(), Data: goodBaseline()}); err != nil {
+		t.Fatalf("Save Error: %s", err)
+	}
+
+	// The CO2 and TVOC data is swapped when writing it back to the SGP30
+	BaselineWrite := append(append([]byte{0x20, 0x1e}, GoodBaselineData[3:6]...), GoodBaselineData[0:3]...)
+	NewBaselineData := []byte{0x88, 0xa2, 0x0b, 0x8d, 0xc4, 0x61}
+
+	// Restore the baseline and then save a new one after reading the air quality
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Good serial number
+			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
+			{Addr: 0x58, W: []byte{0x20, 0x03}, R: []byte{}},
+			{Addr: 0x58, W: BaselineWrite, R: []byte{}},
+			{Addr: 0x58, W: []byte{0x20, 0x08}, R: []byte{}},
+			{Addr: 0x58, W: []byte{}, R: GoodAirQualityData},
+			{Addr: 0x58, W: []byte{0x20, 0x15}, R: NewBaselineData},
+		},
+	}
+	d, err := New(&bus, WithBaselineStore(ms), WithSaveInterval(0), WithAutoStart(true))
+	if err != nil {
+		t.Fatalf("Good Baseline Error: %s", err)
+	}
+	if _, err := d.ReadAirQuality(); err != nil {
+		t.Fatalf("Read Good AirQuality Error: %s", err)
+	}
+	baseline, err := ms.Load()
+	if err != nil {
+		t.Fatalf("Load Error: %s", err)
+	}
+	if !bytes.Equal(baseline.Data[:], NewBaselineData) {
+		t.Fatalf("Baseline was not saved: %v", baseline)
+	}
+	if stats := d.Stats(); stats.BaselineSaves != 1 || stats.BaselineSaveErrors != 0 {
+		t.Fatalf("Stats Error: %+v", stats)
+	}
+}
+
+func TestSaveOnChange(t *testing.T) {
+	ms := NewMemoryStore()
+	if err := ms.Save(Baseline{Timestamp: time.Now(), Data: goodBaseline()}); err != nil {
+		t.Fatalf("Save Error: %s", err)
+	}
+
+	BaselineWrite := append(append([]byte{0x20, 0x1e}, GoodBaselineData[3:6]...), GoodBaselineData[0:3]...)
+	SmallChangeData := []byte{0x88, 0xa2, 0x0b, 0x8d, 0xc4, 0x61}
+	BigChangeData := []byte{0x89, 0xa1, 0xac, 0x8d, 0xc4, 0x61}
+
+	// Restore the baseline, skip saving a small change, and then save a big one
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Good serial number
+			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
+			{Addr: 0x58, W: []byte{0x20, 0x03}, R: []byte{}},
+			{Addr: 0x58, W: BaselineWrite, R: []byte{}},
+			{Addr: 0x58, W: []byte{0x20, 0x08}, R: []byte{}},
+			{Addr: 0x58, W: []byte{}, R: GoodAirQualityData},
+			{Addr: 0x58, W: []byte{0x20, 0x15}, R: SmallChangeData},
+			{Addr: 0x58, W: []byte{0x20, 0x08}, R: []byte{}},
+			{Addr: 0x58, W: []byte{}, R: GoodAirQualityData},
+			{Addr: 0x58, W: []byte{0x20, 0x15}, R: BigChangeData},
+		},
+	}
+	d, err := New(&bus, WithBaselineStore(ms), WithSaveInterval(0), WithSaveOnChange(0x10, time.Hour), WithAutoStart(true))
+	if err != nil {
+		t.Fatalf("Good Baseline Error: %s", err)
+	}
+	if _, err := d.ReadAirQuality(); err != nil {
+		t.Fatalf("Read Good AirQuality Error: %s", err)
+	}
+	if stats := d.Stats(); stats.BaselineSaves != 0 {
+		t.Fatalf("Small baseline change was saved: %+v", stats)
+	}
+	if _, err := d.ReadAirQuality(); err != nil {
+		t.Fatalf("Read Good AirQuality Error: %s", err)
+	}
+	baseline, err := ms.Load()
+	if err != nil {
+		t.Fatalf("Load Error: %s", err)
+	}
+	if !bytes.Equal(baseline.Data[:], BigChangeData) {
+		t.Fatalf("Baseline was not saved: %v", baseline)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatalf("Playback Close Error: %s", err)
+	}
+}
+
+func TestOtherSensorBaseline(t *testing.T) {
+	ms := NewMemoryStore()
+	if err := ms.Save(Baseline{Timestamp: time.Now(), Data: goodBaseline(), Serial: 0x1234}); err != nil {
+		t.Fatalf("Save Error: %s", err)
+	}
+
+	// A baseline from another sensor is not restored, and measurements are not started
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Good serial number
+			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
+		},
+	}
+	if _, err := New(&bus, WithBaselineStore(ms)); err != nil {
+		t.Fatalf("Other sensor Baseline Error: %s", err)
+	}
+}
+
+func TestStaleBaseline(t *testing.T) {
+	ms := NewMemoryStore()
+	err := ms.Save(Baseline{Timestamp: time.Now().Add(-MaxBaselineAge - time.Hour), Data: goodBaseline()})
+	if err != nil {
+		t.Fatalf("Save Error: %s", err)
+	}
+
+	// A stale baseline is not restored, and measurements are not started
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Good serial number
+			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
+		},
+	}
+	if _, err := New(&bus, WithBaselineStore(ms), WithSaveInterval(time.Second)); err != nil {
+		t.Fatalf("Stale Baseline Error: %s", err)
+	}
+}
+
+// errStore fails to save the baseline
+type errStore struct {
+	NoopStore
+}
+
+func (errStore) Save(Baseline) error {
+	return errors.New("disk full")
+}
+
+func TestBaselineErrorHandler(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Good serial number
+			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
+			{Addr: 0x58, W: []byte{0x20, 0x08}, R: []byte{}},
+			{Addr: 0x58, W: []byte{}, R: GoodAirQualityData},
+			{Addr: 0x58, W: []byte{0x20, 0x15}, R: GoodBaselineData},
+		},
+	}
+	d, err := New(&bus, WithBaselineStore(errStore{}), WithSaveInterval(0))
+	if err != nil {
+		t.Fatalf("Good serial number Error: %s", err)
+	}
+
+	// Without a handler the error is returned
+	if _, err := d.ReadAirQuality(); err == nil {
+		t.Fatal("Baseline save Error not returned")
+	}
+
+	// With a handler the reading is returned and the handler gets the error
+	bus = i2ctest.Playback{Ops: bus.Ops}
+	var saveErr error
+	d, err = New(&bus,
+		WithBaselineStore(errStore{}),
+		WithSaveInterval(0),
+		WithBaselineErrorHandler(func(err error) { saveErr = err }))
+	if err != nil {
+		t.Fatalf("Good serial number Error: %s", err)
+	}
+	r, err := d.ReadAirQuality()
+	if err != nil {
+		t.Fatalf("Read Good AirQuality Error: %s", err)
+	}
+	if r.ECO2 != 414 || r.TVOC != 13 {
+		t.Errorf("AirQuality reading is wrong: %v", r)
+	}
+	if saveErr == nil {
+		t.Fatal("Baseline save Error not passed to handler")
+	}
+}
+
+func TestAsyncBaselineSave(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Good serial number
+			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
+			{Addr: 0x58, W: []byte{0x20, 0x08}, R: []byte{}},
+			{Addr: 0x58, W: []byte{}, R: GoodAirQualityData},
+			{Addr: 0x58, W: []byte{0x20, 0x15}, R: GoodBaselineData},
+		},
+	}
+	ms := NewMemoryStore()
+	d, err := New(&bus, WithBaselineStore(ms), WithSaveInterval(0), WithAsyncBaselineSave())
+	if err != nil {
+		t.Fatalf("Good serial number Error: %s", err)
+	}
+	if _, err := d.ReadAirQuality(); err != nil {
+		t.Fatalf("Read Good AirQuality Error: %s", err)
+	}
+	d.saves.Wait()
+	baseline, err := ms.Load()
+	if err != nil {
+		t.Fatalf("Load Error: %s", err)
+	}
+	if baseline == nil || !bytes.Equal(baseline.Data[:], GoodBaselineData) {
+		t.Fatalf("Baseline was not saved: %v", baseline)
+	}
+
+	// Write errors are passed to the handler instead of being returned
+	bus = i2ctest.Playback{Ops: bus.Ops}
+	saveErr := make(chan error, 1)
+	d, err = New(&bus,
+		WithBaselineStore(errStore{}),
+		WithSaveInterval(0),
+		WithAsyncBaselineSave(),
+		WithBaselineErrorHandler(func(err error) { saveErr <- err }))
+	if err != nil {
+		t.Fatalf("Good serial number Error: %s", err)
+	}
+	if _, err := d.ReadAirQuality(); err != nil {
+		t.Fatalf("Read Good AirQuality Error: %s", err)
+	}
+	if err := <-saveErr; err == nil {
+		t.Fatal("Baseline save Error not passed to the handler")
+	}
+}
+
+func TestRestoreOnStart(t *testing.T) {
+	ms := NewMemoryStore()
+	if err := ms.Save(Baseline{Timestamp: time.Now(), Data: goodBaseline()}); err != nil {
+		t.Fatalf("Save Error: %s", err)
+	}
+
+	// The CO2 and TVOC data is swapped when writing it back to the SGP30
+	BaselineWrite := append(append([]byte{0x20, 0x1e}, GoodBaselineData[3:6]...), GoodBaselineData[0:3]...)
+
+	// New does not start the measurements, the baseline is restored by StartMeasurements
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Good serial number
+			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
+			{Addr: 0x58, W: []byte{0x20, 0x03}, R: []byte{}},
+			{Addr: 0x58, W: BaselineWrite, R: []byte{}},
+		},
+	}
+	d, err := New(&bus, WithBaselineStore(ms))
+	if err != nil {
+		t.Fatalf("Good Baseline Error: %s", err)
+	}
+	if d.measuring {
+		t.Fatal("New started the measurements")
+	}
+	if err := d.StartMeasurements(); err != nil {
+		t.Fatalf("StartMeasurements Error: %s", err)
+	}
+	if _, ok := d.Baseline(); !ok {
+		t.Fatal("Baseline was not restored")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatalf("Playback Close Error: %s", err)
+	}
+}
+
+func TestSaveInterval(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Good serial number
+			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
+			{Addr: 0x58, W: []byte{0x20, 0x08}, R: []byte{}},
+			{Addr: 0x58, W: []byte{}, R: GoodAirQualityData},
+			{Addr: 0x58, W: []byte{0x20, 0x08}, R: []byte{}},
+			{Addr: 0x58, W: []byte{}, R: GoodAirQualityData},
+			{Addr: 0x58, W: []byte{0x20, 0x15}, R: GoodBaselineData},
+		},
+	}
+	ms := NewMemoryStore()
+	clock := &fakeClock{t: time.Date(2020, 12, 1, 10, 30, 0, 0, time.UTC)}
+	d, err := New(&bus, WithBaselineStore(ms), WithSaveInterval(time.Hour), WithClock(clock))
+	if err != nil {
+		t.Fatalf("Good serial number Error: %s", err)
+	}
+
+	// The baseline is not saved before the interval has passed
+	clock.Advance(time.Hour - time.Second)
+	if _, err := d.ReadAirQuality(); err != nil {
+		t.Fatalf("Read Good AirQuality Error: %s", err)
+	}
+	if baseline, err := ms.Load(); err != nil || baseline != nil {
+		t.Fatalf("Baseline saved too early: %v %v", baseline, err)
+	}
+
+	clock.Advance(time.Second)
+	if _, err := d.ReadAirQuality(); err != nil {
+		t.Fatalf("Read Good AirQuality Error: %s", err)
+	}
+	baseline, err := ms.Load()
+	if err != nil || baseline == nil {
+		t.Fatalf("Baseline was not saved: %v %v", baseline, err)
+	}
+	if !baseline.Timestamp.Equal(clock.Now()) {
+		t.Errorf("Baseline timestamp is wrong: %s", baseline.Timestamp)
+	}
+	if age := d.BaselineAge(); age != 0 {
+		t.Errorf("BaselineAge is wrong: %s", age)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatalf("Playback Close Error: %s", err)
+	}
+}
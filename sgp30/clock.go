@@ -0,0 +1,36 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sgp30
+
+import (
+	"time"
+)
+
+// Clock returns the current time
+//
+// It is used for the baseline save interval, the baseline age, warm-up tracking, and
+// the reading timestamps, and can be replaced with WithClock to test them without
+// waiting. The delays needed by the sensor's commands always use the real time.
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock is the default Clock, using time.Now
+type systemClock struct{}
+
+// Now returns the current time
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// now returns the current time from the Dev's clock
+func (d *Dev) now() time.Time {
+	return d.clock.Now()
+}
+
+// since returns the time elapsed since t on the Dev's clock
+func (d *Dev) since(t time.Time) time.Duration {
+	return d.clock.Now().Sub(t)
+}
@@ -0,0 +1,67 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sgp30
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bcl/air-sensors/internal/sensirion"
+)
+
+// SGP30 commands from the datasheet, for use with Command
+const (
+	CmdInitAirQuality           uint16 = 0x2003 // Start the air quality measurements
+	CmdMeasureAirQuality        uint16 = 0x2008 // Read CO2eq and TVOC, 2 words, 12ms
+	CmdGetBaseline              uint16 = 0x2015 // Read the CO2eq and TVOC baseline, 2 words, 10ms
+	CmdSetBaseline              uint16 = 0x201e // Set the TVOC and CO2eq baseline, 2 words
+	CmdSetHumidity              uint16 = 0x2061 // Set the absolute humidity, 1 word in 8.8 g/m³
+	CmdMeasureTest              uint16 = 0x2032 // Run the on-chip self test, 1 word, 220ms
+	CmdGetFeatureSet            uint16 = 0x202f // Read the product type and version, 1 word, 10ms
+	CmdMeasureRawSignals        uint16 = 0x2050 // Read H2 and Ethanol signals, 2 words, 25ms
+	CmdGetTVOCInceptiveBaseline uint16 = 0x20b3 // Read the TVOC inceptive baseline, 1 word, 10ms
+	CmdSetTVOCBaseline          uint16 = 0x2077 // Set the TVOC baseline, 1 word
+	CmdGetSerialID              uint16 = 0x3682 // Read the serial number, 3 words, 1ms
+)
+
+// Command sends a command, with optional argument words, to the sensor and returns
+// the response words. The CRC8 of the arguments is added, and the CRC8 of the response
+// is checked and removed.
+//
+// If wait is 0 the response is read in the same transaction as the command, otherwise
+// the sensor is given wait to execute the command before reading respWords words.
+//
+// This can be used to run commands that are not wrapped by the rest of the API, the
+// commands, their timing, and their responses are described in the datasheet. Running
+// commands out of order can leave the sensor in a state that the Dev does not expect.
+func (d *Dev) Command(cmd uint16, wait time.Duration, respWords int, args ...uint16) ([]uint16, error) {
+	w := sensirion.Encode(cmd, args...)
+	data := make([]byte, respWords*3)
+
+	if wait == 0 {
+		if err := d.i2c.Tx(w, data); err != nil {
+			return nil, d.busError(fmt.Sprintf("sgp30: Error while sending command 0x%04X", cmd), err)
+		}
+	} else {
+		if err := d.i2c.Tx(w, nil); err != nil {
+			return nil, d.busError(fmt.Sprintf("sgp30: Error while sending command 0x%04X", cmd), err)
+		}
+		time.Sleep(wait)
+		if respWords > 0 {
+			if err := d.i2c.Tx(nil, data); err != nil {
+				return nil, d.notReadyError(fmt.Sprintf("sgp30: Error while reading command 0x%04X", cmd), err)
+			}
+		}
+	}
+
+	resp := make([]uint16, respWords)
+	for i := range resp {
+		if !sensirion.CheckCRC8(data[i*3 : i*3+3]) {
+			return nil, d.crcError(fmt.Sprintf("command 0x%04X word %d", cmd, i+1), data[i*3:i*3+3])
+		}
+		resp[i] = sensirion.Word(data, i*3)
+	}
+	return resp, nil
+}
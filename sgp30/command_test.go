@@ -0,0 +1,60 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sgp30
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"periph.io/x/periph/conn/i2c/i2ctest"
+)
+
+func TestCommand(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Good serial number
+			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
+			// Read the response in the same transaction
+			{Addr: 0x58, W: []byte{0x20, 0x15}, R: GoodBaselineData},
+			// Read the response after waiting
+			{Addr: 0x58, W: []byte{0x20, 0x08}, R: []byte{}},
+			{Addr: 0x58, W: []byte{}, R: GoodAirQualityData},
+			// Send an argument
+			{Addr: 0x58, W: append([]byte{0x20, 0x77}, GoodInceptiveData...), R: []byte{}},
+			// Bad CRC
+			{Addr: 0x58, W: []byte{0x20, 0x15}, R: BadBaselineData},
+		},
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("Good serial number Error: %s", err)
+	}
+
+	resp, err := d.Command(CmdGetBaseline, 0, 2)
+	if err != nil {
+		t.Fatalf("Command Error: %s", err)
+	}
+	if len(resp) != 2 || resp[0] != 0x88a1 || resp[1] != 0x8dc4 {
+		t.Errorf("Command response is wrong: %v", resp)
+	}
+
+	resp, err = d.Command(CmdMeasureAirQuality, 12*time.Millisecond, 2)
+	if err != nil {
+		t.Fatalf("Command Error: %s", err)
+	}
+	if len(resp) != 2 || resp[0] != 414 || resp[1] != 13 {
+		t.Errorf("Command response is wrong: %v", resp)
+	}
+
+	if _, err = d.Command(CmdSetTVOCBaseline, 10*time.Millisecond, 0, 0x8dc4); err != nil {
+		t.Fatalf("Command Error: %s", err)
+	}
+
+	if _, err = d.Command(CmdGetBaseline, 0, 2); !errors.Is(err, ErrCRC) {
+		t.Fatalf("Command CRC Error: %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sgp30
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrCRC is returned when the CRC8 of data read from the sensor, or passed to
+	// SetBaseline, does not match. This is usually transient.
+	ErrCRC = errors.New("sgp30: CRC8 failed")
+
+	// ErrBusIO is returned when an I²C transaction with the sensor fails
+	ErrBusIO = errors.New("sgp30: I²C bus error")
+
+	// ErrNotReady is returned when the sensor does not return the results of a
+	// measurement, the sensor NAKs the read until the measurement is finished.
+	// Errors matching ErrNotReady also match ErrBusIO.
+	ErrNotReady = errors.New("sgp30: measurement not ready")
+
+	// ErrWarmingUp is returned by ReadAirQuality during warm-up when the
+	// WarmupSuppress policy is used
+	ErrWarmingUp = errors.New("sgp30: warming up")
+
+	// ErrUnsupported is returned when a command is not supported by the sensor's
+	// feature set version
+	ErrUnsupported = errors.New("sgp30: unsupported by feature set")
+)
+
+// Error describes a failure communicating with the sensor
+//
+// Use errors.Is with ErrCRC, ErrBusIO, ErrNotReady, ErrWarmingUp, or ErrUnsupported to check what kind of failure
+// it is, the underlying I²C error, if any, is available with errors.Unwrap.
+type Error struct {
+	Kind error  // ErrCRC, ErrBusIO, ErrNotReady, ErrWarmingUp, or ErrUnsupported
+	Msg  string // Description of the failure
+	Err  error  // Underlying error, or nil
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Msg + ": " + e.Err.Error()
+	}
+	return e.Msg
+}
+
+// Unwrap returns the underlying error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is returns true if target is the Kind of error
+func (e *Error) Is(target error) bool {
+	return target == e.Kind || (e.Kind == ErrNotReady && target == ErrBusIO)
+}
+
+// busError returns an ErrBusIO Error for a failed I²C transaction
+func busError(msg string, err error) error {
+	return &Error{Kind: ErrBusIO, Msg: msg, Err: err}
+}
+
+// notReadyError returns an ErrNotReady Error for a failed read of the measurement results
+func notReadyError(msg string, err error) error {
+	return &Error{Kind: ErrNotReady, Msg: msg, Err: err}
+}
+
+// crcError returns an ErrCRC Error for the data that failed the CRC8 check
+func crcError(what string, data []byte) error {
+	return &Error{Kind: ErrCRC, Msg: fmt.Sprintf("sgp30: %s CRC8 failed on: %v", what, data)}
+}
@@ -0,0 +1,111 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sgp30
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// EventKind identifies what happened to the sensor
+type EventKind int
+
+const (
+	// EventReinit is sent when the readings were stuck at 400ppm CO2 and 0ppb TVOC
+	// after the warm-up, and the measurements were restarted
+	EventReinit EventKind = iota
+
+	// EventCadence is sent when ReadAirQuality is called too early or too late, the
+	// sensor's dynamic baseline compensation needs it to be called every
+	// MeasurementInterval
+	EventCadence
+
+	// EventRecovery is sent when the watchdog tried to recover the sensor after too
+	// many consecutive I²C errors
+	EventRecovery
+)
+
+// String returns a description of the event kind
+func (k EventKind) String() string {
+	switch k {
+	case EventReinit:
+		return "reinit"
+	case EventCadence:
+		return "cadence"
+	case EventRecovery:
+		return "recovery"
+	}
+	return "unknown"
+}
+
+// Event describes something the driver did on its own, outside of the caller's requests
+type Event struct {
+	Kind EventKind // What happened
+	Time time.Time // When it happened
+	Err  error     // Error from the recovery, or nil if it succeeded
+
+	Interval time.Duration // Time between the readings, for EventCadence
+}
+
+// event passes the event to the event handler, if there is one
+func (d *Dev) event(e Event) {
+	if d.onEvent != nil {
+		e.Time = d.now()
+		d.onEvent(e)
+	}
+}
+
+// checkStuck restarts the measurements when the readings have been stuck at the
+// warm-up values of 400ppm CO2 and 0ppb TVOC for longer than the stuck timeout.
+// This happens when the sensor misses the init command, or after a brown-out.
+func (d *Dev) checkStuck(r Reading) {
+	if d.stuckTimeout == 0 || !d.IsWarmedUp() {
+		return
+	}
+	if r.ECO2 != 400 || r.TVOC != 0 {
+		d.stuckSince = time.Time{}
+		return
+	}
+	if d.stuckSince.IsZero() {
+		d.stuckSince = r.Timestamp
+		return
+	}
+	if r.Timestamp.Sub(d.stuckSince) < d.stuckTimeout {
+		return
+	}
+
+	d.logf("sgp30: readings stuck at 400ppm/0ppb since %s, restarting measurements", d.stuckSince.Format(time.RFC3339))
+	d.stuckSince = time.Time{}
+	err := d.initMeasurements()
+	if err != nil {
+		d.logf("sgp30: restarting measurements failed: %s", err)
+	}
+	d.event(Event{Kind: EventReinit, Err: err})
+}
+
+// checkCadence warns when the time since the previous reading is further from the
+// MeasurementInterval than the cadence tolerance
+func (d *Dev) checkCadence(r Reading) {
+	if d.cadenceTolerance == 0 {
+		return
+	}
+	last := d.lastRead
+	d.lastRead = r.Timestamp
+	if last.IsZero() {
+		return
+	}
+
+	interval := r.Timestamp.Sub(last)
+	diff := interval - d.MeasurementInterval()
+	if diff < 0 {
+		diff = -diff
+	}
+	if diff <= d.cadenceTolerance {
+		return
+	}
+	atomic.AddUint64(&d.stats.cadenceWarnings, 1)
+	d.logf("sgp30: %s between readings, expected %s", interval, d.MeasurementInterval())
+	d.event(Event{Kind: EventCadence, Interval: interval})
+}
@@ -5,6 +5,8 @@
 package sgp30_test
 
 import (
+	"context"
+	"expvar"
 	"fmt"
 	"log"
 	"time"
@@ -28,7 +30,7 @@ func Example() {
 	}
 	defer bus.Close()
 
-	d, err := sgp30.New(bus, ".sgp30_baseline", 30*time.Second)
+	d, err := sgp30.New(bus, sgp30.WithBaselineFile(".sgp30_baseline"), sgp30.WithSaveInterval(30*time.Second))
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -40,17 +42,39 @@ func Example() {
 	}
 	fmt.Printf("Serial Number: %X\n", sn)
 
-	// Start measuring air quality
-	if err = d.StartMeasurements(); err != nil {
+	// Start measuring air quality, reading it every second
+	readings, err := d.Start(context.Background())
+	if err != nil {
 		log.Fatal(err)
 	}
 
-	for {
-		time.Sleep(1 * time.Second)
-		if co2, tvoc, err := d.ReadAirQuality(); err != nil {
-			log.Fatal(err)
-		} else {
-			fmt.Printf("CO2 : %d ppm\nTVOC: %d ppb\n", co2, tvoc)
+	for aq := range readings {
+		if aq.Err != nil {
+			log.Fatal(aq.Err)
 		}
+		fmt.Printf("CO2 : %d ppm\nTVOC: %d ppb\n", aq.ECO2, aq.TVOC)
+	}
+}
+
+func ExampleDev_Stats() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Open a handle to the first available I²C bus:
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer bus.Close()
+
+	d, err := sgp30.New(bus, sgp30.WithRetries(2, 10*time.Millisecond))
+	if err != nil {
+		log.Fatal(err)
 	}
+	defer d.Halt() //nolint
+
+	// Publish the driver's counters on /debug/vars
+	expvar.Publish("sgp30", expvar.Func(func() interface{} { return d.Stats() }))
 }
@@ -0,0 +1,234 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sgp30
+
+import (
+	"time"
+
+	"periph.io/x/periph/conn/i2c"
+)
+
+const (
+	// DefaultAddr is the I²C address of the SGP30
+	DefaultAddr uint16 = 0x58
+
+	// DefaultSaveInterval is how often the baseline is saved when a baseline store
+	// is used. The datasheet recommends reading the baseline once an hour.
+	DefaultSaveInterval = time.Hour
+
+	// WarmupTime is how long the sensor returns 400ppm CO2 and 0ppb TVOC after the
+	// measurements are started
+	WarmupTime = 15 * time.Second
+)
+
+// WarmupPolicy selects how ReadAirQuality handles readings made during warm-up
+type WarmupPolicy int
+
+const (
+	// WarmupIgnore returns the warm-up readings unchanged
+	WarmupIgnore WarmupPolicy = iota
+	// WarmupFlag sets Reading.Warmup on the warm-up readings
+	WarmupFlag
+	// WarmupSuppress returns ErrWarmingUp instead of the warm-up readings
+	WarmupSuppress
+)
+
+// Logger is used to log events that are not returned as errors, eg. a stale baseline
+// being ignored. It is satisfied by *log.Logger
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// Option configures the Dev returned by New
+type Option func(*Dev)
+
+// WithBaselineFile loads the baseline calibration data from path at startup, and saves
+// the new data to it every save interval when ReadAirQuality is called.
+func WithBaselineFile(path string) Option {
+	return func(d *Dev) {
+		d.store = NewFileStore(path)
+	}
+}
+
+// WithBaselineStore loads the baseline calibration data from store at startup, and saves
+// the new data to it every save interval when ReadAirQuality is called.
+func WithBaselineStore(store BaselineStore) Option {
+	return func(d *Dev) {
+		d.store = store
+	}
+}
+
+// WithSaveInterval sets how often the baseline is saved to the baseline store
+//
+// eg. pass 30 * time.Second to save the baseline data every 30 seconds
+func WithSaveInterval(interval time.Duration) Option {
+	return func(d *Dev) {
+		d.baselineInterval = interval
+	}
+}
+
+// WithSaveOnChange only writes the baseline to the baseline store when either of the
+// baseline words has changed by more than delta since the last write, or when
+// maxInterval has passed since then. The baseline is still read from the sensor every
+// save interval. This reduces the number of writes to flash storage, eg. a Raspberry Pi's
+// SD card.
+func WithSaveOnChange(delta uint16, maxInterval time.Duration) Option {
+	return func(d *Dev) {
+		d.saveDelta = delta
+		d.maxSaveInterval = maxInterval
+	}
+}
+
+// WithAsyncBaselineSave writes the baseline to the baseline store from a goroutine
+// instead of from ReadAirQuality, so that a slow write, eg. to a Raspberry Pi's SD
+// card, does not disturb the 1 second measurement interval. The baseline is still read
+// from the sensor by ReadAirQuality.
+//
+// Write errors are passed to the handler set with WithBaselineErrorHandler, or logged
+// if there is no handler. Halt waits for the background writes to finish.
+func WithAsyncBaselineSave() Option {
+	return func(d *Dev) {
+		d.asyncSave = true
+	}
+}
+
+// WithBaselineErrorHandler sets a function to be called when reading or saving the
+// baseline fails while running ReadAirQuality.
+//
+// When it is set the errors are no longer returned by ReadAirQuality, so that a
+// failure to save the baseline does not also discard the air quality reading.
+func WithBaselineErrorHandler(f func(error)) Option {
+	return func(d *Dev) {
+		d.baselineErr = f
+	}
+}
+
+// WithAddress sets the I²C address of the sensor, the default is DefaultAddr
+// The SGP30's address is fixed, but some breakout boards sit behind an address
+// translator which moves it.
+//
+// NOTE: Reset and WithIdleOnHalt use the General Call address, which is not changed.
+func WithAddress(addr uint16) Option {
+	return func(d *Dev) {
+		d.addr = addr
+	}
+}
+
+// WithLogger sets the Logger used to report events that are not errors
+func WithLogger(l Logger) Option {
+	return func(d *Dev) {
+		d.log = l
+	}
+}
+
+// WithRetries sets the number of times ReadAirQuality retries a read that failed with
+// a CRC or I²C error, which are often transient.
+//
+// The first retry waits for backoff, and the delay is doubled for each following retry.
+// Keep the total delay short, the readings need to be made at 1 second intervals for
+// the sensor's dynamic baseline compensation algorithm.
+func WithRetries(n int, backoff time.Duration) Option {
+	return func(d *Dev) {
+		d.retries = n
+		d.backoff = backoff
+	}
+}
+
+// WithIdleOnHalt resets the sensor when Halt is called, stopping the measurements and
+// returning it to idle mode.
+//
+// NOTE: The soft reset uses the I²C General Call address, all devices on the bus that
+// support General Call will also be reset.
+func WithIdleOnHalt() Option {
+	return func(d *Dev) {
+		d.idleOnHalt = true
+	}
+}
+
+// WithWarmupPolicy sets how ReadAirQuality handles the fixed 400ppm CO2 and 0ppb TVOC
+// readings made in the first 15s after starting the measurements. The default is
+// WarmupIgnore.
+func WithWarmupPolicy(policy WarmupPolicy) Option {
+	return func(d *Dev) {
+		d.warmup = policy
+	}
+}
+
+// WithStuckDetection restarts the measurements, restoring the baseline from the baseline
+// store, if the readings stay at 400ppm CO2 and 0ppb TVOC for longer than timeout
+// after the warm-up. This usually means the sensor missed the init command, or was
+// reset by a brown-out. An EventReinit is sent to the event handler when it happens.
+func WithStuckDetection(timeout time.Duration) Option {
+	return func(d *Dev) {
+		d.stuckTimeout = timeout
+	}
+}
+
+// WithCadenceCheck warns when ReadAirQuality is called more than tolerance earlier or
+// later than the MeasurementInterval, 1s for the SGP30, after the previous reading.
+// The sensor's dynamic baseline compensation silently degrades when it is not read at
+// the right rate. The warnings are logged, counted in Stats, and an EventCadence is sent
+// to the event handler.
+//
+// eg. pass 200 * time.Millisecond to warn when readings are not 0.8s to 1.2s apart
+func WithCadenceCheck(tolerance time.Duration) Option {
+	return func(d *Dev) {
+		d.cadenceTolerance = tolerance
+	}
+}
+
+// WithEventHandler sets a function that is called when the driver does something on its
+// own, like restarting stuck measurements. It is called from the goroutine calling
+// ReadAirQuality, or the measurement loop, and should not block.
+func WithEventHandler(handler func(Event)) Option {
+	return func(d *Dev) {
+		d.onEvent = handler
+	}
+}
+
+// WithAutoStart starts the measurements in New, restoring the baseline from the baseline
+// store if there is one. The default is false, and the measurements are started by
+// Start or StartMeasurements.
+func WithAutoStart(start bool) Option {
+	return func(d *Dev) {
+		d.autoStart = start
+	}
+}
+
+// WithClock sets the Clock used for the baseline save interval, the baseline age,
+// warm-up tracking, and the reading timestamps. The default uses time.Now.
+func WithClock(clock Clock) Option {
+	return func(d *Dev) {
+		d.clock = clock
+	}
+}
+
+// WithWatchdog tries to recover the sensor after failures consecutive I²C errors from
+// ReadAirQuality. The bus is re-opened if WithBusOpener was used, the sensor is reset,
+// and the measurements are restarted with the baseline restored from the baseline
+// store. An EventRecovery is sent to the event handler for each attempt.
+//
+// NOTE: The soft reset uses the I²C General Call address, all devices on the bus that
+// support General Call will also be reset.
+func WithWatchdog(failures int) Option {
+	return func(d *Dev) {
+		d.watchdog = failures
+	}
+}
+
+// BusOpener opens a new I²C bus
+type BusOpener func() (i2c.BusCloser, error)
+
+// WithBusOpener sets a function used by the watchdog to open a new bus, eg.
+//
+//	sgp30.WithBusOpener(func() (i2c.BusCloser, error) { return i2creg.Open("") })
+//
+// The bus passed to New is not closed, buses opened by the watchdog are closed when
+// they are replaced, and by Halt.
+func WithBusOpener(open BusOpener) Option {
+	return func(d *Dev) {
+		d.openBus = open
+	}
+}
@@ -5,95 +5,331 @@
 package sgp30
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"io/ioutil"
+	"math"
+	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/sigurn/crc8"
 	"periph.io/x/periph/conn"
 	"periph.io/x/periph/conn/i2c"
-)
+	"periph.io/x/periph/conn/physic"
 
-var (
-	crc8sgp30 = crc8.MakeTable(crc8.Params{
-		Poly:   0x31,
-		Init:   0xFF,
-		RefIn:  false,
-		RefOut: false,
-		XorOut: 0x00,
-		Check:  0xA1,
-		Name:   "CRC-8/SGP30",
-	})
+	"github.com/bcl/air-sensors/internal/sensirion"
 )
 
-func checkCRC8(data []byte) bool {
-	return crc8.Checksum(data[:], crc8sgp30) == 0x00
-}
-
 // New returns a SGP30 device struct for communicating with the device
 //
-// If baselineFile is passed the baseline calibration data will be read from the file
-// at startup, and new data will be saved at baselineInterval interval when ReadAirQuality
+// If a baseline store is passed with WithBaselineFile or WithBaselineStore the baseline
+// calibration data will be read from it at startup and restored when the measurements
+// are started, and new data will be saved to it every save interval when ReadAirQuality
 // is called.
 //
-// eg. pass 30 * time.Second to save the baseline data every 30 seconds
-func New(i i2c.Bus, baselineFile string, baselineInterval time.Duration) (*Dev, error) {
-	d := &Dev{i2c: &i2c.Dev{Bus: i, Addr: 0x58}}
-	if _, err := d.GetSerialNumber(); err != nil {
+// The measurements are not started unless WithAutoStart(true) is passed, call Start or
+// StartMeasurements to start them.
+func New(i i2c.Bus, opts ...Option) (*Dev, error) {
+	d := &Dev{bus: i, addr: DefaultAddr, baselineInterval: DefaultSaveInterval, stats: &counters{}, clock: systemClock{}, powerMode: LowPower}
+	for _, opt := range opts {
+		opt(d)
+	}
+	d.created = d.now()
+	d.i2c = &i2c.Dev{Bus: i, Addr: d.addr}
+
+	var err error
+	if d.serial, err = d.GetSerialNumber(); err != nil {
 		return nil, err
 	}
 
-	// Restore the baseline from the saved data if it exists
-	if len(baselineFile) > 0 {
-		d.baselineFile = baselineFile
-		d.baselineInterval = baselineInterval
-		d.lastSave = time.Now()
-		// Restore the baseline data if it exists, ignore missing file
-		if baseline, err := ioutil.ReadFile(baselineFile); err == nil {
-			err = d.SetBaseline(baseline)
-			if err != nil {
-				return nil, err
-			}
+	// The feature set is used to check which commands are supported
+	if d.productType, d.productVersion, err = d.GetFeatures(); err != nil {
+		return nil, err
+	}
+
+	// Load the baseline from the saved data if it exists
+	if d.store != nil {
+		d.lastSave = d.now()
+		if err := d.loadBaseline(); err != nil {
+			return nil, err
+		}
+	}
+	if d.autoStart {
+		if err := d.StartMeasurements(); err != nil {
+			return nil, err
 		}
 	}
 	return d, nil
 }
 
+// loadBaseline loads the baseline from the store, to be restored by StartMeasurements,
+// if it has been saved and is not older than MaxBaselineAge, and was saved from this
+// sensor if it includes a serial number.
+func (d *Dev) loadBaseline() error {
+	d.restore = nil
+	if d.store == nil {
+		return nil
+	}
+	baseline, err := d.store.Load()
+	if err != nil {
+		return fmt.Errorf("sgp30: Error while loading baseline: %w", err)
+	}
+	if baseline == nil {
+		return nil
+	}
+	// Don't seed the sensor with a baseline that is too old
+	if baseline.Stale(d.now()) {
+		d.logf("sgp30: Ignoring stale baseline from %s", baseline.Timestamp)
+		return nil
+	}
+	// Don't seed the sensor with a baseline from a different sensor
+	if baseline.Serial != 0 && baseline.Serial != d.serial {
+		d.logf("sgp30: Ignoring baseline from sensor %012X", baseline.Serial)
+		return nil
+	}
+	// Catch a corrupted baseline now instead of when the measurements are started
+	if !sensirion.CheckCRC8(baseline.Data[0:3]) {
+		return d.crcError("saved baseline word 1", baseline.Data[0:3])
+	}
+	if !sensirion.CheckCRC8(baseline.Data[3:6]) {
+		return d.crcError("saved baseline word 2", baseline.Data[3:6])
+	}
+	d.restore = baseline
+	return nil
+}
+
+// logf logs the message if a Logger was passed to New
+func (d *Dev) logf(format string, v ...interface{}) {
+	if d.log != nil {
+		d.log.Printf(format, v...)
+	}
+}
+
 // Dev holds the connection and error details for the device
-// as well as the path to the baseline file and how often to save it.
+// as well as the baseline store and how often to save it.
 type Dev struct {
+	bus              i2c.Bus       // i2c bus the sgp30 is connected to
+	addr             uint16        // i2c address of the sgp30
 	i2c              conn.Conn     // i2c device handle for the sgp30
-	baselineFile     string        // Path and filename for storing baseline values
+	log              Logger        // Optional logger
+	clock            Clock         // Source of the current time
+	stats            *counters     // Counters returned by Stats
+	serial           uint64        // Serial number of the sensor
+	productType      uint8         // Product type from the feature set
+	productVersion   uint8         // Product version from the feature set
+	powerMode        PowerMode     // SGPC3 power mode
+	measuring        bool          // Air quality measurements have been started
+	started          time.Time     // When the measurements were started
+	warmup           WarmupPolicy  // How to handle readings made during warm-up
+	stuckTimeout     time.Duration // Restart measurements stuck at 400/0 for this long, 0 disables it
+	stuckSince       time.Time     // When the readings became stuck at 400/0
+	onEvent          func(Event)   // Called with events, or nil
+	cadenceTolerance time.Duration // Warn when readings are further than this from the interval, 0 disables it
+	lastRead         time.Time     // When the previous reading was made, for the cadence check
+	watchdog         int           // Recover after this many consecutive I²C errors, 0 disables it
+	busFailures      int           // Consecutive I²C errors from ReadAirQuality
+	openBus          BusOpener     // Opens a new bus for the watchdog, or nil
+	ownedBus         i2c.BusCloser // Bus opened by the watchdog, closed by Halt
+	idleOnHalt       bool          // Reset the sensor when Halt is called
+	autoStart        bool          // Start the measurements in New
+	restore          *Baseline     // Baseline to restore when the measurements are started
+	retries          int           // Number of times to retry a failed air quality read
+	backoff          time.Duration // Delay before the first retry, doubled for each retry
+	store            BaselineStore // Storage for the baseline values
 	baselineInterval time.Duration // How often to save the baseline data
 	lastSave         time.Time     // Last time baseline was saved
+	saveDelta        uint16        // Only save the baseline when it changes by more than this, 0 always saves
+	maxSaveInterval  time.Duration // Save the baseline at least this often when saveDelta is used
+	asyncSave        bool          // Write the baseline to the store from a goroutine
+	baselineErr      func(error)   // Called with baseline save errors
+	created          time.Time     // When New was called
 	err              error         //nolint
+
+	mu      sync.Mutex         // Protects cancel
+	cancel  context.CancelFunc // Stops the measurement loop started by Start
+	running chan struct{}      // Closed when the measurement loop exits
+
+	bmu          sync.Mutex // Protects lastBaseline
+	lastBaseline *Baseline  // Last baseline read from the sensor or restored, or nil
+
+	smu     sync.Mutex     // Protects saved, pending, and writing
+	saved   *Baseline      // Last baseline written to, or restored from, the store
+	pending *Baseline      // Baseline waiting to be written by the background goroutine
+	writing bool           // The background goroutine is running
+	saves   sync.WaitGroup // Background baseline writes
+}
+
+var _ conn.Resource = &Dev{}
+
+// Reading holds the air quality measurements from the SGP30
+type Reading struct {
+	ECO2      uint16    `json:"eco2"`             // CO2 equivalent in ppm
+	TVOC      uint16    `json:"tvoc"`             // TVOC in ppb
+	Timestamp time.Time `json:"timestamp"`        // When the reading was made
+	Warmup    bool      `json:"warmup,omitempty"` // Made during warm-up, with WarmupFlag
+}
+
+// AirQuality holds a reading from the measurement loop started by Start
+type AirQuality struct {
+	Reading
+	Err error `json:"-"` // Error reading the sensor, the Reading is not valid when this is set
+}
+
+// String implements conn.Resource.
+func (d *Dev) String() string {
+	return fmt.Sprintf("sgp30{%s, 0x%02x}", d.bus, d.addr)
 }
 
 // Halt implements conn.Resource.
+//
+// It stops the measurement loop started by Start, and saves the baseline to the
+// baseline store if one was configured and the measurements were started.
+// If WithIdleOnHalt was used the sensor is also reset, returning it to idle mode.
+//
+// It is safe to call Halt more than once, and the measurements can be restarted
+// afterwards with Start or StartMeasurements.
 func (d *Dev) Halt() error {
+	d.mu.Lock()
+	cancel, running := d.cancel, d.running
+	d.mu.Unlock()
+	if cancel != nil {
+		cancel()
+		<-running
+	}
+
+	if d.store != nil && d.measuring {
+		// Let any background write finish first so it cannot overwrite this one
+		d.saves.Wait()
+		if err := d.saveBaseline(false); err != nil {
+			return err
+		}
+	}
+	if d.idleOnHalt {
+		if err := d.softReset(); err != nil {
+			return err
+		}
+	}
+	if d.ownedBus != nil {
+		err := d.ownedBus.Close()
+		d.ownedBus = nil
+		return err
+	}
 	return nil
 }
 
+// Reset sends a soft reset to the sensor and then restarts the measurements
+//
+// The baseline is restored from the baseline store if one was configured, otherwise
+// the sensor starts over with its 12 hour early operation phase.
+//
+// NOTE: The soft reset uses the I²C General Call address, all devices on the bus that
+// support General Call will also be reset.
+func (d *Dev) Reset() error {
+	if err := d.softReset(); err != nil {
+		return err
+	}
+	return d.initMeasurements()
+}
+
+// softReset sends a soft reset to the sensor, which stops the measurements and returns
+// it to idle mode
+func (d *Dev) softReset() error {
+	// Send a 0x06 to the General Call address
+	if err := d.bus.Tx(0x00, []byte{0x06}, nil); err != nil {
+		return d.busError("sgp30: Error while sending soft reset", err)
+	}
+	d.measuring = false
+
+	// Requires a short delay for the sensor to power back up
+	time.Sleep(1 * time.Millisecond)
+	return nil
+}
+
+// initMeasurements starts measurements, restoring the latest baseline from the store
+// if one has been saved
+func (d *Dev) initMeasurements() error {
+	if err := d.loadBaseline(); err != nil {
+		return err
+	}
+	return d.StartMeasurements()
+}
+
+// Start starts the measurements and reads the air quality every MeasurementInterval,
+// as required by the sensor's dynamic baseline compensation algorithm. The readings
+// are sent to the returned channel, including any errors.
+//
+// The baseline is restored from the baseline store if one was configured.
+//
+// The measurement loop stops and the channel is closed when ctx is cancelled.
+func (d *Dev) Start(ctx context.Context) (<-chan AirQuality, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.cancel != nil {
+		return nil, fmt.Errorf("sgp30: measurement loop is already running")
+	}
+
+	if err := d.initMeasurements(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	d.cancel = cancel
+	d.running = make(chan struct{})
+	ch := make(chan AirQuality, 1)
+	go d.measure(ctx, ch)
+	return ch, nil
+}
+
+// measure reads the air quality every MeasurementInterval until ctx is cancelled
+func (d *Dev) measure(ctx context.Context, ch chan<- AirQuality) {
+	defer func() {
+		d.mu.Lock()
+		d.cancel()
+		d.cancel = nil
+		close(d.running)
+		d.mu.Unlock()
+		close(ch)
+	}()
+
+	ticker := time.NewTicker(d.MeasurementInterval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		var aq AirQuality
+		aq.Reading, aq.Err = d.ReadAirQuality()
+		select {
+		case <-ctx.Done():
+			return
+		case ch <- aq:
+		}
+	}
+}
+
 // GetSerialNumber returns the 48 bit serial number of the device
 func (d *Dev) GetSerialNumber() (uint64, error) {
 	// Send a 0x3682
 	// Receive 3 words + 8 bit CRC on each
 	var data [9]byte
 	if err := d.i2c.Tx([]byte{0x36, 0x82}, data[:]); err != nil {
-		return 0, fmt.Errorf("sgp30: Error while reading serial number: %w", err)
+		return 0, d.busError("sgp30: Error while reading serial number", err)
 	}
 
-	if !checkCRC8(data[0:3]) {
-		return 0, fmt.Errorf("sgp30: serial number word 1 CRC8 failed on: %v", data[0:3])
+	if !sensirion.CheckCRC8(data[0:3]) {
+		return 0, d.crcError("serial number word 1", data[0:3])
 	}
-	if !checkCRC8(data[3:6]) {
-		return 0, fmt.Errorf("sgp30: serial number word 2 CRC8 failed on: %v", data[3:6])
+	if !sensirion.CheckCRC8(data[3:6]) {
+		return 0, d.crcError("serial number word 2", data[3:6])
 	}
-	if !checkCRC8(data[6:9]) {
-		return 0, fmt.Errorf("sgp30: serial number word 3 CRC8 failed on: %v", data[6:9])
+	if !sensirion.CheckCRC8(data[6:9]) {
+		return 0, d.crcError("serial number word 3", data[6:9])
 	}
 
-	return uint64(word(data[:], 0))<<24 + uint64(word(data[:], 3))<<16 + uint64(word(data[:], 6)), nil
+	return uint64(sensirion.Word(data[:], 0))<<32 | uint64(sensirion.Word(data[:], 3))<<16 | uint64(sensirion.Word(data[:], 6)), nil
 }
 
 // GetFeatures returns the 8 bit product type, and 8 bit product version
@@ -102,118 +338,504 @@ func (d *Dev) GetFeatures() (uint8, uint8, error) {
 	// Receive 1 word + 8 bit CRC
 	var data [3]byte
 	if err := d.i2c.Tx([]byte{0x20, 0x2f}, data[:]); err != nil {
-		return 0, 0, fmt.Errorf("sgp30: Error while reading features: %w", err)
+		return 0, 0, d.busError("sgp30: Error while reading features", err)
 	}
 
-	if !checkCRC8(data[0:3]) {
-		return 0, 0, fmt.Errorf("sgp30: features CRC8 failed on: %v", data[0:3])
+	if !sensirion.CheckCRC8(data[0:3]) {
+		return 0, 0, d.crcError("features", data[0:3])
 	}
 
 	return data[0], data[1], nil
 }
 
+// SelfTest runs the on-chip self test and returns an error if it fails
+//
+// The self test should be run before calling StartMeasurements, it interrupts the
+// measurements and StartMeasurements will need to be called again afterwards.
+func (d *Dev) SelfTest() error {
+	// Send a 0x2032
+	// Receive 1 word + 8 bit CRC
+	if err := d.i2c.Tx([]byte{0x20, 0x32}, nil); err != nil {
+		return d.busError("sgp30: Error while requesting self test", err)
+	}
+
+	// Requires a 220ms delay before reading results
+	time.Sleep(220 * time.Millisecond)
+	var data [3]byte
+	if err := d.i2c.Tx(nil, data[:]); err != nil {
+		return d.notReadyError("sgp30: Error while reading self test", err)
+	}
+
+	if !sensirion.CheckCRC8(data[0:3]) {
+		return d.crcError("self test", data[0:3])
+	}
+	if sensirion.Word(data[:], 0) != 0xD400 {
+		return fmt.Errorf("sgp30: self test failed: 0x%04X", sensirion.Word(data[:], 0))
+	}
+	return nil
+}
+
 // StartMeasurements sends the Inlet Air Quality command to start measuring
-// ReadAirQuality needs to be called every second after this has been sent
+// ReadAirQuality needs to be called every MeasurementInterval after this has been sent
+//
+// If a baseline was loaded from the baseline store it is restored after starting the
+// measurements.
 //
 // Note that for 15s after the measurements have started the readings will return
 // 400ppm CO2 and 0ppb TVOC
 func (d *Dev) StartMeasurements() error {
+	if d.restore != nil {
+		baseline := d.restore
+		d.restore = nil
+		if err := d.SetBaseline(baseline.Data[:]); err != nil {
+			return err
+		}
+		d.setSaved(baseline)
+		d.setLastBaseline(*baseline)
+		return nil
+	}
+	return d.initAirQuality()
+}
+
+// initAirQuality sends the Init Air Quality command
+func (d *Dev) initAirQuality() error {
 	// Send a 0x2003
 	if err := d.i2c.Tx([]byte{0x20, 0x03}, nil); err != nil {
-		return fmt.Errorf("sgp30: Error starting air quality measurements: %w", err)
+		return d.busError("sgp30: Error starting air quality measurements", err)
 	}
+	d.measuring = true
+	d.started = d.now()
+	d.lastRead = time.Time{}
 
 	return nil
 }
 
-// ReadAirQuality returns the CO2 and TVOC readings as 16 bit values
+// IsWarmedUp returns true when the measurements have been running for longer than
+// WarmupTime, and the readings are no longer fixed at 400ppm CO2 and 0ppb TVOC
+func (d *Dev) IsWarmedUp() bool {
+	return d.measuring && d.since(d.started) >= WarmupTime
+}
+
+// ReadAirQuality returns the CO2 and TVOC readings
 // CO2 is in ppm and TVOC is in ppb
 //
-// If a baselineFile was passed to New the baseline data will be saved to disk every
-// baselineInterval
-func (d *Dev) ReadAirQuality() (uint16, uint16, error) {
+// If a baseline store was configured the baseline data will be saved to it every
+// save interval. Errors saving the baseline are returned unless a handler has been
+// set with WithBaselineErrorHandler.
+//
+// If WithRetries was used, transient CRC and I²C errors are retried before returning
+// an error.
+//
+// The readings made during the first 15s after starting the measurements are returned
+// unchanged unless WithWarmupPolicy was used to flag or suppress them.
+//
+// If WithStuckDetection was used, and the readings stay at 400ppm CO2 and 0ppb TVOC
+// after the warm-up, the measurements are restarted and the baseline restored.
+//
+// If WithWatchdog was used, the sensor is reset after too many consecutive I²C errors.
+func (d *Dev) ReadAirQuality() (Reading, error) {
+	r, err := d.readAirQuality()
+	backoff := d.backoff
+	for retry := 0; err != nil && retry < d.retries; retry++ {
+		// Only CRC failures and I²C errors are worth retrying
+		if !errors.Is(err, ErrCRC) && !errors.Is(err, ErrBusIO) {
+			break
+		}
+		d.logf("sgp30: Retrying air quality read after error: %s", err)
+		atomic.AddUint64(&d.stats.retries, 1)
+		time.Sleep(backoff)
+		backoff *= 2
+		r, err = d.readAirQuality()
+	}
+	d.checkWatchdog(err)
+	if err != nil {
+		return Reading{}, err
+	}
+
+	if d.store != nil && d.since(d.lastSave) >= d.baselineInterval {
+		d.lastSave = d.now()
+		if err := d.saveBaseline(d.asyncSave); err != nil {
+			if d.baselineErr == nil {
+				return Reading{}, err
+			}
+			d.baselineErr(err)
+		}
+	}
+
+	d.checkCadence(r)
+	d.checkStuck(r)
+
+	if !d.IsWarmedUp() {
+		switch d.warmup {
+		case WarmupFlag:
+			r.Warmup = true
+		case WarmupSuppress:
+			return Reading{}, &Error{Kind: ErrWarmingUp, Msg: "sgp30: Sensor is warming up"}
+		}
+	}
+
+	return r, nil
+}
+
+// readAirQuality sends the Measure Air Quality command and returns the results
+func (d *Dev) readAirQuality() (Reading, error) {
+	if d.IsSGPC3() {
+		return d.readTVOC()
+	}
+
 	// Send a 0x2008
 	// Receive 2 words with + 8 bit CRC on each
 	if err := d.i2c.Tx([]byte{0x20, 0x08}, nil); err != nil {
-		return 0, 0, fmt.Errorf("sgp30: Error while requesting air quality: %w", err)
+		return Reading{}, d.busError("sgp30: Error while requesting air quality", err)
 	}
 
 	// Requires a short delay before reading results
 	time.Sleep(10 * time.Millisecond)
 	var data [6]byte
 	if err := d.i2c.Tx(nil, data[:]); err != nil {
-		return 0, 0, fmt.Errorf("sgp30: Error while reading air quality: %w", err)
+		return Reading{}, d.notReadyError("sgp30: Error while reading air quality", err)
 	}
 
-	if !checkCRC8(data[0:3]) {
-		return 0, 0, fmt.Errorf("sgp30: read air quality word 1 CRC8 failed on: %v", data[0:3])
+	if !sensirion.CheckCRC8(data[0:3]) {
+		return Reading{}, d.crcError("read air quality word 1", data[0:3])
 	}
-	if !checkCRC8(data[3:6]) {
-		return 0, 0, fmt.Errorf("sgp30: read air quality word 2 CRC8 failed on: %v", data[3:6])
+	if !sensirion.CheckCRC8(data[3:6]) {
+		return Reading{}, d.crcError("read air quality word 2", data[3:6])
 	}
 
-	if len(d.baselineFile) > 0 && time.Since(d.lastSave) >= d.baselineInterval {
-		d.lastSave = time.Now()
-		baseline, err := d.ReadBaseline()
-		if err != nil {
-			return 0, 0, fmt.Errorf("sgp30: Error while reading baseline: %w", err)
+	return Reading{
+		ECO2:      sensirion.Word(data[:], 0),
+		TVOC:      sensirion.Word(data[:], 3),
+		Timestamp: d.now(),
+	}, nil
+}
+
+// ReadRawSignals returns the raw H2 and Ethanol signals as 16 bit values
+// These are the signals used internally by the sensor to calculate the CO2 and TVOC
+// readings, and are intended for part verification and testing.
+//
+// It requires a sensor with feature set 0x20 or later.
+func (d *Dev) ReadRawSignals() (uint16, uint16, error) {
+	if err := d.notSGPC3("raw signals"); err != nil {
+		return 0, 0, err
+	}
+	if err := d.checkFeatureSet(0x20); err != nil {
+		return 0, 0, err
+	}
+
+	// Send a 0x2050
+	// Receive 2 words with + 8 bit CRC on each
+	if err := d.i2c.Tx([]byte{0x20, 0x50}, nil); err != nil {
+		return 0, 0, d.busError("sgp30: Error while requesting raw signals", err)
+	}
+
+	// Requires a 25ms delay before reading results
+	time.Sleep(25 * time.Millisecond)
+	var data [6]byte
+	if err := d.i2c.Tx(nil, data[:]); err != nil {
+		return 0, 0, d.notReadyError("sgp30: Error while reading raw signals", err)
+	}
+
+	if !sensirion.CheckCRC8(data[0:3]) {
+		return 0, 0, d.crcError("read raw signals word 1", data[0:3])
+	}
+	if !sensirion.CheckCRC8(data[3:6]) {
+		return 0, 0, d.crcError("read raw signals word 2", data[3:6])
+	}
+
+	return sensirion.Word(data[:], 0), sensirion.Word(data[:], 3), nil
+}
+
+// saveBaseline reads the current baseline from the sensor and saves it to the store
+// If background is true the store is written by a goroutine, and errors are passed
+// to the baseline error handler.
+func (d *Dev) saveBaseline(background bool) error {
+	baseline, err := d.ReadBaseline()
+	if err != nil {
+		atomic.AddUint64(&d.stats.baselineSaveErrors, 1)
+		return err
+	}
+	// Skip writing a baseline that has not changed much since the last one
+	saved := d.getSaved()
+	if d.saveDelta > 0 && saved != nil && d.since(saved.Timestamp) < d.maxSaveInterval &&
+		!baselineChanged(saved.Data, baseline, d.saveDelta) {
+		return nil
+	}
+	b := Baseline{Data: baseline, Timestamp: d.now(), Serial: d.serial}
+	if background {
+		d.queueBaseline(b)
+		return nil
+	}
+	return d.writeBaseline(b)
+}
+
+// writeBaseline writes the baseline to the store
+func (d *Dev) writeBaseline(b Baseline) error {
+	if err := d.store.Save(b); err != nil {
+		atomic.AddUint64(&d.stats.baselineSaveErrors, 1)
+		return fmt.Errorf("sgp30: Error while saving baseline: %w", err)
+	}
+	d.setSaved(&b)
+	atomic.AddUint64(&d.stats.baselineSaves, 1)
+	return nil
+}
+
+// queueBaseline writes the baseline to the store from a goroutine
+// Only one write runs at a time, if a write is already running the newest baseline
+// replaces any other one waiting to be written.
+func (d *Dev) queueBaseline(b Baseline) {
+	d.smu.Lock()
+	defer d.smu.Unlock()
+	d.pending = &b
+	if d.writing {
+		return
+	}
+	d.writing = true
+	d.saves.Add(1)
+	go func() {
+		defer d.saves.Done()
+		for {
+			d.smu.Lock()
+			b := d.pending
+			d.pending = nil
+			if b == nil {
+				d.writing = false
+				d.smu.Unlock()
+				return
+			}
+			d.smu.Unlock()
+
+			if err := d.writeBaseline(*b); err != nil {
+				if d.baselineErr != nil {
+					d.baselineErr(err)
+				} else {
+					d.logf("%s", err)
+				}
+			}
 		}
-		if err = ioutil.WriteFile(d.baselineFile, baseline[:], 0644); err != nil {
-			return 0, 0, err
+	}()
+}
+
+// getSaved returns the last baseline written to, or restored from, the store
+func (d *Dev) getSaved() *Baseline {
+	d.smu.Lock()
+	defer d.smu.Unlock()
+	return d.saved
+}
+
+// setSaved records the last baseline written to, or restored from, the store
+func (d *Dev) setSaved(b *Baseline) {
+	d.smu.Lock()
+	defer d.smu.Unlock()
+	d.saved = b
+}
+
+// baselineChanged returns true if either of the baseline words differ by more than delta
+func baselineChanged(old, new [6]byte, delta uint16) bool {
+	for _, i := range []int{0, 3} {
+		a, b := sensirion.Word(old[:], i), sensirion.Word(new[:], i)
+		if a > b && a-b > delta || b > a && b-a > delta {
+			return true
 		}
 	}
-
-	return word(data[:], 0), word(data[:], 3), nil
+	return false
 }
 
 // ReadBaseline returns the 6 data bytes for the measurement baseline
 // These values should be saved to disk and restore using SetBaseline when the program
 // restarts.
 func (d *Dev) ReadBaseline() ([6]byte, error) {
+	if d.IsSGPC3() {
+		data, err := d.readTVOCBaseline()
+		if err != nil {
+			return [6]byte{}, err
+		}
+		d.setLastBaseline(Baseline{Data: data, Timestamp: d.now(), Serial: d.serial})
+		return data, nil
+	}
+
 	// Send a 0x2015
 	// Receive 2 words + 8 bit CRC on each
 	var data [6]byte
 	if err := d.i2c.Tx([]byte{0x20, 0x15}, data[:]); err != nil {
-		return [6]byte{}, fmt.Errorf("sgp30: Error while reading baseline: %w", err)
+		return [6]byte{}, d.busError("sgp30: Error while reading baseline", err)
 	}
 
-	if !checkCRC8(data[0:3]) {
-		return [6]byte{}, fmt.Errorf("sgp30: baseline word 1 CRC8 failed on: %v", data[0:3])
+	if !sensirion.CheckCRC8(data[0:3]) {
+		return [6]byte{}, d.crcError("baseline word 1", data[0:3])
 	}
-	if !checkCRC8(data[3:6]) {
-		return [6]byte{}, fmt.Errorf("sgp30: baseline word 2 CRC8 failed on: %v", data[3:6])
+	if !sensirion.CheckCRC8(data[3:6]) {
+		return [6]byte{}, d.crcError("baseline word 2", data[3:6])
 	}
+	d.setLastBaseline(Baseline{Data: data, Timestamp: d.now(), Serial: d.serial})
 
 	return data, nil
 }
 
+// setLastBaseline records the baseline returned by Baseline
+func (d *Dev) setLastBaseline(b Baseline) {
+	d.bmu.Lock()
+	defer d.bmu.Unlock()
+	d.lastBaseline = &b
+}
+
+// Baseline returns the last baseline read from the sensor, or restored from the
+// baseline store, and true. If there is no baseline yet it returns false.
+func (d *Dev) Baseline() (Baseline, bool) {
+	d.bmu.Lock()
+	defer d.bmu.Unlock()
+	if d.lastBaseline == nil {
+		return Baseline{}, false
+	}
+	return *d.lastBaseline, true
+}
+
+// BaselineAge returns how long ago the last baseline was read from the sensor, or the
+// age of the restored baseline. If there is no baseline yet it returns the time since
+// New was called.
+//
+// The baseline is read every save interval when a baseline store is used, so an age
+// much larger than that means the sensor is not producing a fresh baseline.
+func (d *Dev) BaselineAge() time.Duration {
+	if b, ok := d.Baseline(); ok {
+		return d.since(b.Timestamp)
+	}
+	return d.since(d.created)
+}
+
 // SetBaseline sets the measurement baseline data bytes
 // The values should have been previously read from the device using ReadBaseline
 //
 // NOTE: The data order for setting it is TVOC, CO2 even though the order when
 // reading is CO2, TVOC. This assumes that the baseline data passed in is CO2, TVOC
 func (d *Dev) SetBaseline(baseline []byte) error {
-	if !checkCRC8(baseline[0:3]) {
-		return fmt.Errorf("sgp30: set baseline word 1 CRC8 failed on: %v", baseline[0:3])
+	if !sensirion.CheckCRC8(baseline[0:3]) {
+		return d.crcError("set baseline word 1", baseline[0:3])
 	}
-	if !checkCRC8(baseline[3:6]) {
-		return fmt.Errorf("sgp30: set baseline word 2 CRC8 failed on: %v", baseline[3:6])
+	if !sensirion.CheckCRC8(baseline[3:6]) {
+		return d.crcError("set baseline word 2", baseline[3:6])
 	}
 
 	// Send InitAirQuality
-	if err := d.StartMeasurements(); err != nil {
+	if err := d.initAirQuality(); err != nil {
 		return err
 	}
 
 	// Send a 0x201e + TVOC, CO2 baseline data (2 words + CRCs)
-	data := append(append([]byte{0x20, 0x1e}, baseline[3:6]...), baseline[0:3]...)
+	// The SGPC3 only has the TVOC baseline word
+	data := append([]byte{0x20, 0x1e}, baseline[3:6]...)
+	if !d.IsSGPC3() {
+		data = append(data, baseline[0:3]...)
+	}
 	if err := d.i2c.Tx(data, nil); err != nil {
-		return fmt.Errorf("sgp30: Error while setting baseline: %w", err)
+		return d.busError("sgp30: Error while setting baseline", err)
 	}
 	return nil
 }
 
-// word returns 16 bits from the byte stream, starting at index i
-func word(data []byte, i int) uint16 {
-	return uint16(data[i])<<8 + uint16(data[i+1])
+// SetHumidity sets the absolute humidity, in g/m³, used for on-chip humidity compensation
+// of the air quality readings. The value is sent as an 8.8 fixed point number so the
+// maximum value is 255.996 g/m³.
+//
+// Passing 0 disables humidity compensation.
+//
+// It requires a sensor with feature set 0x20 or later.
+func (d *Dev) SetHumidity(absHumidity float64) error {
+	if absHumidity < 0 || absHumidity >= 256 {
+		return fmt.Errorf("sgp30: absolute humidity out of range: %f", absHumidity)
+	}
+	if err := d.checkFeatureSet(0x20); err != nil {
+		return err
+	}
+	// Send a 0x2061 + humidity in 8.8 fixed point (1 word + CRC)
+	data := append([]byte{0x20, 0x61}, sensirion.WordCRC(fixed88(absHumidity))...)
+	if err := d.i2c.Tx(data, nil); err != nil {
+		return d.busError("sgp30: Error while setting humidity", err)
+	}
+	return nil
+}
+
+// CompensateFromEnv sets the humidity compensation from the temperature and relative
+// humidity measured by another sensor, eg. one of the periph.io environmental sensors.
+// The relative humidity is converted to absolute humidity and passed to SetHumidity.
+func (d *Dev) CompensateFromEnv(env physic.Env) error {
+	return d.SetHumidity(AbsoluteHumidity(env.Temperature.Celsius(), float64(env.Humidity)/float64(physic.PercentRH)))
+}
+
+// AbsoluteHumidity returns the absolute humidity in g/m³ for a temperature in °C and a
+// relative humidity in %, using the approximation from the SGP30 datasheet.
+func AbsoluteHumidity(celsius, rh float64) float64 {
+	return 216.7 * ((rh / 100) * 6.112 * math.Exp((17.62*celsius)/(243.12+celsius)) / (273.15 + celsius))
+}
+
+// fixed88 converts a value to 8.8 fixed point, the smallest non-zero value is 1/256
+func fixed88(v float64) uint16 {
+	f := math.Round(v * 256)
+	if f > math.MaxUint16 {
+		return math.MaxUint16
+	}
+	// 0 disables compensation, so don't let a small value round down to it
+	if f == 0 && v > 0 {
+		return 1
+	}
+	return uint16(f)
+}
+
+// ReadTVOCInceptiveBaseline returns the TVOC inceptive baseline
+// This is the baseline determined by the sensor during its first hour of operation
+// and it can be passed to SetTVOCInceptiveBaseline to speed up the TVOC baseline
+// calibration of a new sensor.
+//
+// It requires a sensor with feature set 0x22 or later.
+func (d *Dev) ReadTVOCInceptiveBaseline() (uint16, error) {
+	if err := d.checkFeatureSet(0x22); err != nil {
+		return 0, err
+	}
+
+	// Send a 0x20b3
+	// Receive 1 word + 8 bit CRC
+	if err := d.i2c.Tx([]byte{0x20, 0xb3}, nil); err != nil {
+		return 0, d.busError("sgp30: Error while requesting TVOC inceptive baseline", err)
+	}
+
+	// Requires a short delay before reading results
+	time.Sleep(10 * time.Millisecond)
+	var data [3]byte
+	if err := d.i2c.Tx(nil, data[:]); err != nil {
+		return 0, d.notReadyError("sgp30: Error while reading TVOC inceptive baseline", err)
+	}
+
+	if !sensirion.CheckCRC8(data[0:3]) {
+		return 0, d.crcError("TVOC inceptive baseline", data[0:3])
+	}
+	return sensirion.Word(data[:], 0), nil
+}
+
+// SetTVOCInceptiveBaseline sets the TVOC baseline, it should be called after
+// StartMeasurements and is only intended to be used for the first hour of operation
+// of a sensor that does not have a saved baseline.
+//
+// It requires a sensor with feature set 0x22 or later.
+func (d *Dev) SetTVOCInceptiveBaseline(baseline uint16) error {
+	if err := d.checkFeatureSet(0x22); err != nil {
+		return err
+	}
+
+	// Send a 0x2077 + TVOC baseline (1 word + CRC)
+	data := append([]byte{0x20, 0x77}, sensirion.WordCRC(baseline)...)
+	if err := d.i2c.Tx(data, nil); err != nil {
+		return d.busError("sgp30: Error while setting TVOC inceptive baseline", err)
+	}
+	return nil
+}
+
+// checkFeatureSet returns ErrUnsupported if the sensor's product version is older than version
+func (d *Dev) checkFeatureSet(version uint8) error {
+	if d.productVersion < version {
+		return &Error{
+			Kind: ErrUnsupported,
+			Msg:  fmt.Sprintf("sgp30: unsupported by feature set 0x%02X, requires 0x%02X", d.productVersion, version),
+		}
+	}
+	return nil
 }
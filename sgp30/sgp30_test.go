@@ -5,43 +5,74 @@
 package sgp30
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io/ioutil"
+	"math"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
+	"periph.io/x/periph/conn/i2c"
 	"periph.io/x/periph/conn/i2c/i2ctest"
+	"periph.io/x/periph/conn/physic"
+
+	"github.com/bcl/air-sensors/internal/sensirion"
 )
 
 var (
-	BadSerialNumber    = []byte{0, 0, 0, 0, 0, 0, 0, 0, 0}
-	GoodSerialNumber   = []byte{0x00, 0x00, 0x81, 0x01, 0x57, 0x9C, 0xAC, 0xA2, 0x54}
-	BadBaselineData    = []byte{0, 0, 0, 0, 0, 0}
-	GoodBaselineData   = []byte{0x88, 0xa1, 0x58, 0x8d, 0xc4, 0x61}
-	BadFeaturesData    = []byte{0, 0, 0}
-	GoodFeaturesData   = []byte{0x00, 0x22, 0x65}
-	BadAirQualityData  = []byte{0, 0, 0, 0, 0, 0}
-	GoodAirQualityData = []byte{0x01, 0x9e, 0x53, 0x00, 0x0d, 0xcd}
+	BadSerialNumber     = []byte{0, 0, 0, 0, 0, 0, 0, 0, 0}
+	GoodSerialNumber    = []byte{0x00, 0x00, 0x81, 0x01, 0x57, 0x9C, 0xAC, 0xA2, 0x54}
+	BadBaselineData     = []byte{0, 0, 0, 0, 0, 0}
+	GoodBaselineData    = []byte{0x88, 0xa1, 0x58, 0x8d, 0xc4, 0x61}
+	BadFeaturesData     = []byte{0, 0, 0}
+	GoodFeaturesData    = []byte{0x00, 0x22, 0x65}
+	OldFeaturesData     = []byte{0x00, 0x20, 0x07}
+	BadAirQualityData   = []byte{0, 0, 0, 0, 0, 0}
+	GoodAirQualityData  = []byte{0x01, 0x9e, 0x53, 0x00, 0x0d, 0xcd}
+	StuckAirQualityData = []byte{0x01, 0x90, 0x4c, 0x00, 0x00, 0x81}
+	BadRawSignalsData   = []byte{0, 0, 0, 0, 0, 0}
+	FailSelfTestData    = []byte{0x00, 0x00, 0x81}
+	GoodSelfTestData    = []byte{0xd4, 0x00, 0xc6}
+	GoodInceptiveData   = []byte{0x8d, 0xc4, 0x61}
+	GoodRawSignalsData  = []byte{0x36, 0x0c, 0x14, 0x45, 0x74, 0x43}
 )
 
+// fakeClock is a Clock that only moves when it is advanced
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.t
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.t = c.t.Add(d)
+}
+
 func TestWord(t *testing.T) {
 	data := []byte{0x00, 0x01, 0x80, 0x0A, 0x55, 0xAA, 0xFF, 0x7F}
 	result := []uint16{0x0001, 0x800A, 0x55AA, 0xFF7F}
 	for i := 0; i < len(result); i++ {
-		if word(data, i*2) != result[i] {
+		if sensirion.Word(data, i*2) != result[i] {
 			t.Errorf("word error: i == %d", i)
 		}
 	}
 }
 
 func TestChecksum(t *testing.T) {
-	if !checkCRC8(GoodSerialNumber[0:3]) {
+	if !sensirion.CheckCRC8(GoodSerialNumber[0:3]) {
 		t.Fatal("serial number word 1 CRC8 error")
 	}
-	if !checkCRC8(GoodSerialNumber[3:6]) {
+	if !sensirion.CheckCRC8(GoodSerialNumber[3:6]) {
 		t.Fatal("serial number word 2 CRC8 error")
 	}
-	if !checkCRC8(GoodSerialNumber[6:9]) {
+	if !sensirion.CheckCRC8(GoodSerialNumber[6:9]) {
 		t.Fatal("serial number word 3 CRC8 error")
 	}
 }
@@ -52,7 +83,7 @@ func TestFailReadChipID(t *testing.T) {
 		Ops:       []i2ctest.IO{},
 		DontPanic: true,
 	}
-	if _, err := New(&bus, "", time.Second); err == nil {
+	if _, err := New(&bus); err == nil {
 		t.Fatal("can't read chip ID")
 	}
 }
@@ -64,7 +95,7 @@ func TestBadSerialNumber(t *testing.T) {
 			{Addr: 0x58, W: []byte{0x36, 0x82}, R: BadSerialNumber},
 		},
 	}
-	if _, err := New(&bus, "", time.Second); err == nil {
+	if _, err := New(&bus); err == nil {
 		t.Fatal("Bad serial number Error")
 	}
 }
@@ -74,13 +105,37 @@ func TestGoodSerialNumber(t *testing.T) {
 		Ops: []i2ctest.IO{
 			// Good serial number
 			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
 		},
 	}
-	if _, err := New(&bus, "", time.Second); err != nil {
+	if _, err := New(&bus); err != nil {
 		t.Fatalf("Good serial number Error: %s", err)
 	}
 }
 
+func TestSerialNumberWords(t *testing.T) {
+	// These serial numbers collide if the words overlap
+	serials := map[uint64][]byte{
+		0x000100000000: {0x00, 0x01, 0xb0, 0x00, 0x00, 0x81, 0x00, 0x00, 0x81},
+		0x000001000000: {0x00, 0x00, 0x81, 0x01, 0x00, 0x75, 0x00, 0x00, 0x81},
+	}
+	for serial, data := range serials {
+		bus := i2ctest.Playback{
+			Ops: []i2ctest.IO{
+				{Addr: 0x58, W: []byte{0x36, 0x82}, R: data},
+				{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
+			},
+		}
+		d, err := New(&bus)
+		if err != nil {
+			t.Fatalf("New Error: %s", err)
+		}
+		if d.serial != serial {
+			t.Errorf("Serial number Error: 0x%012X != 0x%012X", d.serial, serial)
+		}
+	}
+}
+
 func TestBadBaselineData(t *testing.T) {
 	// Temporary baseline file, defer removal
 	bf, err := ioutil.TempFile("", "sgp30.")
@@ -94,17 +149,16 @@ func TestBadBaselineData(t *testing.T) {
 		t.Fatalf("TempFile Write Error: %s", err)
 	}
 
-	// Calling New with a baseline reads the serial number, starts measurements,
-	// and then writes the baseline data
+	// Calling New with a bad baseline reads the serial number, and then fails to
+	// load the baseline data
 	bus := i2ctest.Playback{
 		Ops: []i2ctest.IO{
 			// Good serial number
 			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
-			{Addr: 0x58, W: []byte{0x20, 0x03}, R: []byte{}},
-			{Addr: 0x58, W: []byte{0x20, 0x15}, R: BadBaselineData},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
 		},
 	}
-	if _, err := New(&bus, bf.Name(), time.Second); err == nil {
+	if _, err := New(&bus, WithBaselineFile(bf.Name()), WithSaveInterval(time.Second)); err == nil {
 		t.Fatal("Bad baseline data")
 	}
 }
@@ -125,23 +179,24 @@ func TestGoodBaselineData(t *testing.T) {
 	// The CO2 and TVOC data is swapped when writing it back to the SGP30
 	BaselineWrite := append(append([]byte{0x20, 0x1e}, GoodBaselineData[3:6]...), GoodBaselineData[0:3]...)
 
-	// Calling New with a baseline reads the serial number, starts measurements,
-	// and then writes the baseline data
+	// Calling New with a baseline and auto start reads the serial number, starts
+	// measurements, and then writes the baseline data
 	bus := i2ctest.Playback{
 		Ops: []i2ctest.IO{
 			// Good serial number
 			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
 			{Addr: 0x58, W: []byte{0x20, 0x03}, R: []byte{}},
 			{Addr: 0x58, W: BaselineWrite, R: []byte{}},
 		},
 	}
-	if _, err := New(&bus, bf.Name(), time.Second); err != nil {
+	if _, err := New(&bus, WithBaselineFile(bf.Name()), WithSaveInterval(time.Second), WithAutoStart(true)); err != nil {
 		t.Fatalf("Good Baseline Error: %s", err)
 	}
 }
 
 func TestBadFeatures(t *testing.T) {
-	// Calling New with a baseline reads the serial number
+	// Calling New reads the serial number and the feature set
 	bus := i2ctest.Playback{
 		Ops: []i2ctest.IO{
 			// Good serial number
@@ -149,25 +204,22 @@ func TestBadFeatures(t *testing.T) {
 			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: BadFeaturesData},
 		},
 	}
-	d, err := New(&bus, "", time.Second)
-	if err != nil {
-		t.Fatalf("Bad Features: %s", err)
-	}
-	if _, _, err := d.GetFeatures(); err == nil {
+	if _, err := New(&bus); err == nil {
 		t.Fatal("Bad Features Error")
 	}
 }
 
 func TestGoodFeatures(t *testing.T) {
-	// Calling New with a baseline reads the serial number
+	// Calling New reads the serial number and the feature set
 	bus := i2ctest.Playback{
 		Ops: []i2ctest.IO{
 			// Good serial number
 			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
 			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
 		},
 	}
-	d, err := New(&bus, "", time.Second)
+	d, err := New(&bus)
 	if err != nil {
 		t.Fatalf("Good Features: %s", err)
 	}
@@ -188,10 +240,11 @@ func TestReadBadBaseline(t *testing.T) {
 		Ops: []i2ctest.IO{
 			// Good serial number
 			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
 			{Addr: 0x58, W: []byte{0x20, 0x15}, R: BadBaselineData},
 		},
 	}
-	d, err := New(&bus, "", time.Second)
+	d, err := New(&bus)
 	if err != nil {
 		t.Fatalf("Good serial number Error: %s", err)
 	}
@@ -205,16 +258,30 @@ func TestReadGoodBaseline(t *testing.T) {
 		Ops: []i2ctest.IO{
 			// Good serial number
 			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
 			{Addr: 0x58, W: []byte{0x20, 0x15}, R: GoodBaselineData},
 		},
 	}
-	d, err := New(&bus, "", time.Second)
+	d, err := New(&bus)
 	if err != nil {
 		t.Fatalf("Good serial number Error: %s", err)
 	}
+	if _, ok := d.Baseline(); ok {
+		t.Fatal("Baseline returned before reading it")
+	}
 	if _, err := d.ReadBaseline(); err != nil {
 		t.Fatalf("Read Good Baseline Error: %s", err)
 	}
+	b, ok := d.Baseline()
+	if !ok {
+		t.Fatal("Baseline not returned after reading it")
+	}
+	if b.CO2() != 0x88a1 || b.TVOC() != 0x8dc4 {
+		t.Errorf("Baseline words are wrong: %04X %04X", b.CO2(), b.TVOC())
+	}
+	if age := d.BaselineAge(); age < 0 || age > time.Minute {
+		t.Errorf("BaselineAge is wrong: %s", age)
+	}
 }
 
 func TestBadAirQuality(t *testing.T) {
@@ -222,15 +289,16 @@ func TestBadAirQuality(t *testing.T) {
 		Ops: []i2ctest.IO{
 			// Good serial number
 			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
 			{Addr: 0x58, W: []byte{0x20, 0x08}, R: []byte{}},
 			{Addr: 0x58, W: []byte{}, R: BadAirQualityData},
 		},
 	}
-	d, err := New(&bus, "", time.Second)
+	d, err := New(&bus)
 	if err != nil {
 		t.Fatalf("Good serial number Error: %s", err)
 	}
-	if _, _, err := d.ReadAirQuality(); err == nil {
+	if _, err := d.ReadAirQuality(); err == nil {
 		t.Fatalf("Read Bad AirQuality Error")
 	}
 }
@@ -240,22 +308,787 @@ func TestGoodAirQuality(t *testing.T) {
 		Ops: []i2ctest.IO{
 			// Good serial number
 			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
 			{Addr: 0x58, W: []byte{0x20, 0x08}, R: []byte{}},
 			{Addr: 0x58, W: []byte{}, R: GoodAirQualityData},
 		},
 	}
-	d, err := New(&bus, "", time.Second)
+	d, err := New(&bus)
 	if err != nil {
 		t.Fatalf("Good serial number Error: %s", err)
 	}
-	co2, tvoc, err := d.ReadAirQuality()
+	r, err := d.ReadAirQuality()
 	if err != nil {
 		t.Fatalf("Read Good AirQuality Error: %s", err)
 	}
-	if co2 != 414 {
+	if r.ECO2 != 414 {
 		t.Error("CO2 reading is wrong")
 	}
-	if tvoc != 13 {
+	if r.TVOC != 13 {
 		t.Error("TVOC reading is wrong")
 	}
+	if r.Timestamp.IsZero() {
+		t.Error("Timestamp is missing")
+	}
+}
+
+func TestWordCRC(t *testing.T) {
+	// Datasheet example, 0xBEEF has a CRC of 0x92
+	if !bytes.Equal(sensirion.WordCRC(0xBEEF), []byte{0xBE, 0xEF, 0x92}) {
+		t.Fatalf("wordCRC error: %v", sensirion.WordCRC(0xBEEF))
+	}
+}
+
+func TestFixed88(t *testing.T) {
+	tests := []struct {
+		v      float64
+		result uint16
+	}{
+		{0, 0x0000},
+		{0.001, 0x0001},
+		{1.0 / 256, 0x0001},
+		{11.757, 0x0BC2},
+		{255.996, 0xFFFF},
+	}
+	for _, tt := range tests {
+		if r := fixed88(tt.v); r != tt.result {
+			t.Errorf("fixed88(%f) == 0x%04X, expected 0x%04X", tt.v, r, tt.result)
+		}
+	}
+}
+
+func TestSetHumidity(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Good serial number
+			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
+			{Addr: 0x58, W: append([]byte{0x20, 0x61}, sensirion.WordCRC(0x0BC2)...), R: []byte{}},
+		},
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("Good serial number Error: %s", err)
+	}
+	if err := d.SetHumidity(11.757); err != nil {
+		t.Fatalf("Set Humidity Error: %s", err)
+	}
+	if err := d.SetHumidity(256); err == nil {
+		t.Fatal("Set Humidity out of range Error")
+	}
+}
+
+func TestAbsoluteHumidity(t *testing.T) {
+	ah := AbsoluteHumidity(25, 50)
+	if math.Abs(ah-11.484) > 0.001 {
+		t.Fatalf("AbsoluteHumidity Error: %f", ah)
+	}
+}
+
+func TestCompensateFromEnv(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
+			{Addr: 0x58, W: append([]byte{0x20, 0x61}, sensirion.WordCRC(0x0B7C)...), R: []byte{}},
+		},
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("Good serial number Error: %s", err)
+	}
+	env := physic.Env{
+		Temperature: 25*physic.Kelvin + physic.ZeroCelsius,
+		Humidity:    50 * physic.PercentRH,
+	}
+	if err := d.CompensateFromEnv(env); err != nil {
+		t.Fatalf("CompensateFromEnv Error: %s", err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatalf("Playback Close Error: %s", err)
+	}
+}
+
+func TestBadRawSignals(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Good serial number
+			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
+			{Addr: 0x58, W: []byte{0x20, 0x50}, R: []byte{}},
+			{Addr: 0x58, W: []byte{}, R: BadRawSignalsData},
+		},
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("Good serial number Error: %s", err)
+	}
+	if _, _, err := d.ReadRawSignals(); err == nil {
+		t.Fatalf("Read Bad Raw Signals Error")
+	}
+}
+
+func TestGoodRawSignals(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Good serial number
+			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
+			{Addr: 0x58, W: []byte{0x20, 0x50}, R: []byte{}},
+			{Addr: 0x58, W: []byte{}, R: GoodRawSignalsData},
+		},
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("Good serial number Error: %s", err)
+	}
+	h2, ethanol, err := d.ReadRawSignals()
+	if err != nil {
+		t.Fatalf("Read Good Raw Signals Error: %s", err)
+	}
+	if h2 != 13836 {
+		t.Error("H2 signal is wrong")
+	}
+	if ethanol != 17780 {
+		t.Error("Ethanol signal is wrong")
+	}
+}
+
+func TestSelfTest(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Good serial number
+			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
+			{Addr: 0x58, W: []byte{0x20, 0x32}, R: []byte{}},
+			{Addr: 0x58, W: []byte{}, R: GoodSelfTestData},
+			{Addr: 0x58, W: []byte{0x20, 0x32}, R: []byte{}},
+			{Addr: 0x58, W: []byte{}, R: FailSelfTestData},
+		},
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("Good serial number Error: %s", err)
+	}
+	if err := d.SelfTest(); err != nil {
+		t.Fatalf("Self Test Error: %s", err)
+	}
+	if err := d.SelfTest(); err == nil {
+		t.Fatal("Failed Self Test Error")
+	}
+}
+
+func TestReset(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Good serial number
+			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
+			{Addr: 0x00, W: []byte{0x06}, R: []byte{}},
+			{Addr: 0x58, W: []byte{0x20, 0x03}, R: []byte{}},
+		},
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("Good serial number Error: %s", err)
+	}
+	if err := d.Reset(); err != nil {
+		t.Fatalf("Reset Error: %s", err)
+	}
+}
+
+func TestResetBaseline(t *testing.T) {
+	// Temporary baseline file, defer removal
+	bf, err := ioutil.TempFile("", "sgp30.")
+	if err != nil {
+		t.Fatalf("TempFile Error: %s", err)
+	}
+	defer os.Remove(bf.Name())
+
+	_, err = bf.Write(GoodBaselineData)
+	if err != nil {
+		t.Fatalf("TempFile Write Error: %s", err)
+	}
+
+	// The CO2 and TVOC data is swapped when writing it back to the SGP30
+	BaselineWrite := append(append([]byte{0x20, 0x1e}, GoodBaselineData[3:6]...), GoodBaselineData[0:3]...)
+
+	// Reset restores the baseline after starting measurements
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Good serial number
+			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
+			{Addr: 0x58, W: []byte{0x20, 0x03}, R: []byte{}},
+			{Addr: 0x58, W: BaselineWrite, R: []byte{}},
+			{Addr: 0x00, W: []byte{0x06}, R: []byte{}},
+			{Addr: 0x58, W: []byte{0x20, 0x03}, R: []byte{}},
+			{Addr: 0x58, W: BaselineWrite, R: []byte{}},
+		},
+	}
+	d, err := New(&bus, WithBaselineFile(bf.Name()), WithSaveInterval(time.Second), WithAutoStart(true))
+	if err != nil {
+		t.Fatalf("Good Baseline Error: %s", err)
+	}
+	if err := d.Reset(); err != nil {
+		t.Fatalf("Reset Error: %s", err)
+	}
+}
+
+func TestReadTVOCInceptiveBaseline(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Good serial number
+			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
+			{Addr: 0x58, W: []byte{0x20, 0xb3}, R: []byte{}},
+			{Addr: 0x58, W: []byte{}, R: GoodInceptiveData},
+		},
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("Good serial number Error: %s", err)
+	}
+	baseline, err := d.ReadTVOCInceptiveBaseline()
+	if err != nil {
+		t.Fatalf("Read TVOC Inceptive Baseline Error: %s", err)
+	}
+	if baseline != 0x8dc4 {
+		t.Errorf("TVOC Inceptive Baseline is wrong: 0x%04X", baseline)
+	}
+}
+
+func TestSetTVOCInceptiveBaseline(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Good serial number
+			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
+			{Addr: 0x58, W: append([]byte{0x20, 0x77}, GoodInceptiveData...), R: []byte{}},
+		},
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("Good serial number Error: %s", err)
+	}
+	if err := d.SetTVOCInceptiveBaseline(0x8dc4); err != nil {
+		t.Fatalf("Set TVOC Inceptive Baseline Error: %s", err)
+	}
+}
+
+func TestTVOCInceptiveBaselineUnsupported(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Good serial number
+			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: OldFeaturesData},
+		},
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("Good serial number Error: %s", err)
+	}
+	if _, err := d.ReadTVOCInceptiveBaseline(); err == nil {
+		t.Fatal("Read TVOC Inceptive Baseline on old feature set Error")
+	}
+	if err := d.SetTVOCInceptiveBaseline(0x8dc4); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("Set TVOC Inceptive Baseline on old feature set Error: %v", err)
+	}
+}
+
+func TestUnsupported(t *testing.T) {
+	OldestFeaturesData := []byte{0x00, 0x10, 0xc2}
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Good serial number
+			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: OldestFeaturesData},
+		},
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("Good serial number Error: %s", err)
+	}
+	if _, _, err := d.ReadRawSignals(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Read Raw Signals on old feature set Error: %v", err)
+	}
+	if err := d.SetHumidity(11.757); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Set Humidity on old feature set Error: %v", err)
+	}
+}
+
+func TestStart(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Good serial number
+			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
+			{Addr: 0x58, W: []byte{0x20, 0x03}, R: []byte{}},
+			{Addr: 0x58, W: []byte{0x20, 0x08}, R: []byte{}},
+			{Addr: 0x58, W: []byte{}, R: GoodAirQualityData},
+			{Addr: 0x58, W: []byte{0x20, 0x08}, R: []byte{}},
+			{Addr: 0x58, W: []byte{}, R: BadAirQualityData},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("Good serial number Error: %s", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch, err := d.Start(ctx)
+	if err != nil {
+		t.Fatalf("Start Error: %s", err)
+	}
+	if _, err := d.Start(ctx); err == nil {
+		t.Fatal("Start while running Error")
+	}
+
+	aq := <-ch
+	if aq.Err != nil {
+		t.Fatalf("Read Good AirQuality Error: %s", aq.Err)
+	}
+	if aq.ECO2 != 414 || aq.TVOC != 13 {
+		t.Errorf("AirQuality reading is wrong: %v", aq)
+	}
+	aq = <-ch
+	if aq.Err == nil {
+		t.Fatal("Read Bad AirQuality Error")
+	}
+
+	cancel()
+	for range ch {
+	}
+}
+
+func TestErrors(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Good serial number
+			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
+			{Addr: 0x58, W: []byte{0x20, 0x08}, R: []byte{}},
+			{Addr: 0x58, W: []byte{}, R: BadAirQualityData},
+			{Addr: 0x58, W: []byte{0x20, 0x08}, R: []byte{}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("Good serial number Error: %s", err)
+	}
+
+	_, err = d.ReadAirQuality()
+	if !errors.Is(err, ErrCRC) {
+		t.Errorf("Bad AirQuality is not ErrCRC: %s", err)
+	}
+	if errors.Is(err, ErrBusIO) {
+		t.Errorf("Bad AirQuality is ErrBusIO: %s", err)
+	}
+
+	// The read of the results fails
+	_, err = d.ReadAirQuality()
+	if !errors.Is(err, ErrNotReady) || !errors.Is(err, ErrBusIO) {
+		t.Errorf("Missing AirQuality is not ErrNotReady: %s", err)
+	}
+	var sgpErr *Error
+	if !errors.As(err, &sgpErr) || errors.Unwrap(err) == nil {
+		t.Errorf("Missing AirQuality does not wrap the bus error: %s", err)
+	}
+
+	// The command fails
+	_, err = d.ReadAirQuality()
+	if !errors.Is(err, ErrBusIO) || errors.Is(err, ErrNotReady) {
+		t.Errorf("Failed AirQuality is not ErrBusIO: %s", err)
+	}
+}
+
+func TestAddress(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Good serial number
+			{Addr: 0x59, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x59, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
+		},
+	}
+	if _, err := New(&bus, WithAddress(0x59)); err != nil {
+		t.Fatalf("Good serial number Error: %s", err)
+	}
+}
+
+// testLogger collects the logged messages
+type testLogger []string
+
+func (l *testLogger) Printf(format string, v ...interface{}) {
+	*l = append(*l, fmt.Sprintf(format, v...))
+}
+
+func TestLogger(t *testing.T) {
+	ms := NewMemoryStore()
+	err := ms.Save(Baseline{Timestamp: time.Now().Add(-MaxBaselineAge - time.Hour), Data: goodBaseline()})
+	if err != nil {
+		t.Fatalf("Save Error: %s", err)
+	}
+
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Good serial number
+			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
+		},
+	}
+	var l testLogger
+	if _, err := New(&bus, WithBaselineStore(ms), WithLogger(&l)); err != nil {
+		t.Fatalf("Stale Baseline Error: %s", err)
+	}
+	if len(l) != 1 || !strings.Contains(l[0], "stale baseline") {
+		t.Fatalf("Stale baseline was not logged: %v", l)
+	}
+}
+
+func TestRetries(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Good serial number
+			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
+			{Addr: 0x58, W: []byte{0x20, 0x08}, R: []byte{}},
+			{Addr: 0x58, W: []byte{}, R: BadAirQualityData},
+			{Addr: 0x58, W: []byte{0x20, 0x08}, R: []byte{}},
+			{Addr: 0x58, W: []byte{}, R: GoodAirQualityData},
+			{Addr: 0x58, W: []byte{0x20, 0x08}, R: []byte{}},
+			{Addr: 0x58, W: []byte{}, R: BadAirQualityData},
+			{Addr: 0x58, W: []byte{0x20, 0x08}, R: []byte{}},
+			{Addr: 0x58, W: []byte{}, R: BadAirQualityData},
+		},
+	}
+	d, err := New(&bus, WithRetries(1, time.Millisecond))
+	if err != nil {
+		t.Fatalf("Good serial number Error: %s", err)
+	}
+
+	// The first read fails the CRC check, the retry succeeds
+	r, err := d.ReadAirQuality()
+	if err != nil {
+		t.Fatalf("Read AirQuality retry Error: %s", err)
+	}
+	if r.ECO2 != 414 || r.TVOC != 13 {
+		t.Errorf("AirQuality reading is wrong: %v", r)
+	}
+
+	// Both reads fail
+	if _, err = d.ReadAirQuality(); !errors.Is(err, ErrCRC) {
+		t.Fatalf("Read AirQuality retry did not fail: %v", err)
+	}
+
+	stats := d.Stats()
+	if stats.CRCErrors != 3 || stats.Retries != 2 || stats.BusErrors != 0 {
+		t.Fatalf("Stats Error: %+v", stats)
+	}
+}
+
+func TestHalt(t *testing.T) {
+	// Halt without measurements started doesn't save the baseline
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Good serial number
+			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
+		},
+	}
+	ms := NewMemoryStore()
+	d, err := New(&bus, WithBaselineStore(ms))
+	if err != nil {
+		t.Fatalf("Good serial number Error: %s", err)
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatalf("Halt Error: %s", err)
+	}
+	if baseline, _ := ms.Load(); baseline != nil {
+		t.Fatalf("Halt saved the baseline: %v", baseline)
+	}
+}
+
+func TestHaltStart(t *testing.T) {
+	// Halt stops the measurement loop, saves the baseline, and resets the sensor
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Good serial number
+			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
+			{Addr: 0x58, W: []byte{0x20, 0x03}, R: []byte{}},
+			{Addr: 0x58, W: []byte{0x20, 0x15}, R: GoodBaselineData},
+			{Addr: 0x00, W: []byte{0x06}, R: []byte{}},
+		},
+	}
+	ms := NewMemoryStore()
+	d, err := New(&bus, WithBaselineStore(ms), WithIdleOnHalt())
+	if err != nil {
+		t.Fatalf("Good serial number Error: %s", err)
+	}
+	ch, err := d.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start Error: %s", err)
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatalf("Halt Error: %s", err)
+	}
+	// The channel is closed when the loop exits
+	for range ch {
+	}
+
+	baseline, err := ms.Load()
+	if err != nil {
+		t.Fatalf("Load Error: %s", err)
+	}
+	if baseline == nil || !bytes.Equal(baseline.Data[:], GoodBaselineData) {
+		t.Fatalf("Halt did not save the baseline: %v", baseline)
+	}
+}
+
+func TestString(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Good serial number
+			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
+		},
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("Good serial number Error: %s", err)
+	}
+	if s := d.String(); s != "sgp30{playback, 0x58}" {
+		t.Fatalf("String Error: %s", s)
+	}
+	// Halt can be called more than once
+	for i := 0; i < 2; i++ {
+		if err := d.Halt(); err != nil {
+			t.Fatalf("Halt Error: %s", err)
+		}
+	}
+}
+
+func TestReadingJSON(t *testing.T) {
+	r := Reading{ECO2: 414, TVOC: 13, Timestamp: time.Date(2020, 12, 1, 10, 30, 0, 0, time.UTC)}
+	data, err := json.Marshal(r)
+	if err != nil {
+		t.Fatalf("Marshal Error: %s", err)
+	}
+	expected := `{"eco2":414,"tvoc":13,"timestamp":"2020-12-01T10:30:00Z"}`
+	if string(data) != expected {
+		t.Fatalf("Reading JSON Error: %s", data)
+	}
+}
+
+func TestWarmup(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
+			{Addr: 0x58, W: []byte{0x20, 0x03}, R: []byte{}},
+			{Addr: 0x58, W: []byte{0x20, 0x08}, R: []byte{}},
+			{Addr: 0x58, W: []byte{}, R: GoodAirQualityData},
+			{Addr: 0x58, W: []byte{0x20, 0x08}, R: []byte{}},
+			{Addr: 0x58, W: []byte{}, R: GoodAirQualityData},
+			{Addr: 0x58, W: []byte{0x20, 0x08}, R: []byte{}},
+			{Addr: 0x58, W: []byte{}, R: GoodAirQualityData},
+		},
+	}
+	clock := &fakeClock{t: time.Now()}
+	d, err := New(&bus, WithWarmupPolicy(WarmupFlag), WithClock(clock))
+	if err != nil {
+		t.Fatalf("Good serial number Error: %s", err)
+	}
+	if d.IsWarmedUp() {
+		t.Fatal("IsWarmedUp before starting the measurements")
+	}
+	if err := d.StartMeasurements(); err != nil {
+		t.Fatalf("StartMeasurements Error: %s", err)
+	}
+	if d.IsWarmedUp() {
+		t.Fatal("IsWarmedUp right after starting the measurements")
+	}
+
+	r, err := d.ReadAirQuality()
+	if err != nil {
+		t.Fatalf("Read AirQuality Error: %s", err)
+	}
+	if !r.Warmup {
+		t.Error("Warm-up reading is not flagged")
+	}
+
+	d.warmup = WarmupSuppress
+	if _, err := d.ReadAirQuality(); !errors.Is(err, ErrWarmingUp) {
+		t.Errorf("Warm-up reading is not suppressed: %v", err)
+	}
+
+	clock.Advance(WarmupTime)
+	if !d.IsWarmedUp() {
+		t.Fatal("IsWarmedUp is false after the warm-up time")
+	}
+	r, err = d.ReadAirQuality()
+	if err != nil {
+		t.Fatalf("Read AirQuality Error: %s", err)
+	}
+	if r.Warmup {
+		t.Error("Reading after warm-up is flagged")
+	}
+}
+
+func TestStuckDetection(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
+			{Addr: 0x58, W: []byte{0x20, 0x03}, R: []byte{}},
+			{Addr: 0x58, W: []byte{0x20, 0x08}, R: []byte{}},
+			{Addr: 0x58, W: []byte{}, R: StuckAirQualityData},
+			{Addr: 0x58, W: []byte{0x20, 0x08}, R: []byte{}},
+			{Addr: 0x58, W: []byte{}, R: StuckAirQualityData},
+			// Measurements are restarted
+			{Addr: 0x58, W: []byte{0x20, 0x03}, R: []byte{}},
+		},
+	}
+	var events []Event
+	clock := &fakeClock{t: time.Now()}
+	d, err := New(&bus,
+		WithClock(clock),
+		WithStuckDetection(time.Minute),
+		WithEventHandler(func(e Event) { events = append(events, e) }))
+	if err != nil {
+		t.Fatalf("Good serial number Error: %s", err)
+	}
+	if err := d.StartMeasurements(); err != nil {
+		t.Fatalf("StartMeasurements Error: %s", err)
+	}
+	clock.Advance(WarmupTime)
+
+	if _, err := d.ReadAirQuality(); err != nil {
+		t.Fatalf("Read AirQuality Error: %s", err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("Unexpected events: %v", events)
+	}
+
+	// The readings have been stuck for the timeout
+	clock.Advance(time.Minute)
+	if _, err := d.ReadAirQuality(); err != nil {
+		t.Fatalf("Read AirQuality Error: %s", err)
+	}
+	if len(events) != 1 || events[0].Kind != EventReinit || events[0].Err != nil {
+		t.Fatalf("Wrong events: %v", events)
+	}
+	if d.IsWarmedUp() {
+		t.Error("IsWarmedUp right after restarting the measurements")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatalf("Playback Close Error: %s", err)
+	}
+}
+
+func TestCadenceCheck(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
+			{Addr: 0x58, W: []byte{0x20, 0x08}, R: []byte{}},
+			{Addr: 0x58, W: []byte{}, R: GoodAirQualityData},
+			{Addr: 0x58, W: []byte{0x20, 0x08}, R: []byte{}},
+			{Addr: 0x58, W: []byte{}, R: GoodAirQualityData},
+			{Addr: 0x58, W: []byte{0x20, 0x08}, R: []byte{}},
+			{Addr: 0x58, W: []byte{}, R: GoodAirQualityData},
+		},
+	}
+	var events []Event
+	clock := &fakeClock{t: time.Now()}
+	d, err := New(&bus,
+		WithClock(clock),
+		WithCadenceCheck(200*time.Millisecond),
+		WithEventHandler(func(e Event) { events = append(events, e) }))
+	if err != nil {
+		t.Fatalf("Good serial number Error: %s", err)
+	}
+	if _, err := d.ReadAirQuality(); err != nil {
+		t.Fatalf("Read AirQuality Error: %s", err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("Unexpected events: %v", events)
+	}
+
+	// The next reading is made on time
+	clock.Advance(time.Second)
+	if _, err := d.ReadAirQuality(); err != nil {
+		t.Fatalf("Read AirQuality Error: %s", err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("Unexpected events: %v", events)
+	}
+
+	// Reading again right away is too early
+	if _, err := d.ReadAirQuality(); err != nil {
+		t.Fatalf("Read AirQuality Error: %s", err)
+	}
+	if len(events) != 1 || events[0].Kind != EventCadence || events[0].Interval >= time.Second {
+		t.Fatalf("Wrong events: %v", events)
+	}
+	if stats := d.Stats(); stats.CadenceWarnings != 1 {
+		t.Fatalf("Stats Error: %+v", stats)
+	}
+}
+
+func TestWatchdog(t *testing.T) {
+	// The first bus fails after reading the serial number and features
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Good serial number
+			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
+		},
+		DontPanic: true,
+	}
+	// The re-opened bus resets the sensor and restarts the measurements
+	newBus := &i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x00, W: []byte{0x06}, R: []byte{}},
+			{Addr: 0x58, W: []byte{0x20, 0x03}, R: []byte{}},
+			{Addr: 0x58, W: []byte{0x20, 0x08}, R: []byte{}},
+			{Addr: 0x58, W: []byte{}, R: GoodAirQualityData},
+		},
+	}
+	var events []Event
+	d, err := New(&bus,
+		WithWatchdog(2),
+		WithBusOpener(func() (i2c.BusCloser, error) { return newBus, nil }),
+		WithEventHandler(func(e Event) { events = append(events, e) }))
+	if err != nil {
+		t.Fatalf("Good serial number Error: %s", err)
+	}
+
+	if _, err := d.ReadAirQuality(); !errors.Is(err, ErrBusIO) {
+		t.Fatalf("Read AirQuality did not fail: %v", err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("Unexpected events: %v", events)
+	}
+	if _, err := d.ReadAirQuality(); !errors.Is(err, ErrBusIO) {
+		t.Fatalf("Read AirQuality did not fail: %v", err)
+	}
+	if len(events) != 1 || events[0].Kind != EventRecovery || events[0].Err != nil {
+		t.Fatalf("Wrong events: %v", events)
+	}
+
+	r, err := d.ReadAirQuality()
+	if err != nil {
+		t.Fatalf("Read AirQuality after recovery Error: %s", err)
+	}
+	if r.ECO2 != 414 || r.TVOC != 13 {
+		t.Errorf("AirQuality reading is wrong: %v", r)
+	}
+
+	// Halt closes the bus opened by the watchdog
+	if err := d.Halt(); err != nil {
+		t.Fatalf("Halt Error: %s", err)
+	}
 }
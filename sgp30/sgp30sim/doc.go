@@ -0,0 +1,11 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package sgp30sim simulates a Sensiron SGP30 Gas Sensor on an I²C bus.
+//
+// Sim implements i2c.Bus so it can be passed to sgp30.New, allowing applications to
+// be developed and tested without the hardware. It models the 15 second warm-up
+// after starting the measurements, the slow drift of the baseline, and calculates
+// the CRC8 of all the data it returns.
+package sgp30sim
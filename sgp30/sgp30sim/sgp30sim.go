@@ -0,0 +1,247 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sgp30sim
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"periph.io/x/periph/conn/i2c"
+	"periph.io/x/periph/conn/physic"
+
+	"github.com/bcl/air-sensors/internal/sensirion"
+)
+
+var (
+	// ErrNAK is returned when the simulated sensor does not acknowledge a transaction
+	ErrNAK = errors.New("sgp30sim: NAK")
+)
+
+const (
+	// WarmupTime is how long the sensor returns 400ppm CO2 and 0ppb TVOC after the
+	// measurements are started.
+	WarmupTime = 15 * time.Second
+
+	// DriftInterval is how many measurements it takes for the baseline to drift by 1
+	DriftInterval = 60
+)
+
+// Sim is a simulated SGP30 that implements i2c.Bus
+//
+// Set ECO2 and TVOC to the values that the measurements should return after the
+// warm-up period. The other fields can be changed before using it to simulate
+// different sensors, use SetAirQuality to change the readings while it is in use.
+type Sim struct {
+	mu sync.Mutex
+
+	Addr        uint16           // I²C address of the sensor
+	Serial      uint64           // 48 bit serial number
+	FeatureSet  uint16           // Product type and version
+	ECO2        uint16           // CO2 in ppm returned after warm-up
+	TVOC        uint16           // TVOC in ppb returned after warm-up
+	H2          uint16           // Raw H2 signal
+	Ethanol     uint16           // Raw Ethanol signal
+	BadCRC      int              // Number of responses to return with a bad CRC8
+	Now         func() time.Time // Returns the current time, used to model the warm-up
+	Humidity    uint16           // Absolute humidity set by the host, 8.8 fixed point
+	InitCount   int              // Number of times Init Air Quality was received
+	ResetCount  int              // Number of soft resets received
+	Measuring   bool             // Init Air Quality has been received
+	co2Baseline uint16
+	tvocBase    uint16
+	inceptive   uint16
+	started     time.Time
+	count       int
+	pending     []byte
+}
+
+// New returns a simulated SGP30 with a feature set of 0x0022
+func New() *Sim {
+	return &Sim{
+		Addr:        0x58,
+		Serial:      0x0000_0157_ACA2,
+		FeatureSet:  0x0022,
+		ECO2:        414,
+		TVOC:        13,
+		H2:          13836,
+		Ethanol:     17780,
+		Now:         time.Now,
+		co2Baseline: 0x88a1,
+		tvocBase:    0x8dc4,
+		inceptive:   0x8dc4,
+	}
+}
+
+var _ i2c.Bus = &Sim{}
+
+// String implements i2c.Bus
+func (s *Sim) String() string {
+	return "sgp30sim"
+}
+
+// SetSpeed implements i2c.Bus, the speed is ignored
+func (s *Sim) SetSpeed(f physic.Frequency) error {
+	return nil
+}
+
+// SetAirQuality sets the CO2 and TVOC values returned after warm-up
+func (s *Sim) SetAirQuality(eco2, tvoc uint16) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ECO2 = eco2
+	s.TVOC = tvoc
+}
+
+// Baseline returns the current CO2 and TVOC baseline values
+func (s *Sim) Baseline() (uint16, uint16) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.co2Baseline, s.tvocBase
+}
+
+// Tx implements i2c.Bus
+//
+// A command written without a read stores the response, which is returned by the
+// next read-only transaction. A command with a read returns the response immediately.
+func (s *Sim) Tx(addr uint16, w, r []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Soft reset using the General Call address
+	if addr == 0x00 {
+		if len(w) == 1 && w[0] == 0x06 {
+			s.Measuring = false
+			s.pending = nil
+			s.ResetCount++
+			return nil
+		}
+		return ErrNAK
+	}
+	if addr != s.Addr {
+		return ErrNAK
+	}
+
+	if len(w) == 0 {
+		return s.read(r)
+	}
+	if len(w) < 2 {
+		return ErrNAK
+	}
+
+	resp, err := s.command(uint16(w[0])<<8|uint16(w[1]), w[2:])
+	if err != nil {
+		return err
+	}
+	s.pending = resp
+	if len(r) > 0 {
+		return s.read(r)
+	}
+	return nil
+}
+
+// read returns the pending response
+func (s *Sim) read(r []byte) error {
+	if s.pending == nil || len(r) > len(s.pending) {
+		return ErrNAK
+	}
+	copy(r, s.pending)
+	s.pending = nil
+	if s.BadCRC > 0 {
+		s.BadCRC--
+		r[len(r)-1] ^= 0xFF
+	}
+	return nil
+}
+
+// command runs the command and returns the response, or nil if it has none
+func (s *Sim) command(cmd uint16, args []byte) ([]byte, error) {
+	switch cmd {
+	case 0x3682: // Get serial ID
+		return words(uint16(s.Serial>>32), uint16(s.Serial>>16), uint16(s.Serial)), nil
+	case 0x202f: // Get feature set
+		return words(s.FeatureSet), nil
+	case 0x2003: // Init air quality
+		s.Measuring = true
+		s.InitCount++
+		s.started = s.Now()
+		s.count = 0
+		return nil, nil
+	case 0x2008: // Measure air quality
+		// There are no results to read until the measurements are started
+		if !s.Measuring {
+			return nil, nil
+		}
+		s.drift()
+		if s.Now().Sub(s.started) < WarmupTime {
+			return words(400, 0), nil
+		}
+		return words(s.ECO2, s.TVOC), nil
+	case 0x2015: // Get baseline
+		return words(s.co2Baseline, s.tvocBase), nil
+	case 0x201e: // Set baseline, TVOC then CO2
+		w, err := argWords(args, 2)
+		if err != nil {
+			return nil, err
+		}
+		s.tvocBase, s.co2Baseline = w[0], w[1]
+		return nil, nil
+	case 0x2061: // Set humidity
+		w, err := argWords(args, 1)
+		if err != nil {
+			return nil, err
+		}
+		s.Humidity = w[0]
+		return nil, nil
+	case 0x2032: // Measure test
+		return words(0xD400), nil
+	case 0x2050: // Measure raw signals
+		return words(s.H2, s.Ethanol), nil
+	case 0x20b3: // Get TVOC inceptive baseline
+		return words(s.inceptive), nil
+	case 0x2077: // Set TVOC baseline
+		w, err := argWords(args, 1)
+		if err != nil {
+			return nil, err
+		}
+		s.tvocBase = w[0]
+		return nil, nil
+	}
+	return nil, fmt.Errorf("sgp30sim: unknown command 0x%04X: %w", cmd, ErrNAK)
+}
+
+// drift slowly increases the baseline as measurements are made
+func (s *Sim) drift() {
+	s.count++
+	if s.count%DriftInterval == 0 {
+		s.co2Baseline++
+		s.tvocBase++
+	}
+}
+
+// words returns the words with their CRC8
+func words(w ...uint16) []byte {
+	var data []byte
+	for _, v := range w {
+		data = append(data, sensirion.WordCRC(v)...)
+	}
+	return data
+}
+
+// argWords checks the CRC8 of the n argument words and returns them
+func argWords(args []byte, n int) ([]uint16, error) {
+	if len(args) != n*3 {
+		return nil, fmt.Errorf("sgp30sim: wrong argument length %d: %w", len(args), ErrNAK)
+	}
+	var w []uint16
+	for i := 0; i < len(args); i += 3 {
+		if !sensirion.CheckCRC8(args[i : i+3]) {
+			return nil, fmt.Errorf("sgp30sim: argument CRC8 failed on %v: %w", args[i:i+3], ErrNAK)
+		}
+		w = append(w, sensirion.Word(args, i))
+	}
+	return w, nil
+}
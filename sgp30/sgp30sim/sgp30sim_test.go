@@ -0,0 +1,158 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sgp30sim
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bcl/air-sensors/sgp30"
+)
+
+// fakeClock returns a time that is advanced by the test
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func TestWords(t *testing.T) {
+	// Datasheet example, 0xBEEF has a CRC of 0x92
+	data := words(0xBEEF)
+	if len(data) != 3 || data[0] != 0xBE || data[1] != 0xEF || data[2] != 0x92 {
+		t.Fatalf("words Error: %v", data)
+	}
+	w, err := argWords(data, 1)
+	if err != nil || w[0] != 0xBEEF {
+		t.Fatalf("argWords Error: %v %s", w, err)
+	}
+	data[2] = 0
+	if _, err := argWords(data, 1); err == nil {
+		t.Fatal("argWords bad CRC Error")
+	}
+}
+
+func TestSim(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	sim := New()
+	sim.Now = clock.Now
+
+	d, err := sgp30.New(sim)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	sn, err := d.GetSerialNumber()
+	if err != nil {
+		t.Fatalf("GetSerialNumber Error: %s", err)
+	}
+	if sn != 0x0157ACA2 {
+		t.Errorf("Wrong serial number: %X", sn)
+	}
+
+	// Measurements fail before they are started
+	if _, err := d.ReadAirQuality(); !errors.Is(err, sgp30.ErrNotReady) {
+		t.Fatalf("ReadAirQuality before start Error: %v", err)
+	}
+
+	if err := d.StartMeasurements(); err != nil {
+		t.Fatalf("StartMeasurements Error: %s", err)
+	}
+	r, err := d.ReadAirQuality()
+	if err != nil {
+		t.Fatalf("ReadAirQuality Error: %s", err)
+	}
+	if r.ECO2 != 400 || r.TVOC != 0 {
+		t.Errorf("Wrong warm-up reading: %v", r)
+	}
+
+	clock.now = clock.now.Add(WarmupTime)
+	sim.SetAirQuality(800, 50)
+	r, err = d.ReadAirQuality()
+	if err != nil {
+		t.Fatalf("ReadAirQuality Error: %s", err)
+	}
+	if r.ECO2 != 800 || r.TVOC != 50 {
+		t.Errorf("Wrong reading: %v", r)
+	}
+
+	// A bad CRC is detected by the driver
+	sim.BadCRC = 1
+	if _, err := d.ReadAirQuality(); !errors.Is(err, sgp30.ErrCRC) {
+		t.Fatalf("ReadAirQuality bad CRC Error: %v", err)
+	}
+}
+
+func TestSimBaseline(t *testing.T) {
+	sim := New()
+	d, err := sgp30.New(sim)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if err := d.StartMeasurements(); err != nil {
+		t.Fatalf("StartMeasurements Error: %s", err)
+	}
+
+	// The baseline drifts as measurements are made
+	for i := 0; i < DriftInterval; i++ {
+		if _, err := d.ReadAirQuality(); err != nil {
+			t.Fatalf("ReadAirQuality Error: %s", err)
+		}
+	}
+	co2, tvoc := sim.Baseline()
+	if co2 != 0x88a2 || tvoc != 0x8dc5 {
+		t.Fatalf("Baseline did not drift: %04X %04X", co2, tvoc)
+	}
+
+	// The baseline can be read and restored
+	baseline, err := d.ReadBaseline()
+	if err != nil {
+		t.Fatalf("ReadBaseline Error: %s", err)
+	}
+	if err := d.Reset(); err != nil {
+		t.Fatalf("Reset Error: %s", err)
+	}
+	if err := d.SetBaseline(baseline[:]); err != nil {
+		t.Fatalf("SetBaseline Error: %s", err)
+	}
+	if co2, tvoc = sim.Baseline(); co2 != 0x88a2 || tvoc != 0x8dc5 {
+		t.Fatalf("Baseline was not restored: %04X %04X", co2, tvoc)
+	}
+	if sim.ResetCount != 1 || sim.InitCount != 3 {
+		t.Fatalf("Wrong reset and init counts: %d %d", sim.ResetCount, sim.InitCount)
+	}
+}
+
+func TestSimCommands(t *testing.T) {
+	sim := New()
+	d, err := sgp30.New(sim)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if err := d.SelfTest(); err != nil {
+		t.Fatalf("SelfTest Error: %s", err)
+	}
+	h2, ethanol, err := d.ReadRawSignals()
+	if err != nil {
+		t.Fatalf("ReadRawSignals Error: %s", err)
+	}
+	if h2 != sim.H2 || ethanol != sim.Ethanol {
+		t.Errorf("Wrong raw signals: %d %d", h2, ethanol)
+	}
+	if err := d.SetHumidity(11.757); err != nil {
+		t.Fatalf("SetHumidity Error: %s", err)
+	}
+	if sim.Humidity != 0x0BC2 {
+		t.Errorf("Wrong humidity: 0x%04X", sim.Humidity)
+	}
+	if err := d.SetTVOCInceptiveBaseline(0x1234); err != nil {
+		t.Fatalf("SetTVOCInceptiveBaseline Error: %s", err)
+	}
+	if _, tvoc := sim.Baseline(); tvoc != 0x1234 {
+		t.Errorf("Wrong TVOC baseline: 0x%04X", tvoc)
+	}
+}
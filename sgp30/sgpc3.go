@@ -0,0 +1,119 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sgp30
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bcl/air-sensors/internal/sensirion"
+)
+
+// Product types from the upper 4 bits of the feature set
+const (
+	ProductSGP30 uint8 = 0
+	ProductSGPC3 uint8 = 1
+)
+
+// SGPC3 commands that differ from the SGP30, for use with Command
+const (
+	CmdSGPC3SetPowerMode uint16 = 0x209f // Set the power mode, 1 word
+)
+
+// PowerMode selects the SGPC3's measurement interval
+type PowerMode uint16
+
+const (
+	// UltraLowPower measures every 30s
+	UltraLowPower PowerMode = 0
+	// LowPower measures every 2s, the default
+	LowPower PowerMode = 1
+)
+
+// IsSGPC3 returns true if the sensor is an SGPC3, detected from its feature set
+//
+// The SGPC3 is an ultra-low power TVOC only version of the SGP30. It uses the same
+// protocol, but ReadAirQuality only returns TVOC, the baseline only has a TVOC
+// word, and it needs to be read every 2s, or every 30s in UltraLowPower mode.
+func (d *Dev) IsSGPC3() bool {
+	return d.productType>>4 == ProductSGPC3
+}
+
+// SetPowerMode sets the SGPC3's power mode, it needs to be called before starting
+// the measurements.
+func (d *Dev) SetPowerMode(mode PowerMode) error {
+	if !d.IsSGPC3() {
+		return &Error{Kind: ErrUnsupported, Msg: "sgp30: power mode is only supported by the SGPC3"}
+	}
+	data := append([]byte{0x20, 0x9f}, sensirion.WordCRC(uint16(mode))...)
+	if err := d.i2c.Tx(data, nil); err != nil {
+		return d.busError("sgp30: Error while setting power mode", err)
+	}
+	d.powerMode = mode
+	return nil
+}
+
+// MeasurementInterval returns how often ReadAirQuality needs to be called for the
+// sensor's dynamic baseline compensation to work. This is 1s for the SGP30, and 2s
+// or 30s for the SGPC3 depending on its power mode.
+func (d *Dev) MeasurementInterval() time.Duration {
+	switch {
+	case !d.IsSGPC3():
+		return time.Second
+	case d.powerMode == UltraLowPower:
+		return 30 * time.Second
+	}
+	return 2 * time.Second
+}
+
+// notSGPC3 returns ErrUnsupported if the sensor is an SGPC3
+func (d *Dev) notSGPC3(what string) error {
+	if d.IsSGPC3() {
+		return &Error{Kind: ErrUnsupported, Msg: fmt.Sprintf("sgp30: %s is not supported by the SGPC3", what)}
+	}
+	return nil
+}
+
+// readTVOC returns the SGPC3's TVOC reading
+func (d *Dev) readTVOC() (Reading, error) {
+	// Send a 0x2008
+	// Receive 1 word + 8 bit CRC
+	if err := d.i2c.Tx([]byte{0x20, 0x08}, nil); err != nil {
+		return Reading{}, d.busError("sgp30: Error while requesting TVOC", err)
+	}
+
+	// Requires a 50ms delay before reading results
+	time.Sleep(50 * time.Millisecond)
+	var data [3]byte
+	if err := d.i2c.Tx(nil, data[:]); err != nil {
+		return Reading{}, d.notReadyError("sgp30: Error while reading TVOC", err)
+	}
+
+	if !sensirion.CheckCRC8(data[0:3]) {
+		return Reading{}, d.crcError("read TVOC", data[0:3])
+	}
+
+	return Reading{
+		TVOC:      sensirion.Word(data[:], 0),
+		Timestamp: d.now(),
+	}, nil
+}
+
+// readTVOCBaseline returns the SGPC3's TVOC baseline in the same layout as the SGP30's
+// baseline, with a CO2 word of 0
+func (d *Dev) readTVOCBaseline() ([6]byte, error) {
+	// Send a 0x2015
+	// Receive 1 word + 8 bit CRC
+	var data [6]byte
+	if err := d.i2c.Tx([]byte{0x20, 0x15}, data[3:6]); err != nil {
+		return [6]byte{}, d.busError("sgp30: Error while reading baseline", err)
+	}
+
+	if !sensirion.CheckCRC8(data[3:6]) {
+		return [6]byte{}, d.crcError("baseline TVOC word", data[3:6])
+	}
+	copy(data[0:3], sensirion.WordCRC(0))
+	return data, nil
+}
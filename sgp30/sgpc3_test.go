@@ -0,0 +1,105 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sgp30
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"periph.io/x/periph/conn/i2c/i2ctest"
+)
+
+var (
+	SGPC3FeaturesData = []byte{0x10, 0x06, 0x49}
+	SGPC3TVOCData     = []byte{0x00, 0x14, 0x06}
+)
+
+func TestSGPC3(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Good serial number
+			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: SGPC3FeaturesData},
+			{Addr: 0x58, W: []byte{0x20, 0x9f, 0x00, 0x00, 0x81}, R: []byte{}},
+			{Addr: 0x58, W: []byte{0x20, 0x03}, R: []byte{}},
+			{Addr: 0x58, W: []byte{0x20, 0x08}, R: []byte{}},
+			{Addr: 0x58, W: []byte{}, R: SGPC3TVOCData},
+			{Addr: 0x58, W: []byte{0x20, 0x15}, R: GoodInceptiveData},
+			// The baseline only has the TVOC word
+			{Addr: 0x58, W: []byte{0x20, 0x03}, R: []byte{}},
+			{Addr: 0x58, W: append([]byte{0x20, 0x1e}, GoodInceptiveData...), R: []byte{}},
+		},
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("SGPC3 features Error: %s", err)
+	}
+	if !d.IsSGPC3() {
+		t.Fatal("SGPC3 not detected")
+	}
+	if d.MeasurementInterval() != 2*time.Second {
+		t.Errorf("Low power MeasurementInterval is wrong: %s", d.MeasurementInterval())
+	}
+	if err := d.SetPowerMode(UltraLowPower); err != nil {
+		t.Fatalf("SetPowerMode Error: %s", err)
+	}
+	if d.MeasurementInterval() != 30*time.Second {
+		t.Errorf("Ultra low power MeasurementInterval is wrong: %s", d.MeasurementInterval())
+	}
+	if err := d.StartMeasurements(); err != nil {
+		t.Fatalf("StartMeasurements Error: %s", err)
+	}
+
+	r, err := d.ReadAirQuality()
+	if err != nil {
+		t.Fatalf("Read TVOC Error: %s", err)
+	}
+	if r.TVOC != 20 || r.ECO2 != 0 {
+		t.Errorf("TVOC reading is wrong: %+v", r)
+	}
+
+	baseline, err := d.ReadBaseline()
+	if err != nil {
+		t.Fatalf("Read Baseline Error: %s", err)
+	}
+	if !bytes.Equal(baseline[3:6], GoodInceptiveData) {
+		t.Errorf("TVOC baseline is wrong: %v", baseline)
+	}
+	if err := d.SetBaseline(baseline[:]); err != nil {
+		t.Fatalf("Set Baseline Error: %s", err)
+	}
+
+	if _, _, err := d.ReadRawSignals(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Raw signals are not unsupported: %v", err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatalf("Playback Close Error: %s", err)
+	}
+}
+
+func TestPowerModeUnsupported(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Good serial number
+			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
+		},
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("Good serial number Error: %s", err)
+	}
+	if d.IsSGPC3() {
+		t.Fatal("SGP30 detected as an SGPC3")
+	}
+	if d.MeasurementInterval() != time.Second {
+		t.Errorf("MeasurementInterval is wrong: %s", d.MeasurementInterval())
+	}
+	if err := d.SetPowerMode(LowPower); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Power mode is not unsupported: %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sgp30
+
+import (
+	"sync/atomic"
+)
+
+// Stats holds counters of the driver's activity, for monitoring the health of the sensor
+//
+// It can be published with expvar, eg.
+//
+//	expvar.Publish("sgp30", expvar.Func(func() interface{} { return d.Stats() }))
+type Stats struct {
+	CRCErrors          uint64 `json:"crc_errors"`           // Data that failed the CRC8 check
+	BusErrors          uint64 `json:"bus_errors"`           // Failed I²C transactions, including not ready
+	NotReady           uint64 `json:"not_ready"`            // Measurement results that could not be read
+	Retries            uint64 `json:"retries"`              // Air quality reads that were retried
+	BaselineSaves      uint64 `json:"baseline_saves"`       // Baselines saved to the baseline store
+	BaselineSaveErrors uint64 `json:"baseline_save_errors"` // Baselines that failed to be read or saved
+	CadenceWarnings    uint64 `json:"cadence_warnings"`     // Readings made too early or too late
+}
+
+// counters holds the Stats counters, updated with atomic operations
+// It is allocated separately from Dev to keep the 64 bit counters aligned on 32 bit
+// platforms.
+type counters struct {
+	crcErrors          uint64
+	busErrors          uint64
+	notReady           uint64
+	retries            uint64
+	baselineSaves      uint64
+	baselineSaveErrors uint64
+	cadenceWarnings    uint64
+}
+
+// Stats returns a snapshot of the driver's counters
+func (d *Dev) Stats() Stats {
+	return Stats{
+		CRCErrors:          atomic.LoadUint64(&d.stats.crcErrors),
+		BusErrors:          atomic.LoadUint64(&d.stats.busErrors),
+		NotReady:           atomic.LoadUint64(&d.stats.notReady),
+		Retries:            atomic.LoadUint64(&d.stats.retries),
+		BaselineSaves:      atomic.LoadUint64(&d.stats.baselineSaves),
+		BaselineSaveErrors: atomic.LoadUint64(&d.stats.baselineSaveErrors),
+		CadenceWarnings:    atomic.LoadUint64(&d.stats.cadenceWarnings),
+	}
+}
+
+// busError counts the bus error and returns an ErrBusIO Error
+func (d *Dev) busError(msg string, err error) error {
+	atomic.AddUint64(&d.stats.busErrors, 1)
+	return busError(msg, err)
+}
+
+// notReadyError counts the bus error and returns an ErrNotReady Error
+func (d *Dev) notReadyError(msg string, err error) error {
+	atomic.AddUint64(&d.stats.busErrors, 1)
+	atomic.AddUint64(&d.stats.notReady, 1)
+	return notReadyError(msg, err)
+}
+
+// crcError counts the CRC failure and returns an ErrCRC Error
+func (d *Dev) crcError(what string, data []byte) error {
+	atomic.AddUint64(&d.stats.crcErrors, 1)
+	return crcError(what, data)
+}
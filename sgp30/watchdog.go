@@ -0,0 +1,67 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sgp30
+
+import (
+	"errors"
+
+	"periph.io/x/periph/conn/i2c"
+)
+
+// checkWatchdog counts consecutive I²C errors from ReadAirQuality, and tries to
+// recover the sensor when the watchdog limit is reached. err is nil after a good
+// reading.
+func (d *Dev) checkWatchdog(err error) {
+	if d.watchdog == 0 {
+		return
+	}
+	if err == nil || !errors.Is(err, ErrBusIO) {
+		d.busFailures = 0
+		return
+	}
+	d.busFailures++
+	if d.busFailures < d.watchdog {
+		return
+	}
+	d.busFailures = 0
+
+	d.logf("sgp30: %d consecutive I²C errors, trying to recover: %s", d.watchdog, err)
+	err = d.recover()
+	if err != nil {
+		d.logf("sgp30: recovery failed: %s", err)
+	}
+	d.event(Event{Kind: EventRecovery, Err: err})
+}
+
+// recover re-opens the bus if WithBusOpener was used, resets the sensor, and restarts
+// the measurements, restoring the baseline from the store
+func (d *Dev) recover() error {
+	if d.openBus != nil {
+		if err := d.reopenBus(); err != nil {
+			return err
+		}
+	}
+	if err := d.softReset(); err != nil {
+		return err
+	}
+	return d.initMeasurements()
+}
+
+// reopenBus replaces the bus with a new one from the bus opener, closing the previous
+// bus if it was also opened by the watchdog
+func (d *Dev) reopenBus() error {
+	if d.ownedBus != nil {
+		d.ownedBus.Close() //nolint
+		d.ownedBus = nil
+	}
+	bus, err := d.openBus()
+	if err != nil {
+		return err
+	}
+	d.ownedBus = bus
+	d.bus = bus
+	d.i2c = &i2c.Dev{Bus: bus, Addr: d.addr}
+	return nil
+}
@@ -0,0 +1,14 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package sgp41 controls a Sensirion SGP41 VOC and NOx sensor over I²C.
+//
+// The SGP41 returns raw VOC and NOx signals which are converted into the VOC Index
+// and NOx Index by a Go port of Sensirion's Gas Index Algorithm. The sensor needs to
+// be read every second for the algorithm to work.
+//
+// Datasheet
+//
+// https://sensirion.com/media/documents/5FE8673C/61E96F50/Sensirion_Gas_Sensors_Datasheet_SGP41.pdf
+package sgp41
@@ -0,0 +1,352 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sgp41
+
+import (
+	"math"
+)
+
+// GasIndexType selects the VOC or NOx Index algorithm
+type GasIndexType int
+
+const (
+	// VOC calculates the VOC Index, 100 is the average over the past 24 hours
+	VOC GasIndexType = iota
+	// NOx calculates the NOx Index, 1 is the average over the past 24 hours
+	NOx
+)
+
+// Gas Index Algorithm tuning, from Sensirion's reference implementation
+const (
+	samplingInterval = 1.0 // Seconds between samples
+
+	initialBlackout           = 45.0
+	indexGain                 = 230.0
+	srawStdInitial            = 50.0
+	srawStdBonusVOC           = 220.0
+	srawStdNOx                = 2000.0
+	tauMeanHours              = 12.0
+	tauVarianceHours          = 12.0
+	tauInitialMeanVOC         = 20.0
+	tauInitialMeanNOx         = 1200.0
+	initDurationMeanVOC       = 3600 * 0.75
+	initDurationMeanNOx       = 3600 * 4.75
+	initTransitionMean        = 0.01
+	tauInitialVariance        = 2500.0
+	initDurationVarianceVOC   = 3600 * 1.45
+	initDurationVarianceNOx   = 3600 * 5.70
+	initTransitionVariance    = 0.01
+	gatingThresholdVOC        = 340.0
+	gatingThresholdNOx        = 30.0
+	gatingThresholdInitial    = 510.0
+	gatingThresholdTransition = 0.09
+	gatingMaxDurationVOC      = 60 * 3.0
+	gatingMaxDurationNOx      = 60 * 12.0
+	gatingMaxRatio            = 0.3
+	sigmoidL                  = 500.0
+	sigmoidKVOC               = -0.0065
+	sigmoidX0VOC              = 213.0
+	sigmoidKNOx               = -0.0101
+	sigmoidX0NOx              = 614.0
+	indexOffsetVOC            = 100.0
+	indexOffsetNOx            = 1.0
+	lpTauFast                 = 20.0
+	lpTauSlow                 = 500.0
+	lpAlpha                   = -0.2
+	srawMinimumVOC            = 20000
+	srawMinimumNOx            = 10000
+	gammaScaling              = 64.0
+	additionalGammaScaling    = 8.0
+	fix16Max                  = 32767.0
+)
+
+// GasIndex converts the raw VOC or NOx signal into the VOC or NOx Index
+//
+// It is a port of Sensirion's Gas Index Algorithm. It learns the sensor's baseline
+// and its variation, and reports the current signal relative to it, so it needs to
+// be passed every reading, made at 1 second intervals.
+type GasIndex struct {
+	kind                 GasIndexType
+	indexOffset          float64
+	srawMinimum          int
+	gatingMaxDuration    float64
+	initDurationMean     float64
+	initDurationVariance float64
+	gatingThreshold      float64
+
+	uptime   float64
+	sraw     float64
+	gasIndex float64
+
+	// Mean and variance estimator
+	mveInitialized          bool
+	mveMean                 float64
+	mveSrawOffset           float64
+	mveStd                  float64
+	mveGammaMean            float64
+	mveGammaVariance        float64
+	mveGammaInitialMean     float64
+	mveGammaInitialVariance float64
+	mveCurGammaMean         float64
+	mveCurGammaVariance     float64
+	mveUptimeGamma          float64
+	mveUptimeGating         float64
+	mveGatingDuration       float64
+	mveSigmoidK             float64
+	mveSigmoidX0            float64
+
+	// MOX model
+	moxSrawStd  float64
+	moxSrawMean float64
+
+	// Sigmoid scaling
+	sigmoidK             float64
+	sigmoidX0            float64
+	sigmoidOffsetDefault float64
+
+	// Adaptive lowpass filter
+	lpA1, lpA2       float64
+	lpInitialized    bool
+	lpX1, lpX2, lpX3 float64
+}
+
+// NewGasIndex returns a GasIndex for the VOC or NOx signal
+func NewGasIndex(kind GasIndexType) *GasIndex {
+	g := &GasIndex{kind: kind}
+	if kind == NOx {
+		g.indexOffset = indexOffsetNOx
+		g.srawMinimum = srawMinimumNOx
+		g.gatingMaxDuration = gatingMaxDurationNOx
+		g.initDurationMean = initDurationMeanNOx
+		g.initDurationVariance = initDurationVarianceNOx
+		g.gatingThreshold = gatingThresholdNOx
+	} else {
+		g.indexOffset = indexOffsetVOC
+		g.srawMinimum = srawMinimumVOC
+		g.gatingMaxDuration = gatingMaxDurationVOC
+		g.initDurationMean = initDurationMeanVOC
+		g.initDurationVariance = initDurationVarianceVOC
+		g.gatingThreshold = gatingThresholdVOC
+	}
+	g.Reset()
+	return g
+}
+
+// Reset starts learning the baseline over again
+func (g *GasIndex) Reset() {
+	g.uptime = 0
+	g.sraw = 0
+	g.gasIndex = 0
+
+	g.mveSetParameters()
+	g.moxSetParameters(g.mveStd, g.mveMean+g.mveSrawOffset)
+	if g.kind == NOx {
+		g.sigmoidK, g.sigmoidX0, g.sigmoidOffsetDefault = sigmoidKNOx, sigmoidX0NOx, indexOffsetNOx
+	} else {
+		g.sigmoidK, g.sigmoidX0, g.sigmoidOffsetDefault = sigmoidKVOC, sigmoidX0VOC, indexOffsetVOC
+	}
+	g.lpA1 = samplingInterval / (lpTauFast + samplingInterval)
+	g.lpA2 = samplingInterval / (lpTauSlow + samplingInterval)
+	g.lpInitialized = false
+}
+
+// Process returns the gas index for the raw signal
+// It returns 0 during the first 45s blackout.
+func (g *GasIndex) Process(sraw uint16) int {
+	if g.uptime <= initialBlackout {
+		g.uptime += samplingInterval
+		return int(g.gasIndex + 0.5)
+	}
+
+	if sraw > 0 && sraw < 65000 {
+		s := int(sraw)
+		if s < g.srawMinimum+1 {
+			s = g.srawMinimum + 1
+		} else if s > g.srawMinimum+32767 {
+			s = g.srawMinimum + 32767
+		}
+		g.sraw = float64(s - g.srawMinimum)
+	}
+
+	if g.kind == VOC || g.mveInitialized {
+		g.gasIndex = g.sigmoidScaled(g.moxModel(g.sraw))
+	} else {
+		g.gasIndex = g.indexOffset
+	}
+	g.gasIndex = g.lowpass(g.gasIndex)
+	if g.gasIndex < 0.5 {
+		g.gasIndex = 0.5
+	}
+	if g.sraw > 0 {
+		g.mveProcess(g.sraw)
+		g.moxSetParameters(g.mveStd, g.mveMean+g.mveSrawOffset)
+	}
+	return int(g.gasIndex + 0.5)
+}
+
+// mveSetParameters initializes the mean and variance estimator
+func (g *GasIndex) mveSetParameters() {
+	g.mveInitialized = false
+	g.mveMean = 0
+	g.mveSrawOffset = 0
+	g.mveStd = srawStdInitial
+	g.mveGammaMean = (additionalGammaScaling * gammaScaling * (samplingInterval / 3600)) /
+		(tauMeanHours + (samplingInterval / 3600))
+	g.mveGammaVariance = (gammaScaling * (samplingInterval / 3600)) /
+		(tauVarianceHours + (samplingInterval / 3600))
+	tauInitialMean := tauInitialMeanVOC
+	if g.kind == NOx {
+		tauInitialMean = tauInitialMeanNOx
+	}
+	g.mveGammaInitialMean = (additionalGammaScaling * gammaScaling * samplingInterval) /
+		(tauInitialMean + samplingInterval)
+	g.mveGammaInitialVariance = (gammaScaling * samplingInterval) / (tauInitialVariance + samplingInterval)
+	g.mveCurGammaMean = 0
+	g.mveCurGammaVariance = 0
+	g.mveUptimeGamma = 0
+	g.mveUptimeGating = 0
+	g.mveGatingDuration = 0
+}
+
+// mveSigmoid returns the estimator's sigmoid of sample, using the parameters set
+// with mveSetSigmoid
+func (g *GasIndex) mveSigmoid(sample float64) float64 {
+	x := g.mveSigmoidK * (sample - g.mveSigmoidX0)
+	if x < -50 {
+		return 1
+	} else if x > 50 {
+		return 0
+	}
+	return 1 / (1 + math.Exp(x))
+}
+
+// mveSetSigmoid sets the estimator's sigmoid parameters
+func (g *GasIndex) mveSetSigmoid(x0, k float64) {
+	g.mveSigmoidX0 = x0
+	g.mveSigmoidK = k
+}
+
+// mveCalculateGamma updates the estimator's adaptation rates, gating the updates
+// while the gas index is high so that events are not learned as the baseline
+func (g *GasIndex) mveCalculateGamma() {
+	uptimeLimit := fix16Max - samplingInterval
+	if g.mveUptimeGamma < uptimeLimit {
+		g.mveUptimeGamma += samplingInterval
+	}
+	if g.mveUptimeGating < uptimeLimit {
+		g.mveUptimeGating += samplingInterval
+	}
+
+	g.mveSetSigmoid(g.initDurationMean, initTransitionMean)
+	sigmoidGammaMean := g.mveSigmoid(g.mveUptimeGamma)
+	gammaMean := g.mveGammaMean + (g.mveGammaInitialMean-g.mveGammaMean)*sigmoidGammaMean
+	gatingThresholdMean := g.gatingThreshold +
+		(gatingThresholdInitial-g.gatingThreshold)*g.mveSigmoid(g.mveUptimeGating)
+	g.mveSetSigmoid(gatingThresholdMean, gatingThresholdTransition)
+	sigmoidGatingMean := g.mveSigmoid(g.gasIndex)
+	g.mveCurGammaMean = sigmoidGatingMean * gammaMean
+
+	g.mveSetSigmoid(g.initDurationVariance, initTransitionVariance)
+	sigmoidGammaVariance := g.mveSigmoid(g.mveUptimeGamma)
+	gammaVariance := g.mveGammaVariance +
+		(g.mveGammaInitialVariance-g.mveGammaVariance)*(sigmoidGammaVariance-sigmoidGammaMean)
+	gatingThresholdVariance := g.gatingThreshold +
+		(gatingThresholdInitial-g.gatingThreshold)*g.mveSigmoid(g.mveUptimeGating)
+	g.mveSetSigmoid(gatingThresholdVariance, gatingThresholdTransition)
+	sigmoidGatingVariance := g.mveSigmoid(g.gasIndex)
+	g.mveCurGammaVariance = sigmoidGatingVariance * gammaVariance
+
+	g.mveGatingDuration += (samplingInterval / 60) *
+		((1-sigmoidGatingMean)*(1+gatingMaxRatio) - gatingMaxRatio)
+	if g.mveGatingDuration < 0 {
+		g.mveGatingDuration = 0
+	}
+	if g.mveGatingDuration > g.gatingMaxDuration {
+		g.mveUptimeGating = 0
+	}
+}
+
+// mveProcess updates the estimated mean and standard deviation of the signal
+func (g *GasIndex) mveProcess(sraw float64) {
+	if !g.mveInitialized {
+		g.mveInitialized = true
+		g.mveSrawOffset = sraw
+		g.mveMean = 0
+		return
+	}
+
+	if g.mveMean >= 100 || g.mveMean <= -100 {
+		g.mveSrawOffset += g.mveMean
+		g.mveMean = 0
+	}
+	sraw -= g.mveSrawOffset
+	g.mveCalculateGamma()
+	deltaSgp := (sraw - g.mveMean) / gammaScaling
+	var c float64
+	if deltaSgp < 0 {
+		c = g.mveStd - deltaSgp
+	} else {
+		c = g.mveStd + deltaSgp
+	}
+	additionalScaling := 1.0
+	if c > 1440 {
+		additionalScaling = (c / 1440) * (c / 1440)
+	}
+	g.mveStd = math.Sqrt(additionalScaling*(gammaScaling-g.mveCurGammaVariance)) *
+		math.Sqrt(g.mveStd*(g.mveStd/(gammaScaling*additionalScaling))+
+			((g.mveCurGammaVariance*deltaSgp)/additionalScaling)*deltaSgp)
+	g.mveMean += (g.mveCurGammaMean * deltaSgp) / additionalGammaScaling
+}
+
+// moxSetParameters sets the MOX model's baseline
+func (g *GasIndex) moxSetParameters(srawStd, srawMean float64) {
+	g.moxSrawStd = srawStd
+	g.moxSrawMean = srawMean
+}
+
+// moxModel returns the signal's deviation from the baseline
+func (g *GasIndex) moxModel(sraw float64) float64 {
+	if g.kind == NOx {
+		return ((sraw - g.moxSrawMean) / srawStdNOx) * indexGain
+	}
+	return ((sraw - g.moxSrawMean) / (-1 * (g.moxSrawStd + srawStdBonusVOC))) * indexGain
+}
+
+// sigmoidScaled maps the deviation onto the 1-500 index range
+func (g *GasIndex) sigmoidScaled(sample float64) float64 {
+	x := g.sigmoidK * (sample - g.sigmoidX0)
+	if x < -50 {
+		return sigmoidL
+	} else if x > 50 {
+		return 0
+	}
+
+	if sample >= 0 {
+		var shift float64
+		if g.sigmoidOffsetDefault == 1 {
+			shift = (500.0 / 499.0) * (1 - g.indexOffset)
+		} else {
+			shift = (sigmoidL - (5 * g.indexOffset)) / 4
+		}
+		return ((sigmoidL + shift) / (1 + math.Exp(x))) - shift
+	}
+	return (g.indexOffset / g.sigmoidOffsetDefault) * (sigmoidL / (1 + math.Exp(x)))
+}
+
+// lowpass smooths the index, reacting quickly to large changes
+func (g *GasIndex) lowpass(sample float64) float64 {
+	if !g.lpInitialized {
+		g.lpX1, g.lpX2, g.lpX3 = sample, sample, sample
+		g.lpInitialized = true
+	}
+	g.lpX1 = (1-g.lpA1)*g.lpX1 + g.lpA1*sample
+	g.lpX2 = (1-g.lpA2)*g.lpX2 + g.lpA2*sample
+	absDelta := math.Abs(g.lpX1 - g.lpX2)
+	f1 := math.Exp(lpAlpha * absDelta)
+	tauA := (lpTauSlow-lpTauFast)*f1 + lpTauFast
+	a3 := samplingInterval / (samplingInterval + tauA)
+	g.lpX3 = (1-a3)*g.lpX3 + a3*sample
+	return g.lpX3
+}
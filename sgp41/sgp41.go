@@ -0,0 +1,170 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sgp41
+
+import (
+	"fmt"
+	"time"
+
+	"periph.io/x/periph/conn"
+	"periph.io/x/periph/conn/i2c"
+	"periph.io/x/periph/conn/physic"
+
+	"github.com/bcl/air-sensors/internal/sensirion"
+)
+
+// DefaultAddr is the I²C address of the SGP41
+const DefaultAddr uint16 = 0x59
+
+// ConditioningTime is how long ReadAirQuality conditions the NOx pixel after New
+// The datasheet recommends 10s, and warns that more than 10s can damage the sensor.
+const ConditioningTime = 10 * time.Second
+
+// SelfTestOK is the result of a successful self test
+const SelfTestOK uint16 = 0xd400
+
+// SGP41 commands from the datasheet
+const (
+	cmdConditioning    uint16 = 0x2612 // Condition the NOx pixel, returns the VOC signal
+	cmdMeasureRaw      uint16 = 0x2619 // Returns the VOC and NOx signals
+	cmdSelfTest        uint16 = 0x280e // Run the self test
+	cmdTurnHeaterOff   uint16 = 0x3615 // Turn the hotplate off, and go idle
+	cmdGetSerialNumber uint16 = 0x3682 // Returns the 48 bit serial number
+)
+
+// Reading holds the air quality readings from the SGP41
+type Reading struct {
+	VOCIndex     int       `json:"voc_index"`              // VOC Index, 1-500, 100 is the average
+	NOxIndex     int       `json:"nox_index"`              // NOx Index, 1-500, 1 is the average
+	SrawVOC      uint16    `json:"sraw_voc"`               // Raw VOC signal in ticks
+	SrawNOx      uint16    `json:"sraw_nox"`               // Raw NOx signal in ticks, 0 while conditioning
+	Conditioning bool      `json:"conditioning,omitempty"` // Made while conditioning the NOx pixel
+	Timestamp    time.Time `json:"timestamp"`              // When the reading was made
+}
+
+// Dev holds the connection to the SGP41 and its gas index algorithms
+type Dev struct {
+	i2c     conn.Conn // i2c device handle for the sgp41
+	serial  uint64    // 48 bit serial number
+	started time.Time // When New was called, for the conditioning
+	voc     *GasIndex // VOC Index algorithm
+	nox     *GasIndex // NOx Index algorithm
+}
+
+var _ conn.Resource = &Dev{}
+
+// New returns a SGP41 device struct for communicating with the device
+//
+// ReadAirQuality needs to be called every second, the first ConditioningTime of
+// readings only have a VOC signal while the NOx pixel is conditioned.
+func New(i i2c.Bus) (*Dev, error) {
+	d := &Dev{
+		i2c:     &i2c.Dev{Bus: i, Addr: DefaultAddr},
+		started: time.Now(),
+		voc:     NewGasIndex(VOC),
+		nox:     NewGasIndex(NOx),
+	}
+
+	resp, err := d.command(cmdGetSerialNumber, time.Millisecond, 3)
+	if err != nil {
+		return nil, err
+	}
+	d.serial = uint64(resp[0])<<32 | uint64(resp[1])<<16 | uint64(resp[2])
+	return d, nil
+}
+
+// String implements conn.Resource.
+func (d *Dev) String() string {
+	return fmt.Sprintf("sgp41{%s}", d.i2c)
+}
+
+// Halt implements conn.Resource.
+//
+// It turns off the hotplate, the next reading turns it back on.
+func (d *Dev) Halt() error {
+	_, err := d.command(cmdTurnHeaterOff, 0, 0)
+	return err
+}
+
+// SerialNumber returns the 48 bit serial number of the sensor
+func (d *Dev) SerialNumber() uint64 {
+	return d.serial
+}
+
+// SelfTest runs the sensor's built in self test, it takes 320ms
+// It returns an error if the VOC or NOx pixel failed the test.
+func (d *Dev) SelfTest() error {
+	resp, err := d.command(cmdSelfTest, 320*time.Millisecond, 1)
+	if err != nil {
+		return err
+	}
+	if resp[0] != SelfTestOK {
+		return fmt.Errorf("sgp41: Self test failed: 0x%04X", resp[0])
+	}
+	return nil
+}
+
+// ReadAirQuality returns the VOC and NOx Index, and the raw signals they are
+// calculated from
+//
+// The temperature and humidity in env are used to compensate the raw signals, pass
+// nil to use the default of 25°C and 50%rH.
+//
+// It needs to be called every second for the gas index algorithms to work. The VOC
+// Index is 0 for the first 45s, as is the NOx Index which then stays at 1 until the
+// algorithm has learned the sensor's baseline.
+func (d *Dev) ReadAirQuality(env *physic.Env) (Reading, error) {
+	rh, t := compensationTicks(env)
+
+	var r Reading
+	if time.Since(d.started) < ConditioningTime {
+		resp, err := d.command(cmdConditioning, 50*time.Millisecond, 1, rh, t)
+		if err != nil {
+			return Reading{}, err
+		}
+		r.SrawVOC = resp[0]
+		r.Conditioning = true
+	} else {
+		resp, err := d.command(cmdMeasureRaw, 50*time.Millisecond, 2, rh, t)
+		if err != nil {
+			return Reading{}, err
+		}
+		r.SrawVOC = resp[0]
+		r.SrawNOx = resp[1]
+		r.NOxIndex = d.nox.Process(r.SrawNOx)
+	}
+	r.VOCIndex = d.voc.Process(r.SrawVOC)
+	r.Timestamp = time.Now()
+	return r, nil
+}
+
+// compensationTicks returns the humidity and temperature in the sensor's ticks
+func compensationTicks(env *physic.Env) (uint16, uint16) {
+	if env == nil {
+		// The datasheet's defaults, 50%rH and 25°C
+		return 0x8000, 0x6666
+	}
+
+	// RH ticks = RH * 65535 / 100, T ticks = (T + 45) * 65535 / 175
+	rh := int64(env.Humidity) * 65535 / int64(100*physic.PercentRH)
+	t := int64(env.Temperature-physic.ZeroCelsius+45*physic.Celsius) * 65535 / int64(175*physic.Celsius)
+	return clampTicks(rh), clampTicks(t)
+}
+
+// clampTicks limits the ticks to 0-65535
+func clampTicks(v int64) uint16 {
+	if v < 0 {
+		return 0
+	} else if v > 65535 {
+		return 65535
+	}
+	return uint16(v)
+}
+
+// command sends a command, with optional argument words, waits for it to execute,
+// and then returns the response words
+func (d *Dev) command(cmd uint16, wait time.Duration, respWords int, args ...uint16) ([]uint16, error) {
+	return sensirion.Command(d.i2c, "sgp41", cmd, wait, respWords, args...)
+}
@@ -0,0 +1,271 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sgp41
+
+import (
+	"testing"
+	"time"
+
+	"periph.io/x/periph/conn/i2c/i2ctest"
+	"periph.io/x/periph/conn/physic"
+)
+
+var (
+	GoodSerialNumber = []byte{0x00, 0x01, 0xb0, 0x02, 0x03, 0x0b, 0x04, 0x05, 0xf7}
+	BadSerialNumber  = []byte{0, 0, 0, 0, 0, 0, 0, 0, 0}
+	DefaultArgs      = []byte{0x80, 0x00, 0xa2, 0x66, 0x66, 0x93}
+	ConditioningData = []byte{0x69, 0x78, 0x56}
+	GoodRawData      = []byte{0x69, 0x78, 0x56, 0x3a, 0x98, 0x5d}
+	GoodSelfTestData = []byte{0xd4, 0x00, 0xc6}
+	FailSelfTestData = []byte{0x4b, 0x00, 0x12}
+)
+
+func TestGoodSerialNumber(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the serial number
+			{Addr: 0x59, W: []byte{0x36, 0x82}},
+			{Addr: 0x59, R: GoodSerialNumber},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if d.SerialNumber() != 0x000102030405 {
+		t.Fatalf("SerialNumber Error: 0x%X", d.SerialNumber())
+	}
+}
+
+func TestBadSerialNumber(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x59, W: []byte{0x36, 0x82}},
+			{Addr: 0x59, R: BadSerialNumber},
+		},
+	}
+	if _, err := New(&bus); err == nil {
+		t.Fatal("Bad serial number Error")
+	}
+}
+
+func TestFailSerialNumber(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops:       []i2ctest.IO{},
+		DontPanic: true,
+	}
+	if _, err := New(&bus); err == nil {
+		t.Fatal("Failed serial number Error")
+	}
+}
+
+func TestString(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the serial number
+			{Addr: 0x59, W: []byte{0x36, 0x82}},
+			{Addr: 0x59, R: GoodSerialNumber},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if d.String() != "sgp41{playback(89)}" {
+		t.Fatalf("String Error: %s", d.String())
+	}
+}
+
+func TestHalt(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the serial number
+			{Addr: 0x59, W: []byte{0x36, 0x82}},
+			{Addr: 0x59, R: GoodSerialNumber},
+			{Addr: 0x59, W: []byte{0x36, 0x15}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatalf("Halt Error: %s", err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatalf("Close Error: %s", err)
+	}
+}
+
+func TestSelfTest(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the serial number
+			{Addr: 0x59, W: []byte{0x36, 0x82}},
+			{Addr: 0x59, R: GoodSerialNumber},
+			{Addr: 0x59, W: []byte{0x28, 0x0e}},
+			{Addr: 0x59, R: GoodSelfTestData},
+			{Addr: 0x59, W: []byte{0x28, 0x0e}},
+			{Addr: 0x59, R: FailSelfTestData},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if err := d.SelfTest(); err != nil {
+		t.Fatalf("SelfTest Error: %s", err)
+	}
+	if err := d.SelfTest(); err == nil {
+		t.Fatal("Failed SelfTest Error")
+	}
+}
+
+func TestReadConditioning(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the serial number
+			{Addr: 0x59, W: []byte{0x36, 0x82}},
+			{Addr: 0x59, R: GoodSerialNumber},
+			{Addr: 0x59, W: append([]byte{0x26, 0x12}, DefaultArgs...)},
+			{Addr: 0x59, R: ConditioningData},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	r, err := d.ReadAirQuality(nil)
+	if err != nil {
+		t.Fatalf("ReadAirQuality Error: %s", err)
+	}
+	if !r.Conditioning || r.SrawVOC != 0x6978 || r.SrawNOx != 0 {
+		t.Fatalf("ReadAirQuality conditioning Error: %#v", r)
+	}
+	if r.VOCIndex != 0 || r.NOxIndex != 0 {
+		t.Fatalf("ReadAirQuality blackout Error: %#v", r)
+	}
+}
+
+func TestReadRaw(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the serial number
+			{Addr: 0x59, W: []byte{0x36, 0x82}},
+			{Addr: 0x59, R: GoodSerialNumber},
+			{Addr: 0x59, W: append([]byte{0x26, 0x19}, DefaultArgs...)},
+			{Addr: 0x59, R: GoodRawData},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	d.started = time.Now().Add(-ConditioningTime)
+	r, err := d.ReadAirQuality(nil)
+	if err != nil {
+		t.Fatalf("ReadAirQuality Error: %s", err)
+	}
+	if r.Conditioning || r.SrawVOC != 0x6978 || r.SrawNOx != 0x3a98 {
+		t.Fatalf("ReadAirQuality raw Error: %#v", r)
+	}
+}
+
+func TestReadBadCRC(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the serial number
+			{Addr: 0x59, W: []byte{0x36, 0x82}},
+			{Addr: 0x59, R: GoodSerialNumber},
+			{Addr: 0x59, W: append([]byte{0x26, 0x19}, DefaultArgs...)},
+			{Addr: 0x59, R: []byte{0x69, 0x78, 0x56, 0x3a, 0x98, 0x00}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	d.started = time.Now().Add(-ConditioningTime)
+	if _, err := d.ReadAirQuality(nil); err == nil {
+		t.Fatal("ReadAirQuality bad CRC Error")
+	}
+}
+
+func TestCompensationTicks(t *testing.T) {
+	tests := []struct {
+		env    *physic.Env
+		rh, tc uint16
+	}{
+		{nil, 0x8000, 0x6666},
+		{&physic.Env{Temperature: physic.ZeroCelsius + 25*physic.Celsius, Humidity: 50 * physic.PercentRH}, 0x7fff, 0x6666},
+		{&physic.Env{Temperature: physic.ZeroCelsius - 50*physic.Celsius, Humidity: 0}, 0, 0},
+		{&physic.Env{Temperature: physic.ZeroCelsius + 150*physic.Celsius, Humidity: 110 * physic.PercentRH}, 0xffff, 0xffff},
+	}
+	for _, tt := range tests {
+		rh, tc := compensationTicks(tt.env)
+		if rh != tt.rh || tc != tt.tc {
+			t.Errorf("compensationTicks(%v) Error: 0x%04X 0x%04X", tt.env, rh, tc)
+		}
+	}
+}
+
+func TestGasIndexBlackout(t *testing.T) {
+	for _, kind := range []GasIndexType{VOC, NOx} {
+		g := NewGasIndex(kind)
+		for i := 0; i < 45; i++ {
+			if idx := g.Process(27000); idx != 0 {
+				t.Fatalf("Process blackout Error: %d at %d", idx, i)
+			}
+		}
+	}
+}
+
+func TestGasIndexSteady(t *testing.T) {
+	tests := []struct {
+		kind  GasIndexType
+		sraw  uint16
+		index int
+	}{
+		{VOC, 27000, 100},
+		{NOx, 15000, 1},
+	}
+	for _, tt := range tests {
+		g := NewGasIndex(tt.kind)
+		var idx int
+		for i := 0; i < 600; i++ {
+			idx = g.Process(tt.sraw)
+		}
+		if idx != tt.index {
+			t.Errorf("Process steady state Error: %d != %d", idx, tt.index)
+		}
+	}
+}
+
+func TestGasIndexEvent(t *testing.T) {
+	g := NewGasIndex(VOC)
+	for i := 0; i < 600; i++ {
+		g.Process(27000)
+	}
+	// VOCs lower the raw signal and raise the index
+	var idx int
+	for i := 0; i < 60; i++ {
+		idx = g.Process(25000)
+	}
+	if idx <= 100 {
+		t.Fatalf("Process VOC event Error: %d", idx)
+	}
+
+	g.Reset()
+	if idx := g.Process(27000); idx != 0 {
+		t.Fatalf("Reset Error: %d", idx)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sht3x
+
+import (
+	"fmt"
+	"time"
+
+	"periph.io/x/periph/conn/physic"
+)
+
+// AlertLimit selects one of the 4 alert thresholds
+//
+// The ALERT pin goes high when the temperature or humidity rises above the high set
+// limit, or falls below the low set limit. It goes low again once it crosses the
+// matching clear limit.
+type AlertLimit int
+
+const (
+	// AlertHighSet raises the alert above it
+	AlertHighSet AlertLimit = iota
+	// AlertHighClear clears a high alert below it
+	AlertHighClear
+	// AlertLowClear clears a low alert above it
+	AlertLowClear
+	// AlertLowSet raises the alert below it
+	AlertLowSet
+)
+
+// Commands to read and write the alert limits, for each AlertLimit
+var (
+	readAlertCmds  = [...]uint16{0xe11f, 0xe114, 0xe109, 0xe102}
+	writeAlertCmds = [...]uint16{0x611d, 0x6116, 0x610b, 0x6100}
+)
+
+// SetAlertLimit sets the temperature and humidity of the alert limit
+//
+// The sensor only keeps the upper 7 bits of the humidity and the upper 9 bits of the
+// temperature, so the limits have a resolution of about 0.8%rH and 0.7°C.
+func (d *Dev) SetAlertLimit(limit AlertLimit, env physic.Env) error {
+	if limit < AlertHighSet || limit > AlertLowSet {
+		return fmt.Errorf("sht3x: Unknown alert limit: %d", limit)
+	}
+	_, err := d.command(writeAlertCmds[limit], time.Millisecond, 0, alertWord(env))
+	return err
+}
+
+// AlertLimit returns the temperature and humidity of the alert limit
+func (d *Dev) AlertLimit(limit AlertLimit) (physic.Env, error) {
+	if limit < AlertHighSet || limit > AlertLowSet {
+		return physic.Env{}, fmt.Errorf("sht3x: Unknown alert limit: %d", limit)
+	}
+	resp, err := d.command(readAlertCmds[limit], time.Millisecond, 1)
+	if err != nil {
+		return physic.Env{}, err
+	}
+	return alertEnv(resp[0]), nil
+}
+
+// alertWord packs the upper 7 bits of the humidity ticks and the upper 9 bits of the
+// temperature ticks into the alert limit word
+func alertWord(env physic.Env) uint16 {
+	rh := clampTicks(int64(env.Humidity) * 65535 / int64(100*physic.PercentRH))
+	t := clampTicks(int64(env.Temperature-physic.ZeroCelsius+45*physic.Celsius) * 65535 / int64(175*physic.Celsius))
+	return rh&0xfe00 | t>>7
+}
+
+// alertEnv unpacks the alert limit word
+func alertEnv(w uint16) physic.Env {
+	return convertEnv((w&0x01ff)<<7, w&0xfe00)
+}
+
+// clampTicks limits the ticks to 0-65535
+func clampTicks(v int64) uint16 {
+	if v < 0 {
+		return 0
+	} else if v > 65535 {
+		return 65535
+	}
+	return uint16(v)
+}
@@ -0,0 +1,15 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package sht3x controls a Sensirion SHT30, SHT31, or SHT35 temperature and humidity
+// sensor over I²C.
+//
+// Sense makes a single shot measurement, or StartPeriodic makes the sensor measure
+// continuously and ReadPeriodic returns the latest measurement. The readings can be
+// passed to the SGP30's CompensateFromEnv for its humidity compensation.
+//
+// Datasheet
+//
+// https://sensirion.com/media/documents/213E6A3B/63A5A569/Datasheet_SHT3x_DIS.pdf
+package sht3x
@@ -0,0 +1,86 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sht3x_test
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"periph.io/x/periph/conn/i2c/i2creg"
+	"periph.io/x/periph/conn/physic"
+	"periph.io/x/periph/host"
+
+	"github.com/bcl/air-sensors/sgp30"
+	"github.com/bcl/air-sensors/sht3x"
+)
+
+func Example() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Open a handle to the first available I²C bus:
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer bus.Close()
+
+	d, err := sht3x.New(bus)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var env physic.Env
+	if err := d.Sense(&env); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%8s %9s\n", env.Temperature, env.Humidity)
+}
+
+func Example_humidityCompensation() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Open a handle to the first available I²C bus:
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer bus.Close()
+
+	sht, err := sht3x.New(bus)
+	if err != nil {
+		log.Fatal(err)
+	}
+	sgp, err := sgp30.New(bus)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer sgp.Halt() //nolint
+	if err := sgp.StartMeasurements(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Compensate the SGP30's readings with the SHT3x's humidity
+	for range time.Tick(time.Second) {
+		var env physic.Env
+		if err := sht.Sense(&env); err != nil {
+			log.Fatal(err)
+		}
+		if err := sgp.CompensateFromEnv(env); err != nil {
+			log.Fatal(err)
+		}
+		aq, err := sgp.ReadAirQuality()
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("CO2 : %d ppm\nTVOC: %d ppb\n", aq.ECO2, aq.TVOC)
+	}
+}
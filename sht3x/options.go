@@ -0,0 +1,44 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sht3x
+
+const (
+	// DefaultAddr is the I²C address of the SHT3x with its ADDR pin low
+	DefaultAddr uint16 = 0x44
+
+	// AltAddr is the I²C address of the SHT3x with its ADDR pin high
+	AltAddr uint16 = 0x45
+)
+
+// Repeatability selects the measurement's repeatability, higher repeatability
+// takes longer and uses more power
+type Repeatability int
+
+const (
+	// RepeatabilityHigh takes up to 15.5ms, the default
+	RepeatabilityHigh Repeatability = iota
+	// RepeatabilityMedium takes up to 6.5ms
+	RepeatabilityMedium
+	// RepeatabilityLow takes up to 4.5ms
+	RepeatabilityLow
+)
+
+// Option configures the Dev returned by New
+type Option func(*Dev)
+
+// WithAddress sets the I²C address of the sensor, the default is DefaultAddr
+func WithAddress(addr uint16) Option {
+	return func(d *Dev) {
+		d.addr = addr
+	}
+}
+
+// WithRepeatability sets the repeatability of the measurements, the default is
+// RepeatabilityHigh.
+func WithRepeatability(r Repeatability) Option {
+	return func(d *Dev) {
+		d.repeatability = r
+	}
+}
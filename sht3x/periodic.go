@@ -0,0 +1,77 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sht3x
+
+import (
+	"fmt"
+	"time"
+
+	"periph.io/x/periph/conn/physic"
+)
+
+// Rate selects how many measurements per second the periodic mode makes
+type Rate int
+
+const (
+	// Rate05 measures every 2s
+	Rate05 Rate = iota
+	// Rate1 measures every second
+	Rate1
+	// Rate2 measures twice a second
+	Rate2
+	// Rate4 measures 4 times a second
+	Rate4
+	// Rate10 measures 10 times a second
+	Rate10
+	// RateART is the accelerated response time mode, measuring 4 times a second
+	RateART
+)
+
+// periodicCmds are the periodic measurement commands for each Rate and Repeatability
+var periodicCmds = [...][3]uint16{
+	{0x2032, 0x2024, 0x202f},
+	{0x2130, 0x2126, 0x212d},
+	{0x2236, 0x2220, 0x222b},
+	{0x2334, 0x2322, 0x2329},
+	{0x2737, 0x2721, 0x272a},
+	{cmdART, cmdART, cmdART},
+}
+
+// StartPeriodic starts measuring periodically at the rate, using the repeatability
+// set with WithRepeatability. Use ReadPeriodic to read the measurements.
+func (d *Dev) StartPeriodic(rate Rate) error {
+	if rate < Rate05 || rate > RateART {
+		return fmt.Errorf("sht3x: Unknown periodic rate: %d", rate)
+	}
+	if _, err := d.command(periodicCmds[rate][d.repeatability], 0, 0); err != nil {
+		return err
+	}
+	d.periodic = true
+	return nil
+}
+
+// StopPeriodic stops the periodic measurements
+func (d *Dev) StopPeriodic() error {
+	if _, err := d.command(cmdBreak, time.Millisecond, 0); err != nil {
+		return err
+	}
+	d.periodic = false
+	return nil
+}
+
+// ReadPeriodic returns the latest periodic measurement
+// The sensor does not acknowledge the read when there is no new measurement, which
+// is returned as an error.
+func (d *Dev) ReadPeriodic(env *physic.Env) error {
+	if !d.periodic {
+		return fmt.Errorf("sht3x: Periodic measurements are not running")
+	}
+	resp, err := d.command(cmdFetchData, 0, 2)
+	if err != nil {
+		return err
+	}
+	*env = convertEnv(resp[0], resp[1])
+	return nil
+}
@@ -0,0 +1,163 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sht3x
+
+import (
+	"fmt"
+	"time"
+
+	"periph.io/x/periph/conn"
+	"periph.io/x/periph/conn/i2c"
+	"periph.io/x/periph/conn/physic"
+
+	"github.com/bcl/air-sensors/internal/sensirion"
+)
+
+// SHT3x commands from the datasheet
+const (
+	cmdFetchData   uint16 = 0xe000 // Returns the latest periodic measurement
+	cmdART         uint16 = 0x2b32 // Start accelerated response time periodic measurements
+	cmdBreak       uint16 = 0x3093 // Stop the periodic measurements
+	cmdSoftReset   uint16 = 0x30a2 // Reset the sensor
+	cmdHeaterOn    uint16 = 0x306d // Turn the heater on
+	cmdHeaterOff   uint16 = 0x3066 // Turn the heater off
+	cmdStatus      uint16 = 0xf32d // Returns the status register
+	cmdClearStatus uint16 = 0x3041 // Clear the status register's alert flags
+)
+
+// singleShotCmds are the single shot measurement commands without clock stretching,
+// for each Repeatability
+var singleShotCmds = [...]uint16{0x2400, 0x240b, 0x2416}
+
+// measurementTimes are the maximum single shot measurement durations for each
+// Repeatability
+var measurementTimes = [...]time.Duration{
+	16 * time.Millisecond,
+	7 * time.Millisecond,
+	5 * time.Millisecond,
+}
+
+// Status register bits
+const (
+	StatusAlertPending   uint16 = 1 << 15 // At least one alert is pending
+	StatusHeaterOn       uint16 = 1 << 13 // The heater is on
+	StatusRHAlert        uint16 = 1 << 11 // Humidity alert
+	StatusTAlert         uint16 = 1 << 10 // Temperature alert
+	StatusReset          uint16 = 1 << 4  // Reset detected since the last ClearStatus
+	StatusCommandFailed  uint16 = 1 << 1  // The last command was not processed
+	StatusChecksumFailed uint16 = 1 << 0  // The last write's checksum failed
+)
+
+// Dev holds the connection to the SHT3x
+type Dev struct {
+	i2c           conn.Conn     // i2c device handle for the sht3x
+	addr          uint16        // I²C address of the sht3x
+	repeatability Repeatability // Repeatability of the measurements
+	periodic      bool          // Periodic measurements are running
+}
+
+var _ conn.Resource = &Dev{}
+
+// New returns a SHT3x device struct for communicating with the device
+// It reads the status register to make sure that the sensor is present.
+func New(i i2c.Bus, opts ...Option) (*Dev, error) {
+	d := &Dev{
+		addr:          DefaultAddr,
+		repeatability: RepeatabilityHigh,
+	}
+	for _, o := range opts {
+		o(d)
+	}
+	if d.repeatability < RepeatabilityHigh || d.repeatability > RepeatabilityLow {
+		return nil, fmt.Errorf("sht3x: Unknown repeatability: %d", d.repeatability)
+	}
+	d.i2c = &i2c.Dev{Bus: i, Addr: d.addr}
+
+	if _, err := d.Status(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// String implements conn.Resource.
+func (d *Dev) String() string {
+	return fmt.Sprintf("sht3x{%s}", d.i2c)
+}
+
+// Halt implements conn.Resource.
+//
+// It stops the periodic measurements.
+func (d *Dev) Halt() error {
+	if !d.periodic {
+		return nil
+	}
+	return d.StopPeriodic()
+}
+
+// Sense makes a single shot measurement of the temperature and relative humidity
+// It cannot be used while the periodic measurements are running.
+func (d *Dev) Sense(env *physic.Env) error {
+	if d.periodic {
+		return fmt.Errorf("sht3x: Single shot measurement while periodic measurements are running")
+	}
+	resp, err := d.command(singleShotCmds[d.repeatability], measurementTimes[d.repeatability], 2)
+	if err != nil {
+		return err
+	}
+	*env = convertEnv(resp[0], resp[1])
+	return nil
+}
+
+// convertEnv returns the temperature and humidity from the sensor's ticks
+func convertEnv(t, rh uint16) physic.Env {
+	// T = -45 + 175 * raw / (2^16 - 1), RH = 100 * raw / (2^16 - 1)
+	return physic.Env{
+		Temperature: physic.ZeroCelsius - 45*physic.Celsius + physic.Temperature(int64(t)*175000/65535)*physic.MilliCelsius,
+		Humidity:    physic.RelativeHumidity(int64(rh) * int64(100*physic.PercentRH) / 65535),
+	}
+}
+
+// SoftReset resets the sensor, stopping the periodic measurements and turning off
+// the heater
+func (d *Dev) SoftReset() error {
+	if _, err := d.command(cmdSoftReset, 2*time.Millisecond, 0); err != nil {
+		return err
+	}
+	d.periodic = false
+	return nil
+}
+
+// SetHeater turns the sensor's heater on or off
+// The heater is used to check the sensor's plausibility, it raises the temperature
+// by a few degrees.
+func (d *Dev) SetHeater(on bool) error {
+	cmd := cmdHeaterOff
+	if on {
+		cmd = cmdHeaterOn
+	}
+	_, err := d.command(cmd, time.Millisecond, 0)
+	return err
+}
+
+// Status returns the sensor's status register, see the Status constants for its bits
+func (d *Dev) Status() (uint16, error) {
+	resp, err := d.command(cmdStatus, time.Millisecond, 1)
+	if err != nil {
+		return 0, err
+	}
+	return resp[0], nil
+}
+
+// ClearStatus clears the status register's alert and reset flags
+func (d *Dev) ClearStatus() error {
+	_, err := d.command(cmdClearStatus, time.Millisecond, 0)
+	return err
+}
+
+// command sends a command, with optional argument words, waits for it to execute,
+// and then returns the response words
+func (d *Dev) command(cmd uint16, wait time.Duration, respWords int, args ...uint16) ([]uint16, error) {
+	return sensirion.Command(d.i2c, "sht3x", cmd, wait, respWords, args...)
+}
@@ -0,0 +1,271 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sht3x
+
+import (
+	"testing"
+
+	"periph.io/x/periph/conn/i2c/i2ctest"
+	"periph.io/x/periph/conn/physic"
+)
+
+var (
+	GoodStatusData      = []byte{0x80, 0x10, 0xe1}
+	BadStatusData       = []byte{0x80, 0x10, 0x00}
+	GoodMeasurementData = []byte{0x66, 0x67, 0xa2, 0x5e, 0xb9, 0x3c}
+	BadMeasurementData  = []byte{0x66, 0x67, 0xa2, 0x5e, 0xb9, 0x00}
+	GoodEnv             = physic.Env{
+		Temperature: physic.ZeroCelsius + 25002*physic.MilliCelsius,
+		Humidity:    3700160 * physic.TenthMicroRH,
+	}
+)
+
+func TestNew(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the status
+			{Addr: 0x44, W: []byte{0xf3, 0x2d}},
+			{Addr: 0x44, R: GoodStatusData},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if d.String() != "sht3x{playback(68)}" {
+		t.Fatalf("String Error: %s", d.String())
+	}
+}
+
+func TestNewAddress(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x45, W: []byte{0xf3, 0x2d}},
+			{Addr: 0x45, R: GoodStatusData},
+		},
+	}
+	if _, err := New(&bus, WithAddress(AltAddr)); err != nil {
+		t.Fatalf("New with address Error: %s", err)
+	}
+}
+
+func TestBadStatus(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x44, W: []byte{0xf3, 0x2d}},
+			{Addr: 0x44, R: BadStatusData},
+		},
+	}
+	if _, err := New(&bus); err == nil {
+		t.Fatal("Bad status Error")
+	}
+}
+
+func TestFailNew(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops:       []i2ctest.IO{},
+		DontPanic: true,
+	}
+	if _, err := New(&bus); err == nil {
+		t.Fatal("Failed New Error")
+	}
+	if _, err := New(&bus, WithRepeatability(Repeatability(3))); err == nil {
+		t.Fatal("Unknown repeatability Error")
+	}
+}
+
+func TestSense(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the status
+			{Addr: 0x44, W: []byte{0xf3, 0x2d}},
+			{Addr: 0x44, R: GoodStatusData},
+			{Addr: 0x44, W: []byte{0x24, 0x00}},
+			{Addr: 0x44, R: GoodMeasurementData},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	var env physic.Env
+	if err := d.Sense(&env); err != nil {
+		t.Fatalf("Sense Error: %s", err)
+	}
+	if env != GoodEnv {
+		t.Fatalf("Sense Error: %s %s", env.Temperature, env.Humidity)
+	}
+}
+
+func TestSenseRepeatability(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x44, W: []byte{0xf3, 0x2d}},
+			{Addr: 0x44, R: GoodStatusData},
+			{Addr: 0x44, W: []byte{0x24, 0x16}},
+			{Addr: 0x44, R: GoodMeasurementData},
+		},
+	}
+	d, err := New(&bus, WithRepeatability(RepeatabilityLow))
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	var env physic.Env
+	if err := d.Sense(&env); err != nil {
+		t.Fatalf("Sense Error: %s", err)
+	}
+}
+
+func TestSenseBadCRC(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the status
+			{Addr: 0x44, W: []byte{0xf3, 0x2d}},
+			{Addr: 0x44, R: GoodStatusData},
+			{Addr: 0x44, W: []byte{0x24, 0x00}},
+			{Addr: 0x44, R: BadMeasurementData},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	var env physic.Env
+	if err := d.Sense(&env); err == nil {
+		t.Fatal("Sense bad CRC Error")
+	}
+}
+
+func TestHeaterStatus(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the status
+			{Addr: 0x44, W: []byte{0xf3, 0x2d}},
+			{Addr: 0x44, R: GoodStatusData},
+			{Addr: 0x44, W: []byte{0x30, 0x6d}},
+			{Addr: 0x44, W: []byte{0xf3, 0x2d}},
+			{Addr: 0x44, R: []byte{0x20, 0x00, 0x5d}},
+			{Addr: 0x44, W: []byte{0x30, 0x66}},
+			{Addr: 0x44, W: []byte{0x30, 0x41}},
+			{Addr: 0x44, W: []byte{0x30, 0xa2}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if err := d.SetHeater(true); err != nil {
+		t.Fatalf("SetHeater Error: %s", err)
+	}
+	status, err := d.Status()
+	if err != nil {
+		t.Fatalf("Status Error: %s", err)
+	}
+	if status&StatusHeaterOn == 0 {
+		t.Errorf("Status heater Error: 0x%04X", status)
+	}
+	if err := d.SetHeater(false); err != nil {
+		t.Fatalf("SetHeater Error: %s", err)
+	}
+	if err := d.ClearStatus(); err != nil {
+		t.Fatalf("ClearStatus Error: %s", err)
+	}
+	if err := d.SoftReset(); err != nil {
+		t.Fatalf("SoftReset Error: %s", err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatalf("Close Error: %s", err)
+	}
+}
+
+func TestPeriodic(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the status
+			{Addr: 0x44, W: []byte{0xf3, 0x2d}},
+			{Addr: 0x44, R: GoodStatusData},
+			{Addr: 0x44, W: []byte{0x21, 0x30}},
+			{Addr: 0x44, W: []byte{0xe0, 0x00}},
+			{Addr: 0x44, R: GoodMeasurementData},
+			{Addr: 0x44, W: []byte{0x30, 0x93}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	var env physic.Env
+	if err := d.ReadPeriodic(&env); err == nil {
+		t.Fatal("ReadPeriodic not running Error")
+	}
+	if err := d.StartPeriodic(Rate(6)); err == nil {
+		t.Fatal("StartPeriodic unknown rate Error")
+	}
+	if err := d.StartPeriodic(Rate1); err != nil {
+		t.Fatalf("StartPeriodic Error: %s", err)
+	}
+	if err := d.Sense(&env); err == nil {
+		t.Fatal("Sense while periodic Error")
+	}
+	if err := d.ReadPeriodic(&env); err != nil {
+		t.Fatalf("ReadPeriodic Error: %s", err)
+	}
+	if env != GoodEnv {
+		t.Fatalf("ReadPeriodic Error: %s %s", env.Temperature, env.Humidity)
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatalf("Halt Error: %s", err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatalf("Close Error: %s", err)
+	}
+}
+
+func TestAlertLimit(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the status
+			{Addr: 0x44, W: []byte{0xf3, 0x2d}},
+			{Addr: 0x44, R: GoodStatusData},
+			{Addr: 0x44, W: []byte{0x61, 0x1d, 0xcd, 0x33, 0xfd}},
+			{Addr: 0x44, W: []byte{0xe1, 0x1f}},
+			{Addr: 0x44, R: []byte{0xcd, 0x33, 0xfd}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	limit := physic.Env{
+		Temperature: physic.ZeroCelsius + 60*physic.Celsius,
+		Humidity:    80 * physic.PercentRH,
+	}
+	if err := d.SetAlertLimit(AlertHighSet, limit); err != nil {
+		t.Fatalf("SetAlertLimit Error: %s", err)
+	}
+	env, err := d.AlertLimit(AlertHighSet)
+	if err != nil {
+		t.Fatalf("AlertLimit Error: %s", err)
+	}
+	// The limits only have 7 and 9 bits of resolution
+	if env.Temperature < limit.Temperature-physic.Celsius || env.Temperature > limit.Temperature {
+		t.Errorf("AlertLimit temperature Error: %s", env.Temperature)
+	}
+	if env.Humidity < limit.Humidity-physic.PercentRH || env.Humidity > limit.Humidity {
+		t.Errorf("AlertLimit humidity Error: %s", env.Humidity)
+	}
+	if err := d.SetAlertLimit(AlertLimit(4), limit); err == nil {
+		t.Error("SetAlertLimit unknown limit Error")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatalf("Close Error: %s", err)
+	}
+}
@@ -0,0 +1,15 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package sht4x controls a Sensirion SHT40, SHT41, or SHT45 temperature and humidity
+// sensor over I²C.
+//
+// Sense measures the temperature and relative humidity using the precision set with
+// WithPrecision. HeaterPulse runs the built in heater, for removing condensation or
+// creep in very humid conditions, and returns the measurement made at the end of it.
+//
+// Datasheet
+//
+// https://sensirion.com/media/documents/33FD6951/6555C40E/Sensirion_Datasheet_SHT4x.pdf
+package sht4x
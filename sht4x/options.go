@@ -0,0 +1,39 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sht4x
+
+// DefaultAddr is the I²C address of the SHT4x-AD1B, the -BD1B uses 0x45 and the
+// -CD1B uses 0x46
+const DefaultAddr uint16 = 0x44
+
+// Precision selects the measurement's repeatability, higher precision takes longer
+// and uses more power
+type Precision int
+
+const (
+	// PrecisionHigh takes up to 8.3ms, the default
+	PrecisionHigh Precision = iota
+	// PrecisionMedium takes up to 4.5ms
+	PrecisionMedium
+	// PrecisionLow takes up to 1.6ms
+	PrecisionLow
+)
+
+// Option configures the Dev returned by New
+type Option func(*Dev)
+
+// WithAddress sets the I²C address of the sensor, the default is DefaultAddr
+func WithAddress(addr uint16) Option {
+	return func(d *Dev) {
+		d.addr = addr
+	}
+}
+
+// WithPrecision sets the precision of the measurements, the default is PrecisionHigh
+func WithPrecision(p Precision) Option {
+	return func(d *Dev) {
+		d.precision = p
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sht4x
+
+import (
+	"fmt"
+	"time"
+
+	"periph.io/x/periph/conn"
+	"periph.io/x/periph/conn/i2c"
+	"periph.io/x/periph/conn/physic"
+
+	"github.com/bcl/air-sensors/internal/sensirion"
+)
+
+// SHT4x commands from the datasheet
+const (
+	cmdSerialNumber uint8 = 0x89 // Returns the 32 bit serial number
+	cmdSoftReset    uint8 = 0x94 // Reset the sensor
+)
+
+// measureCmds are the measurement commands for each Precision
+var measureCmds = [...]uint8{0xfd, 0xf6, 0xe0}
+
+// measureTimes are the maximum measurement durations for each Precision
+var measureTimes = [...]time.Duration{
+	9 * time.Millisecond,
+	5 * time.Millisecond,
+	2 * time.Millisecond,
+}
+
+// Heater selects the power and duration of a heater pulse
+type Heater int
+
+const (
+	// Heater200mW1s heats at 200mW for 1s
+	Heater200mW1s Heater = iota
+	// Heater200mW100ms heats at 200mW for 0.1s
+	Heater200mW100ms
+	// Heater110mW1s heats at 110mW for 1s
+	Heater110mW1s
+	// Heater110mW100ms heats at 110mW for 0.1s
+	Heater110mW100ms
+	// Heater20mW1s heats at 20mW for 1s
+	Heater20mW1s
+	// Heater20mW100ms heats at 20mW for 0.1s
+	Heater20mW100ms
+)
+
+// heaterCmds are the heater commands for each Heater
+var heaterCmds = [...]uint8{0x39, 0x32, 0x2f, 0x24, 0x1e, 0x15}
+
+// heaterTimes are the maximum durations of each Heater, including the measurement
+var heaterTimes = [...]time.Duration{
+	1100 * time.Millisecond,
+	110 * time.Millisecond,
+	1100 * time.Millisecond,
+	110 * time.Millisecond,
+	1100 * time.Millisecond,
+	110 * time.Millisecond,
+}
+
+// Dev holds the connection to the SHT4x
+type Dev struct {
+	i2c       conn.Conn // i2c device handle for the sht4x
+	addr      uint16    // I²C address of the sht4x
+	precision Precision // Precision of the measurements
+	serial    uint32    // 32 bit serial number
+}
+
+var _ conn.Resource = &Dev{}
+
+// New returns a SHT4x device struct for communicating with the device
+// It reads the serial number to make sure that the sensor is present.
+func New(i i2c.Bus, opts ...Option) (*Dev, error) {
+	d := &Dev{
+		addr:      DefaultAddr,
+		precision: PrecisionHigh,
+	}
+	for _, o := range opts {
+		o(d)
+	}
+	if d.precision < PrecisionHigh || d.precision > PrecisionLow {
+		return nil, fmt.Errorf("sht4x: Unknown precision: %d", d.precision)
+	}
+	d.i2c = &i2c.Dev{Bus: i, Addr: d.addr}
+
+	resp, err := sensirion.Command8(d.i2c, "sht4x", cmdSerialNumber, time.Millisecond, 2)
+	if err != nil {
+		return nil, err
+	}
+	d.serial = uint32(resp[0])<<16 | uint32(resp[1])
+	return d, nil
+}
+
+// String implements conn.Resource.
+func (d *Dev) String() string {
+	return fmt.Sprintf("sht4x{%s}", d.i2c)
+}
+
+// Halt implements conn.Resource.
+//
+// The SHT4x idles between measurements, so there is nothing to halt.
+func (d *Dev) Halt() error {
+	return nil
+}
+
+// SerialNumber returns the 32 bit serial number of the sensor
+func (d *Dev) SerialNumber() uint32 {
+	return d.serial
+}
+
+// Sense measures the temperature and relative humidity
+func (d *Dev) Sense(env *physic.Env) error {
+	resp, err := sensirion.Command8(d.i2c, "sht4x", measureCmds[d.precision], measureTimes[d.precision], 2)
+	if err != nil {
+		return err
+	}
+	*env = convertEnv(resp[0], resp[1])
+	return nil
+}
+
+// HeaterPulse runs the heater, and then returns a high precision measurement made at
+// the end of the pulse. The temperature is raised by the heater.
+//
+// The heater is meant to be used at no more than a 10% duty cycle, eg. a 1s pulse no
+// more than every 10s.
+func (d *Dev) HeaterPulse(h Heater, env *physic.Env) error {
+	if h < Heater200mW1s || h > Heater20mW100ms {
+		return fmt.Errorf("sht4x: Unknown heater pulse: %d", h)
+	}
+	resp, err := sensirion.Command8(d.i2c, "sht4x", heaterCmds[h], heaterTimes[h], 2)
+	if err != nil {
+		return err
+	}
+	*env = convertEnv(resp[0], resp[1])
+	return nil
+}
+
+// SoftReset resets the sensor
+func (d *Dev) SoftReset() error {
+	_, err := sensirion.Command8(d.i2c, "sht4x", cmdSoftReset, time.Millisecond, 0)
+	return err
+}
+
+// convertEnv returns the temperature and humidity from the sensor's ticks
+func convertEnv(t, rh uint16) physic.Env {
+	// T = -45 + 175 * raw / (2^16 - 1), RH = -6 + 125 * raw / (2^16 - 1)
+	// The humidity can be outside 0-100%rH, which is clamped as the datasheet suggests.
+	h := int64(rh)*int64(125*physic.PercentRH)/65535 - int64(6*physic.PercentRH)
+	if h < 0 {
+		h = 0
+	} else if h > int64(100*physic.PercentRH) {
+		h = int64(100 * physic.PercentRH)
+	}
+	return physic.Env{
+		Temperature: physic.ZeroCelsius - 45*physic.Celsius + physic.Temperature(int64(t)*175000/65535)*physic.MilliCelsius,
+		Humidity:    physic.RelativeHumidity(h),
+	}
+}
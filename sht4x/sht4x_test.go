@@ -0,0 +1,153 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sht4x
+
+import (
+	"testing"
+
+	"periph.io/x/periph/conn/i2c/i2ctest"
+	"periph.io/x/periph/conn/physic"
+)
+
+var (
+	GoodSerialNumber    = []byte{0x12, 0x34, 0x37, 0x56, 0x78, 0x7d}
+	BadSerialNumber     = []byte{0x12, 0x34, 0x37, 0x56, 0x78, 0x00}
+	GoodMeasurementData = []byte{0x66, 0x67, 0xa2, 0x5e, 0xb9, 0x3c}
+	BadMeasurementData  = []byte{0x66, 0x67, 0xa2, 0x5e, 0xb9, 0x00}
+	GoodEnv             = physic.Env{
+		Temperature: physic.ZeroCelsius + 25002*physic.MilliCelsius,
+		Humidity:    4025200 * physic.TenthMicroRH,
+	}
+)
+
+func TestNew(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the serial number
+			{Addr: 0x44, W: []byte{0x89}},
+			{Addr: 0x44, R: GoodSerialNumber},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if d.SerialNumber() != 0x12345678 {
+		t.Fatalf("SerialNumber Error: 0x%X", d.SerialNumber())
+	}
+	if d.String() != "sht4x{playback(68)}" {
+		t.Fatalf("String Error: %s", d.String())
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatalf("Halt Error: %s", err)
+	}
+}
+
+func TestBadSerialNumber(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x44, W: []byte{0x89}},
+			{Addr: 0x44, R: BadSerialNumber},
+		},
+	}
+	if _, err := New(&bus); err == nil {
+		t.Fatal("Bad serial number Error")
+	}
+}
+
+func TestNewOptions(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x46, W: []byte{0x89}},
+			{Addr: 0x46, R: GoodSerialNumber},
+			{Addr: 0x46, W: []byte{0xe0}},
+			{Addr: 0x46, R: GoodMeasurementData},
+		},
+	}
+	if _, err := New(&bus, WithPrecision(Precision(3))); err == nil {
+		t.Fatal("Unknown precision Error")
+	}
+	d, err := New(&bus, WithAddress(0x46), WithPrecision(PrecisionLow))
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	var env physic.Env
+	if err := d.Sense(&env); err != nil {
+		t.Fatalf("Sense Error: %s", err)
+	}
+}
+
+func TestSense(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the serial number
+			{Addr: 0x44, W: []byte{0x89}},
+			{Addr: 0x44, R: GoodSerialNumber},
+			{Addr: 0x44, W: []byte{0xfd}},
+			{Addr: 0x44, R: GoodMeasurementData},
+			{Addr: 0x44, W: []byte{0xfd}},
+			{Addr: 0x44, R: BadMeasurementData},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	var env physic.Env
+	if err := d.Sense(&env); err != nil {
+		t.Fatalf("Sense Error: %s", err)
+	}
+	if env != GoodEnv {
+		t.Fatalf("Sense Error: %s %s", env.Temperature, env.Humidity)
+	}
+	if err := d.Sense(&env); err == nil {
+		t.Fatal("Sense bad CRC Error")
+	}
+}
+
+func TestConvertHumidityClamp(t *testing.T) {
+	if env := convertEnv(0, 0); env.Humidity != 0 {
+		t.Errorf("convertEnv low humidity Error: %s", env.Humidity)
+	}
+	if env := convertEnv(0, 0xffff); env.Humidity != 100*physic.PercentRH {
+		t.Errorf("convertEnv high humidity Error: %s", env.Humidity)
+	}
+}
+
+func TestHeaterPulse(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the serial number
+			{Addr: 0x44, W: []byte{0x89}},
+			{Addr: 0x44, R: GoodSerialNumber},
+			{Addr: 0x44, W: []byte{0x15}},
+			{Addr: 0x44, R: GoodMeasurementData},
+			{Addr: 0x44, W: []byte{0x94}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	var env physic.Env
+	if err := d.HeaterPulse(Heater(6), &env); err == nil {
+		t.Fatal("HeaterPulse unknown heater Error")
+	}
+	if err := d.HeaterPulse(Heater20mW100ms, &env); err != nil {
+		t.Fatalf("HeaterPulse Error: %s", err)
+	}
+	if env != GoodEnv {
+		t.Fatalf("HeaterPulse Error: %s %s", env.Temperature, env.Humidity)
+	}
+	if err := d.SoftReset(); err != nil {
+		t.Fatalf("SoftReset Error: %s", err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatalf("Close Error: %s", err)
+	}
+}
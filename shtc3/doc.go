@@ -0,0 +1,14 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package shtc3 controls a Sensirion SHTC3 temperature and humidity sensor over I²C.
+//
+// By default the measurements are read by polling, without clock stretching, which
+// works on buses that do not support it, like the Raspberry Pi's. The sensor can be
+// put to sleep between measurements to save power.
+//
+// Datasheet
+//
+// https://sensirion.com/media/documents/643F9C8E/63A5A436/Datasheet_SHTC3.pdf
+package shtc3
@@ -0,0 +1,27 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package shtc3
+
+// DefaultAddr is the I²C address of the SHTC3
+const DefaultAddr uint16 = 0x70
+
+// Option configures the Dev returned by New
+type Option func(*Dev)
+
+// WithClockStretching reads the measurement in the same transaction as the command,
+// with the sensor stretching the clock until it is ready, instead of polling.
+func WithClockStretching() Option {
+	return func(d *Dev) {
+		d.stretch = true
+	}
+}
+
+// WithLowPower uses the low power measurement mode, which takes 0.8ms instead of
+// 12.1ms but is less repeatable.
+func WithLowPower() Option {
+	return func(d *Dev) {
+		d.lowPower = true
+	}
+}
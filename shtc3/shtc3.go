@@ -0,0 +1,168 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package shtc3
+
+import (
+	"fmt"
+	"time"
+
+	"periph.io/x/periph/conn"
+	"periph.io/x/periph/conn/i2c"
+	"periph.io/x/periph/conn/physic"
+
+	"github.com/bcl/air-sensors/internal/sensirion"
+)
+
+// SHTC3 commands from the datasheet
+const (
+	cmdSleep     uint16 = 0xb098 // Put the sensor to sleep
+	cmdWakeup    uint16 = 0x3517 // Wake the sensor up
+	cmdReadID    uint16 = 0xefc8 // Returns the ID register
+	cmdSoftReset uint16 = 0x805d // Reset the sensor
+)
+
+// measureCmds are the temperature first measurement commands, indexed by low power
+// and clock stretching
+var measureCmds = [2][2]uint16{
+	{0x7866, 0x7ca2},
+	{0x609c, 0x6458},
+}
+
+// measureTimes are the maximum measurement durations, normal and low power
+var measureTimes = [2]time.Duration{
+	13 * time.Millisecond,
+	time.Millisecond,
+}
+
+// Dev holds the connection to the SHTC3
+type Dev struct {
+	i2c      conn.Conn // i2c device handle for the shtc3
+	stretch  bool      // Read the measurement using clock stretching
+	lowPower bool      // Use the low power measurement mode
+	asleep   bool      // Put to sleep with Sleep
+	id       uint16    // ID register
+}
+
+var _ conn.Resource = &Dev{}
+
+// New returns a SHTC3 device struct for communicating with the device
+// It wakes the sensor up, and reads its ID register to make sure that it is a SHTC3.
+func New(i i2c.Bus, opts ...Option) (*Dev, error) {
+	d := &Dev{
+		i2c: &i2c.Dev{Bus: i, Addr: DefaultAddr},
+	}
+	for _, o := range opts {
+		o(d)
+	}
+
+	if err := d.Wakeup(); err != nil {
+		return nil, err
+	}
+	resp, err := sensirion.Command(d.i2c, "shtc3", cmdReadID, time.Millisecond, 1)
+	if err != nil {
+		return nil, err
+	}
+	// Bits 11 and 5:0 identify the SHTC3
+	if resp[0]&0x083f != 0x0807 {
+		return nil, fmt.Errorf("shtc3: SHTC3 not found, ID is 0x%04X", resp[0])
+	}
+	d.id = resp[0]
+	return d, nil
+}
+
+// String implements conn.Resource.
+func (d *Dev) String() string {
+	return fmt.Sprintf("shtc3{%s}", d.i2c)
+}
+
+// Halt implements conn.Resource.
+//
+// It puts the sensor to sleep.
+func (d *Dev) Halt() error {
+	return d.Sleep()
+}
+
+// ID returns the sensor's ID register
+func (d *Dev) ID() uint16 {
+	return d.id
+}
+
+// Sleep puts the sensor to sleep, Sense wakes it up for each measurement
+func (d *Dev) Sleep() error {
+	if _, err := sensirion.Command(d.i2c, "shtc3", cmdSleep, 0, 0); err != nil {
+		return err
+	}
+	d.asleep = true
+	return nil
+}
+
+// Wakeup wakes the sensor up after Sleep, it takes 240µs
+func (d *Dev) Wakeup() error {
+	if _, err := sensirion.Command(d.i2c, "shtc3", cmdWakeup, time.Millisecond, 0); err != nil {
+		return err
+	}
+	d.asleep = false
+	return nil
+}
+
+// SoftReset resets the sensor
+func (d *Dev) SoftReset() error {
+	_, err := sensirion.Command(d.i2c, "shtc3", cmdSoftReset, time.Millisecond, 0)
+	return err
+}
+
+// Sense measures the temperature and relative humidity
+// If the sensor is asleep it is woken up for the measurement, and put back to sleep.
+func (d *Dev) Sense(env *physic.Env) error {
+	if d.asleep {
+		if err := d.Wakeup(); err != nil {
+			return err
+		}
+		defer d.Sleep() //nolint
+	}
+
+	lp, cs := 0, 0
+	if d.lowPower {
+		lp = 1
+	}
+	if d.stretch {
+		cs = 1
+	}
+	cmd := measureCmds[lp][cs]
+
+	var resp []uint16
+	var err error
+	if d.stretch {
+		resp, err = d.readStretched(cmd)
+	} else {
+		resp, err = sensirion.Command(d.i2c, "shtc3", cmd, measureTimes[lp], 2)
+	}
+	if err != nil {
+		return err
+	}
+
+	// T = -45 + 175 * raw / 2^16, RH = 100 * raw / 2^16
+	t := int64(resp[0])
+	env.Temperature = physic.ZeroCelsius - 45*physic.Celsius + physic.Temperature(t*175000/65536)*physic.MilliCelsius
+	rh := int64(resp[1])
+	env.Humidity = physic.RelativeHumidity(rh * int64(100*physic.PercentRH) / 65536)
+	return nil
+}
+
+// readStretched sends the measurement command and reads the 2 response words in the
+// same transaction, the sensor stretches the clock until the measurement is ready
+func (d *Dev) readStretched(cmd uint16) ([]uint16, error) {
+	var data [6]byte
+	if err := d.i2c.Tx(sensirion.Encode(cmd), data[:]); err != nil {
+		return nil, fmt.Errorf("shtc3: Error while reading measurement: %w", err)
+	}
+	if !sensirion.CheckCRC8(data[0:3]) {
+		return nil, fmt.Errorf("shtc3: Temperature CRC8 failed on: %v", data[0:3])
+	}
+	if !sensirion.CheckCRC8(data[3:6]) {
+		return nil, fmt.Errorf("shtc3: Humidity CRC8 failed on: %v", data[3:6])
+	}
+	return []uint16{sensirion.Word(data[:], 0), sensirion.Word(data[:], 3)}, nil
+}
@@ -0,0 +1,173 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package shtc3
+
+import (
+	"testing"
+
+	"periph.io/x/periph/conn/i2c/i2ctest"
+	"periph.io/x/periph/conn/physic"
+)
+
+var (
+	GoodIDData          = []byte{0x08, 0x87, 0x5b}
+	WrongIDData         = []byte{0x12, 0x34, 0x37}
+	GoodMeasurementData = []byte{0x66, 0x67, 0xa2, 0x5e, 0xb9, 0x3c}
+	BadMeasurementData  = []byte{0x66, 0x67, 0xa2, 0x5e, 0xb9, 0x00}
+	GoodEnv             = physic.Env{
+		Temperature: physic.ZeroCelsius + 25001*physic.MilliCelsius,
+		Humidity:    3700103 * physic.TenthMicroRH,
+	}
+)
+
+func TestNew(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Wake it and read the ID
+			{Addr: 0x70, W: []byte{0x35, 0x17}},
+			{Addr: 0x70, W: []byte{0xef, 0xc8}},
+			{Addr: 0x70, R: GoodIDData},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if d.ID() != 0x0887 {
+		t.Fatalf("ID Error: 0x%04X", d.ID())
+	}
+	if d.String() != "shtc3{playback(112)}" {
+		t.Fatalf("String Error: %s", d.String())
+	}
+}
+
+func TestWrongID(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x70, W: []byte{0x35, 0x17}},
+			{Addr: 0x70, W: []byte{0xef, 0xc8}},
+			{Addr: 0x70, R: WrongIDData},
+		},
+	}
+	if _, err := New(&bus); err == nil {
+		t.Fatal("Wrong ID Error")
+	}
+}
+
+func TestFailNew(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops:       []i2ctest.IO{},
+		DontPanic: true,
+	}
+	if _, err := New(&bus); err == nil {
+		t.Fatal("Failed New Error")
+	}
+}
+
+func TestSense(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Wake it and read the ID
+			{Addr: 0x70, W: []byte{0x35, 0x17}},
+			{Addr: 0x70, W: []byte{0xef, 0xc8}},
+			{Addr: 0x70, R: GoodIDData},
+			{Addr: 0x70, W: []byte{0x78, 0x66}},
+			{Addr: 0x70, R: GoodMeasurementData},
+			{Addr: 0x70, W: []byte{0x78, 0x66}},
+			{Addr: 0x70, R: BadMeasurementData},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	var env physic.Env
+	if err := d.Sense(&env); err != nil {
+		t.Fatalf("Sense Error: %s", err)
+	}
+	if env != GoodEnv {
+		t.Fatalf("Sense Error: %s %s", env.Temperature, env.Humidity)
+	}
+	if err := d.Sense(&env); err == nil {
+		t.Fatal("Sense bad CRC Error")
+	}
+}
+
+func TestSenseClockStretching(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Wake it and read the ID
+			{Addr: 0x70, W: []byte{0x35, 0x17}},
+			{Addr: 0x70, W: []byte{0xef, 0xc8}},
+			{Addr: 0x70, R: GoodIDData},
+			{Addr: 0x70, W: []byte{0x64, 0x58}, R: GoodMeasurementData},
+			{Addr: 0x70, W: []byte{0x64, 0x58}, R: BadMeasurementData},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus, WithClockStretching(), WithLowPower())
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	var env physic.Env
+	if err := d.Sense(&env); err != nil {
+		t.Fatalf("Sense Error: %s", err)
+	}
+	if env != GoodEnv {
+		t.Fatalf("Sense Error: %s %s", env.Temperature, env.Humidity)
+	}
+	if err := d.Sense(&env); err == nil {
+		t.Fatal("Sense bad CRC Error")
+	}
+}
+
+func TestSleep(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Wake it and read the ID
+			{Addr: 0x70, W: []byte{0x35, 0x17}},
+			{Addr: 0x70, W: []byte{0xef, 0xc8}},
+			{Addr: 0x70, R: GoodIDData},
+			{Addr: 0x70, W: []byte{0xb0, 0x98}},
+			// Sense wakes it up, and puts it back to sleep
+			{Addr: 0x70, W: []byte{0x35, 0x17}},
+			{Addr: 0x70, W: []byte{0x60, 0x9c}},
+			{Addr: 0x70, R: GoodMeasurementData},
+			{Addr: 0x70, W: []byte{0xb0, 0x98}},
+			{Addr: 0x70, W: []byte{0x35, 0x17}},
+			{Addr: 0x70, W: []byte{0x80, 0x5d}},
+			{Addr: 0x70, W: []byte{0xb0, 0x98}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus, WithLowPower())
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if err := d.Sleep(); err != nil {
+		t.Fatalf("Sleep Error: %s", err)
+	}
+	var env physic.Env
+	if err := d.Sense(&env); err != nil {
+		t.Fatalf("Sense Error: %s", err)
+	}
+	if !d.asleep {
+		t.Fatal("Sense did not go back to sleep")
+	}
+	if err := d.Wakeup(); err != nil {
+		t.Fatalf("Wakeup Error: %s", err)
+	}
+	if err := d.SoftReset(); err != nil {
+		t.Fatalf("SoftReset Error: %s", err)
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatalf("Halt Error: %s", err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatalf("Close Error: %s", err)
+	}
+}
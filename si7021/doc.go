@@ -0,0 +1,15 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package si7021 controls a Silicon Labs Si7021 temperature and humidity sensor over
+// I²C.
+//
+// Sense measures the humidity, and reads the temperature that the sensor measured
+// to compensate it, checking the humidity's CRC8. The heater can be used to drive
+// off condensation, or to check that the sensor is working.
+//
+// Datasheet
+//
+// https://www.silabs.com/documents/public/data-sheets/Si7021-A20.pdf
+package si7021
@@ -0,0 +1,223 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package si7021
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sigurn/crc8"
+	"periph.io/x/periph/conn"
+	"periph.io/x/periph/conn/i2c"
+	"periph.io/x/periph/conn/physic"
+)
+
+// Addr is the I²C address of the Si7021, it cannot be changed
+const Addr uint16 = 0x40
+
+// Device IDs from the first byte of the second half of the electronic serial number
+const (
+	DeviceSi7013 uint8 = 0x0d
+	DeviceSi7020 uint8 = 0x14
+	DeviceSi7021 uint8 = 0x15
+)
+
+// Firmware revisions
+const (
+	Firmware1_0 uint8 = 0xff
+	Firmware2_0 uint8 = 0x20
+)
+
+// MaxHeaterLevel is the highest heater level, 94.2mA
+const MaxHeaterLevel uint8 = 0x0f
+
+// Si7021 commands from the datasheet
+var (
+	cmdMeasureRH    = []byte{0xf5}       // Measure the humidity, no hold master mode
+	cmdReadTemp     = []byte{0xe0}       // Read the temperature from the humidity measurement
+	cmdReset        = []byte{0xfe}       // Resets the sensor
+	cmdReadUser     = []byte{0xe7}       // Read the user register 1
+	cmdReadIDA      = []byte{0xfa, 0x0f} // Read the first half of the electronic ID
+	cmdReadIDB      = []byte{0xfc, 0xc9} // Read the second half of the electronic ID
+	cmdReadFirmware = []byte{0x84, 0xb8} // Read the firmware revision
+)
+
+const (
+	cmdWriteUser   uint8 = 0xe6 // Write the user register 1
+	cmdWriteHeater uint8 = 0x51 // Write the heater control register
+	userHeater     uint8 = 0x04 // Heater enable bit in the user register
+)
+
+var (
+	crc8si7021 = crc8.MakeTable(crc8.Params{
+		Poly:   0x31,
+		Init:   0x00,
+		RefIn:  false,
+		RefOut: false,
+		XorOut: 0x00,
+		Check:  0xA2,
+		Name:   "CRC-8/Si7021",
+	})
+)
+
+// Dev holds the connection to the Si7021
+type Dev struct {
+	i2c      conn.Conn // i2c device handle for the si7021
+	serial   uint64    // Electronic serial number
+	deviceID uint8     // Device ID from the serial number
+}
+
+var _ conn.Resource = &Dev{}
+
+// New returns an Si7021 device struct for communicating with the device
+//
+// It reads the electronic serial number, which includes the device ID. Other sensors
+// in the family, and compatible sensors like the HTU21D, also work, check DeviceID
+// to see which one it is.
+func New(i i2c.Bus) (*Dev, error) {
+	d := &Dev{i2c: &i2c.Dev{Bus: i, Addr: Addr}}
+	if err := d.readSerial(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// String implements conn.Resource.
+func (d *Dev) String() string {
+	return fmt.Sprintf("si7021{%s}", d.i2c)
+}
+
+// Halt implements conn.Resource.
+//
+// The Si7021 sleeps after each measurement, so there is nothing to halt.
+func (d *Dev) Halt() error {
+	return nil
+}
+
+// SerialNumber returns the 64 bit electronic serial number
+func (d *Dev) SerialNumber() uint64 {
+	return d.serial
+}
+
+// DeviceID returns the device ID, eg. DeviceSi7021
+func (d *Dev) DeviceID() uint8 {
+	return d.deviceID
+}
+
+// FirmwareRevision returns the firmware revision, Firmware1_0 or Firmware2_0
+func (d *Dev) FirmwareRevision() (uint8, error) {
+	var rev [1]byte
+	if err := d.i2c.Tx(cmdReadFirmware, rev[:]); err != nil {
+		return 0, fmt.Errorf("si7021: Error while reading firmware revision: %w", err)
+	}
+	return rev[0], nil
+}
+
+// Reset resets the sensor, turning off the heater
+func (d *Dev) Reset() error {
+	if err := d.i2c.Tx(cmdReset, nil); err != nil {
+		return fmt.Errorf("si7021: Error while resetting: %w", err)
+	}
+	// Requires a 15ms delay after the reset
+	time.Sleep(15 * time.Millisecond)
+	return nil
+}
+
+// Sense measures the relative humidity, and returns it with the temperature measured
+// during the humidity measurement
+func (d *Dev) Sense(env *physic.Env) error {
+	if err := d.i2c.Tx(cmdMeasureRH, nil); err != nil {
+		return fmt.Errorf("si7021: Error while requesting humidity: %w", err)
+	}
+	// Requires a 12ms humidity conversion and a 10.8ms temperature conversion
+	time.Sleep(23 * time.Millisecond)
+	var rh [3]byte
+	if err := d.i2c.Tx(nil, rh[:]); err != nil {
+		return fmt.Errorf("si7021: Error while reading humidity: %w", err)
+	}
+	if crc8.Checksum(rh[0:2], crc8si7021) != rh[2] {
+		return fmt.Errorf("si7021: Humidity CRC8 failed on: %v", rh)
+	}
+	var t [2]byte
+	if err := d.i2c.Tx(cmdReadTemp, t[:]); err != nil {
+		return fmt.Errorf("si7021: Error while reading temperature: %w", err)
+	}
+
+	// RH = -6 + 125 * raw / 2^16, clamped to 0-100%
+	h := int64(rh[0])<<8 | int64(rh[1])
+	humidity := physic.RelativeHumidity(h*int64(125*physic.PercentRH)/65536) - 6*physic.PercentRH
+	if humidity < 0 {
+		humidity = 0
+	} else if humidity > 100*physic.PercentRH {
+		humidity = 100 * physic.PercentRH
+	}
+	env.Humidity = humidity
+
+	// T = -46.85 + 175.72 * raw / 2^16
+	c := int64(t[0])<<8 | int64(t[1])
+	env.Temperature = physic.ZeroCelsius - 46850*physic.MilliCelsius + physic.Temperature(c*175720/65536)*physic.MilliCelsius
+	return nil
+}
+
+// SetHeater turns the heater on or off, and sets its level from 0 (3.09mA) to
+// MaxHeaterLevel (94.2mA), about 6mA per level
+func (d *Dev) SetHeater(on bool, level uint8) error {
+	if level > MaxHeaterLevel {
+		return fmt.Errorf("si7021: Invalid heater level: %d", level)
+	}
+	if err := d.i2c.Tx([]byte{cmdWriteHeater, level}, nil); err != nil {
+		return fmt.Errorf("si7021: Error while setting heater level: %w", err)
+	}
+	var user [1]byte
+	if err := d.i2c.Tx(cmdReadUser, user[:]); err != nil {
+		return fmt.Errorf("si7021: Error while reading user register: %w", err)
+	}
+	if on {
+		user[0] |= userHeater
+	} else {
+		user[0] &^= userHeater
+	}
+	if err := d.i2c.Tx([]byte{cmdWriteUser, user[0]}, nil); err != nil {
+		return fmt.Errorf("si7021: Error while writing user register: %w", err)
+	}
+	return nil
+}
+
+// readSerial reads the electronic serial number, checking the CRC8s, which cover all
+// of the serial number bytes before them
+func (d *Dev) readSerial() error {
+	// SNA_3, CRC, SNA_2, CRC, SNA_1, CRC, SNA_0, CRC
+	var a [8]byte
+	if err := d.i2c.Tx(cmdReadIDA, a[:]); err != nil {
+		return fmt.Errorf("si7021: Error while reading serial number: %w", err)
+	}
+	// SNB_3, SNB_2, CRC, SNB_1, SNB_0, CRC
+	var b [6]byte
+	if err := d.i2c.Tx(cmdReadIDB, b[:]); err != nil {
+		return fmt.Errorf("si7021: Error while reading serial number: %w", err)
+	}
+
+	var sn []byte
+	for i := 0; i < 8; i += 2 {
+		sn = append(sn, a[i])
+		if crc8.Checksum(sn, crc8si7021) != a[i+1] {
+			return fmt.Errorf("si7021: Serial number CRC8 failed on: %v", a)
+		}
+	}
+	sn = []byte{b[0], b[1]}
+	if crc8.Checksum(sn, crc8si7021) != b[2] {
+		return fmt.Errorf("si7021: Serial number CRC8 failed on: %v", b)
+	}
+	sn = append(sn, b[3], b[4])
+	if crc8.Checksum(sn, crc8si7021) != b[5] {
+		return fmt.Errorf("si7021: Serial number CRC8 failed on: %v", b)
+	}
+
+	for _, v := range []byte{a[0], a[2], a[4], a[6], b[0], b[1], b[3], b[4]} {
+		d.serial = d.serial<<8 | uint64(v)
+	}
+	d.deviceID = b[0]
+	return nil
+}
@@ -0,0 +1,168 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package si7021
+
+import (
+	"testing"
+
+	"periph.io/x/periph/conn/i2c/i2ctest"
+	"periph.io/x/periph/conn/physic"
+)
+
+var ()
+
+func TestNew(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the serial number
+			{Addr: 0x40, W: []byte{0xfa, 0x0f}, R: []byte{0x11, 0x72, 0x22, 0x7e, 0x33, 0x71, 0x44, 0x30}},
+			{Addr: 0x40, W: []byte{0xfc, 0xc9}, R: []byte{0x15, 0xaa, 0x3e, 0xbb, 0xcc, 0x6e}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if d.String() != "si7021{playback(64)}" {
+		t.Fatalf("String Error: %s", d.String())
+	}
+	if d.SerialNumber() != 0x1122334415aabbcc {
+		t.Errorf("SerialNumber Error: %016X", d.SerialNumber())
+	}
+	if d.DeviceID() != DeviceSi7021 {
+		t.Errorf("DeviceID Error: 0x%02X", d.DeviceID())
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSerialCRC(t *testing.T) {
+	bus := &i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x40, W: []byte{0xfa, 0x0f}, R: []byte{0x11, 0x72, 0x22, 0x7e, 0x33, 0x71, 0x44, 0x31}},
+			{Addr: 0x40, W: []byte{0xfc, 0xc9}, R: []byte{0x15, 0xaa, 0x3e, 0xbb, 0xcc, 0x6e}},
+		},
+		DontPanic: true,
+	}
+	if _, err := New(bus); err == nil {
+		t.Fatal("Serial number CRC Error")
+	}
+}
+
+func TestSense(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the serial number
+			{Addr: 0x40, W: []byte{0xfa, 0x0f}, R: []byte{0x11, 0x72, 0x22, 0x7e, 0x33, 0x71, 0x44, 0x30}},
+			{Addr: 0x40, W: []byte{0xfc, 0xc9}, R: []byte{0x15, 0xaa, 0x3e, 0xbb, 0xcc, 0x6e}},
+			{Addr: 0x40, W: []byte{0xf5}},
+			{Addr: 0x40, W: []byte{}, R: []byte{0x7c, 0x80, 0xf5}},
+			{Addr: 0x40, W: []byte{0xe0}, R: []byte{0x6a, 0x3c}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	var env physic.Env
+	if err := d.Sense(&env); err != nil {
+		t.Fatalf("Sense Error: %s", err)
+	}
+	if env.Humidity != 5479101*physic.TenthMicroRH {
+		t.Errorf("Humidity Error: %s", env.Humidity)
+	}
+	if env.Temperature != physic.ZeroCelsius+26069*physic.MilliCelsius {
+		t.Errorf("Temperature Error: %s", env.Temperature)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSenseCRC(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the serial number
+			{Addr: 0x40, W: []byte{0xfa, 0x0f}, R: []byte{0x11, 0x72, 0x22, 0x7e, 0x33, 0x71, 0x44, 0x30}},
+			{Addr: 0x40, W: []byte{0xfc, 0xc9}, R: []byte{0x15, 0xaa, 0x3e, 0xbb, 0xcc, 0x6e}},
+			{Addr: 0x40, W: []byte{0xf5}},
+			{Addr: 0x40, W: []byte{}, R: []byte{0x7c, 0x81, 0xf5}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	var env physic.Env
+	if err := d.Sense(&env); err == nil {
+		t.Fatal("CRC Error")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHeater(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the serial number
+			{Addr: 0x40, W: []byte{0xfa, 0x0f}, R: []byte{0x11, 0x72, 0x22, 0x7e, 0x33, 0x71, 0x44, 0x30}},
+			{Addr: 0x40, W: []byte{0xfc, 0xc9}, R: []byte{0x15, 0xaa, 0x3e, 0xbb, 0xcc, 0x6e}},
+			{Addr: 0x40, W: []byte{0x51, 0x0f}},
+			{Addr: 0x40, W: []byte{0xe7}, R: []byte{0x3a}},
+			{Addr: 0x40, W: []byte{0xe6, 0x3e}},
+			{Addr: 0x40, W: []byte{0x51, 0x00}},
+			{Addr: 0x40, W: []byte{0xe7}, R: []byte{0x3e}},
+			{Addr: 0x40, W: []byte{0xe6, 0x3a}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if err := d.SetHeater(true, MaxHeaterLevel); err != nil {
+		t.Fatalf("SetHeater Error: %s", err)
+	}
+	if err := d.SetHeater(false, 0); err != nil {
+		t.Fatalf("SetHeater Error: %s", err)
+	}
+	if err := d.SetHeater(true, MaxHeaterLevel+1); err == nil {
+		t.Error("Invalid heater level Error")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFirmwareReset(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the serial number
+			{Addr: 0x40, W: []byte{0xfa, 0x0f}, R: []byte{0x11, 0x72, 0x22, 0x7e, 0x33, 0x71, 0x44, 0x30}},
+			{Addr: 0x40, W: []byte{0xfc, 0xc9}, R: []byte{0x15, 0xaa, 0x3e, 0xbb, 0xcc, 0x6e}},
+			{Addr: 0x40, W: []byte{0x84, 0xb8}, R: []byte{0x20}},
+			{Addr: 0x40, W: []byte{0xfe}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if rev, err := d.FirmwareRevision(); err != nil || rev != Firmware2_0 {
+		t.Errorf("FirmwareRevision Error: 0x%02X %s", rev, err)
+	}
+	if err := d.Reset(); err != nil {
+		t.Fatalf("Reset Error: %s", err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
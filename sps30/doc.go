@@ -0,0 +1,16 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package sps30 controls a Sensirion SPS30 particulate matter sensor over I²C.
+//
+// The SPS30 measures the mass concentration of PM1.0, PM2.5, PM4.0 and PM10, and the
+// number concentration of PM0.5 to PM10, every second after StartMeasurement. Its fan
+// is cleaned automatically every week, or manually with StartFanCleaning.
+//
+// The SEL pin needs to be connected to GND to select the I²C interface.
+//
+// Datasheet
+//
+// https://sensirion.com/media/documents/8600FF88/616542B5/Sensirion_PM_Sensors_Datasheet_SPS30.pdf
+package sps30
@@ -0,0 +1,255 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sps30
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"periph.io/x/periph/conn"
+	"periph.io/x/periph/conn/i2c"
+
+	"github.com/bcl/air-sensors/internal/sensirion"
+)
+
+// Addr is the I²C address of the SPS30, it cannot be changed
+const Addr uint16 = 0x69
+
+// DefaultAutoCleanInterval is the fan auto cleaning interval set at the factory
+const DefaultAutoCleanInterval = 168 * time.Hour
+
+// FanCleaningTime is how long the fan cleaning runs, the readings are not updated
+// while cleaning
+const FanCleaningTime = 10 * time.Second
+
+// SPS30 commands from the datasheet
+const (
+	cmdStartMeasurement uint16 = 0x0010 // Start measuring, 1 word for the output format
+	cmdStopMeasurement  uint16 = 0x0104 // Stop measuring and go idle
+	cmdReadDataReady    uint16 = 0x0202 // Returns 1 when new readings are ready
+	cmdReadMeasurement  uint16 = 0x0300 // Returns the readings
+	cmdSleep            uint16 = 0x1001 // Enter sleep mode, firmware 2.0 or later
+	cmdWakeUp           uint16 = 0x1103 // Leave sleep mode, firmware 2.0 or later
+	cmdStartFanCleaning uint16 = 0x5607 // Clean the fan, while measuring
+	cmdAutoClean        uint16 = 0x8004 // Read or write the auto cleaning interval in seconds
+	cmdReadSerialNumber uint16 = 0xd033 // Returns the serial number as a string
+	cmdReadVersion      uint16 = 0xd100 // Returns the firmware major and minor version
+	cmdReadStatus       uint16 = 0xd206 // Returns the device status register
+	cmdClearStatus      uint16 = 0xd210 // Clears the device status register
+	cmdReset            uint16 = 0xd304 // Resets the sensor
+
+	formatFloat uint16 = 0x0300 // Big-endian IEEE754 float output format
+)
+
+// Status is the device status register
+type Status uint32
+
+// Device status register bits
+const (
+	StatusFanSpeed Status = 1 << 21 // The fan speed is too high or too low
+	StatusLaser    Status = 1 << 5  // The laser current is out of range
+	StatusFan      Status = 1 << 4  // The fan is switched on, but not turning
+)
+
+// ErrNotReady is returned by ReadMeasurement when there is no new reading
+var ErrNotReady = errors.New("sps30: Reading is not ready")
+
+// Reading holds the readings from the SPS30
+type Reading struct {
+	MassPM1     float32   `json:"mass_pm1"`     // PM1.0 in μg/m3
+	MassPM2_5   float32   `json:"mass_pm2_5"`   // PM2.5 in μg/m3
+	MassPM4     float32   `json:"mass_pm4"`     // PM4.0 in μg/m3
+	MassPM10    float32   `json:"mass_pm10"`    // PM10 in μg/m3
+	NumPM0_5    float32   `json:"num_pm0_5"`    // Particles of 0.3-0.5μm per cm3
+	NumPM1      float32   `json:"num_pm1"`      // Particles of 0.3-1.0μm per cm3
+	NumPM2_5    float32   `json:"num_pm2_5"`    // Particles of 0.3-2.5μm per cm3
+	NumPM4      float32   `json:"num_pm4"`      // Particles of 0.3-4.0μm per cm3
+	NumPM10     float32   `json:"num_pm10"`     // Particles of 0.3-10μm per cm3
+	TypicalSize float32   `json:"typical_size"` // Typical particle size in μm
+	Timestamp   time.Time `json:"timestamp"`    // When the reading was made
+}
+
+// Dev holds the connection to the SPS30
+type Dev struct {
+	i2c    conn.Conn // i2c device handle for the sps30
+	serial string    // Serial number
+	major  uint8     // Firmware major version
+	minor  uint8     // Firmware minor version
+}
+
+var _ conn.Resource = &Dev{}
+
+// New returns a SPS30 device struct for communicating with the device
+//
+// It reads the serial number and firmware version, StartMeasurement needs to be
+// called before reading the measurements.
+func New(i i2c.Bus) (*Dev, error) {
+	d := &Dev{i2c: &i2c.Dev{Bus: i, Addr: Addr}}
+
+	resp, err := d.command(cmdReadSerialNumber, 0, 16)
+	if err != nil {
+		return nil, err
+	}
+	d.serial = sensirion.String(resp)
+
+	if resp, err = d.command(cmdReadVersion, 0, 1); err != nil {
+		return nil, err
+	}
+	d.major, d.minor = uint8(resp[0]>>8), uint8(resp[0])
+	return d, nil
+}
+
+// String implements conn.Resource.
+func (d *Dev) String() string {
+	return fmt.Sprintf("sps30{%s}", d.i2c)
+}
+
+// Halt implements conn.Resource.
+//
+// It stops the measurements, turning off the fan and the laser.
+func (d *Dev) Halt() error {
+	return d.StopMeasurement()
+}
+
+// SerialNumber returns the serial number of the sensor
+func (d *Dev) SerialNumber() string {
+	return d.serial
+}
+
+// FirmwareVersion returns the major and minor firmware version
+func (d *Dev) FirmwareVersion() (uint8, uint8) {
+	return d.major, d.minor
+}
+
+// StartMeasurement starts the fan and the measurements, new readings are ready every
+// second. The first readings take up to 30s to stabilize.
+func (d *Dev) StartMeasurement() error {
+	_, err := d.command(cmdStartMeasurement, 20*time.Millisecond, 0, formatFloat)
+	return err
+}
+
+// StopMeasurement stops the measurements and the fan
+func (d *Dev) StopMeasurement() error {
+	_, err := d.command(cmdStopMeasurement, 20*time.Millisecond, 0)
+	return err
+}
+
+// DataReady returns true when there is a new reading
+func (d *Dev) DataReady() (bool, error) {
+	resp, err := d.command(cmdReadDataReady, 0, 1)
+	if err != nil {
+		return false, err
+	}
+	return resp[0]&0x01 == 0x01, nil
+}
+
+// ReadMeasurement returns the latest reading, or ErrNotReady if there is no new
+// reading since the last one
+func (d *Dev) ReadMeasurement() (Reading, error) {
+	ready, err := d.DataReady()
+	if err != nil {
+		return Reading{}, err
+	}
+	if !ready {
+		return Reading{}, ErrNotReady
+	}
+
+	// 10 floats, each split into 2 words
+	resp, err := d.command(cmdReadMeasurement, 0, 20)
+	if err != nil {
+		return Reading{}, err
+	}
+	var f [10]float32
+	for i := range f {
+		f[i] = math.Float32frombits(uint32(resp[i*2])<<16 | uint32(resp[i*2+1]))
+	}
+	return Reading{
+		MassPM1:     f[0],
+		MassPM2_5:   f[1],
+		MassPM4:     f[2],
+		MassPM10:    f[3],
+		NumPM0_5:    f[4],
+		NumPM1:      f[5],
+		NumPM2_5:    f[6],
+		NumPM4:      f[7],
+		NumPM10:     f[8],
+		TypicalSize: f[9],
+		Timestamp:   time.Now(),
+	}, nil
+}
+
+// StartFanCleaning runs the fan at full speed for FanCleaningTime, it only works
+// while measuring
+func (d *Dev) StartFanCleaning() error {
+	_, err := d.command(cmdStartFanCleaning, 5*time.Millisecond, 0)
+	return err
+}
+
+// AutoCleanInterval returns the fan auto cleaning interval, 0 if it is disabled
+func (d *Dev) AutoCleanInterval() (time.Duration, error) {
+	resp, err := d.command(cmdAutoClean, 5*time.Millisecond, 2)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(uint32(resp[0])<<16|uint32(resp[1])) * time.Second, nil
+}
+
+// SetAutoCleanInterval sets the fan auto cleaning interval, with a resolution of 1s,
+// 0 disables it. It is saved in the sensor, and with firmware before 2.2 only used
+// after a Reset.
+func (d *Dev) SetAutoCleanInterval(interval time.Duration) error {
+	s := interval / time.Second
+	if s < 0 || s > math.MaxUint32 {
+		return fmt.Errorf("sps30: Invalid auto cleaning interval: %s", interval)
+	}
+	_, err := d.command(cmdAutoClean, 20*time.Millisecond, 0, uint16(s>>16), uint16(s))
+	return err
+}
+
+// ReadStatus returns the device status register, and clears it if clear is true
+func (d *Dev) ReadStatus(clear bool) (Status, error) {
+	resp, err := d.command(cmdReadStatus, 0, 2)
+	if err != nil {
+		return 0, err
+	}
+	if clear {
+		if _, err := d.command(cmdClearStatus, 5*time.Millisecond, 0); err != nil {
+			return 0, err
+		}
+	}
+	return Status(uint32(resp[0])<<16 | uint32(resp[1])), nil
+}
+
+// Sleep puts the sensor into its lowest power mode, it needs to be stopped first
+//
+// It needs firmware 2.0 or later.
+func (d *Dev) Sleep() error {
+	_, err := d.command(cmdSleep, 5*time.Millisecond, 0)
+	return err
+}
+
+// WakeUp wakes the sensor from Sleep
+//
+// The first command only wakes the sensor's interface and is not acknowledged, so it
+// is sent twice.
+func (d *Dev) WakeUp() error {
+	d.i2c.Tx(sensirion.Encode(cmdWakeUp), nil) //nolint
+	_, err := d.command(cmdWakeUp, 5*time.Millisecond, 0)
+	return err
+}
+
+// Reset resets the sensor, it needs StartMeasurement to start measuring again
+func (d *Dev) Reset() error {
+	_, err := d.command(cmdReset, 100*time.Millisecond, 0)
+	return err
+}
+
+// command sends a command, with optional argument words, waits for it to execute,
+// and then returns the response words
+func (d *Dev) command(cmd uint16, wait time.Duration, respWords int, args ...uint16) ([]uint16, error) {
+	return sensirion.Command(d.i2c, "sps30", cmd, wait, respWords, args...)
+}
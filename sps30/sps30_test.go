@@ -0,0 +1,223 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package sps30
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"periph.io/x/periph/conn/i2c/i2ctest"
+
+	"github.com/bcl/air-sensors/internal/sensirion"
+)
+
+var ()
+
+// floatsCRC returns the floats as words with their CRC8
+func floatsCRC(floats ...float32) []byte {
+	var words []uint16
+	for _, f := range floats {
+		b := math.Float32bits(f)
+		words = append(words, uint16(b>>16), uint16(b))
+	}
+	return sensirion.WordCRC(words...)
+}
+
+func TestNew(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the serial number
+			{Addr: 0x69, W: []byte{0xd0, 0x33}},
+			{Addr: 0x69, W: []byte{}, R: sensirion.WordCRC(0x3841, 0x3432, 0x4535, 0x3646, 0x3146, 0x3030, 0x4433, 0x3300, 0, 0, 0, 0, 0, 0, 0, 0)},
+			{Addr: 0x69, W: []byte{0xd1, 0x00}},
+			{Addr: 0x69, W: []byte{}, R: sensirion.WordCRC(0x0203)},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if d.String() != "sps30{playback(105)}" {
+		t.Fatalf("String Error: %s", d.String())
+	}
+	if d.SerialNumber() != "8A42E56F1F00D33" {
+		t.Errorf("SerialNumber Error: %q", d.SerialNumber())
+	}
+	if major, minor := d.FirmwareVersion(); major != 2 || minor != 3 {
+		t.Errorf("FirmwareVersion Error: %d.%d", major, minor)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSerialCRC(t *testing.T) {
+	bus := &i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x69, W: []byte{0xd0, 0x33}},
+			{Addr: 0x69, W: []byte{}, R: make([]byte, 48)},
+		},
+		DontPanic: true,
+	}
+	if _, err := New(bus); err == nil {
+		t.Fatal("Serial number CRC Error")
+	}
+}
+
+func TestMeasurement(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the serial number
+			{Addr: 0x69, W: []byte{0xd0, 0x33}},
+			{Addr: 0x69, W: []byte{}, R: sensirion.WordCRC(0x3841, 0x3432, 0x4535, 0x3646, 0x3146, 0x3030, 0x4433, 0x3300, 0, 0, 0, 0, 0, 0, 0, 0)},
+			{Addr: 0x69, W: []byte{0xd1, 0x00}},
+			{Addr: 0x69, W: []byte{}, R: sensirion.WordCRC(0x0203)},
+			{Addr: 0x69, W: []byte{0x00, 0x10, 0x03, 0x00, 0xac}},
+			{Addr: 0x69, W: []byte{0x02, 0x02}},
+			{Addr: 0x69, W: []byte{}, R: sensirion.WordCRC(0x0000)},
+			{Addr: 0x69, W: []byte{0x02, 0x02}},
+			{Addr: 0x69, W: []byte{}, R: sensirion.WordCRC(0x0001)},
+			{Addr: 0x69, W: []byte{0x03, 0x00}},
+			{Addr: 0x69, W: []byte{}, R: floatsCRC(4.5, 6.25, 7, 7.5, 30.5, 35.75, 36, 36.25, 36.5, 0.5)},
+			{Addr: 0x69, W: []byte{0x01, 0x04}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if err := d.StartMeasurement(); err != nil {
+		t.Fatalf("StartMeasurement Error: %s", err)
+	}
+	if _, err := d.ReadMeasurement(); !errors.Is(err, ErrNotReady) {
+		t.Errorf("ErrNotReady Error: %v", err)
+	}
+	r, err := d.ReadMeasurement()
+	if err != nil {
+		t.Fatalf("ReadMeasurement Error: %s", err)
+	}
+	if r.MassPM1 != 4.5 || r.MassPM2_5 != 6.25 || r.MassPM4 != 7 || r.MassPM10 != 7.5 {
+		t.Errorf("Mass concentration Error: %#v", r)
+	}
+	if r.NumPM0_5 != 30.5 || r.NumPM1 != 35.75 || r.NumPM2_5 != 36 || r.NumPM4 != 36.25 || r.NumPM10 != 36.5 {
+		t.Errorf("Number concentration Error: %#v", r)
+	}
+	if r.TypicalSize != 0.5 {
+		t.Errorf("TypicalSize Error: %f", r.TypicalSize)
+	}
+	if err := d.Halt(); err != nil {
+		t.Fatalf("Halt Error: %s", err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFanCleaning(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the serial number
+			{Addr: 0x69, W: []byte{0xd0, 0x33}},
+			{Addr: 0x69, W: []byte{}, R: sensirion.WordCRC(0x3841, 0x3432, 0x4535, 0x3646, 0x3146, 0x3030, 0x4433, 0x3300, 0, 0, 0, 0, 0, 0, 0, 0)},
+			{Addr: 0x69, W: []byte{0xd1, 0x00}},
+			{Addr: 0x69, W: []byte{}, R: sensirion.WordCRC(0x0203)},
+			{Addr: 0x69, W: []byte{0x80, 0x04}},
+			{Addr: 0x69, W: []byte{}, R: sensirion.WordCRC(0x0009, 0x3a80)},
+			{Addr: 0x69, W: append([]byte{0x80, 0x04}, sensirion.WordCRC(0x0001, 0x5180)...)},
+			{Addr: 0x69, W: []byte{0x56, 0x07}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	interval, err := d.AutoCleanInterval()
+	if err != nil {
+		t.Fatalf("AutoCleanInterval Error: %s", err)
+	}
+	if interval != DefaultAutoCleanInterval {
+		t.Errorf("AutoCleanInterval Error: %s", interval)
+	}
+	if err := d.SetAutoCleanInterval(24 * time.Hour); err != nil {
+		t.Fatalf("SetAutoCleanInterval Error: %s", err)
+	}
+	if err := d.SetAutoCleanInterval(-time.Second); err == nil {
+		t.Error("Invalid interval Error")
+	}
+	if err := d.StartFanCleaning(); err != nil {
+		t.Fatalf("StartFanCleaning Error: %s", err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the serial number
+			{Addr: 0x69, W: []byte{0xd0, 0x33}},
+			{Addr: 0x69, W: []byte{}, R: sensirion.WordCRC(0x3841, 0x3432, 0x4535, 0x3646, 0x3146, 0x3030, 0x4433, 0x3300, 0, 0, 0, 0, 0, 0, 0, 0)},
+			{Addr: 0x69, W: []byte{0xd1, 0x00}},
+			{Addr: 0x69, W: []byte{}, R: sensirion.WordCRC(0x0203)},
+			{Addr: 0x69, W: []byte{0xd2, 0x06}},
+			{Addr: 0x69, W: []byte{}, R: sensirion.WordCRC(0x0020, 0x0010)},
+			{Addr: 0x69, W: []byte{0xd2, 0x10}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	status, err := d.ReadStatus(true)
+	if err != nil {
+		t.Fatalf("ReadStatus Error: %s", err)
+	}
+	if status != StatusFanSpeed|StatusFan {
+		t.Errorf("ReadStatus Error: 0x%08X", uint32(status))
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSleepWakeUpReset(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the serial number
+			{Addr: 0x69, W: []byte{0xd0, 0x33}},
+			{Addr: 0x69, W: []byte{}, R: sensirion.WordCRC(0x3841, 0x3432, 0x4535, 0x3646, 0x3146, 0x3030, 0x4433, 0x3300, 0, 0, 0, 0, 0, 0, 0, 0)},
+			{Addr: 0x69, W: []byte{0xd1, 0x00}},
+			{Addr: 0x69, W: []byte{}, R: sensirion.WordCRC(0x0203)},
+			{Addr: 0x69, W: []byte{0x10, 0x01}},
+			{Addr: 0x69, W: []byte{0x11, 0x03}},
+			{Addr: 0x69, W: []byte{0x11, 0x03}},
+			{Addr: 0x69, W: []byte{0xd3, 0x04}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if err := d.Sleep(); err != nil {
+		t.Fatalf("Sleep Error: %s", err)
+	}
+	if err := d.WakeUp(); err != nil {
+		t.Fatalf("WakeUp Error: %s", err)
+	}
+	if err := d.Reset(); err != nil {
+		t.Fatalf("Reset Error: %s", err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,14 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package svm30 controls a Sensirion SVM30 module over I²C.
+//
+// The SVM30 combines an SGP30 gas sensor and an SHTC1 temperature and humidity
+// sensor on one board. The SHTC1's readings are used for the SGP30's humidity
+// compensation.
+//
+// Datasheet
+//
+// https://www.sensirion.com/fileadmin/user_upload/customers/sensirion/Dokumente/9_Gas_Sensors/Datasheets/Sensirion_Gas_Sensors_SVM30_Datasheet.pdf
+package svm30
@@ -0,0 +1,46 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package svm30_test
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"periph.io/x/periph/conn/i2c/i2creg"
+	"periph.io/x/periph/host"
+
+	"github.com/bcl/air-sensors/sgp30"
+	"github.com/bcl/air-sensors/svm30"
+)
+
+func Example() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Open a handle to the first available I²C bus:
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer bus.Close()
+
+	d, err := svm30.New(bus, sgp30.WithBaselineFile(".svm30_baseline"), sgp30.WithAutoStart(true))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer d.Halt() //nolint
+
+	// Read the humidity compensated air quality every second
+	for range time.Tick(time.Second) {
+		r, err := d.ReadAirQuality()
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("CO2 : %d ppm\nTVOC: %d ppb\n%s %s\n", r.ECO2, r.TVOC, r.Temperature, r.Humidity)
+	}
+}
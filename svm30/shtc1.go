@@ -0,0 +1,67 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package svm30
+
+import (
+	"fmt"
+	"time"
+
+	"periph.io/x/periph/conn"
+	"periph.io/x/periph/conn/physic"
+
+	"github.com/bcl/air-sensors/internal/sensirion"
+)
+
+// SHTC1Addr is the I²C address of the SHTC1 on the SVM30
+const SHTC1Addr uint16 = 0x70
+
+// shtc1 reads the temperature and humidity from the SHTC1
+type shtc1 struct {
+	c conn.Conn
+}
+
+// readID returns the SHTC1's ID register
+func (s *shtc1) readID() (uint16, error) {
+	// Send a 0xefc8
+	// Receive 1 word + 8 bit CRC
+	var data [3]byte
+	if err := s.c.Tx([]byte{0xef, 0xc8}, data[:]); err != nil {
+		return 0, fmt.Errorf("svm30: Error while reading SHTC1 ID: %w", err)
+	}
+	if !sensirion.CheckCRC8(data[0:3]) {
+		return 0, fmt.Errorf("svm30: SHTC1 ID CRC8 failed on: %v", data[0:3])
+	}
+	return sensirion.Word(data[:], 0), nil
+}
+
+// sense reads the temperature and relative humidity
+func (s *shtc1) sense(env *physic.Env) error {
+	// Send a 0x7866, measure temperature first without clock stretching
+	if err := s.c.Tx([]byte{0x78, 0x66}, nil); err != nil {
+		return fmt.Errorf("svm30: Error while requesting SHTC1 measurement: %w", err)
+	}
+
+	// Requires a 14.4ms delay before reading results
+	time.Sleep(15 * time.Millisecond)
+	// Receive 2 words + 8 bit CRC on each
+	var data [6]byte
+	if err := s.c.Tx(nil, data[:]); err != nil {
+		return fmt.Errorf("svm30: Error while reading SHTC1 measurement: %w", err)
+	}
+
+	if !sensirion.CheckCRC8(data[0:3]) {
+		return fmt.Errorf("svm30: SHTC1 temperature CRC8 failed on: %v", data[0:3])
+	}
+	if !sensirion.CheckCRC8(data[3:6]) {
+		return fmt.Errorf("svm30: SHTC1 humidity CRC8 failed on: %v", data[3:6])
+	}
+
+	// T = -45 + 175 * raw / 2^16, RH = 100 * raw / 2^16
+	t := int64(sensirion.Word(data[:], 0))
+	env.Temperature = physic.ZeroCelsius - 45*physic.Celsius + physic.Temperature(t*175000/65536)*physic.MilliCelsius
+	rh := int64(sensirion.Word(data[:], 3))
+	env.Humidity = physic.RelativeHumidity(rh * int64(100*physic.PercentRH) / 65536)
+	return nil
+}
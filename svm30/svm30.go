@@ -0,0 +1,101 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package svm30
+
+import (
+	"fmt"
+
+	"periph.io/x/periph/conn"
+	"periph.io/x/periph/conn/i2c"
+	"periph.io/x/periph/conn/physic"
+
+	"github.com/bcl/air-sensors/sgp30"
+)
+
+// Dev holds the SGP30 and SHTC1 on the SVM30
+type Dev struct {
+	SGP30 *sgp30.Dev // The SGP30 gas sensor, for its baseline and other commands
+
+	bus   i2c.Bus // i2c bus the svm30 is connected to
+	shtc1 shtc1   // The SHTC1 temperature and humidity sensor
+}
+
+var _ conn.Resource = &Dev{}
+
+// Reading holds the air quality readings from the SGP30, and the temperature and
+// humidity used to compensate them
+type Reading struct {
+	sgp30.Reading
+	Temperature physic.Temperature      `json:"temperature"` // Temperature
+	Humidity    physic.RelativeHumidity `json:"humidity"`    // Relative humidity
+}
+
+// New returns a SVM30 device struct for communicating with the device
+//
+// The options are passed to sgp30.New, see the sgp30 package for the details of the
+// SGP30's baseline handling.
+func New(i i2c.Bus, opts ...sgp30.Option) (*Dev, error) {
+	d := &Dev{
+		bus:   i,
+		shtc1: shtc1{c: &i2c.Dev{Bus: i, Addr: SHTC1Addr}},
+	}
+
+	id, err := d.shtc1.readID()
+	if err != nil {
+		return nil, err
+	}
+	// Bits 5:0 are the SHTC1 product code
+	if id&0x003f != 0x0007 {
+		return nil, fmt.Errorf("svm30: SHTC1 not found, ID is 0x%04X", id)
+	}
+
+	if d.SGP30, err = sgp30.New(i, opts...); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// String implements conn.Resource.
+func (d *Dev) String() string {
+	return fmt.Sprintf("svm30{%s}", d.bus)
+}
+
+// Halt implements conn.Resource.
+//
+// It halts the SGP30, see sgp30.Dev.Halt.
+func (d *Dev) Halt() error {
+	return d.SGP30.Halt()
+}
+
+// Sense reads the temperature and relative humidity from the SHTC1
+func (d *Dev) Sense(env *physic.Env) error {
+	return d.shtc1.sense(env)
+}
+
+// ReadAirQuality reads the temperature and humidity from the SHTC1, uses them for the
+// SGP30's humidity compensation, and then returns the air quality readings.
+//
+// It needs to be called every second, after starting the SGP30's measurements with
+// StartMeasurements, for the SGP30's dynamic baseline compensation to work.
+func (d *Dev) ReadAirQuality() (Reading, error) {
+	var env physic.Env
+	if err := d.shtc1.sense(&env); err != nil {
+		return Reading{}, err
+	}
+	if err := d.SGP30.CompensateFromEnv(env); err != nil {
+		return Reading{}, err
+	}
+
+	aq, err := d.SGP30.ReadAirQuality()
+	if err != nil {
+		return Reading{}, err
+	}
+	return Reading{Reading: aq, Temperature: env.Temperature, Humidity: env.Humidity}, nil
+}
+
+// StartMeasurements starts the SGP30's air quality measurements
+func (d *Dev) StartMeasurements() error {
+	return d.SGP30.StartMeasurements()
+}
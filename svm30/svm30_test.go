@@ -0,0 +1,115 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package svm30
+
+import (
+	"testing"
+
+	"periph.io/x/periph/conn/i2c/i2ctest"
+	"periph.io/x/periph/conn/physic"
+)
+
+var (
+	GoodSHTC1ID        = []byte{0x08, 0x87, 0x5b}
+	BadSHTC1ID         = []byte{0x00, 0x00, 0x81}
+	GoodSHTC1Data      = []byte{0x66, 0x66, 0x93, 0x80, 0x00, 0xa2}
+	BadSHTC1Data       = []byte{0, 0, 0, 0, 0, 0}
+	GoodSerialNumber   = []byte{0x00, 0x00, 0x81, 0x01, 0x57, 0x9C, 0xAC, 0xA2, 0x54}
+	GoodFeaturesData   = []byte{0x00, 0x22, 0x65}
+	GoodAirQualityData = []byte{0x01, 0x9e, 0x53, 0x00, 0x0d, 0xcd}
+)
+
+func TestBadID(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x70, W: []byte{0xef, 0xc8}, R: BadSHTC1ID},
+		},
+	}
+	if _, err := New(&bus); err == nil {
+		t.Fatal("Bad SHTC1 ID Error")
+	}
+}
+
+func TestOtherID(t *testing.T) {
+	// Only the product code in bits 5:0 identifies the SHTC1
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x70, W: []byte{0xef, 0xc8}, R: []byte{0x00, 0x07, 0x16}},
+			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
+		},
+	}
+	if _, err := New(&bus); err != nil {
+		t.Fatalf("SHTC1 ID Error: %s", err)
+	}
+}
+
+func TestSense(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x70, W: []byte{0xef, 0xc8}, R: GoodSHTC1ID},
+			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
+			{Addr: 0x70, W: []byte{0x78, 0x66}, R: []byte{}},
+			{Addr: 0x70, W: []byte{}, R: GoodSHTC1Data},
+			{Addr: 0x70, W: []byte{0x78, 0x66}, R: []byte{}},
+			{Addr: 0x70, W: []byte{}, R: BadSHTC1Data},
+		},
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	var env physic.Env
+	if err := d.Sense(&env); err != nil {
+		t.Fatalf("Sense Error: %s", err)
+	}
+	if env.Temperature != physic.ZeroCelsius+24998*physic.MilliCelsius {
+		t.Errorf("Temperature is wrong: %s", env.Temperature)
+	}
+	if env.Humidity != 50*physic.PercentRH {
+		t.Errorf("Humidity is wrong: %s", env.Humidity)
+	}
+	if err := d.Sense(&env); err == nil {
+		t.Fatal("Bad SHTC1 data Error")
+	}
+}
+
+func TestReadAirQuality(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x70, W: []byte{0xef, 0xc8}, R: GoodSHTC1ID},
+			{Addr: 0x58, W: []byte{0x36, 0x82}, R: GoodSerialNumber},
+			{Addr: 0x58, W: []byte{0x20, 0x2f}, R: GoodFeaturesData},
+			{Addr: 0x58, W: []byte{0x20, 0x03}, R: []byte{}},
+			{Addr: 0x70, W: []byte{0x78, 0x66}, R: []byte{}},
+			{Addr: 0x70, W: []byte{}, R: GoodSHTC1Data},
+			// 11.48 g/m³ absolute humidity
+			{Addr: 0x58, W: []byte{0x20, 0x61, 0x0b, 0x7c, 0x1e}, R: []byte{}},
+			{Addr: 0x58, W: []byte{0x20, 0x08}, R: []byte{}},
+			{Addr: 0x58, W: []byte{}, R: GoodAirQualityData},
+		},
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if err := d.StartMeasurements(); err != nil {
+		t.Fatalf("StartMeasurements Error: %s", err)
+	}
+	r, err := d.ReadAirQuality()
+	if err != nil {
+		t.Fatalf("ReadAirQuality Error: %s", err)
+	}
+	if r.ECO2 != 414 || r.TVOC != 13 {
+		t.Errorf("AirQuality reading is wrong: %+v", r)
+	}
+	if r.Humidity != 50*physic.PercentRH {
+		t.Errorf("Humidity is wrong: %s", r.Humidity)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatalf("Playback Close Error: %s", err)
+	}
+}
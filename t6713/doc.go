@@ -0,0 +1,14 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package t6713 controls an Amphenol Telaire T6713 NDIR CO2 sensor over I²C.
+//
+// The sensor uses Modbus style commands over I²C, without a CRC. It reads the status
+// and the CO2 concentration, runs a single point calibration, and turns the
+// automatic baseline correction on or off.
+//
+// Datasheet
+//
+// https://www.amphenol-sensors.com/hubfs/Documents/AAS-916-142A-Telaire-T67xx-CO2-Sensor-022719-web.pdf
+package t6713
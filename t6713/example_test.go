@@ -0,0 +1,50 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package t6713_test
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"periph.io/x/periph/conn/i2c/i2creg"
+	"periph.io/x/periph/host"
+
+	"github.com/bcl/air-sensors/t6713"
+)
+
+func Example() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Open a handle to the first available I²C bus:
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer bus.Close()
+
+	d, err := t6713.New(bus)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer d.Halt() //nolint
+
+	// Wait for the sensor to warm up
+	for {
+		r, err := d.ReadCO2()
+		if errors.Is(err, t6713.ErrNotReady) {
+			time.Sleep(5 * time.Second)
+			continue
+		} else if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("CO2: %d ppm\n", r.CO2)
+		break
+	}
+}
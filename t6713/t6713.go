@@ -0,0 +1,209 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package t6713
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"periph.io/x/periph/conn"
+	"periph.io/x/periph/conn/i2c"
+)
+
+// Addr is the I²C address of the T6713
+const Addr uint16 = 0x15
+
+// CalibrationTimeout is how long Calibrate waits for the calibration to finish
+const CalibrationTimeout = 10 * time.Second
+
+// T6713 function codes
+const (
+	fnReadInput  byte = 0x04 // Read an input register
+	fnWriteCoil  byte = 0x05 // Write a single coil
+	responseSize byte = 0x02 // Number of bytes in a register read response
+)
+
+// T6713 registers
+const (
+	regFirmware   uint16 = 0x1389 // Firmware revision
+	regStatus     uint16 = 0x138a // Status
+	regCO2        uint16 = 0x138b // CO2 in ppm
+	coilReset     uint16 = 0x03e8 // Reset the sensor
+	coilCalibrate uint16 = 0x03ec // Start a single point calibration
+	coilABC       uint16 = 0x03ee // Automatic baseline correction on or off
+)
+
+const (
+	commandWait     = 10 * time.Millisecond // Delay between a request and its response
+	calibrationPoll = time.Second           // How often Calibrate reads the status
+)
+
+// Status is the sensor's status register
+type Status uint16
+
+// Status bits
+const (
+	StatusError            Status = 0x0001 // Error condition
+	StatusFlashError       Status = 0x0002 // Flash error
+	StatusCalibrationError Status = 0x0004 // Calibration error
+	StatusWarmup           Status = 0x0800 // Warming up after power on or reset
+	StatusCalibrating      Status = 0x8000 // Single point calibration is running
+)
+
+// ErrNotReady is returned by ReadCO2 while the sensor is warming up or calibrating
+var ErrNotReady = errors.New("t6713: Reading is not ready")
+
+// Reading holds the readings from the T6713
+type Reading struct {
+	CO2       uint16    `json:"co2"`       // CO2 in ppm
+	Timestamp time.Time `json:"timestamp"` // When the reading was made
+}
+
+// Dev holds the connection to the T6713
+type Dev struct {
+	i2c      conn.Conn // i2c device handle for the t6713
+	firmware uint16    // Firmware revision
+}
+
+var _ conn.Resource = &Dev{}
+
+// New returns a T6713 device struct for communicating with the device
+//
+// It reads the firmware revision.
+func New(i i2c.Bus) (*Dev, error) {
+	d := &Dev{i2c: &i2c.Dev{Bus: i, Addr: Addr}}
+
+	var err error
+	if d.firmware, err = d.readRegister(regFirmware); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// String implements conn.Resource.
+func (d *Dev) String() string {
+	return fmt.Sprintf("t6713{%s}", d.i2c)
+}
+
+// Halt implements conn.Resource.
+//
+// The T6713 measures continuously and cannot be halted.
+func (d *Dev) Halt() error {
+	return nil
+}
+
+// FirmwareRevision returns the sensor's firmware revision
+func (d *Dev) FirmwareRevision() uint16 {
+	return d.firmware
+}
+
+// ReadStatus returns the status register
+func (d *Dev) ReadStatus() (Status, error) {
+	status, err := d.readRegister(regStatus)
+	return Status(status), err
+}
+
+// ReadCO2 returns the CO2 concentration
+//
+// It checks the status first, and returns ErrNotReady while the sensor is warming up
+// or calibrating, or an error if the sensor reports one.
+func (d *Dev) ReadCO2() (Reading, error) {
+	status, err := d.ReadStatus()
+	if err != nil {
+		return Reading{}, err
+	}
+	if status&(StatusError|StatusFlashError|StatusCalibrationError) != 0 {
+		return Reading{}, fmt.Errorf("t6713: Sensor error, status is 0x%04X", uint16(status))
+	}
+	if status&(StatusWarmup|StatusCalibrating) != 0 {
+		return Reading{}, ErrNotReady
+	}
+
+	co2, err := d.readRegister(regCO2)
+	if err != nil {
+		return Reading{}, err
+	}
+	return Reading{
+		CO2:       co2,
+		Timestamp: time.Now(),
+	}, nil
+}
+
+// Calibrate runs a single point calibration, and polls the status until it finishes
+//
+// The sensor needs to have been in fresh outdoor air, about 400ppm, or the
+// reference gas, for at least a few minutes.
+func (d *Dev) Calibrate() error {
+	if err := d.writeCoil(coilCalibrate, true); err != nil {
+		return err
+	}
+	for end := time.Now().Add(CalibrationTimeout); time.Now().Before(end); {
+		time.Sleep(calibrationPoll)
+		status, err := d.ReadStatus()
+		if err != nil {
+			return err
+		}
+		if status&StatusCalibrationError != 0 {
+			return fmt.Errorf("t6713: Calibration failed, status is 0x%04X", uint16(status))
+		}
+		if status&StatusCalibrating == 0 {
+			return nil
+		}
+	}
+	return fmt.Errorf("t6713: Calibration timed out")
+}
+
+// SetABC turns the automatic baseline correction on or off. It calibrates the sensor
+// to the lowest concentration seen during each period, which needs the sensor to see
+// fresh air regularly.
+func (d *Dev) SetABC(on bool) error {
+	return d.writeCoil(coilABC, on)
+}
+
+// Reset resets the sensor, it warms up again before ReadCO2 returns a reading
+func (d *Dev) Reset() error {
+	return d.writeCoil(coilReset, true)
+}
+
+// readRegister reads a 16 bit input register
+//
+// The request is the function, the register, and the number of registers, 1. The
+// response is the function, the byte count, and the register.
+func (d *Dev) readRegister(reg uint16) (uint16, error) {
+	w := []byte{fnReadInput, byte(reg >> 8), byte(reg), 0x00, 0x01}
+	if err := d.i2c.Tx(w, nil); err != nil {
+		return 0, fmt.Errorf("t6713: Error while requesting register 0x%04X: %w", reg, err)
+	}
+	time.Sleep(commandWait)
+	var r [4]byte
+	if err := d.i2c.Tx(nil, r[:]); err != nil {
+		return 0, fmt.Errorf("t6713: Error while reading register 0x%04X: %w", reg, err)
+	}
+	if r[0] != fnReadInput || r[1] != responseSize {
+		return 0, fmt.Errorf("t6713: Wrong response reading register 0x%04X: %v", reg, r)
+	}
+	return uint16(r[2])<<8 | uint16(r[3]), nil
+}
+
+// writeCoil turns a coil on or off, the response echoes the request
+func (d *Dev) writeCoil(coil uint16, on bool) error {
+	w := []byte{fnWriteCoil, byte(coil >> 8), byte(coil), 0x00, 0x00}
+	if on {
+		w[3] = 0xff
+	}
+	if err := d.i2c.Tx(w, nil); err != nil {
+		return fmt.Errorf("t6713: Error while writing coil 0x%04X: %w", coil, err)
+	}
+	time.Sleep(commandWait)
+	var r [5]byte
+	if err := d.i2c.Tx(nil, r[:]); err != nil {
+		return fmt.Errorf("t6713: Error while reading coil 0x%04X response: %w", coil, err)
+	}
+	if string(r[:]) != string(w) {
+		return fmt.Errorf("t6713: Wrong response writing coil 0x%04X: %v", coil, r)
+	}
+	return nil
+}
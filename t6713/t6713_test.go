@@ -0,0 +1,216 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package t6713
+
+import (
+	"errors"
+	"testing"
+
+	"periph.io/x/periph/conn/i2c/i2ctest"
+)
+
+func TestNew(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the firmware revision
+			{Addr: 0x15, W: []byte{0x04, 0x13, 0x89, 0x00, 0x01}},
+			{Addr: 0x15, W: []byte{}, R: []byte{0x04, 0x02, 0x01, 0x2c}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if d.String() != "t6713{playback(21)}" {
+		t.Fatalf("String Error: %s", d.String())
+	}
+	if d.FirmwareRevision() != 300 {
+		t.Errorf("FirmwareRevision Error: %d", d.FirmwareRevision())
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBadResponse(t *testing.T) {
+	bus := &i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			{Addr: 0x15, W: []byte{0x04, 0x13, 0x89, 0x00, 0x01}},
+			{Addr: 0x15, W: []byte{}, R: []byte{0x84, 0x02, 0x01, 0x2c}},
+		},
+		DontPanic: true,
+	}
+	if _, err := New(bus); err == nil {
+		t.Fatal("Bad response Error")
+	}
+}
+
+func TestReadCO2(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the firmware revision
+			{Addr: 0x15, W: []byte{0x04, 0x13, 0x89, 0x00, 0x01}},
+			{Addr: 0x15, W: []byte{}, R: []byte{0x04, 0x02, 0x01, 0x2c}},
+			// Warming up
+			{Addr: 0x15, W: []byte{0x04, 0x13, 0x8a, 0x00, 0x01}},
+			{Addr: 0x15, W: []byte{}, R: []byte{0x04, 0x02, 0x08, 0x00}},
+			// Flash error
+			{Addr: 0x15, W: []byte{0x04, 0x13, 0x8a, 0x00, 0x01}},
+			{Addr: 0x15, W: []byte{}, R: []byte{0x04, 0x02, 0x00, 0x02}},
+			// Ready
+			{Addr: 0x15, W: []byte{0x04, 0x13, 0x8a, 0x00, 0x01}},
+			{Addr: 0x15, W: []byte{}, R: []byte{0x04, 0x02, 0x00, 0x00}},
+			{Addr: 0x15, W: []byte{0x04, 0x13, 0x8b, 0x00, 0x01}},
+			{Addr: 0x15, W: []byte{}, R: []byte{0x04, 0x02, 0x02, 0x60}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if _, err := d.ReadCO2(); !errors.Is(err, ErrNotReady) {
+		t.Errorf("Warm-up Error: %v", err)
+	}
+	if _, err := d.ReadCO2(); err == nil || errors.Is(err, ErrNotReady) {
+		t.Errorf("Sensor error Error: %v", err)
+	}
+	r, err := d.ReadCO2()
+	if err != nil {
+		t.Fatalf("ReadCO2 Error: %s", err)
+	}
+	if r.CO2 != 608 {
+		t.Errorf("CO2 Error: %d", r.CO2)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadCO2I2CStatus(t *testing.T) {
+	// Bit 10 is set while the I²C interface is in use, it does not block a reading
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the firmware revision
+			{Addr: 0x15, W: []byte{0x04, 0x13, 0x89, 0x00, 0x01}},
+			{Addr: 0x15, W: []byte{}, R: []byte{0x04, 0x02, 0x01, 0x2c}},
+			{Addr: 0x15, W: []byte{0x04, 0x13, 0x8a, 0x00, 0x01}},
+			{Addr: 0x15, W: []byte{}, R: []byte{0x04, 0x02, 0x04, 0x00}},
+			{Addr: 0x15, W: []byte{0x04, 0x13, 0x8b, 0x00, 0x01}},
+			{Addr: 0x15, W: []byte{}, R: []byte{0x04, 0x02, 0x01, 0xf4}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	r, err := d.ReadCO2()
+	if err != nil {
+		t.Fatalf("ReadCO2 Error: %s", err)
+	}
+	if r.CO2 != 500 {
+		t.Errorf("CO2 Error: %d", r.CO2)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCalibrate(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the firmware revision
+			{Addr: 0x15, W: []byte{0x04, 0x13, 0x89, 0x00, 0x01}},
+			{Addr: 0x15, W: []byte{}, R: []byte{0x04, 0x02, 0x01, 0x2c}},
+			// Start the calibration
+			{Addr: 0x15, W: []byte{0x05, 0x03, 0xec, 0xff, 0x00}},
+			{Addr: 0x15, W: []byte{}, R: []byte{0x05, 0x03, 0xec, 0xff, 0x00}},
+			// Calibration finished
+			{Addr: 0x15, W: []byte{0x04, 0x13, 0x8a, 0x00, 0x01}},
+			{Addr: 0x15, W: []byte{}, R: []byte{0x04, 0x02, 0x00, 0x00}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if err := d.Calibrate(); err != nil {
+		t.Fatalf("Calibrate Error: %s", err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCalibrateError(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the firmware revision
+			{Addr: 0x15, W: []byte{0x04, 0x13, 0x89, 0x00, 0x01}},
+			{Addr: 0x15, W: []byte{}, R: []byte{0x04, 0x02, 0x01, 0x2c}},
+			// Start the calibration
+			{Addr: 0x15, W: []byte{0x05, 0x03, 0xec, 0xff, 0x00}},
+			{Addr: 0x15, W: []byte{}, R: []byte{0x05, 0x03, 0xec, 0xff, 0x00}},
+			// Calibration error
+			{Addr: 0x15, W: []byte{0x04, 0x13, 0x8a, 0x00, 0x01}},
+			{Addr: 0x15, W: []byte{}, R: []byte{0x04, 0x02, 0x80, 0x04}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if err := d.Calibrate(); err == nil {
+		t.Fatal("Calibration error Error")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestABCReset(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Read the firmware revision
+			{Addr: 0x15, W: []byte{0x04, 0x13, 0x89, 0x00, 0x01}},
+			{Addr: 0x15, W: []byte{}, R: []byte{0x04, 0x02, 0x01, 0x2c}},
+			// ABC on and off
+			{Addr: 0x15, W: []byte{0x05, 0x03, 0xee, 0xff, 0x00}},
+			{Addr: 0x15, W: []byte{}, R: []byte{0x05, 0x03, 0xee, 0xff, 0x00}},
+			{Addr: 0x15, W: []byte{0x05, 0x03, 0xee, 0x00, 0x00}},
+			{Addr: 0x15, W: []byte{}, R: []byte{0x05, 0x03, 0xee, 0x00, 0x00}},
+			// Reset
+			{Addr: 0x15, W: []byte{0x05, 0x03, 0xe8, 0xff, 0x00}},
+			{Addr: 0x15, W: []byte{}, R: []byte{0x05, 0x03, 0xe8, 0xff, 0x00}},
+			// Reset with the wrong echo
+			{Addr: 0x15, W: []byte{0x05, 0x03, 0xe8, 0xff, 0x00}},
+			{Addr: 0x15, W: []byte{}, R: []byte{0x05, 0x03, 0xe8, 0x00, 0x00}},
+		},
+		DontPanic: true,
+	}
+	d, err := New(&bus)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	if err := d.SetABC(true); err != nil {
+		t.Fatalf("SetABC Error: %s", err)
+	}
+	if err := d.SetABC(false); err != nil {
+		t.Fatalf("SetABC Error: %s", err)
+	}
+	if err := d.Reset(); err != nil {
+		t.Fatalf("Reset Error: %s", err)
+	}
+	if err := d.Reset(); err == nil {
+		t.Error("Wrong echo Error")
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,15 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package tca9548a controls a TCA9548A 8 channel I²C multiplexer.
+//
+// Sensors with a fixed address, like the PMSA003i at 0x12, cannot share a bus. Each
+// of them can be connected to a separate channel of the multiplexer, and the
+// i2c.Bus returned by Channel passed to the sensor's New function in place of the
+// bus.
+//
+// Datasheet
+//
+// https://www.ti.com/lit/ds/symlink/tca9548a.pdf
+package tca9548a
@@ -0,0 +1,62 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package tca9548a_test
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"periph.io/x/periph/conn/i2c/i2creg"
+	"periph.io/x/periph/host"
+
+	"github.com/bcl/air-sensors/pmsa003i"
+	"github.com/bcl/air-sensors/tca9548a"
+)
+
+func Example() {
+	// Make sure periph is initialized.
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Open a handle to the first available I²C bus:
+	bus, err := i2creg.Open("")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer bus.Close()
+
+	mux, err := tca9548a.New(bus, tca9548a.DefaultAddr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer mux.Halt() //nolint
+
+	// Two PMSA003i sensors, on channels 0 and 1
+	var sensors []*pmsa003i.Dev
+	for ch := 0; ch < 2; ch++ {
+		b, err := mux.Channel(ch)
+		if err != nil {
+			log.Fatal(err)
+		}
+		d, err := pmsa003i.New(b)
+		if err != nil {
+			log.Fatal(err)
+		}
+		sensors = append(sensors, d)
+	}
+
+	for range time.Tick(time.Second) {
+		for _, d := range sensors {
+			r, err := d.ReadSensor()
+			if err != nil {
+				fmt.Printf("%s: %s\n", d, err)
+				continue
+			}
+			fmt.Printf("%s: PM2.5 %3d μg/m3\n", d, r.EnvPm2_5)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package tca9548a
+
+import (
+	"fmt"
+	"sync"
+
+	"periph.io/x/periph/conn"
+	"periph.io/x/periph/conn/i2c"
+	"periph.io/x/periph/conn/physic"
+)
+
+// DefaultAddr is the I²C address of the multiplexer with A0-A2 low, it can be set
+// from 0x70 to 0x77
+const DefaultAddr uint16 = 0x70
+
+// Channels is the number of channels on the multiplexer
+const Channels = 8
+
+// Dev holds the connection to the multiplexer
+type Dev struct {
+	bus  i2c.Bus // The upstream i2c bus the multiplexer is connected to
+	addr uint16  // I²C address of the multiplexer
+
+	mu      sync.Mutex // Serializes transactions on the channels, protects current
+	current int        // The selected channel, -1 for none or unknown
+}
+
+var _ conn.Resource = &Dev{}
+
+// New returns a TCA9548A device struct for communicating with the multiplexer at addr
+//
+// All of the channels are disconnected until a transaction is made on one of them.
+func New(bus i2c.Bus, addr uint16) (*Dev, error) {
+	d := &Dev{bus: bus, addr: addr, current: -1}
+	if err := d.Halt(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// String implements conn.Resource.
+func (d *Dev) String() string {
+	return fmt.Sprintf("tca9548a{%s, 0x%02x}", d.bus, d.addr)
+}
+
+// Halt implements conn.Resource.
+//
+// It disconnects all of the channels.
+func (d *Dev) Halt() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.bus.Tx(d.addr, []byte{0x00}, nil); err != nil {
+		d.current = -1
+		return fmt.Errorf("tca9548a: Error while disconnecting the channels: %w", err)
+	}
+	d.current = -1
+	return nil
+}
+
+// Channel returns an i2c.Bus for the devices connected to channel n, 0-7
+//
+// Each transaction on the returned bus selects its channel first, if it is not
+// already selected. The channels can be used from multiple goroutines, the
+// transactions are serialized.
+func (d *Dev) Channel(n int) (i2c.Bus, error) {
+	if n < 0 || n >= Channels {
+		return nil, fmt.Errorf("tca9548a: Channel %d is out of range 0-%d", n, Channels-1)
+	}
+	return &channel{mux: d, n: n}, nil
+}
+
+// selectChannel connects channel n, the caller needs to hold mu
+func (d *Dev) selectChannel(n int) error {
+	if d.current == n {
+		return nil
+	}
+	if err := d.bus.Tx(d.addr, []byte{1 << uint(n)}, nil); err != nil {
+		d.current = -1
+		return fmt.Errorf("tca9548a: Error while selecting channel %d: %w", n, err)
+	}
+	d.current = n
+	return nil
+}
+
+// channel is an i2c.Bus for one of the multiplexer's channels
+type channel struct {
+	mux *Dev
+	n   int
+}
+
+// String implements i2c.Bus
+func (c *channel) String() string {
+	return fmt.Sprintf("%s/%d", c.mux, c.n)
+}
+
+// Tx implements i2c.Bus, it selects the channel and then does the transaction
+func (c *channel) Tx(addr uint16, w, r []byte) error {
+	c.mux.mu.Lock()
+	defer c.mux.mu.Unlock()
+	if err := c.mux.selectChannel(c.n); err != nil {
+		return err
+	}
+	return c.mux.bus.Tx(addr, w, r)
+}
+
+// SetSpeed implements i2c.Bus, it changes the speed of the upstream bus which is
+// shared by all of the channels.
+func (c *channel) SetSpeed(f physic.Frequency) error {
+	return c.mux.bus.SetSpeed(f)
+}
@@ -0,0 +1,87 @@
+// Copyright 2020 by Brian C. Lane <bcl@brianlane.com>. All rights reserved.
+// Use of this source code is governed under the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package tca9548a
+
+import (
+	"testing"
+
+	"periph.io/x/periph/conn/i2c"
+	"periph.io/x/periph/conn/i2c/i2ctest"
+)
+
+func TestFailNew(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops:       []i2ctest.IO{},
+		DontPanic: true,
+	}
+	if _, err := New(&bus, DefaultAddr); err == nil {
+		t.Fatal("New without a multiplexer Error")
+	}
+}
+
+func TestChannels(t *testing.T) {
+	bus := i2ctest.Playback{
+		Ops: []i2ctest.IO{
+			// Disconnect all channels
+			{Addr: 0x70, W: []byte{0x00}},
+			// Select channel 0, and read from 2 devices on it
+			{Addr: 0x70, W: []byte{0x01}},
+			{Addr: 0x12, W: []byte{}, R: []byte{0x01}},
+			{Addr: 0x13, W: []byte{}, R: []byte{0x02}},
+			// Select channel 7
+			{Addr: 0x70, W: []byte{0x80}},
+			{Addr: 0x12, W: []byte{}, R: []byte{0x03}},
+			// Back to channel 0
+			{Addr: 0x70, W: []byte{0x01}},
+			{Addr: 0x12, W: []byte{}, R: []byte{0x04}},
+			{Addr: 0x70, W: []byte{0x00}},
+		},
+	}
+	d, err := New(&bus, DefaultAddr)
+	if err != nil {
+		t.Fatalf("New Error: %s", err)
+	}
+	ch0, err := d.Channel(0)
+	if err != nil {
+		t.Fatalf("Channel Error: %s", err)
+	}
+	ch7, err := d.Channel(7)
+	if err != nil {
+		t.Fatalf("Channel Error: %s", err)
+	}
+	if _, err := d.Channel(8); err == nil {
+		t.Fatal("Channel out of range Error")
+	}
+	if ch7.String() != "tca9548a{playback, 0x70}/7" {
+		t.Errorf("String Error: %s", ch7)
+	}
+
+	tests := []struct {
+		bus      i2c.Bus
+		addr     uint16
+		expected byte
+	}{
+		{ch0, 0x12, 0x01},
+		{ch0, 0x13, 0x02},
+		{ch7, 0x12, 0x03},
+		{ch0, 0x12, 0x04},
+	}
+	for _, tt := range tests {
+		var data [1]byte
+		if err := tt.bus.Tx(tt.addr, []byte{}, data[:]); err != nil {
+			t.Fatalf("Tx Error: %s", err)
+		}
+		if data[0] != tt.expected {
+			t.Fatalf("Tx Data Error: 0x%02x != 0x%02x", data[0], tt.expected)
+		}
+	}
+
+	if err := d.Halt(); err != nil {
+		t.Fatalf("Halt Error: %s", err)
+	}
+	if err := bus.Close(); err != nil {
+		t.Fatalf("Playback Error: %s", err)
+	}
+}